@@ -0,0 +1,63 @@
+package glaze
+
+import (
+	"errors"
+	"strings"
+)
+
+// AppInfo describes the application shown in the about panel.
+type AppInfo struct {
+	// Name is the application name.
+	Name string
+
+	// Version is the human readable version string (e.g. "1.4.2").
+	Version string
+
+	// Copyright is the copyright line (e.g. "© 2026 Example Inc.").
+	Copyright string
+
+	// Icon is an optional path to an image file used as the panel icon.
+	// macOS accepts any format NSImage can read, GTK any format gdk-pixbuf
+	// can read, and Windows requires an .ico file.
+	Icon string
+
+	// Credits lists the people and projects to credit, one per line.
+	Credits string
+
+	// License is the license text or a short license notice.
+	License string
+}
+
+// ShowAbout opens the platform's standard about dialog for the application:
+// the Cocoa about panel on macOS, GtkAboutDialog on Linux and the shell about
+// box on Windows. Credits and License appear in the panel's scrolling text on
+// macOS and in the dialog's credits and license pages on Linux. The Windows
+// shell about box has no room for them, so a message box listing every
+// field is shown instead when either is set.
+//
+// Every platform ships a standard about dialog that matches the desktop, so
+// ShowAbout uses it instead of opening an HTML window, which would need a
+// second WebView and its own event loop on Linux and Windows.
+//
+// It must be called from the UI thread after a window has been created; use
+// Dispatch from background goroutines. On Linux and Windows the dialog is
+// modal and ShowAbout returns once it is dismissed.
+func ShowAbout(info AppInfo) error {
+	if info.Name == "" {
+		return errors.New("webview: AppInfo.Name must not be empty")
+	}
+	return showAbout(info)
+}
+
+// aboutDetails joins the credits and license for panels that show them as a
+// single block of text.
+func aboutDetails(info AppInfo) string {
+	var parts []string
+	if c := strings.TrimSpace(info.Credits); c != "" {
+		parts = append(parts, c)
+	}
+	if l := strings.TrimSpace(info.License); l != "" {
+		parts = append(parts, l)
+	}
+	return strings.Join(parts, "\n\n")
+}
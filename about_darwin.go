@@ -0,0 +1,41 @@
+package glaze
+
+import "github.com/ebitengine/purego/objc"
+
+func showAbout(info AppInfo) error {
+	opts := objc.ID(objc.GetClass("NSMutableDictionary")).Send(objc.RegisterName("dictionary"))
+	set := func(key string, value objc.ID) {
+		if value != 0 {
+			opts.Send(objc.RegisterName("setObject:forKey:"), value, nsString(key))
+		}
+	}
+
+	// Keys are the string values of the NSAboutPanelOption* constants.
+	set("ApplicationName", nsString(info.Name))
+	if info.Version != "" {
+		set("ApplicationVersion", nsString(info.Version))
+	}
+	if info.Copyright != "" {
+		set("Copyright", nsString(info.Copyright))
+	}
+	if details := aboutDetails(info); details != "" {
+		credits := objc.ID(objc.GetClass("NSAttributedString")).Send(objc.RegisterName("alloc"))
+		credits = credits.Send(objc.RegisterName("initWithString:"), nsString(details))
+		set("Credits", credits)
+		credits.Send(objc.RegisterName("autorelease"))
+	}
+	if info.Icon != "" {
+		img := objc.ID(objc.GetClass("NSImage")).Send(objc.RegisterName("alloc"))
+		set("ApplicationIcon", img.Send(objc.RegisterName("initWithContentsOfFile:"), nsString(info.Icon)))
+	}
+
+	app := objc.ID(objc.GetClass("NSApplication")).Send(objc.RegisterName("sharedApplication"))
+	app.Send(objc.RegisterName("activateIgnoringOtherApps:"), true)
+	app.Send(objc.RegisterName("orderFrontStandardAboutPanelWithOptions:"), opts)
+	return nil
+}
+
+// nsString returns an autoreleased NSString holding a copy of s.
+func nsString(s string) objc.ID {
+	return objc.ID(objc.GetClass("NSString")).Send(objc.RegisterName("stringWithUTF8String:"), s)
+}
@@ -0,0 +1,96 @@
+package glaze
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// gtkAbout holds the GTK 3 entry points used by ShowAbout. They resolve to
+// the same GTK instance the native webview library already initialized.
+var gtkAbout struct {
+	once sync.Once
+	err  error
+
+	dialogNew      func() uintptr
+	setName        func(dialog uintptr, name string)
+	setVersion     func(dialog uintptr, version string)
+	setCopyright   func(dialog uintptr, copyright string)
+	addCredits     func(dialog uintptr, section string, people unsafe.Pointer)
+	setLicense     func(dialog uintptr, license string)
+	setWrapLicense func(dialog uintptr, wrap bool)
+	setLogo        func(dialog uintptr, pixbuf uintptr)
+	pixbufFromFile func(path string, gerr uintptr) uintptr
+	dialogRun      func(dialog uintptr) int32
+	widgetDestroy  func(widget uintptr)
+	objectUnref    func(object uintptr)
+}
+
+func showAbout(info AppInfo) error {
+	gtkAbout.once.Do(func() {
+		gtkAbout.err = openNative("libgtk-3.so.0", []nativeFunc{
+			{&gtkAbout.dialogNew, "gtk_about_dialog_new"},
+			{&gtkAbout.setName, "gtk_about_dialog_set_program_name"},
+			{&gtkAbout.setVersion, "gtk_about_dialog_set_version"},
+			{&gtkAbout.setCopyright, "gtk_about_dialog_set_copyright"},
+			{&gtkAbout.addCredits, "gtk_about_dialog_add_credit_section"},
+			{&gtkAbout.setLicense, "gtk_about_dialog_set_license"},
+			{&gtkAbout.setWrapLicense, "gtk_about_dialog_set_wrap_license"},
+			{&gtkAbout.setLogo, "gtk_about_dialog_set_logo"},
+			{&gtkAbout.pixbufFromFile, "gdk_pixbuf_new_from_file"},
+			{&gtkAbout.dialogRun, "gtk_dialog_run"},
+			{&gtkAbout.widgetDestroy, "gtk_widget_destroy"},
+			{&gtkAbout.objectUnref, "g_object_unref"},
+		})
+	})
+	if gtkAbout.err != nil {
+		return gtkAbout.err
+	}
+
+	dialog := gtkAbout.dialogNew()
+	gtkAbout.setName(dialog, info.Name)
+	if info.Version != "" {
+		gtkAbout.setVersion(dialog, info.Version)
+	}
+	if info.Copyright != "" {
+		gtkAbout.setCopyright(dialog, info.Copyright)
+	}
+	if info.Credits != "" {
+		addCreditSection(dialog, "Credits", info.Credits)
+	}
+	if info.License != "" {
+		gtkAbout.setLicense(dialog, info.License)
+		gtkAbout.setWrapLicense(dialog, true)
+	}
+	if info.Icon != "" {
+		if pixbuf := gtkAbout.pixbufFromFile(info.Icon, 0); pixbuf != 0 {
+			gtkAbout.setLogo(dialog, pixbuf)
+			gtkAbout.objectUnref(pixbuf)
+		}
+	}
+	gtkAbout.dialogRun(dialog)
+	gtkAbout.widgetDestroy(dialog)
+	return nil
+}
+
+// addCreditSection adds the non-empty lines of credits to the dialog's
+// credits page under section. GTK copies the strings.
+func addCreditSection(dialog uintptr, section, credits string) {
+	var keep [][]byte
+	var people []unsafe.Pointer
+	for line := range strings.Lines(credits) {
+		if line = strings.TrimSpace(line); line != "" {
+			cs, ptr := cString(line)
+			keep = append(keep, cs)
+			people = append(people, ptr)
+		}
+	}
+	if len(people) == 0 {
+		return
+	}
+	people = append(people, nil) // NULL-terminated
+	gtkAbout.addCredits(dialog, section, unsafe.Pointer(&people[0]))
+	runtime.KeepAlive(keep)
+	runtime.KeepAlive(people)
+}
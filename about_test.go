@@ -0,0 +1,26 @@
+package glaze
+
+import "testing"
+
+func TestShowAboutRequiresName(t *testing.T) {
+	if err := ShowAbout(AppInfo{Version: "1.0"}); err == nil {
+		t.Fatal("expected error for empty AppInfo.Name")
+	}
+}
+
+func TestAboutDetails(t *testing.T) {
+	tests := []struct {
+		info AppInfo
+		want string
+	}{
+		{AppInfo{Name: "App"}, ""},
+		{AppInfo{Credits: "Ana\nBo\n"}, "Ana\nBo"},
+		{AppInfo{License: "MIT"}, "MIT"},
+		{AppInfo{Credits: "Ana", License: "MIT"}, "Ana\n\nMIT"},
+	}
+	for _, tt := range tests {
+		if got := aboutDetails(tt.info); got != tt.want {
+			t.Errorf("aboutDetails(%+v) = %q, want %q", tt.info, got, tt.want)
+		}
+	}
+}
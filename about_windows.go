@@ -0,0 +1,82 @@
+package glaze
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	shell32        = syscall.NewLazyDLL("shell32.dll")
+	procShellAbout = shell32.NewProc("ShellAboutW")
+
+	user32         = syscall.NewLazyDLL("user32.dll")
+	procLoadImage  = user32.NewProc("LoadImageW")
+	procMessageBox = user32.NewProc("MessageBoxW")
+)
+
+const (
+	imageIcon      = 1
+	lrLoadFromFile = 0x0010
+	lrDefaultSize  = 0x0040
+
+	mbIconInformation = 0x00000040
+)
+
+func showAbout(info AppInfo) error {
+	if details := aboutDetails(info); details != "" {
+		return showAboutMessage(info, details)
+	}
+	// ShellAboutW renders the text before '#' as the title and the text after
+	// it as the first line of the dialog.
+	title := info.Name
+	if info.Version != "" {
+		title += "#" + info.Name + " " + info.Version
+	}
+	app, err := syscall.UTF16PtrFromString(title)
+	if err != nil {
+		return err
+	}
+	other, err := syscall.UTF16PtrFromString(info.Copyright)
+	if err != nil {
+		return err
+	}
+
+	var icon uintptr
+	if info.Icon != "" {
+		path, err := syscall.UTF16PtrFromString(info.Icon)
+		if err != nil {
+			return err
+		}
+		icon, _, _ = procLoadImage.Call(0, uintptr(unsafe.Pointer(path)), imageIcon, 0, 0, lrLoadFromFile|lrDefaultSize)
+	}
+
+	if r, _, err := procShellAbout.Call(0, uintptr(unsafe.Pointer(app)), uintptr(unsafe.Pointer(other)), icon); r == 0 {
+		return fmt.Errorf("webview: ShellAboutW failed: %w", err)
+	}
+	return nil
+}
+
+// showAboutMessage lists every AppInfo field in a message box, since the
+// shell about box cannot show credits or a license.
+func showAboutMessage(info AppInfo, details string) error {
+	header := info.Name
+	if info.Version != "" {
+		header += " " + info.Version
+	}
+	if info.Copyright != "" {
+		header += "\n" + info.Copyright
+	}
+	text, err := syscall.UTF16PtrFromString(header + "\n\n" + details)
+	if err != nil {
+		return err
+	}
+	caption, err := syscall.UTF16PtrFromString("About " + info.Name)
+	if err != nil {
+		return err
+	}
+	if r, _, err := procMessageBox.Call(0, uintptr(unsafe.Pointer(text)), uintptr(unsafe.Pointer(caption)), mbIconInformation); r == 0 {
+		return fmt.Errorf("webview: MessageBoxW failed: %w", err)
+	}
+	return nil
+}
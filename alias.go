@@ -0,0 +1,144 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+)
+
+// deprecatedName is the hidden binding pages report calls to deprecated
+// aliases through.
+const deprecatedName = "__glaze_deprecated"
+
+// aliasJS defines an alias forwarding to the function its entry in
+// __glaze_aliases currently names. It is called with the alias. The first
+// call to a deprecated alias on each page is warned about in the console and
+// reported to Go.
+const aliasJS = `(function (alias) {
+  'use strict';
+  var warned = false;
+  window[alias] = function () {
+    var target = (window.__glaze_aliases || {})[alias];
+    if (!target) {
+      return Promise.reject({ code: "not_found", message: alias + " is not an alias" });
+    }
+    var name = target.name;
+    if (target.message && !warned) {
+      warned = true;
+      console.warn(alias + " is deprecated; use " + name + ". " + target.message);
+      if (typeof window.` + deprecatedName + ` === "function") { window.` + deprecatedName + `(alias, location.href); }
+    }
+    if (typeof window[name] !== "function") {
+      return Promise.reject({ code: "not_found", message: name + " is not bound" });
+    }
+    return window[name].apply(null, arguments);
+  };
+})(`
+
+// aliasEntryJS returns the script setting the target of alias. It is
+// evaluated into the current page and replaces the alias's previous entry
+// among the init scripts, for every page loaded after.
+func aliasEntryJS(alias, name, message string) string {
+	return "(window.__glaze_aliases = window.__glaze_aliases || {})[" + marshalJSON(alias) + "] = {name: " +
+		marshalJSON(name) + ", message: " + marshalJSON(message) + "};"
+}
+
+// AliasOptions configures BindAlias.
+type AliasOptions struct {
+	// Deprecated marks the alias as deprecated with a message telling
+	// frontend developers what to do, such as "Removed in 3.0.". The first
+	// call on each page logs a console warning and is reported to
+	// OnDeprecated.
+	Deprecated string
+
+	// OnDeprecated receives the first call to a deprecated alias on each
+	// page, so the app can log which frontends still need migrating.
+	OnDeprecated func(DeprecatedCall)
+}
+
+// DeprecatedCall describes a call to a deprecated alias.
+type DeprecatedCall struct {
+	// Alias is the name the page called.
+	Alias string
+
+	// Name is the function the call was forwarded to.
+	Name string
+
+	// Message is AliasOptions.Deprecated.
+	Message string
+
+	// URL is the page that made the call.
+	URL string
+}
+
+// aliasTarget is an alias defined with BindAlias.
+type aliasTarget struct {
+	name string
+	opts AliasOptions
+}
+
+// BindAlias makes alias another name for the function bound as name, so a
+// JavaScript API can be renamed while frontends built against the old name
+// keep working:
+//
+//	_ = w.Bind("notes_search", search)
+//	_ = glaze.BindAlias(w, "searchNotes", "notes_search", glaze.AliasOptions{
+//		Deprecated:   "Removed in 3.0.",
+//		OnDeprecated: func(c glaze.DeprecatedCall) { log.Printf("%s called from %s", c.Alias, c.URL) },
+//	})
+//
+// Calls to alias reach whatever is bound as name at the time of the call,
+// with its options. Calling BindAlias again for alias redefines it, on the
+// current page and on pages loaded after. Like Bind, BindAlias must be
+// called from the UI thread.
+func BindAlias(w WebView, alias, name string, opts AliasOptions) error {
+	if w == nil {
+		return errors.New("webview: BindAlias requires a non-nil WebView")
+	}
+	if !jsPath.MatchString(alias) || !jsPath.MatchString(name) || alias == name {
+		return fmt.Errorf("webview: invalid alias %q for %q", alias, name)
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	_, bound := b.bindings[alias]
+	prev, defined := b.aliases[alias]
+	if !bound {
+		if b.aliases == nil {
+			b.aliases = make(map[string]aliasTarget)
+		}
+		b.aliases[alias] = aliasTarget{name: name, opts: opts}
+	}
+	b.mu.Unlock()
+	if bound {
+		return fmt.Errorf("webview: %s is already bound", alias)
+	}
+	if opts.Deprecated != "" {
+		if err := b.bindHidden(deprecatedName, b.deprecatedCall); err != nil {
+			return err
+		}
+	}
+	if !defined || prev.name != name || prev.opts.Deprecated != opts.Deprecated {
+		entry := aliasEntryJS(alias, name, opts.Deprecated)
+		b.mu.Lock()
+		b.setRuntimeScriptLocked("alias-target:"+alias, entry)
+		b.mu.Unlock()
+		w.Eval(entry)
+	}
+	b.injectScript("alias:"+alias, aliasJS+marshalJSON(alias)+");")
+	return nil
+}
+
+// deprecatedCall is bound as deprecatedName.
+func (b *bridge) deprecatedCall(alias, url string) {
+	b.mu.Lock()
+	target, ok := b.aliases[alias]
+	b.mu.Unlock()
+	if !ok || target.opts.OnDeprecated == nil {
+		return
+	}
+	target.opts.OnDeprecated(DeprecatedCall{
+		Alias:   alias,
+		Name:    target.name,
+		Message: target.opts.Deprecated,
+		URL:     url,
+	})
+}
@@ -0,0 +1,80 @@
+package glaze
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBindAlias(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	_ = w.Bind("notes_search", func(q string) []string { return []string{q} })
+
+	var calls []DeprecatedCall
+	err := BindAlias(w, "searchNotes", "notes_search", AliasOptions{
+		Deprecated:   "Removed in 3.0.",
+		OnDeprecated: func(c DeprecatedCall) { calls = append(calls, c) },
+	})
+	if err != nil {
+		t.Fatalf("BindAlias() unexpected error: %v", err)
+	}
+	entry := `(window.__glaze_aliases = window.__glaze_aliases || {})["searchNotes"] = {name: "notes_search", message: "Removed in 3.0."};`
+	want := []string{entry, aliasJS + `"searchNotes");`}
+	if !slices.Equal(w.inits, want) {
+		t.Fatalf("inits = %q, want %q", w.inits, want)
+	}
+	if _, ok := w.bound[deprecatedName]; !ok {
+		t.Fatalf("%s was not bound", deprecatedName)
+	}
+
+	if _, err := w.call(t, deprecatedName, "searchNotes", "http://127.0.0.1/"); err != nil {
+		t.Fatalf("report call unexpected error: %v", err)
+	}
+	wantCall := DeprecatedCall{Alias: "searchNotes", Name: "notes_search", Message: "Removed in 3.0.", URL: "http://127.0.0.1/"}
+	if len(calls) != 1 || calls[0] != wantCall {
+		t.Errorf("OnDeprecated calls = %+v, want %+v", calls, wantCall)
+	}
+
+	if err := BindAlias(w, "findNotes", "notes_search", AliasOptions{}); err != nil {
+		t.Fatalf("BindAlias() unexpected error: %v", err)
+	}
+	if got := w.inits[len(w.inits)-2]; !strings.HasSuffix(got, `["findNotes"] = {name: "notes_search", message: ""};`) {
+		t.Errorf("plain alias entry = %q", got)
+	}
+
+	// Redefining an alias replaces its entry for future pages too, without
+	// defining the alias again.
+	_ = w.Bind("notes_find", func(q string) []string { return []string{q} })
+	n := len(w.inits)
+	if err := BindAlias(w, "findNotes", "notes_find", AliasOptions{}); err != nil {
+		t.Fatalf("BindAlias() redefinition unexpected error: %v", err)
+	}
+	redefined := `(window.__glaze_aliases = window.__glaze_aliases || {})["findNotes"] = {name: "notes_find", message: ""};`
+	if len(w.inits) != n || !slices.Contains(w.inits, redefined) || slices.ContainsFunc(w.inits, func(js string) bool {
+		return strings.Contains(js, `{name: "notes_search", message: ""}`)
+	}) {
+		t.Errorf("inits after redefinition = %q, want the entry replaced by %q", w.inits, redefined)
+	}
+	if w.evals[len(w.evals)-1] != redefined {
+		t.Errorf("current page not updated, last eval = %q", w.evals[len(w.evals)-1])
+	}
+	n = len(w.inits)
+	if err := BindAlias(w, "findNotes", "notes_find", AliasOptions{}); err != nil {
+		t.Fatalf("BindAlias() repeat unexpected error: %v", err)
+	}
+	if len(w.inits) != n {
+		t.Errorf("unchanged alias registered %q", w.inits[n:])
+	}
+
+	for _, tt := range []struct{ alias, name string }{
+		{"notes_search", "other"},
+		{"same", "same"},
+		{"", "notes_search"},
+		{"a;b", "notes_search"},
+	} {
+		if err := BindAlias(w, tt.alias, tt.name, AliasOptions{}); err == nil {
+			t.Errorf("BindAlias(%q, %q) returned no error", tt.alias, tt.name)
+		}
+	}
+}
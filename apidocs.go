@@ -0,0 +1,229 @@
+package glaze
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// apiDocsService is a group of methods sharing a prefix, as bound by
+// BindMethods and BindAll.
+type apiDocsService struct {
+	Name    string
+	Methods []apiDocsMethod
+}
+
+type apiDocsMethod struct {
+	Name   string
+	Params []apiDocsField
+	Result string
+}
+
+type apiDocsField struct {
+	Name     string
+	Type     string
+	Optional bool
+}
+
+type apiDocsType struct {
+	Name   string
+	Fields []apiDocsField
+}
+
+// APIDocsPage renders the functions bound in w as an HTML reference page,
+// from the same data as Manifest: each function's JavaScript call with
+// parameter and result types, grouped by service prefix, followed by the
+// fields of the structs they use. It reflects the bindings at the time of
+// the call; show it in a window with SetHtml, or serve it with
+// APIDocsHandler.
+func APIDocsPage(w WebView) string {
+	m := Manifest(w)
+	groups := make(map[string]*apiDocsService)
+	for _, method := range m.Methods {
+		service := method.Name
+		if i := strings.IndexAny(service, "._"); i > 0 {
+			service = service[:i]
+		} else {
+			service = ""
+		}
+		g := groups[service]
+		if g == nil {
+			g = &apiDocsService{Name: service}
+			groups[service] = g
+		}
+		d := apiDocsMethod{Name: method.Name, Result: "void"}
+		for _, p := range method.Params {
+			f := apiDocsField{Name: p.Name, Type: schemaTypeName(p.Schema)}
+			if p.Variadic {
+				f.Name = "..." + f.Name
+			}
+			d.Params = append(d.Params, f)
+		}
+		if method.Result != nil {
+			d.Result = schemaTypeName(method.Result.Schema)
+		}
+		g.Methods = append(g.Methods, d)
+	}
+	services := make([]apiDocsService, 0, len(groups))
+	for _, g := range groups {
+		services = append(services, *g)
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+
+	types := make([]apiDocsType, 0, len(m.Components.Schemas))
+	for name, s := range m.Components.Schemas {
+		types = append(types, apiDocsType{Name: name, Fields: schemaFields(s)})
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].Name < types[j].Name })
+
+	var buf bytes.Buffer
+	_ = apiDocsTemplate.Execute(&buf, struct { // template is trusted, names are escaped
+		Services []apiDocsService
+		Types    []apiDocsType
+	}{services, types})
+	return buf.String()
+}
+
+// APIDocsHandler serves APIDocsPage for w, rendered on each request so it
+// follows bindings added later. Mount it in development builds only:
+//
+//	if *dev {
+//		mux.Handle("/_glaze/api", glaze.APIDocsHandler(w))
+//	}
+func APIDocsHandler(w WebView) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+		rw.Header().Set("Cache-Control", "no-store")
+		_, _ = rw.Write([]byte(APIDocsPage(w)))
+	})
+}
+
+// schemaTypeName writes a schema from Manifest as a TypeScript-like type.
+func schemaTypeName(s JSONSchema) string {
+	if ref, ok := s["$ref"].(string); ok {
+		return strings.TrimPrefix(ref, "#/components/schemas/")
+	}
+	if alts, ok := s["anyOf"].([]JSONSchema); ok {
+		names := make([]string, len(alts))
+		for i, a := range alts {
+			names[i] = schemaTypeName(a)
+		}
+		return strings.Join(names, " | ")
+	}
+	var kinds []string
+	switch t := s["type"].(type) {
+	case string:
+		kinds = []string{t}
+	case []string:
+		kinds = t
+	default:
+		return "any"
+	}
+	nullable := len(kinds) > 1 && slices.Contains(kinds, "null")
+	kind := kinds[0]
+	var name string
+	switch kind {
+	case "integer":
+		name = "number"
+	case "array":
+		if items, ok := s["prefixItems"].([]JSONSchema); ok {
+			names := make([]string, len(items))
+			for i, item := range items {
+				names[i] = schemaTypeName(item)
+			}
+			name = "[" + strings.Join(names, ", ") + "]"
+			break
+		}
+		elem := "any"
+		if items, ok := s["items"].(JSONSchema); ok {
+			elem = schemaTypeName(items)
+		}
+		if strings.Contains(elem, " ") {
+			elem = "(" + elem + ")"
+		}
+		name = elem + "[]"
+	case "object":
+		if props, ok := s["properties"].(JSONSchema); ok {
+			var parts []string
+			for _, f := range schemaFields(JSONSchema{"properties": props, "required": s["required"]}) {
+				opt := ""
+				if f.Optional {
+					opt = "?"
+				}
+				parts = append(parts, f.Name+opt+": "+f.Type)
+			}
+			name = "{ " + strings.Join(parts, "; ") + " }"
+		} else {
+			elem := "any"
+			if v, ok := s["additionalProperties"].(JSONSchema); ok {
+				elem = schemaTypeName(v)
+			}
+			name = "Record<string, " + elem + ">"
+		}
+	default:
+		name = kind
+	}
+	if nullable {
+		name += " | null"
+	}
+	return name
+}
+
+// schemaFields lists the properties of an object schema, sorted by name.
+func schemaFields(s JSONSchema) []apiDocsField {
+	props, _ := s["properties"].(JSONSchema)
+	required, _ := s["required"].([]string)
+	fields := make([]apiDocsField, 0, len(props))
+	for name, p := range props {
+		ps, _ := p.(JSONSchema)
+		fields = append(fields, apiDocsField{Name: name, Type: schemaTypeName(ps), Optional: !slices.Contains(required, name)})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	return fields
+}
+
+var apiDocsTemplate = template.Must(template.New("apidocs").Parse(`<!doctype html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>Go API</title>
+  <style>
+    body { margin: 0; padding: 16px 24px; font: 14px -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; background: #f9fafb; color: #111827; }
+    h1 { font-size: 20px; }
+    h2 { font-size: 14px; margin: 24px 0 8px 0; color: #6b7280; text-transform: uppercase; letter-spacing: .05em; }
+    code { font: 13px ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; }
+    .method, .type { margin: 8px 0; padding: 8px 12px; background: #fff; border: 1px solid #e5e7eb; border-radius: 6px; }
+    .muted { color: #6b7280; }
+    table { border-collapse: collapse; margin-top: 4px; }
+    td { padding: 2px 12px 2px 0; vertical-align: top; }
+  </style>
+</head>
+<body>
+  <h1>Go API</h1>
+  <p class="muted">Functions bound in this window. Each returns a Promise of its result type.</p>
+  {{- range .Services}}
+  <h2 id="service-{{.Name}}">{{if .Name}}{{.Name}}{{else}}Functions{{end}}</h2>
+  {{- range .Methods}}
+  <div class="method" id="{{.Name}}"><code>{{.Name}}({{range $i, $p := .Params}}{{if $i}}, {{end}}{{$p.Name}}: {{$p.Type}}{{end}}): <span class="muted">Promise&lt;{{.Result}}&gt;</span></code></div>
+  {{- end}}
+  {{- else}}
+  <p>No functions are bound.</p>
+  {{- end}}
+  {{- if .Types}}
+  <h2>Types</h2>
+  {{- range .Types}}
+  <div class="type" id="type-{{.Name}}"><code>{{.Name}}</code>
+    <table>
+    {{- range .Fields}}
+      <tr><td><code>{{.Name}}{{if .Optional}}?{{end}}</code></td><td><code class="muted">{{.Type}}</code></td></tr>
+    {{- end}}
+    </table>
+  </div>
+  {{- end}}
+  {{- end}}
+</body>
+</html>`))
@@ -0,0 +1,62 @@
+package glaze
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type docsNote struct {
+	ID      int       `json:"id"`
+	Title   string    `json:"title"`
+	Tags    []string  `json:"tags,omitempty"`
+	Updated time.Time `json:"updated"`
+}
+
+func TestAPIDocsPage(t *testing.T) {
+	w := &fakeWebView{}
+	for name, f := range map[string]any{
+		"notes_get":    func(ctx context.Context, id int) (*docsNote, error) { return nil, nil },
+		"notes_tag":    func(id int, tags ...string) error { return nil },
+		"ping":         func() string { return "pong" },
+		"__glaze_seen": func() {},
+	} {
+		if err := w.Bind(name, f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bridgeFor(w).hidden = map[string]bool{"__glaze_seen": true}
+
+	page := APIDocsPage(w)
+	for _, want := range []string{
+		`<h2 id="service-notes">notes</h2>`,
+		`notes_get(arg0: number): <span class="muted">Promise&lt;docsNote | null&gt;</span>`,
+		`notes_tag(arg0: number, ...arg1: string[] | null): <span class="muted">Promise&lt;void&gt;</span>`,
+		`ping(): <span class="muted">Promise&lt;string&gt;</span>`,
+		`<div class="type" id="type-docsNote">`,
+		`<code>tags?</code></td><td><code class="muted">string[] | null</code>`,
+		`<code>updated</code></td><td><code class="muted">string</code>`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page lacks %s", want)
+		}
+	}
+	if strings.Contains(page, "__glaze_seen") {
+		t.Error("page lists an internal binding")
+	}
+}
+
+func TestAPIDocsHandler(t *testing.T) {
+	w := &fakeWebView{}
+	rec := httptest.NewRecorder()
+	APIDocsHandler(w).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/_glaze/api", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "No functions are bound.") {
+		t.Fatalf("body = %.200s", rec.Body.String())
+	}
+}
@@ -0,0 +1,303 @@
+package glaze
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// App is a running AppWindow. It is passed to AppOptions.OnStart and
+// returned by AppWindowStart.
+type App struct {
+	handler *swapHandler
+	w       WebView
+	url     string
+
+	srv            *http.Server
+	closeTransport func() error
+	stopOnce       sync.Once
+
+	onClose func(app *App)
+	onExit  func() error
+
+	// Settings for windows opened with OpenWindow, and those windows.
+	window  AppOptions
+	trust   func(WebView) error
+	token   string
+	scheme  bool // served with AppTransportScheme
+	windows []WebView
+
+	// open counts the windows not closed yet; it is used on the UI thread.
+	open int
+}
+
+// WebView returns the window of the app, for the calls App does not wrap,
+// such as Bind.
+func (a *App) WebView() WebView {
+	return a.w
+}
+
+// URL returns the navigable base URL the app is served at.
+func (a *App) URL() string {
+	return a.url
+}
+
+// Navigate loads url in the window; a path such as "/settings" is resolved
+// against URL. It may be called from any goroutine.
+func (a *App) Navigate(url string) {
+	if strings.HasPrefix(url, "/") {
+		url = strings.TrimSuffix(a.url, "/") + url
+	}
+	a.w.Dispatch(func() { a.w.Navigate(url) })
+}
+
+// Eval evaluates js in the current page without waiting for it to run. It
+// may be called from any goroutine; use EvalResult for the result.
+func (a *App) Eval(js string) {
+	a.w.Dispatch(func() { a.w.Eval(js) })
+}
+
+// Emit sends event with payload to the page, as Emit does. It may be called
+// from any goroutine.
+func (a *App) Emit(event string, payload any) error {
+	return Emit(a.w, event, payload)
+}
+
+// OpenWindow opens another window on the app, at path resolved against URL,
+// with the title, size and debug setting of the first. Windows share the
+// server, and Run returns once the last of them is closed, so any window
+// may be closed first, even with the scheme transport, whose requests are
+// handed back through a window still open. The new window has the services
+// of AppOptions.Bind bound; bind anything else its pages use on the
+// returned WebView. OpenWindow must be called from the UI thread, such as
+// in OnStart or a function passed to Dispatch.
+//
+//	settings, err := app.OpenWindow("/settings")
+//	if err == nil {
+//		settings.SetTitle("Settings")
+//	}
+func (a *App) OpenWindow(path string) (WebView, error) {
+	w, err := New(a.window.Debug)
+	if err != nil {
+		return nil, fmt.Errorf("webview: %w", err)
+	}
+	if a.trust != nil {
+		if err := a.trust(w); err != nil {
+			w.Destroy()
+			return nil, err
+		}
+	}
+	if a.scheme {
+		if err := joinScheme(w, appSchemeHost); err != nil {
+			w.Destroy()
+			return nil, err
+		}
+	}
+	if len(a.window.Bind) > 0 {
+		if _, err := BindAll(w, a.window.Bind); err != nil {
+			leaveScheme(w)
+			w.Destroy()
+			return nil, err
+		}
+	}
+	a.link(w)
+	a.track(w)
+	w.SetTitle(a.window.Title)
+	w.SetSize(a.window.Width, a.window.Height, a.window.Hint)
+	w.Navigate(a.windowURL(path))
+	a.windows = append(a.windows, w)
+	return w, nil
+}
+
+// link records w as a window of a, for CaptureWindow and RestoreWindow.
+func (a *App) link(w WebView) {
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.app = a
+	b.mu.Unlock()
+}
+
+// track counts w among the open windows until it is closed, and makes
+// closing the last one end Run. Where closing cannot be watched the count
+// never reaches zero, and the native library ends the loop once its last
+// window is closed.
+func (a *App) track(w WebView) {
+	a.open++
+	_ = watchWindowClose(w, func() { a.windowClosed(w) })
+}
+
+// windowClosed is called on the UI thread when the window w is closed.
+func (a *App) windowClosed(w WebView) {
+	leaveScheme(w)
+	a.open--
+	if a.open == 0 {
+		w.Terminate()
+	}
+}
+
+// windowURL returns the URL a window opened at path first loads, carrying
+// the access token when one is required.
+func (a *App) windowURL(path string) string {
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	u := strings.TrimSuffix(a.url, "/") + path
+	if a.token == "" {
+		return u
+	}
+	u, fragment, _ := strings.Cut(u, "#")
+	sep := "?"
+	if strings.Contains(u, "?") {
+		sep = "&"
+	}
+	u += sep + appTokenParam + "=" + url.QueryEscape(a.token)
+	if fragment != "" {
+		u += "#" + fragment
+	}
+	return u
+}
+
+// Close closes every window of the app, which makes Run return. It may be
+// called from any goroutine.
+func (a *App) Close() {
+	a.w.Dispatch(a.w.Terminate)
+}
+
+// Run runs the UI event loop until the last window is closed, then calls
+// AppOptions.OnClose, shuts the server down, waiting up to five seconds for
+// requests in flight to finish, calls AppOptions.OnExit, runs
+// the hooks registered with OnShutdown and returns their errors. It must be
+// called from the thread that called AppWindowStart, and only once.
+func (a *App) Run() error {
+	a.w.Run()
+	if a.onClose != nil {
+		a.onClose(a)
+	}
+	for _, w := range a.windows {
+		w.Destroy()
+	}
+	a.w.Destroy()
+
+	// Stop serving before shutdown hooks close what handlers depend on.
+	a.stopServer()
+	var exitErr error
+	if a.onExit != nil {
+		exitErr = a.onExit()
+	}
+	return errors.Join(exitErr, RunShutdownHooks())
+}
+
+// RunContext is Run, closing the window when ctx is done, such as on
+// SIGINT with signal.NotifyContext.
+func (a *App) RunContext(ctx context.Context) error {
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			a.Close()
+		case <-stop:
+		}
+	}()
+	return a.Run()
+}
+
+// appShutdownTimeout bounds how long stopping the server waits for the
+// requests in flight to finish.
+const appShutdownTimeout = 5 * time.Second
+
+// stopServer stops accepting requests, waits up to appShutdownTimeout for
+// those in flight, including ones served without the HTTP server by the
+// scheme transport, then closes the server and its transport.
+func (a *App) stopServer() {
+	a.stopOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), appShutdownTimeout)
+		defer cancel()
+		if a.srv != nil {
+			if err := a.srv.Shutdown(ctx); err != nil {
+				_ = a.srv.Close()
+			}
+		}
+		if a.handler != nil {
+			select {
+			case <-a.handler.swap(nil):
+			case <-ctx.Done():
+			}
+		}
+		if a.closeTransport != nil {
+			_ = a.closeTransport()
+		}
+	})
+}
+
+// SetHandler replaces the handler serving the app, for example to reload
+// routes in development or to swap a plugin, without closing the window.
+// Requests that arrive afterwards go to h; requests already being served
+// finish on the previous handler. The returned channel is closed once they
+// have, which is when resources of the old handler can be released:
+//
+//	done := app.SetHandler(newMux)
+//	go func() { <-done; oldPlugin.Close() }()
+//
+// A nil h serves 404s. SetHandler may be called from any goroutine.
+func (a *App) SetHandler(h http.Handler) <-chan struct{} {
+	return a.handler.swap(h)
+}
+
+// swapHandler serves through a replaceable handler and tracks the requests
+// in flight on each one, so a replaced handler can be drained.
+type swapHandler struct {
+	mu      sync.Mutex
+	current *handlerGen
+}
+
+// handlerGen is one handler installed in a swapHandler.
+type handlerGen struct {
+	h       http.Handler
+	active  int
+	retired bool
+	drained chan struct{}
+}
+
+func newSwapHandler(h http.Handler) *swapHandler {
+	return &swapHandler{current: &handlerGen{h: h, drained: make(chan struct{})}}
+}
+
+func (s *swapHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	gen := s.current
+	gen.active++
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		gen.active--
+		if gen.retired && gen.active == 0 {
+			close(gen.drained)
+		}
+		s.mu.Unlock()
+	}()
+	gen.h.ServeHTTP(rw, r)
+}
+
+// swap installs h and returns a channel closed once the requests on the
+// previous handler have finished.
+func (s *swapHandler) swap(h http.Handler) <-chan struct{} {
+	if h == nil {
+		h = http.NotFoundHandler()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old := s.current
+	s.current = &handlerGen{h: h, drained: make(chan struct{})}
+	old.retired = true
+	if old.active == 0 {
+		close(old.drained)
+	}
+	return old.drained
+}
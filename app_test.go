@@ -0,0 +1,297 @@
+package glaze
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestAppSetHandlerDrains(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{})
+	oldHandler := http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		_, _ = io.WriteString(rw, "old")
+	})
+	app := &App{handler: newSwapHandler(oldHandler)}
+	srv := httptest.NewServer(app.handler)
+	defer srv.Close()
+
+	slow := make(chan string, 1)
+	go func() {
+		slow <- fetchBody(t, srv.URL)
+	}()
+	<-started
+
+	done := app.SetHandler(http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(rw, "new")
+	}))
+	if got := fetchBody(t, srv.URL); got != "new" {
+		t.Fatalf("request after SetHandler = %q, want new", got)
+	}
+	select {
+	case <-done:
+		t.Fatal("drained before the in-flight request finished")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	if got := <-slow; got != "old" {
+		t.Fatalf("in-flight request = %q, want it to finish on the old handler", got)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("not drained after the in-flight request finished")
+	}
+}
+
+func TestAppSetHandlerIdle(t *testing.T) {
+	app := &App{handler: newSwapHandler(http.NotFoundHandler())}
+	select {
+	case <-app.SetHandler(nil):
+	default:
+		t.Fatal("idle handler not reported drained at once")
+	}
+	rec := httptest.NewRecorder()
+	app.handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("nil handler status = %d, want 404", rec.Code)
+	}
+}
+
+func fetchBody(t *testing.T, url string) string {
+	t.Helper()
+	resp, err := http.Get(url) //nolint:noctx
+	if err != nil {
+		t.Errorf("GET %s: %v", url, err)
+		return ""
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+// closingWebView records Terminate.
+type closingWebView struct {
+	fakeWebView
+	terminated bool
+}
+
+func (w *closingWebView) Terminate() { w.terminated = true }
+
+func TestAppControls(t *testing.T) {
+	w := &closingWebView{}
+	app := &App{handler: newSwapHandler(http.NotFoundHandler()), w: w, url: "http://127.0.0.1:4000"}
+	if app.WebView() != w {
+		t.Fatal("WebView does not return the window")
+	}
+	if app.URL() != "http://127.0.0.1:4000" {
+		t.Fatalf("URL = %q", app.URL())
+	}
+
+	app.Navigate("/settings")
+	app.Navigate("https://example.com/")
+	want := []string{"http://127.0.0.1:4000/settings", "https://example.com/"}
+	if !slices.Equal(w.navigated, want) {
+		t.Fatalf("navigated = %q, want %q", w.navigated, want)
+	}
+
+	app.Eval("document.title = 'x'")
+	if len(w.evals) != 1 || w.evals[0] != "document.title = 'x'" {
+		t.Fatalf("evals = %q", w.evals)
+	}
+
+	if err := app.Emit("saved", 1); err != nil {
+		t.Fatalf("Emit: %v", err)
+	}
+	if last := w.evals[len(w.evals)-1]; !strings.Contains(last, `_emit("saved", 1)`) {
+		t.Fatalf("Emit did not reach the page: %q", w.evals)
+	}
+
+	app.Close()
+	if !w.terminated {
+		t.Fatal("Close did not terminate the window")
+	}
+}
+
+func TestAppRunStopsServer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &App{handler: newSwapHandler(http.NotFoundHandler()), w: &fakeWebView{}}
+	app.srv = &http.Server{Handler: app.handler}
+	transportClosed := false
+	app.closeTransport = func() error { transportClosed = true; return nil }
+	served := make(chan error, 1)
+	go func() { served <- app.srv.Serve(ln) }()
+
+	if err := app.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	select {
+	case err := <-served:
+		if !errors.Is(err, http.ErrServerClosed) {
+			t.Fatalf("Serve = %v, want ErrServerClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("server still serving after Run returned")
+	}
+	if !transportClosed {
+		t.Fatal("transport not closed after Run returned")
+	}
+}
+
+// loopWebView runs until Terminate.
+type loopWebView struct {
+	fakeWebView
+	stop chan struct{}
+}
+
+func (w *loopWebView) Run()       { <-w.stop }
+func (w *loopWebView) Terminate() { close(w.stop) }
+
+func TestAppRunContext(t *testing.T) {
+	w := &loopWebView{stop: make(chan struct{})}
+	app := &App{handler: newSwapHandler(http.NotFoundHandler()), w: w, srv: &http.Server{}}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- app.RunContext(ctx) }()
+
+	select {
+	case <-done:
+		t.Fatal("RunContext returned before the context was done")
+	case <-time.After(20 * time.Millisecond):
+	}
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("RunContext: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("window not closed when the context was done")
+	}
+}
+
+func TestAppWindowContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := AppWindowContext(ctx, AppOptions{Handler: http.NotFoundHandler()}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("AppWindowContext = %v, want context.Canceled", err)
+	}
+}
+
+func TestAppRunLifecycleOrder(t *testing.T) {
+	defer func() { _ = RunShutdownHooks() }()
+	w := &fakeWebView{}
+	var order []string
+	app := &App{handler: newSwapHandler(http.NotFoundHandler()), w: w}
+	app.closeTransport = func() error { order = append(order, "stop"); return nil }
+	app.onClose = func(got *App) {
+		if got != app {
+			t.Error("OnClose called with another app")
+		}
+		order = append(order, "close")
+	}
+	exitErr := errors.New("checkpoint failed")
+	app.onExit = func() error { order = append(order, "exit"); return exitErr }
+	OnShutdown(0, func() error { order = append(order, "hook"); return nil })
+
+	if err := app.Run(); !errors.Is(err, exitErr) {
+		t.Fatalf("Run = %v, want the OnExit error", err)
+	}
+	if got, want := strings.Join(order, ","), "close,stop,exit,hook"; got != want {
+		t.Fatalf("order = %s, want %s", got, want)
+	}
+}
+
+func TestAppRunWaitsForRequests(t *testing.T) {
+	entered := make(chan struct{})
+	var finished atomic.Bool
+	handler := newSwapHandler(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		close(entered)
+		time.Sleep(100 * time.Millisecond)
+		finished.Store(true)
+	}))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := &App{handler: handler, w: &fakeWebView{}, srv: &http.Server{Handler: handler}}
+	go func() { _ = app.srv.Serve(ln) }()
+	go func() { _, _ = http.Get("http://" + ln.Addr().String() + "/slow") }()
+	<-entered
+
+	app.onExit = func() error {
+		if !finished.Load() {
+			t.Error("OnExit ran while a request was still being served")
+		}
+		return nil
+	}
+	if err := app.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+}
+
+// destroyWebView records Destroy.
+type destroyWebView struct {
+	fakeWebView
+	destroyed bool
+}
+
+func (w *destroyWebView) Destroy() { w.destroyed = true }
+
+func TestAppRunDestroysWindows(t *testing.T) {
+	main, extra := &destroyWebView{}, &destroyWebView{}
+	app := &App{handler: newSwapHandler(http.NotFoundHandler()), w: main, windows: []WebView{extra}}
+	if err := app.Run(); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if !main.destroyed || !extra.destroyed {
+		t.Fatalf("destroyed main=%v extra=%v, want both", main.destroyed, extra.destroyed)
+	}
+}
+
+func TestAppTerminatesAfterLastWindow(t *testing.T) {
+	main, extra := &closingWebView{}, &closingWebView{}
+	app := &App{w: main, open: 2}
+	app.windowClosed(main)
+	if main.terminated || extra.terminated {
+		t.Fatal("closing the first window ended the loop")
+	}
+	app.windowClosed(extra)
+	if !extra.terminated {
+		t.Fatal("closing the last window did not end the loop")
+	}
+}
+
+func TestAppWindowURL(t *testing.T) {
+	app := &App{url: "http://127.0.0.1:8080"}
+	for path, want := range map[string]string{
+		"/settings": "http://127.0.0.1:8080/settings",
+		"settings":  "http://127.0.0.1:8080/settings",
+		"":          "http://127.0.0.1:8080/",
+	} {
+		if got := app.windowURL(path); got != want {
+			t.Errorf("windowURL(%q) = %q, want %q", path, got, want)
+		}
+	}
+	app.token = "t0k"
+	if got, want := app.windowURL("/find?q=go"), "http://127.0.0.1:8080/find?q=go&glaze_token=t0k"; got != want {
+		t.Errorf("windowURL with token = %q, want %q", got, want)
+	}
+	if got, want := app.windowURL("/settings"), "http://127.0.0.1:8080/settings?glaze_token=t0k"; got != want {
+		t.Errorf("windowURL with token = %q, want %q", got, want)
+	}
+}
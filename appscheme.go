@@ -0,0 +1,176 @@
+package glaze
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// appScheme is the URI scheme AppTransportScheme serves the app at, as
+// appScheme://appSchemeHost/; see schemeOrigin.
+const (
+	appScheme     = "glaze"
+	appSchemeHost = "app"
+)
+
+// schemeInvalidDomain is the reserved domain the app is served under on
+// Windows, as https://appSchemeHost.glaze.invalid/.
+const schemeInvalidDomain = "." + appScheme + ".invalid"
+
+// schemeOrigin returns the origin AppTransportScheme serves host at on
+// goos: appScheme://host on Linux, and https://host.glaze.invalid on
+// Windows, where WebView2 registers custom schemes only when its
+// environment is created. The .invalid domain never resolves, so no request
+// for it can leave the machine.
+func schemeOrigin(host, goos string) string {
+	if goos == "windows" {
+		return "https://" + host + schemeInvalidDomain
+	}
+	return appScheme + "://" + host
+}
+
+// schemeResponse buffers the response of a handler serving the app scheme,
+// which engines take whole.
+type schemeResponse struct {
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func (r *schemeResponse) Header() http.Header { return r.header }
+
+func (r *schemeResponse) Write(p []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(p)
+}
+
+func (r *schemeResponse) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+// serveSchemeHTTP runs h for a request the engine intercepted and returns
+// the buffered response. Content-Type is sniffed when h does not set it,
+// as net/http does.
+func serveSchemeHTTP(h http.Handler, method, uri string, header http.Header, body io.Reader) *schemeResponse {
+	res := &schemeResponse{header: make(http.Header)}
+	req, err := http.NewRequest(method, uri, body)
+	if err != nil {
+		res.status = http.StatusBadRequest
+		return res
+	}
+	req.Header = header
+	req.RequestURI = req.URL.RequestURI()
+	h.ServeHTTP(res, req)
+	if res.status == 0 {
+		res.status = http.StatusOK
+	}
+	if res.header.Get("Content-Type") == "" && res.body.Len() > 0 {
+		res.header.Set("Content-Type", http.DetectContentType(res.body.Bytes()))
+	}
+	return res
+}
+
+// schemeHost returns the host of a URI under schemeOrigin, on any OS.
+func schemeHost(uri string) string {
+	rest, ok := strings.CutPrefix(uri, appScheme+"://")
+	if !ok {
+		rest = strings.TrimPrefix(uri, "https://")
+	}
+	if i := strings.IndexAny(rest, "/?#"); i >= 0 {
+		rest = rest[:i]
+	}
+	return strings.TrimSuffix(rest, schemeInvalidDomain)
+}
+
+// schemeRoutes routes the requests of the scheme transport by host. The
+// engine intercepts them for every window; the OS files only translate
+// requests and responses.
+var schemeRoutes struct {
+	mu     sync.Mutex
+	routes map[string]*schemeRoute
+}
+
+// schemeRoute is the handler serving one host of the app scheme, and the
+// windows open on it, in the order they were opened.
+type schemeRoute struct {
+	handler http.Handler
+	windows []WebView
+}
+
+// addSchemeRoute serves h at host to w.
+func addSchemeRoute(w WebView, host string, h http.Handler) error {
+	schemeRoutes.mu.Lock()
+	defer schemeRoutes.mu.Unlock()
+	if _, taken := schemeRoutes.routes[host]; taken {
+		return errors.New("webview: scheme host " + host + " is already served")
+	}
+	if schemeRoutes.routes == nil {
+		schemeRoutes.routes = make(map[string]*schemeRoute)
+	}
+	schemeRoutes.routes[host] = &schemeRoute{handler: h, windows: []WebView{w}}
+	return nil
+}
+
+// joinSchemeRoute adds w to the windows open on host.
+func joinSchemeRoute(w WebView, host string) error {
+	schemeRoutes.mu.Lock()
+	defer schemeRoutes.mu.Unlock()
+	route, ok := schemeRoutes.routes[host]
+	if !ok {
+		return errors.New("webview: scheme host " + host + " is not served")
+	}
+	route.windows = append(route.windows, w)
+	return nil
+}
+
+// leaveScheme removes w from the windows open on any host, once it is
+// closed.
+func leaveScheme(w WebView) {
+	schemeRoutes.mu.Lock()
+	defer schemeRoutes.mu.Unlock()
+	for _, route := range schemeRoutes.routes {
+		route.windows = slices.DeleteFunc(route.windows, func(x WebView) bool { return x == w })
+	}
+}
+
+// stopScheme stops serving host; later requests for it get 404.
+func stopScheme(host string) {
+	schemeRoutes.mu.Lock()
+	delete(schemeRoutes.routes, host)
+	schemeRoutes.mu.Unlock()
+}
+
+// schemeHandler returns the handler serving host.
+func schemeHandler(host string) (http.Handler, bool) {
+	schemeRoutes.mu.Lock()
+	defer schemeRoutes.mu.Unlock()
+	route, ok := schemeRoutes.routes[host]
+	if !ok {
+		return nil, false
+	}
+	return route.handler, true
+}
+
+// dispatchScheme runs fn on the UI thread through a window still open on
+// host: the one a request came from may have been closed while it was
+// served. It reports false when none is left, as the event loop is then
+// ending.
+func dispatchScheme(host string, fn func()) bool {
+	schemeRoutes.mu.Lock()
+	var w WebView
+	if route, ok := schemeRoutes.routes[host]; ok && len(route.windows) > 0 {
+		w = route.windows[0]
+	}
+	schemeRoutes.mu.Unlock()
+	if w == nil {
+		return false
+	}
+	w.Dispatch(fn)
+	return true
+}
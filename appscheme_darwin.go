@@ -0,0 +1,17 @@
+package glaze
+
+import (
+	"errors"
+	"net/http"
+)
+
+// serveScheme fails on macOS: WKWebView takes URL scheme handlers only in
+// the configuration it is created with, which the embedded library owns,
+// and unlike WebView2 it has no hook to intercept requests afterwards.
+func serveScheme(WebView, string, http.Handler) error {
+	return errors.New("webview: the scheme transport is not supported on macOS")
+}
+
+func joinScheme(WebView, string) error {
+	return errors.New("webview: the scheme transport is not supported on macOS")
+}
@@ -0,0 +1,220 @@
+package glaze
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"unsafe"
+
+	"github.com/ebitengine/purego"
+)
+
+// webkitScheme holds the GLib, libsoup and WebKitGTK entry points used to
+// serve AppTransportScheme. Requests, headers and bodies need WebKitGTK
+// 2.40.
+var webkitScheme struct {
+	once sync.Once
+	err  error
+
+	callback uintptr
+
+	registerScheme  func(context uintptr, scheme string, callback, data, destroy uintptr)
+	securityManager func(context uintptr) uintptr
+	registerSecure  func(manager uintptr, scheme string)
+	registerCORS    func(manager uintptr, scheme string)
+	requestURI      func(request uintptr) uintptr
+	requestMethod   func(request uintptr) uintptr
+	requestHeaders  func(request uintptr) uintptr
+	requestBody     func(request uintptr) uintptr
+	responseNew     func(stream uintptr, length int64) uintptr
+	responseStatus  func(response uintptr, code uint32, reason uintptr)
+	responseType    func(response uintptr, contentType string)
+	responseHeaders func(response, headers uintptr)
+	finish          func(request, response uintptr)
+	headersNew      func(kind int32) uintptr
+	headersAppend   func(headers uintptr, name, value string)
+	headersIterInit func(iter, headers uintptr)
+	headersIterNext func(iter uintptr, name, value *uintptr) bool
+	readAll         func(stream uintptr, buf unsafe.Pointer, count uintptr, read *uintptr, cancellable uintptr, gerror *uintptr) bool
+	errorFree       func(gerror uintptr)
+	bytesNew        func(data unsafe.Pointer, size uintptr) uintptr
+	bytesUnref      func(bytes uintptr)
+	streamFromBytes func(bytes uintptr) uintptr
+	objectRef       func(object uintptr) uintptr
+	objectUnref     func(object uintptr)
+	defaultContext  func() uintptr
+}
+
+// soupMessageHeadersResponse is SOUP_MESSAGE_HEADERS_RESPONSE.
+const soupMessageHeadersResponse = 1
+
+func loadWebkitScheme() error {
+	webkitScheme.once.Do(func() {
+		s := &webkitScheme
+		if s.err = openNative("libgio-2.0.so.0", []nativeFunc{
+			{&s.readAll, "g_input_stream_read_all"},
+			{&s.errorFree, "g_error_free"},
+			{&s.bytesNew, "g_bytes_new"},
+			{&s.bytesUnref, "g_bytes_unref"},
+			{&s.streamFromBytes, "g_memory_input_stream_new_from_bytes"},
+			{&s.objectRef, "g_object_ref"},
+			{&s.objectUnref, "g_object_unref"},
+		}); s.err != nil {
+			return
+		}
+		if s.err = openNative("libsoup-3.0.so.0", []nativeFunc{
+			{&s.headersNew, "soup_message_headers_new"},
+			{&s.headersAppend, "soup_message_headers_append"},
+			{&s.headersIterInit, "soup_message_headers_iter_init"},
+			{&s.headersIterNext, "soup_message_headers_iter_next"},
+		}); s.err != nil {
+			return
+		}
+		if s.err = openNative("libwebkit2gtk-4.1.so.0", []nativeFunc{
+			{&s.defaultContext, "webkit_web_context_get_default"},
+			{&s.registerScheme, "webkit_web_context_register_uri_scheme"},
+			{&s.securityManager, "webkit_web_context_get_security_manager"},
+			{&s.registerSecure, "webkit_security_manager_register_uri_scheme_as_secure"},
+			{&s.registerCORS, "webkit_security_manager_register_uri_scheme_as_cors_enabled"},
+			{&s.requestURI, "webkit_uri_scheme_request_get_uri"},
+			{&s.requestMethod, "webkit_uri_scheme_request_get_http_method"},
+			{&s.requestHeaders, "webkit_uri_scheme_request_get_http_headers"},
+			{&s.requestBody, "webkit_uri_scheme_request_get_http_body"},
+			{&s.responseNew, "webkit_uri_scheme_response_new"},
+			{&s.responseStatus, "webkit_uri_scheme_response_set_status"},
+			{&s.responseType, "webkit_uri_scheme_response_set_content_type"},
+			{&s.responseHeaders, "webkit_uri_scheme_response_set_http_headers"},
+			{&s.finish, "webkit_uri_scheme_request_finish_with_response"},
+		}); s.err != nil {
+			s.err = errors.Join(errors.New("webview: the scheme transport needs WebKitGTK 2.40 or later"), s.err)
+			return
+		}
+		s.callback = purego.NewCallback(func(request, _ uintptr) uintptr {
+			serveSchemeRequest(request)
+			return 0
+		})
+		ctx := s.defaultContext()
+		s.registerScheme(ctx, appScheme, s.callback, 0, 0)
+		// Pages served by the app are a secure context, like https://.
+		manager := s.securityManager(ctx)
+		s.registerSecure(manager, appScheme)
+		s.registerCORS(manager, appScheme)
+	})
+	return webkitScheme.err
+}
+
+// serveScheme serves h to w at appScheme://host/. The embedded library
+// creates every view in the default web context, where the scheme is
+// registered once; requests are routed by host.
+func serveScheme(w WebView, host string, h http.Handler) error {
+	if err := loadWebkitScheme(); err != nil {
+		return err
+	}
+	return addSchemeRoute(w, host, h)
+}
+
+// joinScheme serves host to another window w, which shares the default web
+// context.
+func joinScheme(w WebView, host string) error {
+	return joinSchemeRoute(w, host)
+}
+
+// serveSchemeRequest answers a WebKitURISchemeRequest. It is called on the
+// UI thread; the handler runs on its own goroutine and the response is
+// handed back to WebKit on the UI thread.
+func serveSchemeRequest(request uintptr) {
+	s := &webkitScheme
+	uri := goString(s.requestURI(request))
+	method := goString(s.requestMethod(request))
+	if method == "" {
+		method = http.MethodGet
+	}
+	header := make(http.Header)
+	if headers := s.requestHeaders(request); headers != 0 {
+		var iter [3]uintptr // SoupMessageHeadersIter
+		s.headersIterInit(uintptr(unsafe.Pointer(&iter)), headers)
+		var name, value uintptr
+		for s.headersIterNext(uintptr(unsafe.Pointer(&iter)), &name, &value) {
+			header.Add(goString(name), goString(value))
+		}
+	}
+	body := s.requestBody(request) // owned by the caller, may be 0
+
+	host := schemeHost(uri)
+	handler, ok := schemeHandler(host)
+	if !ok {
+		if body != 0 {
+			s.objectUnref(body)
+		}
+		finishSchemeRequest(request, http.StatusNotFound, http.Header{"Content-Type": {"text/plain; charset=utf-8"}}, []byte("not found\n"))
+		return
+	}
+
+	s.objectRef(request)
+	go func() {
+		var data []byte
+		var err error
+		if body != 0 {
+			data, err = readGInputStream(body)
+			s.objectUnref(body)
+		}
+		var res *schemeResponse
+		if err != nil {
+			res = &schemeResponse{status: http.StatusBadRequest, header: http.Header{"Content-Type": {"text/plain; charset=utf-8"}}}
+			res.body.WriteString(err.Error() + "\n")
+		} else {
+			res = serveSchemeHTTP(handler, method, uri, header, bytes.NewReader(data))
+		}
+		// With no window left the request is dropped with the view.
+		dispatchScheme(host, func() {
+			finishSchemeRequest(request, res.status, res.header, res.body.Bytes())
+			s.objectUnref(request)
+		})
+	}()
+}
+
+// finishSchemeRequest completes request with a response.
+func finishSchemeRequest(request uintptr, status int, header http.Header, body []byte) {
+	s := &webkitScheme
+	var ptr unsafe.Pointer
+	if len(body) > 0 {
+		ptr = unsafe.Pointer(&body[0])
+	}
+	gbytes := s.bytesNew(ptr, uintptr(len(body))) // copies body
+	stream := s.streamFromBytes(gbytes)
+	s.bytesUnref(gbytes)
+	response := s.responseNew(stream, int64(len(body)))
+	s.objectUnref(stream)
+	s.responseStatus(response, uint32(status), 0)
+	if ct := header.Get("Content-Type"); ct != "" {
+		s.responseType(response, ct)
+	}
+	headers := s.headersNew(soupMessageHeadersResponse)
+	for name, values := range header {
+		for _, v := range values {
+			s.headersAppend(headers, name, v)
+		}
+	}
+	s.responseHeaders(response, headers) // takes ownership
+	s.finish(request, response)
+	s.objectUnref(response)
+}
+
+// readGInputStream reads a GInputStream to the end.
+func readGInputStream(stream uintptr) ([]byte, error) {
+	var out []byte
+	buf := make([]byte, 32<<10)
+	for {
+		var n, gerror uintptr
+		ok := webkitScheme.readAll(stream, unsafe.Pointer(&buf[0]), uintptr(len(buf)), &n, 0, &gerror)
+		out = append(out, buf[:n]...)
+		if !ok {
+			return nil, fmt.Errorf("webview: read scheme request body: %s", takeGError(gerror, webkitScheme.errorFree))
+		}
+		if n < uintptr(len(buf)) {
+			return out, nil
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package glaze
+
+import (
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestServeSchemeHTTP(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /page", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") != "go" || r.Host != appSchemeHost {
+			t.Errorf("request = %s host %q", r.URL, r.Host)
+		}
+		if r.Header.Get("Accept") != "text/html" {
+			t.Errorf("Accept = %q", r.Header.Get("Accept"))
+		}
+		_, _ = io.WriteString(w, "<html><body>hi</body></html>")
+	})
+	mux.HandleFunc("POST /echo", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write(body)
+	})
+
+	res := serveSchemeHTTP(mux, http.MethodGet, "glaze://app/page?q=go", http.Header{"Accept": {"text/html"}}, strings.NewReader(""))
+	if res.status != http.StatusOK || !strings.HasPrefix(res.header.Get("Content-Type"), "text/html") {
+		t.Fatalf("GET = %d %q", res.status, res.header.Get("Content-Type"))
+	}
+
+	res = serveSchemeHTTP(mux, http.MethodPost, "glaze://app/echo", http.Header{}, strings.NewReader(`{"a":1}`))
+	if res.status != http.StatusCreated || res.body.String() != `{"a":1}` || res.header.Get("Content-Type") != "application/json" {
+		t.Fatalf("POST = %d %q %q", res.status, res.header.Get("Content-Type"), res.body.String())
+	}
+
+	res = serveSchemeHTTP(mux, http.MethodGet, "glaze://app/missing", http.Header{}, strings.NewReader(""))
+	if res.status != http.StatusNotFound {
+		t.Fatalf("missing = %d", res.status)
+	}
+}
+
+func TestServeSchemeConditional(t *testing.T) {
+	modified := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "data.txt", modified, strings.NewReader("0123456789"))
+	})
+
+	res := serveSchemeHTTP(h, http.MethodGet, "glaze://app/data.txt", http.Header{"If-None-Match": {`"v1"`}}, strings.NewReader(""))
+	if res.status != http.StatusNotModified || res.body.Len() != 0 {
+		t.Fatalf("If-None-Match = %d %q", res.status, res.body.String())
+	}
+
+	res = serveSchemeHTTP(h, http.MethodGet, "glaze://app/data.txt", http.Header{"Range": {"bytes=2-5"}}, strings.NewReader(""))
+	if res.status != http.StatusPartialContent || res.body.String() != "2345" || res.header.Get("Content-Range") != "bytes 2-5/10" {
+		t.Fatalf("Range = %d %q %q", res.status, res.header.Get("Content-Range"), res.body.String())
+	}
+}
+
+func TestSchemeHost(t *testing.T) {
+	for uri, want := range map[string]string{
+		"glaze://app":         "app",
+		"glaze://app/":        "app",
+		"glaze://app/a/b?c=d": "app",
+		"glaze://other?x":     "other",
+		"glaze://app#top":     "app",
+
+		"https://app.glaze.invalid":        "app",
+		"https://app.glaze.invalid/a?b=c":  "app",
+		"https://other.glaze.invalid#top":  "other",
+		"https://app.glaze.invalid.evil/x": "app.glaze.invalid.evil",
+	} {
+		if got := schemeHost(uri); got != want {
+			t.Errorf("schemeHost(%q) = %q, want %q", uri, got, want)
+		}
+	}
+}
+
+func TestSchemeOrigin(t *testing.T) {
+	if got := schemeOrigin("app", "linux"); got != "glaze://app" {
+		t.Errorf("linux origin = %q", got)
+	}
+	if got := schemeOrigin("app", "windows"); got != "https://app.glaze.invalid" {
+		t.Errorf("windows origin = %q", got)
+	}
+}
+
+func TestSetupSchemeTransport(t *testing.T) {
+	setup := setupSchemeTransport("linux")
+	if setup.listener != nil || setup.baseURL != "glaze://app" || setup.transport != AppTransportScheme {
+		t.Fatalf("setup = %+v", setup)
+	}
+	setup.start()
+	if err := setup.close(); err != nil {
+		t.Fatal(err)
+	}
+	if setup := setupSchemeTransport("windows"); setup.baseURL != "https://app.glaze.invalid" {
+		t.Fatalf("windows base URL = %q", setup.baseURL)
+	}
+}
+
+func TestSetupSchemeTransportRejectsOptions(t *testing.T) {
+	if runtime.GOOS != "linux" && runtime.GOOS != "windows" {
+		t.Skip("scheme transport unsupported")
+	}
+	for _, opts := range []AppOptions{
+		{Transport: AppTransportScheme, TLS: true},
+		{Transport: AppTransportScheme, RequireToken: true},
+	} {
+		if _, err := setupAppTransport(opts); err == nil {
+			t.Errorf("setupAppTransport(TLS %v, RequireToken %v) succeeded", opts.TLS, opts.RequireToken)
+		}
+	}
+}
+
+// dispatchWebView counts Dispatch calls.
+type dispatchWebView struct {
+	fakeWebView
+	dispatched int
+}
+
+func (w *dispatchWebView) Dispatch(fn func()) {
+	w.dispatched++
+	fn()
+}
+
+func TestDispatchSchemeOutlivesFirstWindow(t *testing.T) {
+	const host = "dispatch-test"
+	first, second := &dispatchWebView{}, &dispatchWebView{}
+	if err := addSchemeRoute(first, host, http.NotFoundHandler()); err != nil {
+		t.Fatal(err)
+	}
+	defer stopScheme(host)
+	if err := addSchemeRoute(second, host, http.NotFoundHandler()); err == nil {
+		t.Error("serving a host twice returned no error")
+	}
+	if err := joinSchemeRoute(second, host); err != nil {
+		t.Fatal(err)
+	}
+
+	leaveScheme(first)
+	ran := false
+	if !dispatchScheme(host, func() { ran = true }) || !ran {
+		t.Fatal("request not finished after the first window closed")
+	}
+	if first.dispatched != 0 || second.dispatched != 1 {
+		t.Errorf("dispatched first=%d second=%d, want the open window only", first.dispatched, second.dispatched)
+	}
+
+	leaveScheme(second)
+	if dispatchScheme(host, func() { t.Error("dispatched with no window open") }) {
+		t.Error("dispatchScheme() = true with no window open")
+	}
+	if err := joinSchemeRoute(first, "missing"); err == nil {
+		t.Error("joining an unserved host returned no error")
+	}
+}
@@ -0,0 +1,294 @@
+package glaze
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Vtable slots of the WebView2 COM methods used to serve
+// AppTransportScheme, counting the three IUnknown methods.
+const (
+	comQueryInterface              = 0  // IUnknown::QueryInterface
+	comAddRef                      = 1  // IUnknown::AddRef
+	webViewAddWebResourceRequested = 55 // ICoreWebView2::add_WebResourceRequested
+	webViewAddWebResourceFilter    = 57 // ICoreWebView2::AddWebResourceRequestedFilter
+	webView2GetEnvironment         = 67 // ICoreWebView2_2::get_Environment
+	environmentCreateResponse      = 4  // ICoreWebView2Environment::CreateWebResourceResponse
+	resourceArgsGetRequest         = 3  // ICoreWebView2WebResourceRequestedEventArgs::get_Request
+	resourceArgsPutResponse        = 5  // ICoreWebView2WebResourceRequestedEventArgs::put_Response
+	resourceArgsGetDeferral        = 6  // ICoreWebView2WebResourceRequestedEventArgs::GetDeferral
+	resourceRequestGetURI          = 3  // ICoreWebView2WebResourceRequest::get_Uri
+	resourceRequestGetMethod       = 5  // ICoreWebView2WebResourceRequest::get_Method
+	resourceRequestGetContent      = 7  // ICoreWebView2WebResourceRequest::get_Content
+	resourceRequestGetHeaders      = 9  // ICoreWebView2WebResourceRequest::get_Headers
+	requestHeadersGetIterator      = 8  // ICoreWebView2HttpRequestHeaders::GetIterator
+	headersIteratorGetCurrent      = 3  // ICoreWebView2HttpHeadersCollectionIterator::GetCurrentHeader
+	headersIteratorHasCurrent      = 4  // ICoreWebView2HttpHeadersCollectionIterator::get_HasCurrentHeader
+	headersIteratorMoveNext        = 5  // ICoreWebView2HttpHeadersCollectionIterator::MoveNext
+	deferralComplete               = 3  // ICoreWebView2Deferral::Complete
+	streamRead                     = 3  // ISequentialStream::Read
+	webResourceContextAll          = 0  // COREWEBVIEW2_WEB_RESOURCE_CONTEXT_ALL
+)
+
+// hresultNoInterface is E_NOINTERFACE.
+const hresultNoInterface uintptr = 0x80004002
+
+var (
+	iidUnknown                = windows.GUID{Data1: 0x00000000, Data2: 0x0000, Data3: 0x0000, Data4: [8]byte{0xC0, 0, 0, 0, 0, 0, 0, 0x46}}
+	iidWebResourceRequested   = windows.GUID{Data1: 0xAB00B74C, Data2: 0x15F1, Data3: 0x4646, Data4: [8]byte{0x80, 0xE8, 0xE7, 0x63, 0x41, 0xD2, 0x5D, 0x71}}
+	iidCoreWebView2_2         = windows.GUID{Data1: 0x9E8F0CF8, Data2: 0xE670, Data3: 0x4B5E, Data4: [8]byte{0xB2, 0xBC, 0x73, 0xE0, 0x61, 0xE3, 0x18, 0x4C}}
+	shlwapi                   = windows.NewLazySystemDLL("shlwapi.dll")
+	procSHCreateMemStream     = shlwapi.NewProc("SHCreateMemStream")
+	webResourceRequestedVtbl  [4]uintptr
+	webResourceRequestedOnce  sync.Once
+	webResourceRequestedEvent struct{ vtbl *[4]uintptr }
+)
+
+// serveScheme serves h to w at schemeOrigin(host). WebView2 registers
+// custom schemes only when its environment is created, which the embedded
+// library owns, so the app is served at an https:// name under the
+// reserved .invalid domain instead: a WebResourceRequested filter answers
+// every request for it before it reaches the network.
+func serveScheme(w WebView, host string, h http.Handler) error {
+	if _, taken := schemeHandler(host); taken {
+		return errors.New("webview: scheme host " + host + " is already served")
+	}
+	if err := interceptScheme(w, host); err != nil {
+		return err
+	}
+	return addSchemeRoute(w, host, h)
+}
+
+// joinScheme serves host to another window w, which needs its own filter.
+func joinScheme(w WebView, host string) error {
+	if err := interceptScheme(w, host); err != nil {
+		return err
+	}
+	return joinSchemeRoute(w, host)
+}
+
+// interceptScheme adds the WebResourceRequested filter and handler for
+// host to the WebView2 of w. Every window shares one handler object, which
+// routes requests by host.
+func interceptScheme(w WebView, host string) error {
+	c, ok := w.(browserControllerer)
+	if !ok {
+		return errors.New("webview: the scheme transport needs a native WebView")
+	}
+	controller := c.browserController()
+	if controller == 0 {
+		return errors.New("webview: the scheme transport needs the WebView2 controller")
+	}
+
+	var core uintptr
+	if hr := comCall(controller, controllerGetCoreWebView2, uintptr(unsafe.Pointer(&core))); hr != 0 || core == 0 {
+		return fmt.Errorf("webview: get CoreWebView2: HRESULT %#x", uint32(hr))
+	}
+	defer comCall(core, comRelease)
+	filter, err := windows.UTF16PtrFromString(schemeOrigin(host, "windows") + "/*")
+	if err != nil {
+		return fmt.Errorf("webview: scheme filter: %w", err)
+	}
+	if hr := comCall(core, webViewAddWebResourceFilter, uintptr(unsafe.Pointer(filter)), webResourceContextAll); hr != 0 {
+		return fmt.Errorf("webview: add WebResourceRequested filter: HRESULT %#x", uint32(hr))
+	}
+	// The handler lives as long as the view, so its token is not kept.
+	var token int64
+	if hr := comCall(core, webViewAddWebResourceRequested, webResourceRequestedHandler(), uintptr(unsafe.Pointer(&token))); hr != 0 {
+		return fmt.Errorf("webview: add WebResourceRequested handler: HRESULT %#x", uint32(hr))
+	}
+	return nil
+}
+
+// webResourceRequestedHandler returns the ICoreWebView2WebResourceRequested
+// EventHandler shared by every window. It is a static COM object: it lives
+// in a package variable and its reference count is never used.
+func webResourceRequestedHandler() uintptr {
+	webResourceRequestedOnce.Do(func() {
+		webResourceRequestedVtbl = [4]uintptr{
+			syscall.NewCallback(func(this, riid, ppv uintptr) uintptr {
+				iid := *(**windows.GUID)(unsafe.Pointer(&riid))
+				out := *(**uintptr)(unsafe.Pointer(&ppv))
+				if *iid != iidUnknown && *iid != iidWebResourceRequested {
+					*out = 0
+					return hresultNoInterface
+				}
+				*out = this
+				return 0
+			}),
+			syscall.NewCallback(func(uintptr) uintptr { return 1 }),
+			syscall.NewCallback(func(uintptr) uintptr { return 1 }),
+			syscall.NewCallback(func(_, sender, args uintptr) uintptr {
+				serveWebResource(sender, args)
+				return 0
+			}),
+		}
+		webResourceRequestedEvent.vtbl = &webResourceRequestedVtbl
+	})
+	return uintptr(unsafe.Pointer(&webResourceRequestedEvent))
+}
+
+// serveWebResource answers a WebResourceRequested event. It is called on
+// the UI thread; the handler runs on its own goroutine under a deferral and
+// the response is handed back to WebView2 on the UI thread.
+func serveWebResource(core, args uintptr) {
+	var request uintptr
+	if hr := comCall(args, resourceArgsGetRequest, uintptr(unsafe.Pointer(&request))); hr != 0 || request == 0 {
+		return
+	}
+	uri := comString(request, resourceRequestGetURI)
+	method := comString(request, resourceRequestGetMethod)
+	if method == "" {
+		method = http.MethodGet
+	}
+	header := webResourceHeaders(request)
+	// The content stream belongs to the UI thread; it is read here, and
+	// app scheme bodies are buffered anyway.
+	var data []byte
+	var content uintptr
+	if hr := comCall(request, resourceRequestGetContent, uintptr(unsafe.Pointer(&content))); hr == 0 && content != 0 {
+		data = readIStream(content)
+		comCall(content, comRelease)
+	}
+	comCall(request, comRelease)
+
+	host := schemeHost(uri)
+	handler, ok := schemeHandler(host)
+	if !ok {
+		respondWebResource(core, args, http.StatusNotFound, http.Header{"Content-Type": {"text/plain; charset=utf-8"}}, []byte("not found\n"))
+		return
+	}
+
+	var deferral uintptr
+	if hr := comCall(args, resourceArgsGetDeferral, uintptr(unsafe.Pointer(&deferral))); hr != 0 || deferral == 0 {
+		return
+	}
+	comCall(core, comAddRef)
+	comCall(args, comAddRef)
+	go func() {
+		res := serveSchemeHTTP(handler, method, uri, header, bytes.NewReader(data))
+		// With no window left the request is dropped with the view.
+		dispatchScheme(host, func() {
+			respondWebResource(core, args, res.status, res.header, res.body.Bytes())
+			comCall(deferral, deferralComplete)
+			comCall(deferral, comRelease)
+			comCall(args, comRelease)
+			comCall(core, comRelease)
+		})
+	}()
+}
+
+// webResourceHeaders returns the headers of an ICoreWebView2WebResource
+// Request.
+func webResourceHeaders(request uintptr) http.Header {
+	header := make(http.Header)
+	var headers uintptr
+	if hr := comCall(request, resourceRequestGetHeaders, uintptr(unsafe.Pointer(&headers))); hr != 0 || headers == 0 {
+		return header
+	}
+	defer comCall(headers, comRelease)
+	var iter uintptr
+	if hr := comCall(headers, requestHeadersGetIterator, uintptr(unsafe.Pointer(&iter))); hr != 0 || iter == 0 {
+		return header
+	}
+	defer comCall(iter, comRelease)
+	for {
+		var has int32
+		if hr := comCall(iter, headersIteratorHasCurrent, uintptr(unsafe.Pointer(&has))); hr != 0 || has == 0 {
+			return header
+		}
+		var name, value *uint16
+		if hr := comCall(iter, headersIteratorGetCurrent, uintptr(unsafe.Pointer(&name)), uintptr(unsafe.Pointer(&value))); hr == 0 {
+			header.Add(takeCoString(name), takeCoString(value))
+		}
+		var more int32
+		if hr := comCall(iter, headersIteratorMoveNext, uintptr(unsafe.Pointer(&more))); hr != 0 || more == 0 {
+			return header
+		}
+	}
+}
+
+// respondWebResource sets the response of a WebResourceRequested event.
+func respondWebResource(core, args uintptr, status int, header http.Header, body []byte) {
+	var core2 uintptr
+	if hr := comCall(core, comQueryInterface, uintptr(unsafe.Pointer(&iidCoreWebView2_2)), uintptr(unsafe.Pointer(&core2))); hr != 0 || core2 == 0 {
+		return
+	}
+	defer comCall(core2, comRelease)
+	var env uintptr
+	if hr := comCall(core2, webView2GetEnvironment, uintptr(unsafe.Pointer(&env))); hr != 0 || env == 0 {
+		return
+	}
+	defer comCall(env, comRelease)
+
+	var ptr unsafe.Pointer
+	if len(body) > 0 {
+		ptr = unsafe.Pointer(&body[0])
+	}
+	stream, _, _ := procSHCreateMemStream.Call(uintptr(ptr), uintptr(len(body))) // copies body
+	if stream == 0 {
+		return
+	}
+	defer comCall(stream, comRelease)
+	var lines strings.Builder
+	for name, values := range header {
+		for _, v := range values {
+			lines.WriteString(name + ": " + v + "\r\n")
+		}
+	}
+	reason, err := windows.UTF16PtrFromString(http.StatusText(status))
+	if err != nil {
+		return
+	}
+	headers, err := windows.UTF16PtrFromString(lines.String())
+	if err != nil {
+		return
+	}
+	var response uintptr
+	if hr := comCall(env, environmentCreateResponse, stream, uintptr(status), uintptr(unsafe.Pointer(reason)), uintptr(unsafe.Pointer(headers)), uintptr(unsafe.Pointer(&response))); hr != 0 || response == 0 {
+		return
+	}
+	defer comCall(response, comRelease)
+	comCall(args, resourceArgsPutResponse, response)
+}
+
+// comString calls a COM getter at slot returning a string the caller frees.
+func comString(obj uintptr, slot int) string {
+	var s *uint16
+	if hr := comCall(obj, slot, uintptr(unsafe.Pointer(&s))); hr != 0 {
+		return ""
+	}
+	return takeCoString(s)
+}
+
+// takeCoString converts and frees a string allocated with CoTaskMemAlloc.
+func takeCoString(s *uint16) string {
+	if s == nil {
+		return ""
+	}
+	defer windows.CoTaskMemFree(unsafe.Pointer(s))
+	return windows.UTF16PtrToString(s)
+}
+
+// readIStream reads an IStream to the end.
+func readIStream(stream uintptr) []byte {
+	var out []byte
+	buf := make([]byte, 32<<10)
+	for {
+		var n uint32
+		hr := comCall(stream, streamRead, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)), uintptr(unsafe.Pointer(&n)))
+		out = append(out, buf[:n]...)
+		// S_FALSE, 1, marks the end of the stream.
+		if hr != 0 || n == 0 {
+			return out
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package glaze
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+)
+
+// appTokenParam is the query parameter the window's first navigation
+// carries the access token in.
+const appTokenParam = "glaze_token"
+
+// newAppToken returns a random access token.
+func newAppToken() (string, error) {
+	var key [24]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(key[:]), nil
+}
+
+// tokenHandler serves h only to requests carrying token: in an
+// "Authorization: Bearer" header, in the cookie named cookie, or in the
+// glaze_token query parameter. A request authorized by the query sets the
+// cookie, and a GET is redirected to the same address without the token so
+// it stays out of the history and of Referer headers.
+func tokenHandler(h http.Handler, token, cookie string) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query().Get(appTokenParam)
+		got := query
+		if auth, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer "); ok {
+			got = auth
+		} else if got == "" {
+			if c, err := req.Cookie(cookie); err == nil {
+				got = c.Value
+			}
+		}
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			http.Error(rw, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if query != "" {
+			http.SetCookie(rw, &http.Cookie{
+				Name:     cookie,
+				Value:    token,
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteStrictMode,
+			})
+			if req.Method == http.MethodGet {
+				u := *req.URL
+				q := u.Query()
+				q.Del(appTokenParam)
+				u.RawQuery = q.Encode()
+				http.Redirect(rw, req, u.RequestURI(), http.StatusSeeOther)
+				return
+			}
+		}
+		h.ServeHTTP(rw, req)
+	})
+}
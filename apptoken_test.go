@@ -0,0 +1,53 @@
+package glaze
+
+import (
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTokenHandler(t *testing.T) {
+	h := tokenHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "app "+r.URL.RawQuery)
+	}), "secret", "glaze_token_1234")
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	get := func(client *http.Client, url string, header http.Header) (int, string, *http.Response) {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return resp.StatusCode, string(body), resp
+	}
+
+	if status, _, _ := get(http.DefaultClient, srv.URL+"/", nil); status != http.StatusUnauthorized {
+		t.Fatalf("no token status = %d, want 401", status)
+	}
+	if status, _, _ := get(http.DefaultClient, srv.URL+"/?glaze_token=wrong", nil); status != http.StatusUnauthorized {
+		t.Fatalf("wrong token status = %d, want 401", status)
+	}
+	if status, body, _ := get(http.DefaultClient, srv.URL+"/api", http.Header{"Authorization": {"Bearer secret"}}); status != http.StatusOK || body != "app " {
+		t.Fatalf("bearer: %d %q", status, body)
+	}
+
+	// The window's first navigation trades the query for a cookie.
+	jar, _ := cookiejar.New(nil)
+	window := &http.Client{Jar: jar}
+	status, body, resp := get(window, srv.URL+"/?page=2&glaze_token=secret", nil)
+	if status != http.StatusOK || body != "app page=2" || resp.Request.URL.Query().Has("glaze_token") {
+		t.Fatalf("priming: %d %q at %s", status, body, resp.Request.URL)
+	}
+	if status, _, _ := get(window, srv.URL+"/assets/app.js", nil); status != http.StatusOK {
+		t.Fatalf("request with cookie status = %d", status)
+	}
+}
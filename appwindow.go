@@ -2,6 +2,8 @@ package glaze
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net"
@@ -28,6 +30,24 @@ const (
 	// A lightweight loopback HTTP gateway is created so the embedded browser can
 	// still navigate with a standard http:// URL.
 	AppTransportUnix AppTransport = "unix"
+
+	// AppTransportPipe serves the application handler over a Windows named
+	// pipe that only the current user can open, with the same loopback HTTP
+	// gateway as AppTransportUnix. It is supported on Windows only.
+	AppTransportPipe AppTransport = "pipe"
+
+	// AppTransportScheme serves the application handler through the
+	// engine's own request interception, with no listener and no gateway:
+	// nothing outside the window can reach it. The app is at glaze://app/
+	// on Linux, with WebKitGTK 2.40 or later, and at
+	// https://app.glaze.invalid/ on Windows, through WebView2's
+	// WebResourceRequested event. The handler sees every request header,
+	// such as Range and If-None-Match, and sets the response status and
+	// headers, so http.ServeContent answers conditional and range requests.
+	// Responses are buffered, so handlers cannot stream. It is not supported on macOS, where WKWebView takes
+	// scheme handlers only when it is created. TLS and RequireToken cannot
+	// be combined with it.
+	AppTransportScheme AppTransport = "scheme"
 )
 
 // AppReadyInfo contains transport details once AppWindow listeners are ready.
@@ -41,11 +61,22 @@ type AppReadyInfo struct {
 	// Backend is the backend listener endpoint.
 	// - tcp: "ip:port"
 	// - unix: "/path/to/socket"
+	// - pipe: `\\.\pipe\name`
+	// - scheme: "glaze://app", or "https://app.glaze.invalid" on Windows
 	Backend string
 
-	// Gateway is the loopback gateway endpoint when unix transport is used.
-	// For tcp transport this matches Backend.
+	// Gateway is the loopback gateway endpoint when unix or pipe transport
+	// is used. For tcp transport this matches Backend; scheme transport
+	// has none.
 	Gateway string
+
+	// Certificate is the ephemeral certificate served when AppOptions.TLS
+	// is set, for clients outside the window that need to pin it.
+	Certificate *x509.Certificate
+
+	// Token is the access token required when AppOptions.RequireToken is
+	// set. Other clients send it as "Authorization: Bearer <token>".
+	Token string
 }
 
 // AppOptions configures an AppWindow.
@@ -75,6 +106,25 @@ type AppOptions struct {
 	// If empty, a temporary socket path is generated automatically.
 	UnixSocketPath string
 
+	// PipeName is an optional pipe name, starting with `\\.\pipe\`, used
+	// when Transport is pipe. If empty, a unique name is generated.
+	PipeName string
+
+	// TLS serves the loopback URL over https:// with an ephemeral
+	// self-signed certificate that only this window trusts, so the page
+	// runs in a secure context. It is supported on Linux and Windows;
+	// AppWindow fails on macOS. The scheme transport is a secure context
+	// already, and AppWindow fails if TLS is set with it.
+	TLS bool
+
+	// RequireToken rejects requests that do not carry an access token
+	// generated at startup, so other local processes cannot reach Handler
+	// through the loopback port or the unix socket. The window receives the
+	// token on its first navigation and keeps it in an HttpOnly cookie.
+	// The scheme transport has no endpoint to protect, and AppWindow fails
+	// if RequireToken is set with it.
+	RequireToken bool
+
 	// Handler is the HTTP handler to serve (typically an http.ServeMux).
 	Handler http.Handler
 
@@ -85,20 +135,104 @@ type AppOptions struct {
 	// OnReadyInfo is called once listeners are up, with transport details.
 	// This is useful to inspect whether backend transport is tcp or unix.
 	OnReadyInfo func(info AppReadyInfo)
+
+	// ErrorPage renders the page shown when the unix transport gateway cannot
+	// reach the backend socket. Defaults to DefaultErrorPage.
+	ErrorPage ErrorPageFunc
+
+	// NetworkShape, when set, simulates a slow or unreliable backend by
+	// delaying, throttling and failing requests to Handler. It is meant for
+	// development builds only.
+	NetworkShape *NetworkShape
+
+	// Terms, when set, must be accepted before the window opens; see
+	// AcceptTerms. AppWindow returns ErrTermsDeclined if they are not.
+	Terms *Terms
+
+	// Bind lists services the pages call directly, alongside Handler,
+	// bound in every window of the app with BindAll: an object has its
+	// methods bound under its key as a prefix, as BindMethods does, and a
+	// function is bound under its key.
+	//
+	//	Bind: map[string]any{"notes": notesSvc, "ping": func() string { return "pong" }},
+	Bind map[string]any
+
+	// OnStart is called from the UI thread once the window is created,
+	// before it is shown, with the App handle used to control it. Callers
+	// of AppWindowStart receive the same handle as its result.
+	OnStart func(app *App)
+
+	// OnClose is called from the UI thread once the window has closed,
+	// while the server still answers requests, to persist state or cancel
+	// background workers that talk to Handler.
+	OnClose func(app *App)
+
+	// OnExit is called after the server has stopped and the requests in
+	// flight have finished, or five seconds have passed without them
+	// finishing, before the hooks registered with OnShutdown, so it can flush
+	// what those hooks close, such as a SQLite WAL checkpoint before the
+	// database is closed. Its error is returned by AppWindow and App.Run.
+	OnExit func() error
 }
 
 // AppWindow creates a native window backed by a local HTTP server.
 //
 // It starts the server on a random loopback port (or the address specified
 // in opts.Addr), opens a webview pointing to it, and runs the UI event loop.
-// When the user closes the window, the server is shut down and AppWindow returns.
+// When the user closes the window, the server is shut down, the hooks
+// registered with OnShutdown run and AppWindow returns their errors.
 //
 // This is the recommended way to wrap a full devengine application as a
 // desktop app — pass the configured http.ServeMux as opts.Handler and
-// everything (templates, assets, routes) works unmodified.
+// everything (templates, assets, routes) works unmodified. It is
+// AppWindowStart followed by App.Run.
 func AppWindow(opts AppOptions) error {
+	app, err := AppWindowStart(opts)
+	if err != nil {
+		return err
+	}
+	return app.Run()
+}
+
+// AppWindowContext is AppWindow, closing the window and shutting the
+// server down when ctx is done, so the app can be stopped from code or on a
+// signal:
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+//	defer stop()
+//	err := glaze.AppWindowContext(ctx, opts)
+//
+// Like AppWindow, it returns the errors of the shutdown hooks, not the
+// context's; a ctx done already returns its error without opening the
+// window.
+func AppWindowContext(ctx context.Context, opts AppOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	app, err := AppWindowStart(opts)
+	if err != nil {
+		return err
+	}
+	return app.RunContext(ctx)
+}
+
+// AppWindowStart is AppWindow without running the UI event loop: it starts
+// the server, creates the window and returns the App handle that drives it,
+// so the caller can keep the WebView, navigate, evaluate and emit before
+// and while the window runs:
+//
+//	app, err := glaze.AppWindowStart(opts)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	go watchReloads(app)
+//	err = app.Run()
+//
+// It must be called from the thread that calls App.Run, which is the UI
+// thread.
+func AppWindowStart(opts AppOptions) (*App, error) {
 	if opts.Handler == nil {
-		return fmt.Errorf("webview: AppOptions.Handler must not be nil")
+		return nil, fmt.Errorf("webview: AppOptions.Handler must not be nil")
 	}
 	if opts.Width <= 0 {
 		opts.Width = 1024
@@ -109,73 +243,173 @@ func AppWindow(opts AppOptions) error {
 	if opts.Title == "" {
 		opts.Title = "App"
 	}
+	if opts.Terms != nil {
+		if err := AcceptTerms(*opts.Terms); err != nil {
+			return nil, err
+		}
+	}
 
 	setup, err := setupAppTransport(opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer func() {
+	abort := func() {
 		if setup.close != nil {
 			_ = setup.close()
 		}
-	}()
+		if setup.listener != nil {
+			_ = setup.listener.Close()
+		}
+	}
+	trust := func(WebView) error { return nil }
+	if setup.cert != nil {
+		if trust, err = trustLoopbackCertificate(*setup.cert); err != nil {
+			abort()
+			return nil, err
+		}
+	}
 
 	// Start extra transport components (for example, Unix loopback gateway).
 	setup.start()
 
 	// Start the application HTTP server in the background.
-	srv := &http.Server{Handler: opts.Handler}
-	defer func() { _ = srv.Close() }()
-	go func() { _ = srv.Serve(setup.listener) }()
+	app := &App{
+		handler:        newSwapHandler(opts.Handler),
+		url:            setup.baseURL,
+		closeTransport: setup.close,
+		onClose:        opts.OnClose,
+		onExit:         opts.OnExit,
+		window:         opts,
+		trust:          trust,
+	}
+	var handler http.Handler = app.handler
+	if opts.NetworkShape != nil {
+		handler = ShapeHandler(handler, *opts.NetworkShape)
+	}
+	startURL := setup.baseURL
+	var token string
+	if opts.RequireToken && setup.listener != nil {
+		if token, err = newAppToken(); err != nil {
+			abort()
+			return nil, fmt.Errorf("webview: generate access token: %w", err)
+		}
+		// Cookies are not scoped by port; naming it by port keeps apps
+		// on other ports from overwriting it.
+		_, port, _ := net.SplitHostPort(setup.gateway)
+		handler = tokenHandler(handler, token, appTokenParam+"_"+port)
+		startURL += "/?" + appTokenParam + "=" + token
+		app.token = token
+	}
+	if setup.listener != nil {
+		app.srv = &http.Server{Handler: handler}
+		go func() { _ = app.srv.Serve(setup.listener) }()
+	}
 
 	if opts.OnReady != nil {
 		opts.OnReady(setup.baseURL)
 	}
 	if opts.OnReadyInfo != nil {
 		opts.OnReadyInfo(AppReadyInfo{
-			URL:       setup.baseURL,
-			Transport: setup.transport,
-			Backend:   setup.backend,
-			Gateway:   setup.gateway,
+			URL:         setup.baseURL,
+			Transport:   setup.transport,
+			Backend:     setup.backend,
+			Gateway:     setup.gateway,
+			Certificate: setup.certificate(),
+			Token:       token,
 		})
 	}
 
 	// Create the webview window.
 	w, err := New(opts.Debug)
 	if err != nil {
-		return fmt.Errorf("webview: %w", err)
+		app.stopServer()
+		return nil, fmt.Errorf("webview: %w", err)
+	}
+	app.w = w
+	app.link(w)
+	if err := trust(w); err != nil {
+		w.Destroy()
+		app.stopServer()
+		return nil, err
+	}
+	if setup.transport == AppTransportScheme {
+		if err := serveScheme(w, appSchemeHost, handler); err != nil {
+			w.Destroy()
+			app.stopServer()
+			return nil, err
+		}
+		app.scheme = true
+	}
+
+	if len(opts.Bind) > 0 {
+		if _, err := BindAll(w, opts.Bind); err != nil {
+			w.Destroy()
+			app.stopServer()
+			return nil, err
+		}
 	}
 
+	app.track(w)
 	w.SetTitle(opts.Title)
 	w.SetSize(opts.Width, opts.Height, opts.Hint)
-	w.Navigate(setup.baseURL)
-	w.Run()
-	w.Destroy()
-
-	return nil
+	if opts.OnStart != nil {
+		opts.OnStart(app)
+	}
+	w.Navigate(startURL)
+	return app, nil
 }
 
 type appTransportSetup struct {
-	listener  net.Listener
+	listener  net.Listener // nil for the scheme transport
 	baseURL   string
 	transport AppTransport
 	backend   string
 	gateway   string
+	cert      *tls.Certificate // served by the gateway, when TLS is set
 	start     func()
 	close     func() error
 }
 
+// certificate returns the parsed certificate served by the gateway, or nil.
+func (s appTransportSetup) certificate() *x509.Certificate {
+	if s.cert == nil {
+		return nil
+	}
+	return s.cert.Leaf
+}
+
 func setupAppTransport(opts AppOptions) (appTransportSetup, error) {
 	transport, err := resolveAppTransport(opts.Transport, runtime.GOOS)
 	if err != nil {
 		return appTransportSetup{}, err
 	}
 
+	if transport == AppTransportScheme {
+		if opts.TLS {
+			return appTransportSetup{}, errors.New("webview: TLS cannot be used with the scheme transport")
+		}
+		if opts.RequireToken {
+			return appTransportSetup{}, errors.New("webview: RequireToken cannot be used with the scheme transport")
+		}
+		return setupSchemeTransport(runtime.GOOS), nil
+	}
+
+	var cert *tls.Certificate
+	if opts.TLS {
+		c, err := loopbackCertificate()
+		if err != nil {
+			return appTransportSetup{}, fmt.Errorf("webview: generate loopback certificate: %w", err)
+		}
+		cert = &c
+	}
+
 	switch transport {
 	case AppTransportTCP:
-		return setupTCPTransport(opts.Addr)
+		return setupTCPTransport(opts.Addr, cert)
 	case AppTransportUnix:
-		return setupUnixTransport(opts.UnixSocketPath)
+		return setupUnixTransport(opts.UnixSocketPath, opts.ErrorPage, cert)
+	case AppTransportPipe:
+		return setupPipeTransport(opts.PipeName, opts.ErrorPage, cert)
 	default:
 		return appTransportSetup{}, fmt.Errorf("webview: unsupported transport %q", transport)
 	}
@@ -195,12 +429,22 @@ func resolveAppTransport(requested AppTransport, goos string) (AppTransport, err
 			return "", errors.New("webview: unix transport is not supported on windows")
 		}
 		return AppTransportUnix, nil
+	case requested == AppTransportPipe:
+		if goos != "windows" {
+			return "", errors.New("webview: pipe transport is only supported on windows")
+		}
+		return AppTransportPipe, nil
+	case requested == AppTransportScheme:
+		if goos != "linux" && goos != "windows" {
+			return "", fmt.Errorf("webview: scheme transport is not supported on %s", goos)
+		}
+		return AppTransportScheme, nil
 	default:
 		return "", fmt.Errorf("webview: invalid transport %q", requested)
 	}
 }
 
-func setupTCPTransport(addr string) (appTransportSetup, error) {
+func setupTCPTransport(addr string, cert *tls.Certificate) (appTransportSetup, error) {
 	if addr == "" {
 		addr = "127.0.0.1:0"
 	}
@@ -231,18 +475,25 @@ func setupTCPTransport(addr string) (appTransportSetup, error) {
 		return appTransportSetup{}, errors.New("webview: failed to read tcp listen address")
 	}
 
+	scheme := "http"
+	if cert != nil {
+		ln = loopbackTLSListener(ln, *cert)
+		scheme = "https"
+	}
+
 	return appTransportSetup{
 		listener:  ln,
-		baseURL:   fmt.Sprintf("http://127.0.0.1:%d", tcpAddr.Port),
+		baseURL:   fmt.Sprintf("%s://127.0.0.1:%d", scheme, tcpAddr.Port),
 		transport: AppTransportTCP,
 		backend:   tcpAddr.String(),
 		gateway:   tcpAddr.String(),
+		cert:      cert,
 		start:     func() {},
 		close:     nil,
 	}, nil
 }
 
-func setupUnixTransport(socketPath string) (appTransportSetup, error) {
+func setupUnixTransport(socketPath string, errorPage ErrorPageFunc, cert *tls.Certificate) (appTransportSetup, error) {
 	path, err := prepareUnixSocketPath(socketPath)
 	if err != nil {
 		return appTransportSetup{}, err
@@ -254,45 +505,105 @@ func setupUnixTransport(socketPath string) (appTransportSetup, error) {
 		return appTransportSetup{}, fmt.Errorf("webview: listen unix %s: %w", path, err)
 	}
 
-	proxyListener, err := net.Listen("tcp", "127.0.0.1:0")
+	dial := func(ctx context.Context) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", path)
+	}
+	setup, err := setupGatewayTransport(unixListener, dial, errorPage, cert)
 	if err != nil {
 		_ = unixListener.Close()
 		_ = removeUnixSocket(path)
+		return appTransportSetup{}, err
+	}
+	setup.transport = AppTransportUnix
+	setup.backend = path
+	closeGateway := setup.close
+	setup.close = func() error {
+		_ = closeGateway()
+		return removeUnixSocket(path)
+	}
+	return setup, nil
+}
+
+func setupPipeTransport(pipeName string, errorPage ErrorPageFunc, cert *tls.Certificate) (appTransportSetup, error) {
+	pipeListener, err := listenPipe(pipeName)
+	if err != nil {
+		return appTransportSetup{}, err
+	}
+	name := pipeListener.Addr().String()
+	dial := func(ctx context.Context) (net.Conn, error) {
+		return dialPipe(ctx, name)
+	}
+	setup, err := setupGatewayTransport(pipeListener, dial, errorPage, cert)
+	if err != nil {
+		_ = pipeListener.Close()
+		return appTransportSetup{}, err
+	}
+	setup.transport = AppTransportPipe
+	setup.backend = name
+	return setup, nil
+}
+
+// setupSchemeTransport prepares the scheme transport. There is nothing to
+// listen on: AppWindowStart registers the handler with the window once it
+// exists, at the origin used on goos.
+func setupSchemeTransport(goos string) appTransportSetup {
+	url := schemeOrigin(appSchemeHost, goos)
+	return appTransportSetup{
+		baseURL:   url,
+		transport: AppTransportScheme,
+		backend:   url,
+		start:     func() {},
+		close: func() error {
+			stopScheme(appSchemeHost)
+			return nil
+		},
+	}
+}
+
+// setupGatewayTransport serves backend, a listener the embedded browser
+// cannot navigate to, through a loopback HTTP gateway that reaches it with
+// dial. The caller fills in the transport and backend of the result.
+func setupGatewayTransport(backend net.Listener, dial func(context.Context) (net.Conn, error), errorPage ErrorPageFunc, cert *tls.Certificate) (appTransportSetup, error) {
+	proxyListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
 		return appTransportSetup{}, fmt.Errorf("webview: listen tcp gateway: %w", err)
 	}
 
-	proxyURL := &url.URL{Scheme: "http", Host: "unix"}
+	proxyURL := &url.URL{Scheme: "http", Host: "backend"}
 	proxy := httputil.NewSingleHostReverseProxy(proxyURL)
 	proxy.Transport = &http.Transport{
 		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
-			var dialer net.Dialer
-			return dialer.DialContext(ctx, "unix", path)
+			return dial(ctx)
 		},
 	}
+	proxy.ErrorHandler = gatewayErrorHandler(errorPage)
+	// Forward every write immediately so streamed pages are not buffered.
+	proxy.FlushInterval = -1
 	proxyServer := &http.Server{Handler: proxy}
 
 	tcpAddr, ok := proxyListener.Addr().(*net.TCPAddr)
 	if !ok {
-		_ = proxyServer.Close()
 		_ = proxyListener.Close()
-		_ = unixListener.Close()
-		_ = removeUnixSocket(path)
 		return appTransportSetup{}, errors.New("webview: failed to read tcp gateway address")
 	}
+	scheme := "http"
+	if cert != nil {
+		proxyListener = loopbackTLSListener(proxyListener, *cert)
+		scheme = "https"
+	}
 
 	return appTransportSetup{
-		listener:  unixListener,
-		baseURL:   fmt.Sprintf("http://127.0.0.1:%d", tcpAddr.Port),
-		transport: AppTransportUnix,
-		backend:   path,
-		gateway:   tcpAddr.String(),
+		listener: backend,
+		baseURL:  fmt.Sprintf("%s://127.0.0.1:%d", scheme, tcpAddr.Port),
+		gateway:  tcpAddr.String(),
+		cert:     cert,
 		start: func() {
 			go func() { _ = proxyServer.Serve(proxyListener) }()
 		},
 		close: func() error {
 			_ = proxyServer.Close()
-			_ = proxyListener.Close()
-			return removeUnixSocket(path)
+			return proxyListener.Close()
 		},
 	}, nil
 }
@@ -1,8 +1,13 @@
 package glaze
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
 	"net/http"
 	"os"
+	"runtime"
+	"strings"
 	"testing"
 )
 
@@ -55,7 +60,7 @@ func TestSetupTCPTransportRejectsNonLoopback(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := setupTCPTransport(tt.addr)
+			result, err := setupTCPTransport(tt.addr, nil)
 			if tt.wantErr {
 				if err == nil {
 					t.Fatal("expected error for non-loopback address")
@@ -70,6 +75,92 @@ func TestSetupTCPTransportRejectsNonLoopback(t *testing.T) {
 	}
 }
 
+func TestSetupAppTransportTLS(t *testing.T) {
+	transports := []AppTransport{AppTransportTCP}
+	if runtime.GOOS != "windows" {
+		transports = append(transports, AppTransportUnix)
+	}
+	for _, transport := range transports {
+		t.Run(string(transport), func(t *testing.T) {
+			setup, err := setupAppTransport(AppOptions{Transport: transport, TLS: true})
+			if err != nil {
+				t.Fatalf("setupAppTransport() unexpected error: %v", err)
+			}
+			if setup.close != nil {
+				defer func() { _ = setup.close() }()
+			}
+			setup.start()
+			srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, _ = io.WriteString(w, "secure")
+			})}
+			go func() { _ = srv.Serve(setup.listener) }()
+			defer srv.Close()
+
+			if !strings.HasPrefix(setup.baseURL, "https://127.0.0.1:") {
+				t.Fatalf("baseURL = %q", setup.baseURL)
+			}
+			cert := setup.certificate()
+			if cert == nil || cert.VerifyHostname("127.0.0.1") != nil || cert.VerifyHostname("localhost") != nil {
+				t.Fatalf("certificate does not cover the loopback hosts: %v", cert)
+			}
+
+			pool := x509.NewCertPool()
+			pool.AddCert(cert)
+			client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+			resp, err := client.Get(setup.baseURL)
+			if err != nil {
+				t.Fatalf("GET with the pinned certificate: %v", err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if string(body) != "secure" {
+				t.Fatalf("body = %q", body)
+			}
+
+			// Clients that do not trust the certificate are refused.
+			if resp, err := http.Get(setup.baseURL); err == nil {
+				resp.Body.Close()
+				t.Fatal("GET without the certificate succeeded")
+			}
+		})
+	}
+}
+
+func TestSetupPipeTransport(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("pipe transport is only supported on windows")
+	}
+	setup, err := setupAppTransport(AppOptions{Transport: AppTransportPipe})
+	if err != nil {
+		t.Fatalf("setupAppTransport() unexpected error: %v", err)
+	}
+	defer func() { _ = setup.close() }()
+	setup.start()
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "piped "+r.URL.Path)
+	})}
+	go func() { _ = srv.Serve(setup.listener) }()
+	defer srv.Close()
+
+	if !strings.HasPrefix(setup.backend, `\\.\pipe\glaze-`) || setup.transport != AppTransportPipe {
+		t.Fatalf("backend = %q, transport = %q", setup.backend, setup.transport)
+	}
+	for range 3 { // several requests reuse and replace pipe instances
+		resp, err := http.Get(setup.baseURL + "/notes")
+		if err != nil {
+			t.Fatalf("GET through the gateway: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != "piped /notes" {
+			t.Fatalf("body = %q", body)
+		}
+	}
+	if _, err := listenPipe(setup.backend); err == nil {
+		t.Fatal("a second listener claimed the pipe name")
+	}
+}
+
 func TestResolveAppTransport(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -84,6 +175,11 @@ func TestResolveAppTransport(t *testing.T) {
 		{name: "explicit tcp", requested: AppTransportTCP, goos: "darwin", want: AppTransportTCP},
 		{name: "explicit unix", requested: AppTransportUnix, goos: "linux", want: AppTransportUnix},
 		{name: "unix windows error", requested: AppTransportUnix, goos: "windows", wantErr: true},
+		{name: "explicit pipe", requested: AppTransportPipe, goos: "windows", want: AppTransportPipe},
+		{name: "pipe linux error", requested: AppTransportPipe, goos: "linux", wantErr: true},
+		{name: "explicit scheme", requested: AppTransportScheme, goos: "linux", want: AppTransportScheme},
+		{name: "scheme windows", requested: AppTransportScheme, goos: "windows", want: AppTransportScheme},
+		{name: "scheme darwin error", requested: AppTransportScheme, goos: "darwin", wantErr: true},
 		{name: "invalid transport", requested: "bogus", goos: "linux", wantErr: true},
 	}
 
@@ -0,0 +1,241 @@
+package glaze
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType        = reflect.TypeFor[time.Duration]()
+	jsonUnmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// decodeArg decodes the JSON argument raw into a new value of type t. With
+// the default codec it also accepts what encoding/json would reject but
+// pages commonly send: durations as strings such as "5s", times as Unix
+// milliseconds (Date.now()), numbers and booleans for encoding.TextUnmarshaler
+// types, maps keyed by booleans or floats, and maps as lists of [key, value]
+// pairs (Array.from of a JavaScript Map or Object.entries).
+func decodeArg(codec Codec, raw json.RawMessage, t reflect.Type) (reflect.Value, error) {
+	ptr := reflect.New(t)
+	if o, ok := ptr.Interface().(optionalArg); ok {
+		return ptr.Elem(), o.decodeArg(codec, raw)
+	}
+	if codec == JSONCodec {
+		if handled, err := decodeLenient(raw, ptr); handled {
+			return ptr.Elem(), err
+		}
+	}
+	return ptr.Elem(), codec.Unmarshal(raw, ptr.Interface())
+}
+
+// decodeLenient decodes raw into ptr for the cases encoding/json does not
+// cover, reporting whether it handled raw.
+func decodeLenient(raw json.RawMessage, ptr reflect.Value) (bool, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return false, nil
+	}
+	t := ptr.Type().Elem()
+	scalar := raw[0] != '"' && raw[0] != '{' && raw[0] != '['
+
+	switch {
+	case t == durationType && raw[0] == '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return true, err
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return true, err
+		}
+		ptr.Elem().SetInt(int64(d))
+		return true, nil
+
+	case t == timeType && scalar:
+		ms, err := strconv.ParseFloat(string(raw), 64)
+		if err != nil || math.IsInf(ms, 0) || math.IsNaN(ms) {
+			return true, fmt.Errorf("cannot use %s as a time; send an RFC 3339 string or Unix milliseconds", raw)
+		}
+		whole := math.Floor(ms)
+		ts := time.UnixMilli(int64(whole)).Add(time.Duration(math.Round((ms - whole) * 1e6)))
+		ptr.Elem().Set(reflect.ValueOf(ts))
+		return true, nil
+
+	case scalar && ptr.Type().Implements(textUnmarshalerType) && !ptr.Type().Implements(jsonUnmarshalerType):
+		return true, ptr.Interface().(encoding.TextUnmarshaler).UnmarshalText(raw)
+
+	case t.Kind() == reflect.Map && raw[0] == '{' && lenientKey(t.Key()):
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return true, err
+		}
+		m := reflect.MakeMapWithSize(t, len(obj))
+		for k, v := range obj {
+			key := reflect.New(t.Key()).Elem()
+			if err := parseKey(k, key); err != nil {
+				return true, fmt.Errorf("map key %q: %w", k, err)
+			}
+			val, err := decodeArg(JSONCodec, v, t.Elem())
+			if err != nil {
+				return true, fmt.Errorf("map key %q: %w", k, err)
+			}
+			m.SetMapIndex(key, val)
+		}
+		ptr.Elem().Set(m)
+		return true, nil
+
+	case t.Kind() == reflect.Map && raw[0] == '[' && !ptr.Type().Implements(jsonUnmarshalerType):
+		var pairs [][]json.RawMessage
+		if err := json.Unmarshal(raw, &pairs); err != nil {
+			return true, fmt.Errorf("cannot use %.40s as a map; send an object or a list of [key, value] pairs", raw)
+		}
+		m := reflect.MakeMapWithSize(t, len(pairs))
+		for i, pair := range pairs {
+			if len(pair) != 2 {
+				return true, fmt.Errorf("entry %d: want a [key, value] pair, got %d elements", i, len(pair))
+			}
+			key, err := decodeArg(JSONCodec, pair[0], t.Key())
+			if err != nil {
+				return true, fmt.Errorf("entry %d key: %w", i, err)
+			}
+			val, err := decodeArg(JSONCodec, pair[1], t.Elem())
+			if err != nil {
+				return true, fmt.Errorf("entry %d value: %w", i, err)
+			}
+			m.SetMapIndex(key, val)
+		}
+		ptr.Elem().Set(m)
+		return true, nil
+
+	}
+	return false, nil
+}
+
+// lenientType reports whether decodeLenient may handle arguments of type t.
+func lenientType(t reflect.Type) bool {
+	p := reflect.PointerTo(t)
+	return t == durationType || t == timeType ||
+		p.Implements(textUnmarshalerType) && !p.Implements(jsonUnmarshalerType) ||
+		t.Kind() == reflect.Map && !p.Implements(jsonUnmarshalerType)
+}
+
+// lenientKey reports whether decodeLenient handles map keys of type t,
+// which encoding/json rejects.
+func lenientKey(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Bool, reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// argError reports that argument i, of type t, could not be decoded. Type
+// mismatches found by encoding/json are described in JavaScript terms, with
+// the path of the offending field, rather than as Go unmarshal errors.
+func argError(i int, t reflect.Type, err error) error {
+	typeErr, ok := err.(*json.UnmarshalTypeError)
+	if !ok {
+		return fmt.Errorf("argument %d (%s): %w", i, t, err)
+	}
+	got := typeErr.Value
+	switch {
+	case got == "string" || got == "number" || got == "array" || got == "object":
+		got = article(got) + " " + got
+	case got == "bool":
+		got = "a boolean"
+	default:
+		got = strings.TrimPrefix(got, "number ")
+	}
+	field := strings.TrimPrefix(typeErr.Field, ".")
+	if field != "" {
+		return &argumentError{fmt.Sprintf("argument %d (%s): field %q must be %s, not %s", i, t, field, jsTypeName(typeErr.Type), got), err}
+	}
+	return &argumentError{fmt.Sprintf("argument %d (%s): must be %s, not %s", i, t, jsTypeName(typeErr.Type), got), err}
+}
+
+// argumentError is an argument decoding error rephrased by argError; it
+// unwraps to the original error.
+type argumentError struct {
+	msg string
+	err error
+}
+
+func (e *argumentError) Error() string { return e.msg }
+func (e *argumentError) Unwrap() error { return e.err }
+
+// jsTypeName describes the JavaScript value a Go type decodes from.
+func jsTypeName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return "a date string or Unix milliseconds"
+	case t == durationType:
+		return "a duration such as \"1.5s\" or nanoseconds"
+	case reflect.PointerTo(t).Implements(textUnmarshalerType):
+		return "a string"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "a base64 string"
+		}
+		return "an array"
+	case reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	default:
+		return t.String()
+	}
+}
+
+// article returns the indefinite article for word.
+func article(word string) string {
+	if strings.ContainsRune("aeiou", rune(word[0])) {
+		return "an"
+	}
+	return "a"
+}
+
+// parseKey sets key from its JSON object key form.
+func parseKey(s string, key reflect.Value) error {
+	switch key.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		key.SetBool(b)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, key.Type().Bits())
+		if err != nil {
+			return err
+		}
+		key.SetFloat(f)
+	}
+	return nil
+}
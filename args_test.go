@@ -0,0 +1,97 @@
+package glaze
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+// level decodes from text only, like many enum types.
+type level int
+
+func (l *level) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "low", "1":
+		*l = 1
+	case "high", "2":
+		*l = 2
+	default:
+		return errUnknownLevel
+	}
+	return nil
+}
+
+var errUnknownLevel = &Error{Code: "invalid", Message: "unknown level"}
+
+func TestDecodeArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		f    any
+		req  string
+		want any
+	}{
+		{name: "duration string", f: func(d time.Duration) time.Duration { return d }, req: `["1m30s"]`, want: 90 * time.Second},
+		{name: "duration nanoseconds", f: func(d time.Duration) time.Duration { return d }, req: `[1000]`, want: time.Microsecond},
+		{name: "time string", f: func(t time.Time) int64 { return t.Unix() }, req: `["2024-01-02T03:04:05Z"]`, want: int64(1704164645)},
+		{name: "time milliseconds", f: func(t time.Time) int64 { return t.UnixMilli() }, req: `[1704164645123]`, want: int64(1704164645123)},
+		{name: "text unmarshaler string", f: func(l level) int { return int(l) }, req: `["high"]`, want: 2},
+		{name: "text unmarshaler number", f: func(l level) int { return int(l) }, req: `[1]`, want: 1},
+		{name: "netip", f: func(a netip.Addr) bool { return a.IsLoopback() }, req: `["127.0.0.1"]`, want: true},
+		{name: "int keys", f: func(m map[int]string) string { return m[2] }, req: `[{"2":"two"}]`, want: "two"},
+		{name: "bool keys", f: func(m map[bool]int) int { return m[true] }, req: `[{"true":1,"false":0}]`, want: 1},
+		{name: "float keys", f: func(m map[float64]time.Duration) time.Duration { return m[0.5] }, req: `[{"0.5":"2s"}]`, want: 2 * time.Second},
+		{name: "variadic durations", f: func(ds ...time.Duration) time.Duration { return ds[1] }, req: `["1s","2s"]`, want: 2 * time.Second},
+		{name: "null", f: func(d *time.Duration) bool { return d == nil }, req: `[null]`, want: true},
+		{name: "map pairs", f: func(m map[int]string) string { return m[2] }, req: `[[[1,"one"],[2,"two"]]]`, want: "two"},
+		{name: "struct keys ignore case", f: func(u struct{ UserID int }) int { return u.UserID }, req: `[{"userid":3}]`, want: 3},
+		{name: "bytes stay base64", f: func(b []byte) string { return string(b) }, req: `["aGk="]`, want: "hi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn, err := makeFuncWrapper(tt.f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := fn("id", tt.req)
+			if err != nil {
+				t.Fatalf("call error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeArgErrorsNameParameter(t *testing.T) {
+	tests := []struct {
+		name string
+		f    any
+		req  string
+		want string
+	}{
+		{name: "bad duration", f: func(string, time.Duration) {}, req: `["a", "soon"]`, want: "argument 1 (time.Duration)"},
+		{name: "bad type", f: func(int) {}, req: `["x"]`, want: "argument 0 (int)"},
+		{name: "bad key", f: func(map[bool]int) {}, req: `[{"maybe":1}]`, want: `argument 0 (map[bool]int): map key "maybe"`},
+		{name: "bad variadic", f: func(string, ...level) {}, req: `["a","low","mid"]`, want: "argument 2 (glaze.level)"},
+		{name: "bad field", f: func(struct{ Age int }) {}, req: `[{"age":"ten"}]`, want: `argument 0 (struct { Age int }): field "age" must be an integer, not a string`},
+		{name: "fraction", f: func(int) {}, req: `[1.5]`, want: "argument 0 (int): must be an integer, not 1.5"},
+		{name: "array for map", f: func(map[string]int) {}, req: `[[1]]`, want: "argument 0 (map[string]int): cannot use [1] as a map"},
+		{name: "short pair", f: func(map[string]int) {}, req: `[[["a"]]]`, want: "argument 0 (map[string]int): entry 0: want a [key, value] pair"},
+		{name: "object for array", f: func([2]int) {}, req: `[{"a":1}]`, want: "argument 0 ([2]int): must be an array, not an object"},
+		{name: "lone value for slice", f: func([]string) {}, req: `["a"]`, want: "argument 0 ([]string): must be an array, not a string"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fn, err := makeFuncWrapper(tt.f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = fn("id", tt.req)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want prefix %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+package glaze
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AssetManifest fingerprints the files of a static asset tree (typically an
+// embed.FS) so they can be served with immutable caching: each file gets a
+// content-hashed name such as app.3f2a9c1b.css that changes whenever its
+// content does.
+//
+// Use Path or the "asset" template function to reference assets from
+// templates, Rewrite to fix up static HTML, and serve the manifest itself as
+// the handler for its URL prefix.
+type AssetManifest struct {
+	prefix string
+
+	// names maps logical names ("css/app.css") to fingerprinted names
+	// ("css/app.3f2a9c1b.css").
+	names  map[string]string
+	assets map[string]*asset
+}
+
+type asset struct {
+	data        []byte
+	etag        string
+	gzipETag    string // the gzip encoding is a different representation
+	contentType string
+
+	gzipOnce sync.Once
+	gzipped  []byte
+}
+
+// NewAssetManifest hashes every file in fsys. prefix is the URL path the
+// manifest is mounted at, such as "/static/".
+func NewAssetManifest(fsys fs.FS, prefix string) (*AssetManifest, error) {
+	m := &AssetManifest{
+		prefix: "/" + strings.Trim(prefix, "/") + "/",
+		names:  make(map[string]string),
+		assets: make(map[string]*asset),
+	}
+	if m.prefix == "//" {
+		m.prefix = "/"
+	}
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		hash := hex.EncodeToString(sum[:4])
+		ext := path.Ext(name)
+		hashed := strings.TrimSuffix(name, ext) + "." + hash + ext
+
+		contentType := mime.TypeByExtension(ext)
+		if contentType == "" {
+			contentType = http.DetectContentType(data)
+		}
+		etag := hex.EncodeToString(sum[:16])
+		a := &asset{data: data, etag: `"` + etag + `"`, gzipETag: `"` + etag + `-gz"`, contentType: contentType}
+		m.names[name] = hashed
+		m.assets[name] = a
+		m.assets[hashed] = a
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("webview: build asset manifest: %w", err)
+	}
+	return m, nil
+}
+
+// Path returns the fingerprinted URL for a logical asset name such as
+// "css/app.css". Unknown names are returned under the prefix unchanged.
+func (m *AssetManifest) Path(name string) string {
+	name = strings.TrimPrefix(name, "/")
+	if hashed, ok := m.names[name]; ok {
+		return m.prefix + hashed
+	}
+	return m.prefix + name
+}
+
+// FuncMap returns template functions for the manifest: {{asset "app.css"}}
+// expands to the fingerprinted URL.
+func (m *AssetManifest) FuncMap() template.FuncMap {
+	return template.FuncMap{"asset": m.Path}
+}
+
+// Rewrite replaces quoted references to logical asset URLs in html (such as
+// href="/static/app.css") with their fingerprinted URLs. References inside
+// CSS files are not rewritten.
+func (m *AssetManifest) Rewrite(html string) string {
+	// Longest names first so "app.css.map" is not clobbered by "app.css".
+	names := make([]string, 0, len(m.names))
+	for name := range m.names {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+
+	pairs := make([]string, 0, len(names)*4)
+	for _, name := range names {
+		from, to := m.prefix+name, m.Path(name)
+		pairs = append(pairs, `"`+from+`"`, `"`+to+`"`, `'`+from+`'`, `'`+to+`'`)
+	}
+	return strings.NewReplacer(pairs...).Replace(html)
+}
+
+// ServeHTTP serves assets below the manifest prefix. Fingerprinted names are
+// served with a one-year immutable Cache-Control; logical names remain
+// available with revalidation through ETags. Text assets are gzip-compressed
+// for clients that accept it, under an ETag of their own.
+func (m *AssetManifest) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutPrefix(r.URL.Path, m.prefix)
+	a := m.assets[name]
+	if !ok || a == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", a.contentType)
+	h.Set("ETag", a.etag)
+	if m.names[name] == "" {
+		h.Set("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		h.Set("Cache-Control", "no-cache")
+	}
+
+	if compressible(a.contentType) {
+		h.Add("Vary", "Accept-Encoding")
+		if r.Header.Get("Range") == "" && strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			h.Set("ETag", a.gzipETag)
+			if r.Header.Get("If-None-Match") == a.gzipETag {
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			h.Set("Content-Encoding", "gzip")
+			_, _ = w.Write(a.gzip())
+			return
+		}
+	}
+	http.ServeContent(w, r, name, time.Time{}, bytes.NewReader(a.data))
+}
+
+func (a *asset) gzip() []byte {
+	a.gzipOnce.Do(func() {
+		var buf bytes.Buffer
+		zw, _ := gzip.NewWriterLevel(&buf, gzip.BestCompression) // valid level never errors
+		_, _ = zw.Write(a.data)
+		_ = zw.Close()
+		a.gzipped = buf.Bytes()
+	})
+	return a.gzipped
+}
+
+func compressible(contentType string) bool {
+	ct, _, _ := strings.Cut(contentType, ";")
+	switch {
+	case strings.HasPrefix(ct, "text/"):
+		return true
+	case ct == "application/javascript", ct == "application/json", ct == "image/svg+xml", ct == "application/wasm":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package glaze
+
+import (
+	"compress/gzip"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func testAssets(t *testing.T) *AssetManifest {
+	t.Helper()
+	m, err := NewAssetManifest(fstest.MapFS{
+		"css/app.css":     {Data: []byte("body { color: red; }")},
+		"css/app.css.map": {Data: []byte("{}")},
+		"logo.png":        {Data: []byte("\x89PNG\r\n\x1a\n")},
+	}, "/static/")
+	if err != nil {
+		t.Fatalf("NewAssetManifest() unexpected error: %v", err)
+	}
+	return m
+}
+
+func TestAssetManifestPath(t *testing.T) {
+	m := testAssets(t)
+	got := m.Path("css/app.css")
+	if !regexp.MustCompile(`^/static/css/app\.[0-9a-f]{8}\.css$`).MatchString(got) {
+		t.Fatalf("Path() = %q, want fingerprinted name", got)
+	}
+	if m.Path("/css/app.css") != got {
+		t.Fatal("Path() should ignore a leading slash")
+	}
+	if m.Path("missing.js") != "/static/missing.js" {
+		t.Fatalf("Path(missing) = %q", m.Path("missing.js"))
+	}
+}
+
+func TestAssetManifestFuncMap(t *testing.T) {
+	m := testAssets(t)
+	tpl := template.Must(template.New("").Funcs(m.FuncMap()).Parse(`<link href="{{asset "css/app.css"}}">`))
+	var b strings.Builder
+	if err := tpl.Execute(&b, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), m.Path("css/app.css")) {
+		t.Fatalf("template output = %q", b.String())
+	}
+}
+
+func TestAssetManifestRewrite(t *testing.T) {
+	m := testAssets(t)
+	in := `<link href="/static/css/app.css"><img src='/static/logo.png'><a href="/static/css/app.css.map">`
+	got := m.Rewrite(in)
+	for _, want := range []string{`"` + m.Path("css/app.css") + `"`, `'` + m.Path("logo.png") + `'`, `"` + m.Path("css/app.css.map") + `"`} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("Rewrite() = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestAssetManifestServe(t *testing.T) {
+	m := testAssets(t)
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, m.Path("logo.png"), nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if cc := rec.Header().Get("Cache-Control"); !strings.Contains(cc, "immutable") {
+		t.Fatalf("fingerprinted Cache-Control = %q, want immutable", cc)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "image/png" {
+		t.Fatalf("Content-Type = %q", ct)
+	}
+
+	rec = httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/static/logo.png", nil))
+	if cc := rec.Header().Get("Cache-Control"); cc != "no-cache" {
+		t.Fatalf("logical Cache-Control = %q, want no-cache", cc)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/static/logo.png", nil)
+	req.Header.Set("If-None-Match", rec.Header().Get("ETag"))
+	rec = httptest.NewRecorder()
+	m.ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotModified {
+		t.Fatalf("conditional status = %d, want 304", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/static/nope.js", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("missing status = %d, want 404", rec.Code)
+	}
+}
+
+func TestAssetManifestGzip(t *testing.T) {
+	m := testAssets(t)
+	req := httptest.NewRequest(http.MethodGet, m.Path("css/app.css"), nil)
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, req)
+
+	if rec.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatal("expected gzip encoding for css")
+	}
+	zr, err := gzip.NewReader(rec.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(zr)
+	if string(body) != "body { color: red; }" {
+		t.Fatalf("decompressed body = %q", body)
+	}
+
+	// Each encoding has its own ETag, and revalidates only against it.
+	gzETag := rec.Header().Get("ETag")
+	plain := httptest.NewRecorder()
+	m.ServeHTTP(plain, httptest.NewRequest(http.MethodGet, m.Path("css/app.css"), nil))
+	etag := plain.Header().Get("ETag")
+	if gzETag == etag || gzETag != strings.TrimSuffix(etag, `"`)+`-gz"` {
+		t.Fatalf("gzip ETag = %s, identity ETag = %s", gzETag, etag)
+	}
+	for _, tt := range []struct {
+		gzip   bool
+		etag   string
+		status int
+	}{
+		{true, gzETag, http.StatusNotModified},
+		{true, etag, http.StatusOK},
+		{false, etag, http.StatusNotModified},
+		{false, gzETag, http.StatusOK},
+	} {
+		req := httptest.NewRequest(http.MethodGet, m.Path("css/app.css"), nil)
+		if tt.gzip {
+			req.Header.Set("Accept-Encoding", "gzip")
+		}
+		req.Header.Set("If-None-Match", tt.etag)
+		rec := httptest.NewRecorder()
+		m.ServeHTTP(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("gzip=%v If-None-Match %s: status = %d, want %d", tt.gzip, tt.etag, rec.Code, tt.status)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// AudioService plays sounds bundled with the application, such as
+// notification chimes and alerts. Sounds are played by the platform's own
+// player rather than the page, so they work before the user has interacted
+// with it, when browser autoplay rules would block an <audio> element.
+//
+// Its exported methods are meant to be bound for the frontend:
+//
+//	audio := glaze.NewAudioService(sounds)
+//	glaze.BindMethods(w, "audio", audio) // audio_play("done.wav"), audio_stop(), ...
+//
+// Playback uses afplay on macOS, PipeWire, PulseAudio or ALSA tools on Linux
+// and the WPF media player through PowerShell on Windows. An AudioService is
+// safe for concurrent use.
+type AudioService struct {
+	fsys fs.FS
+
+	mu      sync.Mutex
+	volume  float64
+	dir     string
+	files   map[string]string
+	playing map[*exec.Cmd]struct{}
+}
+
+// NewAudioService returns a service playing sounds from fsys at full volume.
+func NewAudioService(fsys fs.FS) *AudioService {
+	return &AudioService{
+		fsys:    fsys,
+		volume:  1,
+		files:   make(map[string]string),
+		playing: make(map[*exec.Cmd]struct{}),
+	}
+}
+
+// Play starts playing the named sound from the service's file system and
+// returns without waiting for it to finish. Sounds may overlap.
+func (a *AudioService) Play(name string) error {
+	file, err := a.file(name)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	volume := a.volume
+	a.mu.Unlock()
+
+	cmd, err := startPlayer(playerCommands(runtime.GOOS, file, volume))
+	if err != nil {
+		return fmt.Errorf("webview: play %s: %w", name, err)
+	}
+	a.mu.Lock()
+	a.playing[cmd] = struct{}{}
+	a.mu.Unlock()
+	go func() {
+		_ = cmd.Wait()
+		a.mu.Lock()
+		delete(a.playing, cmd)
+		a.mu.Unlock()
+	}()
+	return nil
+}
+
+// Stop stops every sound that is playing.
+func (a *AudioService) Stop() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for cmd := range a.playing {
+		_ = cmd.Process.Kill()
+	}
+}
+
+// SetVolume sets the volume of sounds played from now on, from 0 (silent)
+// to 1 (full). Values outside that range are clamped.
+func (a *AudioService) SetVolume(v float64) {
+	v = max(0, min(v, 1))
+	a.mu.Lock()
+	a.volume = v
+	a.mu.Unlock()
+}
+
+// Volume returns the current volume.
+func (a *AudioService) Volume() float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.volume
+}
+
+// Close stops playback and removes the temporary copies of the sounds that
+// external players read. The service can still be used afterwards.
+func (a *AudioService) Close() error {
+	a.Stop()
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	dir := a.dir
+	a.dir = ""
+	clear(a.files)
+	if dir == "" {
+		return nil
+	}
+	return os.RemoveAll(dir)
+}
+
+// file returns a path on disk holding the named sound, copying it out of the
+// service's file system on first use.
+func (a *AudioService) file(name string) (string, error) {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if file, ok := a.files[name]; ok {
+		return file, nil
+	}
+	data, err := fs.ReadFile(a.fsys, name)
+	if err != nil {
+		return "", fmt.Errorf("webview: sound %s: %w", name, err)
+	}
+	if a.dir == "" {
+		dir, err := os.MkdirTemp("", "glaze-audio-")
+		if err != nil {
+			return "", fmt.Errorf("webview: sound %s: %w", name, err)
+		}
+		a.dir = dir
+	}
+	file := filepath.Join(a.dir, strconv.Itoa(len(a.files))+path.Ext(name))
+	if err := os.WriteFile(file, data, 0o600); err != nil {
+		return "", fmt.Errorf("webview: sound %s: %w", name, err)
+	}
+	a.files[name] = file
+	return file, nil
+}
+
+// playerCommands lists the commands that can play file at volume, in order
+// of preference.
+func playerCommands(goos, file string, volume float64) []shellCommand {
+	vol := strconv.FormatFloat(volume, 'f', 2, 64)
+	switch goos {
+	case "darwin":
+		return []shellCommand{{name: "afplay", args: []string{"-v", vol, file}}}
+	case "windows":
+		// Pass the path through the environment so it is never parsed as
+		// PowerShell.
+		script := strings.Join([]string{
+			"Add-Type -AssemblyName PresentationCore",
+			"$m = New-Object System.Windows.Media.MediaPlayer",
+			"$m.Volume = [double]$env:GLAZE_SOUND_VOLUME",
+			"$m.Open([uri]$env:GLAZE_SOUND_PATH)",
+			"$m.Play()",
+			"do { Start-Sleep -Milliseconds 50 } until ($m.NaturalDuration.HasTimeSpan)",
+			"Start-Sleep -Milliseconds ([int]$m.NaturalDuration.TimeSpan.TotalMilliseconds)",
+		}, "; ")
+		return []shellCommand{{
+			name: "powershell.exe",
+			args: []string{"-NoProfile", "-NonInteractive", "-Command", script},
+			env:  []string{"GLAZE_SOUND_PATH=" + file, "GLAZE_SOUND_VOLUME=" + vol},
+		}}
+	default:
+		return []shellCommand{
+			{name: "pw-play", args: []string{"--volume=" + vol, file}},
+			{name: "paplay", args: []string{"--volume=" + strconv.Itoa(int(volume*65536)), file}},
+			{name: "aplay", args: []string{"-q", file}},
+		}
+	}
+}
+
+// startPlayer starts the first command that is installed.
+func startPlayer(cmds []shellCommand) (*exec.Cmd, error) {
+	for _, c := range cmds {
+		bin, err := exec.LookPath(c.name)
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(bin, c.args...)
+		if len(c.env) > 0 {
+			cmd.Env = append(os.Environ(), c.env...)
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, err
+		}
+		return cmd, nil
+	}
+	return nil, errors.New("no audio player found")
+}
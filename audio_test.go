@@ -0,0 +1,85 @@
+package glaze
+
+import (
+	"os"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAudioServiceFile(t *testing.T) {
+	a := NewAudioService(fstest.MapFS{
+		"sounds/done.wav": {Data: []byte("RIFF")},
+	})
+	defer a.Close()
+
+	file, err := a.file("/sounds/done.wav")
+	if err != nil {
+		t.Fatalf("file() unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(file)
+	if err != nil || string(data) != "RIFF" {
+		t.Fatalf("extracted sound = %q, %v; want RIFF", data, err)
+	}
+	if again, _ := a.file("sounds/done.wav"); again != file {
+		t.Fatalf("file() = %q on second use, want cached %q", again, file)
+	}
+	if _, err := a.file("missing.wav"); err == nil {
+		t.Fatal("file() expected error for missing sound")
+	}
+
+	if err := a.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(file); !os.IsNotExist(err) {
+		t.Fatalf("sound copy still exists after Close: %v", err)
+	}
+}
+
+func TestAudioServiceVolume(t *testing.T) {
+	a := NewAudioService(fstest.MapFS{})
+	if a.Volume() != 1 {
+		t.Fatalf("Volume() = %v, want 1", a.Volume())
+	}
+	for _, tt := range []struct{ in, want float64 }{{0.5, 0.5}, {-1, 0}, {3, 1}} {
+		a.SetVolume(tt.in)
+		if got := a.Volume(); got != tt.want {
+			t.Errorf("SetVolume(%v): Volume() = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPlayerCommands(t *testing.T) {
+	tests := []struct {
+		goos string
+		want []string
+	}{
+		{"darwin", []string{"afplay"}},
+		{"windows", []string{"powershell.exe"}},
+		{"linux", []string{"pw-play", "paplay", "aplay"}},
+	}
+	for _, tt := range tests {
+		cmds := playerCommands(tt.goos, "/tmp/a.wav", 0.5)
+		var names []string
+		for _, c := range cmds {
+			names = append(names, c.name)
+		}
+		if !slices.Equal(names, tt.want) {
+			t.Errorf("playerCommands(%s) = %v, want %v", tt.goos, names, tt.want)
+		}
+	}
+
+	linux := playerCommands("linux", "/tmp/a.wav", 0.5)
+	if linux[1].args[0] != "--volume=32768" {
+		t.Errorf("paplay volume = %q, want --volume=32768", linux[1].args[0])
+	}
+	if mac := playerCommands("darwin", "/tmp/a.wav", 0.5); !slices.Equal(mac[0].args, []string{"-v", "0.50", "/tmp/a.wav"}) {
+		t.Errorf("afplay args = %v", mac[0].args)
+	}
+}
+
+func TestStartPlayerNoneInstalled(t *testing.T) {
+	if _, err := startPlayer([]shellCommand{{name: "glaze-no-such-player"}}); err == nil {
+		t.Fatal("startPlayer() expected error when no player is installed")
+	}
+}
@@ -0,0 +1,100 @@
+package glaze
+
+import (
+	"runtime/debug"
+	"runtime/metrics"
+	"sync"
+	"time"
+)
+
+// backgroundState tracks whether SetBackgroundMode lowered the priority.
+var backgroundState struct {
+	mu      sync.Mutex
+	on      bool
+	restore func() error
+}
+
+// defaultMemoryPollInterval is used by OnMemoryLimit when no interval is given.
+const defaultMemoryPollInterval = 10 * time.Second
+
+// SetBackgroundMode lowers the process CPU and I/O priority when background
+// is true and restores it when false, so always-running tools stay polite
+// while hidden to the tray. Call it with true when the window is hidden and
+// with false when it is shown or focused again. Repeated calls with the same
+// value are no-ops.
+//
+// It uses PROCESS_MODE_BACKGROUND on Windows and the Darwin background
+// priority band on macOS. On Linux it only lowers the priority in ways it
+// can undo: it raises the nice value of every thread when RLIMIT_NICE or
+// CAP_SYS_NICE allows lowering it back, and otherwise lowers cpu.weight of
+// the app's cgroup when the app has one of its own, such as a systemd
+// scope. When neither applies, it returns an error and leaves the priority
+// unchanged.
+func SetBackgroundMode(background bool) error {
+	backgroundState.mu.Lock()
+	defer backgroundState.mu.Unlock()
+	if background == backgroundState.on {
+		return nil
+	}
+	if !background {
+		if err := backgroundState.restore(); err != nil {
+			return err
+		}
+		backgroundState.on, backgroundState.restore = false, nil
+		return nil
+	}
+	restore, err := enterBackground()
+	if err != nil {
+		return err
+	}
+	backgroundState.on, backgroundState.restore = true, restore
+	return nil
+}
+
+// OnMemoryLimit checks the Go runtime's memory use every interval (10s if
+// zero) and calls fn, from a background goroutine, when it rises above limit
+// bytes, so the app can drop caches such as RenderCache.InvalidateAll. After
+// fn returns, freed memory is handed back to the operating system. fn is
+// called again only after usage has dropped below the limit and crossed it
+// once more. The returned function stops the watchdog.
+//
+// Only memory held by Go is measured; the browser engine runs in its own
+// processes on most platforms.
+func OnMemoryLimit(limit uint64, interval time.Duration, fn func(used uint64)) (stop func()) {
+	if interval <= 0 {
+		interval = defaultMemoryPollInterval
+	}
+	return watchMemory(interval, limit, readGoMemory, func(used uint64) {
+		fn(used)
+		debug.FreeOSMemory()
+	})
+}
+
+type memorySample struct {
+	used uint64
+	over bool
+}
+
+// watchMemory reports readings from read that cross above limit.
+func watchMemory(interval time.Duration, limit uint64, read func() (uint64, error), fn func(uint64)) (stop func()) {
+	sample := func() (memorySample, error) {
+		used, err := read()
+		return memorySample{used: used, over: used > limit}, err
+	}
+	return watchChanges(interval, sample, func(a, b memorySample) bool { return a.over == b.over }, func(s memorySample) {
+		if s.over {
+			fn(s.used)
+		}
+	})
+}
+
+// readGoMemory returns the memory mapped by the Go runtime that has not
+// been released back to the operating system.
+func readGoMemory() (uint64, error) {
+	samples := []metrics.Sample{
+		{Name: "/memory/classes/total:bytes"},
+		{Name: "/memory/classes/heap/released:bytes"},
+	}
+	metrics.Read(samples)
+	return samples[0].Value.Uint64() - samples[1].Value.Uint64(), nil
+}
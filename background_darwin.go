@@ -0,0 +1,25 @@
+package glaze
+
+import (
+	"fmt"
+	"syscall"
+)
+
+const (
+	prioDarwinProcess = 4
+	prioDarwinBG      = 0x1000
+)
+
+// enterBackground moves the process into the Darwin background band, which
+// throttles CPU, disk and network I/O the way App Nap does.
+func enterBackground() (func() error, error) {
+	if err := syscall.Setpriority(prioDarwinProcess, 0, prioDarwinBG); err != nil {
+		return nil, fmt.Errorf("webview: setpriority: %w", err)
+	}
+	return func() error {
+		if err := syscall.Setpriority(prioDarwinProcess, 0, 0); err != nil {
+			return fmt.Errorf("webview: setpriority: %w", err)
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,163 @@
+package glaze
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// backgroundNice is the nice value applied in background mode.
+const backgroundNice = 10
+
+// backgroundCPUWeight is the cgroup cpu.weight applied in background mode,
+// about what nice 10 gives a thread against the default weight of 100.
+const backgroundCPUWeight = "10"
+
+// enterBackground lowers the priority in a way it can undo: by raising the
+// nice value when RLIMIT_NICE or privileges let it be lowered back, and
+// otherwise by lowering the CPU weight of the process's own cgroup. When
+// neither is possible it changes nothing and returns an error, rather than
+// leave the app stuck at a low priority.
+func enterBackground() (func() error, error) {
+	// The raw getpriority syscall returns 20 - nice.
+	prio, err := syscall.Getpriority(syscall.PRIO_PROCESS, 0)
+	if err != nil {
+		return nil, fmt.Errorf("webview: getpriority: %w", err)
+	}
+	original := 20 - prio
+	if original >= backgroundNice {
+		return func() error { return nil }, nil
+	}
+	if niceRevertible(original) {
+		if err := reniceThreads(backgroundNice); err != nil {
+			_ = reniceThreads(original)
+			return nil, err
+		}
+		return func() error { return reniceThreads(original) }, nil
+	}
+	if restore, err := lowerCgroupWeight(); err == nil {
+		return restore, nil
+	}
+	return nil, errors.New("webview: background mode needs RLIMIT_NICE, CAP_SYS_NICE or a cgroup of the app's own to be undone; priority left unchanged")
+}
+
+// niceRevertible reports whether the nice value can be set back to nice
+// once raised: the kernel allows that with CAP_SYS_NICE, assumed for root,
+// or when RLIMIT_NICE is at least 20 - nice.
+func niceRevertible(nice int) bool {
+	if os.Geteuid() == 0 {
+		return true
+	}
+	var lim unix.Rlimit
+	if err := unix.Getrlimit(unix.RLIMIT_NICE, &lim); err != nil {
+		return false
+	}
+	return lim.Cur >= uint64(20-nice)
+}
+
+// reniceThreads sets the nice value of every thread of the process, since
+// Linux keeps one per thread. Threads started later inherit it from their
+// parent.
+func reniceThreads(nice int) error {
+	entries, err := os.ReadDir("/proc/self/task")
+	if err != nil {
+		return fmt.Errorf("webview: list threads: %w", err)
+	}
+	for _, e := range entries {
+		tid, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+		if err := syscall.Setpriority(syscall.PRIO_PROCESS, tid, nice); err != nil && err != syscall.ESRCH {
+			return fmt.Errorf("webview: setpriority %d: %w", nice, err)
+		}
+	}
+	return nil
+}
+
+// lowerCgroupWeight sets cpu.weight of the process's cgroup v2 to
+// backgroundCPUWeight and returns a function restoring it. It refuses
+// cgroups holding processes other than this one and its children, such as
+// the scope of the terminal the app was started from, which a lower weight
+// would slow down too.
+func lowerCgroupWeight() (func() error, error) {
+	data, err := os.ReadFile("/proc/self/cgroup")
+	if err != nil {
+		return nil, err
+	}
+	var dir string
+	for line := range strings.Lines(string(data)) {
+		if path, ok := strings.CutPrefix(strings.TrimSpace(line), "0::"); ok {
+			dir = filepath.Join("/sys/fs/cgroup", path)
+		}
+	}
+	if dir == "" {
+		return nil, errors.New("webview: no cgroup v2")
+	}
+	procs, err := os.ReadFile(filepath.Join(dir, "cgroup.procs"))
+	if err != nil {
+		return nil, err
+	}
+	self := os.Getpid()
+	for _, field := range strings.Fields(string(procs)) {
+		pid, err := strconv.Atoi(field)
+		if err != nil || !descendsFrom(pid, self) {
+			return nil, fmt.Errorf("webview: cgroup %s is shared", dir)
+		}
+	}
+	file := filepath.Join(dir, "cpu.weight")
+	original, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(file, []byte(backgroundCPUWeight), 0); err != nil {
+		return nil, err
+	}
+	return func() error {
+		if err := os.WriteFile(file, bytes.TrimSpace(original), 0); err != nil {
+			return fmt.Errorf("webview: restore cpu.weight: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+// descendsFrom reports whether process pid is ancestor or one of its
+// descendants.
+func descendsFrom(pid, ancestor int) bool {
+	for range 64 {
+		if pid == ancestor {
+			return true
+		}
+		if pid <= 1 {
+			return false
+		}
+		f, err := os.Open("/proc/" + strconv.Itoa(pid) + "/stat")
+		if err != nil {
+			return false
+		}
+		line, _ := bufio.NewReader(f).ReadString('\n')
+		f.Close()
+		// The command name, in parentheses, may hold spaces; the parent
+		// PID is the second field after it.
+		i := strings.LastIndexByte(line, ')')
+		if i < 0 {
+			return false
+		}
+		fields := strings.Fields(line[i+1:])
+		if len(fields) < 2 {
+			return false
+		}
+		if pid, err = strconv.Atoi(fields[1]); err != nil {
+			return false
+		}
+	}
+	return false
+}
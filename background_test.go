@@ -0,0 +1,71 @@
+package glaze
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchMemoryReportsCrossings(t *testing.T) {
+	readings := []uint64{50, 150, 200, 80, 120, 120}
+	var mu sync.Mutex
+	i := 0
+	read := func() (uint64, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if i >= len(readings) {
+			return 0, errors.New("done")
+		}
+		i++
+		return readings[i-1], nil
+	}
+
+	got := make(chan uint64, len(readings))
+	stop := watchMemory(time.Millisecond, 100, read, func(used uint64) { got <- used })
+	defer stop()
+
+	var crossings []uint64
+	for len(crossings) < 2 {
+		select {
+		case used := <-got:
+			crossings = append(crossings, used)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("crossings = %v, want 2", crossings)
+		}
+	}
+	if !slices.Equal(crossings, []uint64{150, 120}) {
+		t.Fatalf("crossings = %v, want [150 120]", crossings)
+	}
+}
+
+func TestSetBackgroundModeRestore(t *testing.T) {
+	defer func() {
+		backgroundState.mu.Lock()
+		backgroundState.on, backgroundState.restore = false, nil
+		backgroundState.mu.Unlock()
+	}()
+	restored := 0
+	backgroundState.on = true
+	backgroundState.restore = func() error { restored++; return nil }
+
+	if err := SetBackgroundMode(true); err != nil {
+		t.Fatalf("SetBackgroundMode(true) unexpected error while active: %v", err)
+	}
+	for range 2 {
+		if err := SetBackgroundMode(false); err != nil {
+			t.Fatalf("SetBackgroundMode(false) unexpected error: %v", err)
+		}
+	}
+	if restored != 1 {
+		t.Fatalf("restore called %d times, want 1", restored)
+	}
+}
+
+func TestReadGoMemory(t *testing.T) {
+	used, err := readGoMemory()
+	if err != nil || used == 0 {
+		t.Fatalf("readGoMemory() = %d, %v", used, err)
+	}
+}
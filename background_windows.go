@@ -0,0 +1,30 @@
+package glaze
+
+import (
+	"fmt"
+	"syscall"
+)
+
+var procSetPriorityClass = kernel32.NewProc("SetPriorityClass")
+
+const (
+	processModeBackgroundBegin = 0x00100000
+	processModeBackgroundEnd   = 0x00200000
+)
+
+// enterBackground switches the process to background processing mode, which
+// lowers its CPU, I/O and memory priority.
+func enterBackground() (func() error, error) {
+	if err := setPriorityClass(processModeBackgroundBegin); err != nil {
+		return nil, err
+	}
+	return func() error { return setPriorityClass(processModeBackgroundEnd) }, nil
+}
+
+func setPriorityClass(class uintptr) error {
+	process, _ := syscall.GetCurrentProcess()
+	if r, _, err := procSetPriorityClass.Call(uintptr(process), class); r == 0 {
+		return fmt.Errorf("webview: SetPriorityClass failed: %w", err)
+	}
+	return nil
+}
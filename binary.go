@@ -0,0 +1,169 @@
+package glaze
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+)
+
+// binaryKey marks an encoded Binary value in JSON.
+const binaryKey = "__glaze_bytes"
+
+// binaryJS converts binary data crossing the bridge. ArrayBuffers, typed
+// arrays and DataViews passed to bound functions, at any depth, are sent as
+// base64 strings, which Go decodes into []byte. Binary values in results
+// arrive as Uint8Array. Progress events still reach the returned promise.
+const binaryJS = `(function () {
+  'use strict';
+  var glaze = window.glaze = window.glaze || {};
+  if (glaze._binaryInstalled) { return; }
+  glaze._binaryInstalled = true;
+  function toBase64(bytes) {
+    var s = "";
+    for (var i = 0; i < bytes.length; i += 0x8000) {
+      s += String.fromCharCode.apply(null, bytes.subarray(i, i + 0x8000));
+    }
+    return btoa(s);
+  }
+  function encode(v) {
+    if (v instanceof ArrayBuffer) { return toBase64(new Uint8Array(v)); }
+    if (ArrayBuffer.isView(v)) { return toBase64(new Uint8Array(v.buffer, v.byteOffset, v.byteLength)); }
+    if (Array.isArray(v)) { return v.map(encode); }
+    if (v && typeof v === "object" && Object.getPrototypeOf(v) === Object.prototype) {
+      var out = {};
+      Object.keys(v).forEach(function (k) { out[k] = encode(v[k]); });
+      return out;
+    }
+    return v;
+  }
+  function decode(v) {
+    if (ArrayBuffer.isView(v)) { return v; }
+    if (Array.isArray(v)) { return v.map(decode); }
+    if (v && typeof v === "object") {
+      var keys = Object.keys(v);
+      if (keys.length === 1 && keys[0] === "` + binaryKey + `") {
+        var s = atob(v.` + binaryKey + `);
+        var bytes = new Uint8Array(s.length);
+        for (var i = 0; i < s.length; i++) { bytes[i] = s.charCodeAt(i); }
+        return bytes;
+      }
+      keys.forEach(function (k) { v[k] = decode(v[k]); });
+    }
+    return v;
+  }
+  function patch() {
+    if (!window.__webview__) { return false; }
+    var proto = Object.getPrototypeOf(window.__webview__);
+    if (proto._glazeBinary || Object.isFrozen(proto)) { return true; }
+    proto._glazeBinary = true;
+    var call = proto.call;
+    proto.call = function (method) {
+      var args = [method];
+      for (var i = 1; i < arguments.length; i++) { args.push(encode(arguments[i])); }
+      var inner = call.apply(this, args);
+      var promise = inner.then(decode);
+      Object.defineProperty(promise, "onprogress", {
+        get: function () { return inner.onprogress; },
+        set: function (fn) { inner.onprogress = fn; }
+      });
+      return promise;
+    };
+    return true;
+  }
+  glaze._decodeBinary = decode;
+  if (!patch()) { document.addEventListener("DOMContentLoaded", patch); }
+})();`
+
+// Binary is a byte slice that reaches JavaScript as a Uint8Array instead of
+// a base64 string. Bound functions that return []byte are converted
+// automatically; use Binary for byte fields nested in results. Typed arrays
+// and ArrayBuffers passed from JavaScript decode into both []byte and
+// Binary.
+type Binary []byte
+
+// MarshalJSON encodes b as an object the glaze runtime turns into a
+// Uint8Array.
+func (b Binary) MarshalJSON() ([]byte, error) {
+	if b == nil {
+		return []byte("null"), nil
+	}
+	return json.Marshal(map[string]string{binaryKey: base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON accepts a base64 string, null or an encoded Binary.
+func (b *Binary) UnmarshalJSON(data []byte) error {
+	var s *string
+	if err := json.Unmarshal(data, &s); err == nil {
+		if s == nil {
+			*b = nil
+			return nil
+		}
+		raw, err := base64.StdEncoding.DecodeString(*s)
+		if err != nil {
+			return err
+		}
+		*b = raw
+		return nil
+	}
+	var obj map[string][]byte
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	raw, ok := obj[binaryKey]
+	if !ok || len(obj) != 1 {
+		return errors.New("webview: invalid binary value")
+	}
+	*b = raw
+	return nil
+}
+
+var binaryType = reflect.TypeFor[Binary]()
+
+// funcUsesBinary reports whether any parameter or result of the function
+// type t can carry byte slices, so the binary runtime is needed.
+func funcUsesBinary(t reflect.Type) bool {
+	seen := make(map[reflect.Type]bool)
+	for i := range t.NumIn() {
+		if usesBinary(t.In(i), seen) {
+			return true
+		}
+	}
+	for i := range t.NumOut() {
+		if usesBinary(t.Out(i), seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// usesBinary reports whether values of t can carry byte slices.
+func usesBinary(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	if t == rawMessageType {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.Uint8 || usesBinary(t.Elem(), seen)
+	case reflect.Array, reflect.Pointer, reflect.Map:
+		return usesBinary(t.Elem(), seen)
+	case reflect.Struct:
+		for i := range t.NumField() {
+			if f := t.Field(i); f.IsExported() && usesBinary(f.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// binaryResult reports whether results of type t are plain byte slices that
+// should be sent as Binary.
+func binaryResult(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 && t != rawMessageType &&
+		!t.Implements(jsonMarshalerType) && !t.Implements(textMarshalerType)
+}
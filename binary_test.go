@@ -0,0 +1,89 @@
+package glaze
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBinaryJSON(t *testing.T) {
+	data, err := json.Marshal(struct{ B Binary }{Binary("hi")})
+	if err != nil || string(data) != `{"B":{"`+binaryKey+`":"aGk="}}` {
+		t.Fatalf("Marshal = %s, %v", data, err)
+	}
+	if data, _ := json.Marshal(Binary(nil)); string(data) != "null" {
+		t.Fatalf("Marshal(nil) = %s, want null", data)
+	}
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: `"aGk="`, want: "hi"},
+		{in: `{"` + binaryKey + `":"aGk="}`, want: "hi"},
+		{in: `null`, want: ""},
+		{in: `"%%"`, wantErr: true},
+		{in: `{"other":"aGk="}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		var b Binary
+		err := json.Unmarshal([]byte(tt.in), &b)
+		if (err != nil) != tt.wantErr || string(b) != tt.want {
+			t.Errorf("Unmarshal(%s) = %q, %v", tt.in, b, err)
+		}
+	}
+}
+
+func TestBindingBinaryPayloads(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	_ = w.Bind("upper", func(b []byte) []byte { return []byte(strings.ToUpper(string(b))) })
+	_ = w.Bind("text", func(s string) string { return s })
+	_ = w.Bind("addr", func() net.IP { return net.IPv4(127, 0, 0, 1) })
+
+	got, err := w.call(t, "upper", base64.StdEncoding.EncodeToString([]byte("abc")))
+	if err != nil {
+		t.Fatalf("upper unexpected error: %v", err)
+	}
+	if b, ok := got.(Binary); !ok || string(b) != "ABC" {
+		t.Fatalf("upper = %#v, want Binary(ABC)", got)
+	}
+	if got, _ := w.call(t, "addr"); !net.IP.Equal(got.(net.IP), net.IPv4(127, 0, 0, 1)) {
+		t.Fatalf("addr = %#v, want net.IP left to its text encoding", got)
+	}
+
+	binaryInits := 0
+	for _, js := range w.inits {
+		if js == binaryJS {
+			binaryInits++
+		}
+	}
+	if binaryInits != 1 {
+		t.Fatalf("binary runtime injected %d times, want 1", binaryInits)
+	}
+}
+
+func TestFuncUsesBinary(t *testing.T) {
+	type nested struct{ Data Binary }
+	tests := []struct {
+		name string
+		fn   any
+		want bool
+	}{
+		{"param", func([]byte) {}, true},
+		{"result", func() ([]byte, error) { return nil, nil }, true},
+		{"nested", func() []nested { return nil }, true},
+		{"raw", func(json.RawMessage) {}, false},
+		{"plain", func(string, map[string]int) bool { return false }, false},
+	}
+	for _, tt := range tests {
+		if got := funcUsesBinary(reflect.TypeOf(tt.fn)); got != tt.want {
+			t.Errorf("%s: funcUsesBinary = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
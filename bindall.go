@@ -0,0 +1,129 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// BindReport describes what BindAll bound, one entry per service in prefix
+// order.
+type BindReport struct {
+	Services []BoundService
+}
+
+// BoundService lists the JavaScript names bound for one BindAll entry.
+type BoundService struct {
+	// Prefix is the map key the service was bound under.
+	Prefix string
+
+	// Type is the Go type of the service, e.g. "*notes.Service".
+	Type string
+
+	// Names are the bound JavaScript names, in method order.
+	Names []string
+}
+
+// Names returns every name in the report.
+func (r BindReport) Names() []string {
+	var names []string
+	for _, s := range r.Services {
+		names = append(names, s.Names...)
+	}
+	return names
+}
+
+// BindAll binds several services at once, each under its map key as
+// BindMethods does; a function value is bound directly under its key.
+//
+//	report, err := glaze.BindAll(w, map[string]any{
+//		"notes": noteSvc,
+//		"filo":  filoSvc,
+//		"ping":  func() string { return "pong" },
+//	})
+//
+// Every name is worked out before anything is bound, and BindAll fails
+// without binding anything if two services produce the same name or a name
+// is already bound, listing all the collisions. If a later Bind fails, the
+// bindings already made are undone. BindAll must be called from the UI
+// thread, like Bind.
+func BindAll(w WebView, services map[string]any) (BindReport, error) {
+	if w == nil {
+		return BindReport{}, errors.New("webview: BindAll requires a non-nil WebView")
+	}
+
+	prefixes := make([]string, 0, len(services))
+	for prefix := range services {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	// Plan every binding and check names before binding any of them.
+	plan := make([][]serviceMethod, len(prefixes))
+	owners := make(map[string][]string)
+	for i, prefix := range prefixes {
+		svc := services[prefix]
+		if v := reflect.ValueOf(svc); v.Kind() == reflect.Func {
+			if v.IsNil() {
+				return BindReport{}, fmt.Errorf("webview: BindAll: %s is a nil function", prefix)
+			}
+			plan[i] = []serviceMethod{{name: prefix, fn: svc}}
+		} else {
+			methods, err := serviceMethods("BindAll", prefix, svc, BindOptions{})
+			if err != nil {
+				return BindReport{}, fmt.Errorf("%w (service %s)", err, prefix)
+			}
+			plan[i] = methods
+		}
+		for _, m := range plan[i] {
+			owners[m.name] = append(owners[m.name], prefix)
+		}
+	}
+
+	b := bridgeFor(w)
+	var collisions []string
+	b.mu.Lock()
+	for name, prefixes := range owners {
+		_, bound := b.funcs[name]
+		switch {
+		case bound:
+			collisions = append(collisions, name+" (already bound)")
+		case len(prefixes) > 1:
+			collisions = append(collisions, name+" ("+strings.Join(prefixes, ", ")+")")
+		}
+	}
+	b.mu.Unlock()
+	if len(collisions) > 0 {
+		sort.Strings(collisions)
+		return BindReport{}, fmt.Errorf("webview: BindAll name collisions: %s", strings.Join(collisions, "; "))
+	}
+
+	var report BindReport
+	var done []string
+	for i, prefix := range prefixes {
+		entry := BoundService{Prefix: prefix, Type: reflect.TypeOf(services[prefix]).String()}
+		for _, m := range plan[i] {
+			if err := w.Bind(m.name, m.fn); err != nil {
+				var errs []error
+				errs = append(errs, fmt.Errorf("binding %s: %w", m.name, err))
+				for j := len(done) - 1; j >= 0; j-- {
+					if err := w.Unbind(done[j]); err != nil {
+						errs = append(errs, fmt.Errorf("unbinding %s: %w", done[j], err))
+					}
+				}
+				return BindReport{}, errors.Join(errs...)
+			}
+			done = append(done, m.name)
+			entry.Names = append(entry.Names, m.name)
+		}
+		report.Services = append(report.Services, entry)
+	}
+	for _, s := range report.Services {
+		if reflect.ValueOf(services[s.Prefix]).Kind() != reflect.Func {
+			b.recordService(s.Prefix, s.Names, BindOptions{}, nil)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,114 @@
+package glaze
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+type bindAllNotes struct{}
+
+func (bindAllNotes) List() []string { return nil }
+
+func (bindAllNotes) Count() int { return 0 }
+
+func TestBindAll(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	report, err := BindAll(w, map[string]any{
+		"notes": bindAllNotes{},
+		"ping":  func() string { return "pong" },
+	})
+	if err != nil {
+		t.Fatalf("BindAll() unexpected error: %v", err)
+	}
+	want := []BoundService{
+		{Prefix: "notes", Type: "glaze.bindAllNotes", Names: []string{"notes_count", "notes_list"}},
+		{Prefix: "ping", Type: "func() string", Names: []string{"ping"}},
+	}
+	if len(report.Services) != len(want) {
+		t.Fatalf("report = %+v, want %+v", report, want)
+	}
+	for i := range want {
+		got := report.Services[i]
+		if got.Prefix != want[i].Prefix || got.Type != want[i].Type || !slices.Equal(got.Names, want[i].Names) {
+			t.Errorf("report.Services[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+	if got, _ := w.call(t, "ping"); got != "pong" {
+		t.Errorf("ping = %v, want pong", got)
+	}
+	if names := report.Names(); len(names) != 3 {
+		t.Errorf("report.Names() = %q", names)
+	}
+}
+
+func TestBindAllCollisions(t *testing.T) {
+	tests := []struct {
+		name     string
+		bound    string
+		services map[string]any
+		want     string
+	}{
+		{
+			name: "between services",
+			services: map[string]any{
+				"notes":       bindAllNotes{},
+				"notes_count": func() int { return 1 },
+			},
+			want: "notes_count (notes, notes_count)",
+		},
+		{
+			name:     "already bound",
+			bound:    "notes_list",
+			services: map[string]any{"notes": bindAllNotes{}},
+			want:     "notes_list (already bound)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &fakeWebView{}
+			defer w.Destroy()
+			if tt.bound != "" {
+				_ = w.Bind(tt.bound, func() {})
+			}
+			before := len(w.bound)
+
+			_, err := BindAll(w, tt.services)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("BindAll() error = %v, want collision %q", err, tt.want)
+			}
+			if len(w.bound) != before {
+				t.Fatalf("BindAll() bound %d names despite the collision", len(w.bound)-before)
+			}
+		})
+	}
+}
+
+func TestBindAllRollsBack(t *testing.T) {
+	w := &fakeWebView{failBind: "ping"}
+	defer w.Destroy()
+
+	_, err := BindAll(w, map[string]any{
+		"notes": bindAllNotes{},
+		"ping":  func() {},
+	})
+	if err == nil {
+		t.Fatal("BindAll() expected error")
+	}
+	if len(w.bound) != 0 {
+		t.Fatalf("bindings left after rollback: %v", w.bound)
+	}
+}
+
+func TestBindAllInvalidService(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	if _, err := BindAll(w, map[string]any{"nothing": nil}); err == nil {
+		t.Fatal("BindAll() expected error for a nil service")
+	}
+	if _, err := BindAll(nil, nil); err == nil {
+		t.Fatal("BindAll(nil) expected error")
+	}
+}
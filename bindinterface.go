@@ -0,0 +1,86 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// BindInterface binds the methods of the interface T, implemented by impl,
+// as BindMethods does. Only the methods of T are bound, whatever else impl
+// has, so the interface is the contract between Go and the page: the
+// TypeScript declarations come from it, and implementations, such as a fake
+// in tests or a mock during frontend work, are swapped behind it.
+//
+//	type NotesAPI interface {
+//		List() ([]Note, error)
+//		Add(text string) (Note, error)
+//	}
+//
+//	_, err := glaze.BindInterface[NotesAPI](w, "notes", NewNotes(db))
+//	// later, or in a test:
+//	_, err = glaze.ReplaceService(w, "notes", fakeNotes{})
+//	// and for the frontend:
+//	src, err := glaze.GenerateTypes("notes", (*NotesAPI)(nil), glaze.BindOptions{})
+//
+// ReplaceService keeps the interface: the new object must implement T.
+func BindInterface[T any](w WebView, prefix string, impl T) ([]string, error) {
+	return BindInterfaceWithOptions(w, prefix, impl, BindOptions{})
+}
+
+// BindInterfaceWithOptions is BindInterface with options controlling how the
+// methods are exposed, as for BindMethodsWithOptions.
+func BindInterfaceWithOptions[T any](w WebView, prefix string, impl T, opts BindOptions) ([]string, error) {
+	if w == nil {
+		return nil, errors.New("webview: BindInterface requires a non-nil WebView")
+	}
+	contract := reflect.TypeFor[T]()
+	if contract.Kind() != reflect.Interface {
+		return nil, fmt.Errorf("webview: BindInterface requires an interface type, not %s", contract)
+	}
+	v, err := contractValue("BindInterface", contract, impl)
+	if err != nil {
+		return nil, err
+	}
+	methods, err := serviceMethodsOf("BindInterface", prefix, v, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var bound, members []string
+	for _, m := range methods {
+		if err := BindWithOptions(w, m.name, m.fn, opts.callOptions()); err != nil {
+			// Leave nothing half-bound: the contract holds as a whole.
+			var errs []error
+			errs = append(errs, fmt.Errorf("binding %s: %w", m.name, err))
+			for i := len(bound) - 1; i >= 0; i-- {
+				if err := w.Unbind(bound[i]); err != nil {
+					errs = append(errs, fmt.Errorf("unbinding %s: %w", bound[i], err))
+				}
+			}
+			return nil, errors.Join(errs...)
+		}
+		bound = append(bound, m.name)
+		members = append(members, m.member)
+	}
+	if opts.Namespace && len(members) > 0 {
+		installNamespace(w, prefix, members)
+	}
+	bridgeFor(w).recordService(prefix, bound, opts, contract)
+	return bound, nil
+}
+
+// contractValue returns obj as a value of the interface type contract, whose
+// methods are those of the interface only.
+func contractValue(op string, contract reflect.Type, obj any) (reflect.Value, error) {
+	v := reflect.ValueOf(obj)
+	if !v.IsValid() || (v.Kind() == reflect.Pointer && v.IsNil()) {
+		return reflect.Value{}, fmt.Errorf("webview: %s requires a non-nil implementation of %s", op, contract)
+	}
+	if !v.Type().Implements(contract) {
+		return reflect.Value{}, fmt.Errorf("webview: %s: %s does not implement %s", op, v.Type(), contract)
+	}
+	iv := reflect.New(contract).Elem()
+	iv.Set(v)
+	return iv, nil
+}
@@ -0,0 +1,109 @@
+package glaze
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+type ifaceNotesAPI interface {
+	List() []string
+	Add(text string) (int, error)
+}
+
+type ifaceNotes struct{ notes []string }
+
+func (n *ifaceNotes) List() []string { return n.notes }
+
+func (n *ifaceNotes) Add(text string) (int, error) {
+	n.notes = append(n.notes, text)
+	return len(n.notes), nil
+}
+
+// Close is not part of ifaceNotesAPI and must stay unbound.
+func (n *ifaceNotes) Close() error { return nil }
+
+type ifaceFakeNotes struct{}
+
+func (ifaceFakeNotes) List() []string             { return []string{"fake"} }
+func (ifaceFakeNotes) Add(string) (int, error)    { return 0, nil }
+func (ifaceFakeNotes) Reset()                     {}
+func (ifaceFakeNotes) Extra(text string) []string { return nil }
+
+func TestBindInterface(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	names, err := BindInterface[ifaceNotesAPI](w, "notes", &ifaceNotes{})
+	if err != nil {
+		t.Fatalf("BindInterface() unexpected error: %v", err)
+	}
+	if want := []string{"notes_add", "notes_list"}; !slices.Equal(names, want) {
+		t.Fatalf("BindInterface() = %v, want %v", names, want)
+	}
+	if _, bound := w.bound["notes_close"]; bound {
+		t.Error("method outside the interface was bound")
+	}
+	if got, _ := w.call(t, "notes_add", "milk"); got != 1 {
+		t.Errorf("notes_add = %v, want 1", got)
+	}
+
+	// A replacement keeps the contract: extra methods stay unbound.
+	if _, err := ReplaceService(w, "notes", ifaceFakeNotes{}); err != nil {
+		t.Fatalf("ReplaceService() unexpected error: %v", err)
+	}
+	if got, _ := w.call(t, "notes_list"); !slices.Equal(got.([]string), []string{"fake"}) {
+		t.Errorf("notes_list = %v, want the fake", got)
+	}
+	if _, bound := w.bound["notes_reset"]; bound {
+		t.Error("ReplaceService bound a method outside the interface")
+	}
+	if _, err := ReplaceService(w, "notes", rebindServiceV1{}); err == nil || !strings.Contains(err.Error(), "does not implement") {
+		t.Errorf("ReplaceService() with a non-implementation error = %v", err)
+	}
+}
+
+func TestBindInterfaceErrors(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	if _, err := BindInterface[*ifaceNotes](w, "notes", &ifaceNotes{}); err == nil {
+		t.Error("BindInterface() with a concrete type expected error")
+	}
+	if _, err := BindInterface[ifaceNotesAPI](w, "notes", nil); err == nil {
+		t.Error("BindInterface() with a nil implementation expected error")
+	}
+	if _, err := BindInterface[ifaceNotesAPI](w, "notes", (*ifaceNotes)(nil)); err == nil {
+		t.Error("BindInterface() with a nil pointer expected error")
+	}
+	if _, err := BindInterface[ifaceNotesAPI](nil, "notes", &ifaceNotes{}); err == nil {
+		t.Error("BindInterface(nil) expected error")
+	}
+
+	failing := &fakeWebView{failBind: "notes_list"}
+	defer failing.Destroy()
+	if _, err := BindInterface[ifaceNotesAPI](failing, "notes", &ifaceNotes{}); err == nil {
+		t.Fatal("BindInterface() expected the bind error")
+	}
+	if _, bound := failing.bound["notes_add"]; bound {
+		t.Error("notes_add left bound after a failed BindInterface")
+	}
+}
+
+func TestGenerateTypesInterface(t *testing.T) {
+	src, err := GenerateTypes("notes", (*ifaceNotesAPI)(nil), BindOptions{})
+	if err != nil {
+		t.Fatalf("GenerateTypes() unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"declare function notes_add(arg0: string): Promise<number>;",
+		"declare function notes_list(): Promise<string[] | null>;",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("GenerateTypes() missing %q in:\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, "notes_close") {
+		t.Error("GenerateTypes() described a method outside the interface")
+	}
+}
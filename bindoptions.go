@@ -0,0 +1,285 @@
+package glaze
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// ErrBindingTimeout is returned, wrapped, by calls that exceed
+// BindingOptions.Timeout. JavaScript sees the promise rejected with its text.
+var ErrBindingTimeout = errors.New("webview: binding call timed out")
+
+// contextType is the type of context.Context parameters, which bound
+// functions receive from glaze rather than from JavaScript.
+var contextType = reflect.TypeFor[context.Context]()
+
+// BindingOptions configures how calls to a single bound function execute.
+type BindingOptions struct {
+	// Timeout bounds each call. When it elapses the JavaScript promise
+	// rejects with ErrBindingTimeout and the context passed to the function,
+	// if it declares a context.Context parameter, is cancelled. Functions
+	// that ignore the context keep running in the background. Zero means no
+	// limit.
+	Timeout time.Duration
+
+	// Serial runs calls strictly one at a time, in the order the page made
+	// them, for stateful services where interleaved calls would corrupt
+	// session state. The page queues each call until the previous one
+	// settles, and Go never runs two calls concurrently, even from
+	// different pages. A call that timed out still holds its place until its
+	// function returns.
+	Serial bool
+
+	// Codec encodes the arguments and result of this binding, overriding
+	// the window's codec set with SetCodec.
+	Codec Codec
+
+	// Params names the parameters JavaScript supplies, in order, so the
+	// page can call the function with one object instead of positional
+	// arguments: with Params {"query", "limit"}, search({query: "milk",
+	// limit: 20}) calls f("milk", 20). Omitted keys are zero values and
+	// unknown keys reject the call; positional calls keep working.
+	// Functions taking a single struct need no Params, since an object
+	// literal already decodes into the struct by its fields' JSON names.
+	Params []string
+}
+
+// BindWithOptions binds f like w.Bind, applying opts to every call.
+//
+//	glaze.BindWithOptions(w, "search", func(ctx context.Context, q string) ([]Hit, error) {
+//		return index.Search(ctx, q)
+//	}, glaze.BindingOptions{Timeout: 5 * time.Second})
+//
+// It must be called from the UI thread, like Bind.
+func BindWithOptions(w WebView, name string, f any, opts BindingOptions) error {
+	wrapped, err := applyBindingOptions(name, f, opts)
+	if err != nil {
+		return err
+	}
+	if err := w.Bind(name, wrapped); err != nil {
+		return err
+	}
+	setCallOptions(w, name, opts)
+	return nil
+}
+
+// setCallOptions records the codec of a binding bound as name and installs
+// the page side of its options.
+func setCallOptions(w WebView, name string, opts BindingOptions) {
+	b := bridgeFor(w)
+	b.mu.Lock()
+	if opts.Codec != nil {
+		if b.codecs == nil {
+			b.codecs = make(map[string]Codec)
+		}
+		b.codecs[name] = opts.Codec
+	} else {
+		delete(b.codecs, name)
+	}
+	b.mu.Unlock()
+	if opts.Serial {
+		wrapStub(b, "serial:"+name, serialJS+marshalJSON(name)+");")
+	}
+	if len(opts.Params) > 0 {
+		params, _ := json.Marshal(opts.Params) // a []string always encodes
+		wrapStub(b, "named:"+name, namedJS+marshalJSON(name)+", "+string(params)+");")
+	}
+}
+
+// wrapStub installs js, which wraps the stub of a bound function, under
+// key.
+func wrapStub(b *bridge, key, js string) {
+	b.mu.Lock()
+	known := b.scripts[key]
+	b.mu.Unlock()
+	if known {
+		// Rebound after Unbind: future pages already wrap the name.
+		b.w.Eval(js)
+		return
+	}
+	b.injectScript(key, js)
+}
+
+// serialJS wraps the bound function whose name it is called with so each
+// call starts once the previous one has settled. Progress events of the
+// underlying call are forwarded to the returned promise, and a call queued
+// inside tx.run stays in its transaction.
+const serialJS = `(function (name) {
+  'use strict';
+  var fn = window[name];
+  if (typeof fn !== "function" || fn._glazeSerial) { return; }
+  var tail = Promise.resolve();
+  var noop = function () {};
+  var queued = function () {
+    var args = arguments;
+    var glaze = window.glaze;
+    var run = glaze && glaze._txBind ? glaze._txBind(fn) : fn;
+    var promise = tail.then(function () {
+      var inner = run.apply(null, args);
+      if (inner && "onprogress" in inner) {
+        inner.onprogress = function (event) {
+          if (typeof promise.onprogress === "function") { promise.onprogress(event); }
+        };
+      }
+      return inner;
+    });
+    promise.onprogress = null;
+    tail = promise.then(noop, noop);
+    return promise;
+  };
+  queued._glazeSerial = true;
+  window[name] = queued;
+})(`
+
+// namedJS wraps the bound function whose name it is called with so a call
+// with a single plain object is spread into the parameters named by
+// BindingOptions.Params.
+const namedJS = `(function (name, params) {
+  'use strict';
+  var fn = window[name];
+  if (typeof fn !== "function" || fn._glazeNamed) { return; }
+  var named = function (obj) {
+    if (arguments.length !== 1 || obj === null || typeof obj !== "object" ||
+        Array.isArray(obj) || ArrayBuffer.isView(obj) || obj instanceof ArrayBuffer) {
+      return fn.apply(null, arguments);
+    }
+    var unknown = Object.keys(obj).filter(function (k) { return params.indexOf(k) < 0; });
+    if (unknown.length > 0) {
+      return Promise.reject({ code: "invalid_arguments", message: "unknown argument: " + unknown.join(", ") });
+    }
+    return fn.apply(null, params.map(function (k) { return obj[k] === undefined ? null : obj[k]; }));
+  };
+  named._glazeNamed = true;
+  window[name] = named;
+})(`
+
+// applyBindingOptions returns f wrapped to honour the Go side of opts. The
+// wrapper keeps f's parameters, so injected ones still reach it, and always
+// returns an error so failures such as timeouts can reject the promise.
+func applyBindingOptions(name string, f any, opts BindingOptions) (any, error) {
+	v := reflect.ValueOf(f)
+	if v.Kind() != reflect.Func {
+		return nil, errors.New("only functions can be bound")
+	}
+	t := v.Type()
+	if len(opts.Params) > 0 {
+		if err := checkParamNames(t, opts.Params); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Timeout <= 0 && !opts.Serial {
+		return f, nil
+	}
+
+	ins := make([]reflect.Type, t.NumIn())
+	ctxIndex := -1
+	for i := range ins {
+		ins[i] = t.In(i)
+		if ins[i] == contextType {
+			ctxIndex = i
+		}
+	}
+
+	outs := make([]reflect.Type, t.NumOut())
+	for i := range outs {
+		outs[i] = t.Out(i)
+	}
+	hasError := len(outs) > 0 && outs[len(outs)-1].Implements(errorType)
+	if len(outs) > 1 && !hasError {
+		return nil, errors.New("last return value must implement error")
+	}
+	if !hasError {
+		outs = append(outs, errorType)
+	}
+	wrappedType := reflect.FuncOf(ins, outs, t.IsVariadic())
+
+	results := func(res []reflect.Value, err error) []reflect.Value {
+		out := make([]reflect.Value, len(outs))
+		for i, typ := range outs {
+			switch {
+			case err != nil && i == len(outs)-1:
+				out[i] = reflect.New(typ).Elem()
+				out[i].Set(reflect.ValueOf(err))
+			case err != nil || i >= len(res):
+				out[i] = reflect.Zero(typ)
+			default:
+				out[i] = res[i]
+			}
+		}
+		return out
+	}
+
+	var mu sync.Mutex
+	call := func(args []reflect.Value) []reflect.Value {
+		if opts.Serial {
+			mu.Lock()
+			defer mu.Unlock()
+		}
+		if t.IsVariadic() {
+			return v.CallSlice(args)
+		}
+		return v.Call(args)
+	}
+
+	return reflect.MakeFunc(wrappedType, func(args []reflect.Value) []reflect.Value {
+		if opts.Timeout <= 0 {
+			return results(call(args), nil)
+		}
+		parent := context.Background()
+		if ctxIndex >= 0 && !args[ctxIndex].IsNil() {
+			parent = args[ctxIndex].Interface().(context.Context)
+		}
+		ctx, cancel := context.WithTimeout(parent, opts.Timeout)
+		defer cancel()
+		if ctxIndex >= 0 {
+			args[ctxIndex] = reflect.ValueOf(&ctx).Elem()
+		}
+
+		done := make(chan []reflect.Value, 1)
+		go func() { done <- call(args) }()
+
+		select {
+		case res := <-done:
+			return results(res, nil)
+		case <-ctx.Done():
+			if parent.Err() != nil {
+				return results(nil, parent.Err())
+			}
+			return results(nil, fmt.Errorf("%w: %s after %s", ErrBindingTimeout, name, opts.Timeout))
+		}
+	}).Interface(), nil
+}
+
+// checkParamNames reports whether names can name the parameters that
+// JavaScript supplies to functions of type t.
+func checkParamNames(t reflect.Type, names []string) error {
+	if t.IsVariadic() {
+		return errors.New("webview: Params cannot name the parameters of a variadic function")
+	}
+	n := 0
+	for i := range t.NumIn() {
+		if _, injected := injectedParams[t.In(i)]; !injected {
+			n++
+		}
+	}
+	if len(names) != n {
+		return fmt.Errorf("webview: Params has %d names for %d parameters", len(names), n)
+	}
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name == "" || seen[name] {
+			return fmt.Errorf("webview: Params name %q is empty or repeated", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+func contextParam(WebView, string) reflect.Value {
+	return reflect.ValueOf(context.Background())
+}
@@ -0,0 +1,201 @@
+package glaze
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBindWithOptionsTimeout(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	cancelled := make(chan struct{})
+	err := BindWithOptions(w, "slow", func(ctx context.Context, n int) (int, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return n, nil
+	}, BindingOptions{Timeout: 20 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("BindWithOptions() unexpected error: %v", err)
+	}
+
+	_, err = w.call(t, "slow", 1)
+	if !errors.Is(err, ErrBindingTimeout) || !strings.Contains(err.Error(), "slow after 20ms") {
+		t.Fatalf("call error = %v, want timeout for slow", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("handler context was not cancelled")
+	}
+}
+
+func TestBindWithOptionsShapes(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	opts := BindingOptions{Timeout: time.Second}
+	block := make(chan struct{})
+	defer close(block)
+	bindings := map[string]any{
+		"value":    func(a, b int) int { return a + b },
+		"variadic": func(parts ...string) string { return strings.Join(parts, "-") },
+		"fails":    func() error { return errors.New("boom") },
+		"nothing":  func() {},
+		"stuck":    func() string { <-block; return "late" },
+	}
+	for name, f := range bindings {
+		o := opts
+		if name == "stuck" {
+			o.Timeout = 10 * time.Millisecond
+		}
+		if err := BindWithOptions(w, name, f, o); err != nil {
+			t.Fatalf("BindWithOptions(%s) unexpected error: %v", name, err)
+		}
+	}
+
+	if got, err := w.call(t, "value", 2, 3); err != nil || got != 5 {
+		t.Errorf("value = %v, %v; want 5", got, err)
+	}
+	if got, err := w.call(t, "variadic", "a", "b"); err != nil || got != "a-b" {
+		t.Errorf("variadic = %v, %v; want a-b", got, err)
+	}
+	if _, err := w.call(t, "fails"); err == nil || err.Error() != "boom" {
+		t.Errorf("fails error = %v, want boom", err)
+	}
+	if _, err := w.call(t, "nothing"); err != nil {
+		t.Errorf("nothing unexpected error: %v", err)
+	}
+	if got, err := w.call(t, "stuck"); !errors.Is(err, ErrBindingTimeout) || got != "" {
+		t.Errorf("stuck = %q, %v; want timeout", got, err)
+	}
+}
+
+func TestBindWithOptionsZeroKeepsFunction(t *testing.T) {
+	f := func(s string) string { return s }
+	got, err := applyBindingOptions("echo", f, BindingOptions{})
+	if err != nil {
+		t.Fatalf("applyBindingOptions() unexpected error: %v", err)
+	}
+	if _, ok := got.(func(string) string); !ok {
+		t.Fatalf("applyBindingOptions() = %T, want the original function", got)
+	}
+	if _, err := applyBindingOptions("bad", 42, BindingOptions{Timeout: time.Second}); err == nil {
+		t.Fatal("applyBindingOptions(non-func) expected error")
+	}
+}
+
+func TestContextParamInjected(t *testing.T) {
+	fn, err := makeFuncWrapper(func(ctx context.Context, s string) bool { return ctx != nil && s == "x" })
+	if err != nil {
+		t.Fatalf("makeFuncWrapper() unexpected error: %v", err)
+	}
+	if got, err := fn("1", `["x"]`); err != nil || got != true {
+		t.Fatalf("call = %v, %v; want true", got, err)
+	}
+}
+
+func TestBindWithOptionsSerial(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	var mu sync.Mutex
+	active, peak := 0, 0
+	err := BindWithOptions(w, "eval", func(s string) string {
+		mu.Lock()
+		active++
+		peak = max(peak, active)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		active--
+		mu.Unlock()
+		return s
+	}, BindingOptions{Serial: true})
+	if err != nil {
+		t.Fatalf("BindWithOptions() unexpected error: %v", err)
+	}
+	if len(w.inits) != 1 || !strings.HasSuffix(w.inits[0], `("eval");`) {
+		t.Fatalf("inits = %q, want the serial queue for eval", w.inits)
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			if _, err := w.call(t, "eval", "x"); err != nil {
+				t.Errorf("call unexpected error: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+	if peak != 1 {
+		t.Fatalf("peak concurrent calls = %d, want 1", peak)
+	}
+
+	// Rebinding re-wraps the current page without registering the queue twice.
+	_ = w.Unbind("eval")
+	if err := BindWithOptions(w, "eval", strings.ToUpper, BindingOptions{Serial: true}); err != nil {
+		t.Fatalf("rebind unexpected error: %v", err)
+	}
+	if len(w.inits) != 1 || w.evals[len(w.evals)-1] != w.inits[0] {
+		t.Fatalf("rebind inits = %d, last eval = %q", len(w.inits), w.evals[len(w.evals)-1])
+	}
+}
+
+func TestBindMethodsWithCallOptions(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	bound, err := BindMethodsWithOptions(w, "calc", bindMethodsService{}, BindOptions{
+		Include: []string{"GetUserByID"},
+		Calls:   BindingOptions{Serial: true},
+	})
+	if err != nil {
+		t.Fatalf("BindMethodsWithOptions() unexpected error: %v", err)
+	}
+	if len(bound) != 1 || len(w.inits) != 1 || !strings.HasSuffix(w.inits[0], `("calc_get_user_by_id");`) {
+		t.Fatalf("bound = %v, inits = %q; want calc_get_user_by_id queued", bound, w.inits)
+	}
+	if got, err := w.call(t, "calc_get_user_by_id", 7); err != nil || got != 1 {
+		t.Fatalf("calc_get_user_by_id = %v, %v; want 1", got, err)
+	}
+}
+
+func TestBindWithOptionsParams(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	search := func(ctx context.Context, query string, limit int) string {
+		return fmt.Sprintf("%s:%d", query, limit)
+	}
+	if err := BindWithOptions(w, "search", search, BindingOptions{Params: []string{"query", "limit"}}); err != nil {
+		t.Fatalf("BindWithOptions() unexpected error: %v", err)
+	}
+	if len(w.inits) != 1 || !strings.HasSuffix(w.inits[0], `("search", ["query","limit"]);`) {
+		t.Fatalf("inits = %q, want the named arguments wrapper for search", w.inits)
+	}
+	if got, err := w.call(t, "search", "milk", 20); err != nil || got != "milk:20" {
+		t.Errorf("positional call = %v, %v, want milk:20", got, err)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		f      any
+		params []string
+	}{
+		{"too few", search, []string{"query"}},
+		{"too many", search, []string{"query", "limit", "page"}},
+		{"repeated", search, []string{"query", "query"}},
+		{"empty", search, []string{"query", ""}},
+		{"variadic", func(...int) {}, []string{"n"}},
+	} {
+		if err := BindWithOptions(w, "bad_"+tt.name, tt.f, BindingOptions{Params: tt.params}); err == nil {
+			t.Errorf("Params %s: BindWithOptions() returned no error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package glaze
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// StructOptions configures BindStruct.
+type StructOptions struct {
+	// BindOptions controls naming as for BindMethodsWithOptions. Include and
+	// Exclude take Go field names, and Name receives the accessor's Go-style
+	// name, such as "GetTitle" or "SetTitle".
+	BindOptions
+
+	// Notify emits a "{prefix}:change" event to the page after every
+	// change made from JavaScript, with {field, value} as payload, so other
+	// views can subscribe with glaze.on.
+	Notify bool
+
+	// OnChange is called after a field is set from JavaScript, with the
+	// field's JSON name and new value.
+	OnChange func(field string, value any)
+}
+
+// BindStruct binds the exported fields of the struct ptr points to as
+// JavaScript getters and setters, so simple app-state objects need no
+// hand-written accessors. For a field Title bound with prefix "state" it
+// exposes state_get_title() and state_set_title(v), or state.getTitle() and
+// state.setTitle(v) in Namespace mode, plus state_get() (state.get())
+// returning the whole struct. Fields tagged json:"-" are skipped.
+//
+// Accesses from JavaScript are serialized. If the struct implements
+// sync.Locker, for example by embedding a sync.Mutex, BindStruct uses it so
+// Go code can safely update the same fields under that lock.
+//
+// It returns the JavaScript names of the bound accessors.
+func BindStruct(w WebView, prefix string, ptr any, opts StructOptions) ([]string, error) {
+	if w == nil {
+		return nil, fmt.Errorf("webview: BindStruct requires a non-nil WebView")
+	}
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("webview: BindStruct requires a non-nil pointer to a struct")
+	}
+	if opts.Namespace && prefix == "" {
+		return nil, fmt.Errorf("webview: BindStruct requires a prefix to use as namespace")
+	}
+
+	lock, ok := ptr.(sync.Locker)
+	if !ok {
+		lock = &sync.Mutex{}
+	}
+	fields, err := structFields(v.Elem().Type(), opts.BindOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &boundStruct{w: w, prefix: prefix, v: v.Elem(), lock: lock, opts: opts}
+	type accessor struct {
+		method string
+		fn     any
+	}
+	accessors := []accessor{{"Get", s.getAll}}
+	for _, f := range fields {
+		accessors = append(accessors, accessor{"Get" + f.goName, s.getter(f)}, accessor{"Set" + f.goName, s.setter(f)})
+	}
+
+	var bound, members []string
+	for _, a := range accessors {
+		name, member := methodJSName(prefix, a.method, opts.BindOptions)
+		if err = w.Bind(name, a.fn); err != nil {
+			err = fmt.Errorf("binding %s: %w", name, err)
+			break
+		}
+		bound = append(bound, name)
+		members = append(members, member)
+	}
+	if opts.Namespace && len(members) > 0 {
+		installNamespace(w, prefix, members)
+	}
+	return bound, err
+}
+
+// structField is a struct field exposed by BindStruct.
+type structField struct {
+	goName   string
+	jsonName string
+	index    []int
+	typ      reflect.Type
+}
+
+// structFields lists the exported, JSON-visible fields of t allowed by opts.
+func structFields(t reflect.Type, opts BindOptions) ([]structField, error) {
+	excluded := make(map[string]bool)
+	for _, name := range opts.Exclude {
+		excluded[name] = true
+	}
+	var included map[string]bool
+	if len(opts.Include) > 0 {
+		included = make(map[string]bool)
+		for _, name := range opts.Include {
+			if f, ok := t.FieldByName(name); !ok || !f.IsExported() {
+				return nil, fmt.Errorf("webview: BindStruct: %s has no exported field %s", t, name)
+			}
+			included[name] = true
+		}
+	}
+
+	var fields []structField
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous || excluded[f.Name] || (included != nil && !included[f.Name]) {
+			continue
+		}
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		jsonName, _, _ := strings.Cut(tag, ",")
+		if jsonName == "" {
+			jsonName = f.Name
+		}
+		fields = append(fields, structField{goName: f.Name, jsonName: jsonName, index: f.Index, typ: f.Type})
+	}
+	return fields, nil
+}
+
+// boundStruct is the state behind the accessors of one BindStruct call.
+type boundStruct struct {
+	w      WebView
+	prefix string
+	v      reflect.Value
+	lock   sync.Locker
+	opts   StructOptions
+}
+
+// Values are encoded while the lock is held, so Go code updating the struct
+// under the same lock never races with the encoder.
+func (s *boundStruct) getAll() (json.RawMessage, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return json.Marshal(s.v.Addr().Interface())
+}
+
+func (s *boundStruct) getter(f structField) func() (json.RawMessage, error) {
+	return func() (json.RawMessage, error) {
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		return json.Marshal(s.v.FieldByIndex(f.index).Interface())
+	}
+}
+
+func (s *boundStruct) setter(f structField) func(json.RawMessage) error {
+	return func(raw json.RawMessage) error {
+		nv := reflect.New(f.typ)
+		if err := json.Unmarshal(raw, nv.Interface()); err != nil {
+			return fmt.Errorf("set %s: %w", f.jsonName, err)
+		}
+		s.lock.Lock()
+		s.v.FieldByIndex(f.index).Set(nv.Elem())
+		s.lock.Unlock()
+
+		value := nv.Elem().Interface()
+		if s.opts.Notify {
+			_ = Emit(s.w, s.prefix+":change", map[string]any{"field": f.jsonName, "value": value})
+		}
+		if s.opts.OnChange != nil {
+			s.opts.OnChange(f.jsonName, value)
+		}
+		return nil
+	}
+}
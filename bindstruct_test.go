@@ -0,0 +1,96 @@
+package glaze
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type appState struct {
+	sync.Mutex
+	Title   string   `json:"title"`
+	Count   int      `json:"count"`
+	Tags    []string `json:"tags"`
+	Secret  string   `json:"-"`
+	private int
+}
+
+func TestBindStruct(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	state := &appState{Title: "Inbox", Count: 2}
+	var changes []string
+	names, err := BindStruct(w, "state", state, StructOptions{
+		Notify:   true,
+		OnChange: func(field string, value any) { changes = append(changes, field) },
+	})
+	if err != nil {
+		t.Fatalf("BindStruct() unexpected error: %v", err)
+	}
+	want := "state_get,state_get_title,state_set_title,state_get_count,state_set_count,state_get_tags,state_set_tags"
+	if strings.Join(names, ",") != want {
+		t.Fatalf("BindStruct() names = %q", names)
+	}
+
+	got, err := w.call(t, "state_get_title")
+	if err != nil || string(got.(json.RawMessage)) != `"Inbox"` {
+		t.Fatalf("state_get_title() = %s, %v", got, err)
+	}
+	if _, err := w.call(t, "state_set_count", 5); err != nil {
+		t.Fatalf("state_set_count() unexpected error: %v", err)
+	}
+	if state.Count != 5 {
+		t.Fatalf("Count = %d, want 5", state.Count)
+	}
+	if _, err := w.call(t, "state_set_count", "five"); err == nil {
+		t.Fatal("state_set_count() expected error for wrong type")
+	}
+	if len(changes) != 1 || changes[0] != "count" {
+		t.Fatalf("changes = %q, want [count]", changes)
+	}
+	if last := w.evals[len(w.evals)-1]; last != `window.glaze._emit("state:change", {"field":"count","value":5});` {
+		t.Fatalf("change event = %s", last)
+	}
+
+	all, _ := w.call(t, "state_get")
+	if string(all.(json.RawMessage)) != `{"title":"Inbox","count":5,"tags":null}` {
+		t.Fatalf("state_get() = %s", all)
+	}
+}
+
+func TestBindStructNamespace(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	names, err := BindStruct(w, "state", &appState{}, StructOptions{
+		BindOptions: BindOptions{Namespace: true, Include: []string{"Title"}},
+	})
+	if err != nil {
+		t.Fatalf("BindStruct() unexpected error: %v", err)
+	}
+	if strings.Join(names, ",") != "state.get,state.getTitle,state.setTitle" {
+		t.Fatalf("BindStruct() names = %q", names)
+	}
+	if len(w.inits) != 1 || !strings.Contains(w.inits[0], `ns["setTitle"]`) {
+		t.Fatalf("namespace script = %q", w.inits)
+	}
+}
+
+func TestBindStructErrors(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	for _, ptr := range []any{nil, appState{}, (*appState)(nil), new(int)} {
+		if _, err := BindStruct(w, "s", ptr, StructOptions{}); err == nil {
+			t.Errorf("BindStruct(%T) expected error", ptr)
+		}
+	}
+	if _, err := BindStruct(w, "s", &appState{}, StructOptions{BindOptions: BindOptions{Include: []string{"private"}}}); err == nil {
+		t.Error("BindStruct() expected error for unexported included field")
+	}
+	if _, err := BindStruct(nil, "s", &appState{}, StructOptions{}); err == nil {
+		t.Error("BindStruct() expected error for nil WebView")
+	}
+}
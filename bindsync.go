@@ -0,0 +1,65 @@
+package glaze
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// syncJS defines the synchronous function of BindSync. It is followed by the
+// name and the JSON value as arguments.
+const syncJS = `(function (name, value) {
+  'use strict';
+  var glaze = window.glaze = window.glaze || {};
+  var values = glaze._sync = glaze._sync || {};
+  values[name] = value;
+  if (window[name] && window[name]._glazeSync) { return; }
+  var get = function (key) {
+    var v = values[name];
+    if (arguments.length === 0) { return v; }
+    return v === null || typeof v !== "object" ? undefined : v[key];
+  };
+  get._glazeSync = true;
+  window[name] = get;
+})(`
+
+// BindSync defines name in the page of w as a synchronous function that
+// returns value, for tiny, frequent lookups such as configuration reads
+// where awaiting a promise is overkill. Called with a key, it returns that
+// member of value:
+//
+//	_ = glaze.BindSync(w, "config", map[string]any{"theme": "dark", "fontSize": 14})
+//
+//	// JavaScript
+//	const theme = config("theme");
+//
+// The page cannot block on Go, so the value is computed in Go ahead of time:
+// call BindSync again to publish a new one. Each call registers a script
+// that defines the value in future pages, so push values that change often
+// with Emit instead. value must encode as JSON. Like Bind, BindSync must be
+// called from the UI thread.
+func BindSync(w WebView, name string, value any) error {
+	if w == nil {
+		return errors.New("webview: BindSync requires a non-nil WebView")
+	}
+	if name == "" {
+		return errors.New("webview: BindSync requires a name")
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	_, bound := b.bindings[name]
+	b.mu.Unlock()
+	if bound {
+		return fmt.Errorf("webview: %s is already bound as an asynchronous function", name)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("webview: encode %s: %w", name, err)
+	}
+	js := syncJS + marshalJSON(name) + ", " + string(data) + ");"
+	b.mu.Lock()
+	b.setRuntimeScriptLocked("sync:"+name, js)
+	b.mu.Unlock()
+	w.Eval(js)
+	return nil
+}
@@ -0,0 +1,42 @@
+package glaze
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBindSync(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	if err := BindSync(w, "config", map[string]any{"theme": "dark"}); err != nil {
+		t.Fatalf("BindSync() unexpected error: %v", err)
+	}
+	if len(w.inits) != 1 || !strings.HasSuffix(w.inits[0], `("config", {"theme":"dark"});`) {
+		t.Fatalf("inits = %q, want the config value defined", w.inits)
+	}
+	if len(w.evals) != 1 || w.evals[0] != w.inits[0] {
+		t.Fatalf("evals = %q, want the current page updated", w.evals)
+	}
+
+	if err := BindSync(w, "config", map[string]any{"theme": "light"}); err != nil {
+		t.Fatalf("BindSync() update unexpected error: %v", err)
+	}
+	if last := w.evals[len(w.evals)-1]; !strings.Contains(last, `"light"`) {
+		t.Errorf("update evaluated %q, want the new value", last)
+	}
+
+	_ = w.Bind("notes_list", func() []string { return nil })
+	for _, tt := range []struct {
+		name  string
+		value any
+	}{
+		{"notes_list", 1},
+		{"", 1},
+		{"bad", func() {}},
+	} {
+		if err := BindSync(w, tt.name, tt.value); err == nil {
+			t.Errorf("BindSync(%q) returned no error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,301 @@
+package glaze
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// bridgeReplyName is the hidden binding the injected runtime uses to send
+// evaluation results back to Go.
+const bridgeReplyName = "__glaze_reply"
+
+// defaultEvalTimeout bounds helpers that evaluate JavaScript without taking a
+// context, so a closed window or a navigation cannot block them forever.
+const defaultEvalTimeout = 30 * time.Second
+
+// bridgeRuntimeJS is the glaze JavaScript runtime injected into every page of
+// a window that uses result-returning helpers. It is idempotent so it can be
+// both registered with Init and evaluated into the current page.
+const bridgeRuntimeJS = `(function () {
+  'use strict';
+  if (window.glaze && window.glaze._installed) { return; }
+  var glaze = window.glaze || {};
+  glaze._installed = true;
+  glaze._eval = function (id, src) {
+    Promise.resolve().then(function () {
+      return (0, eval)(src);
+    }).then(function (value) {
+      window.` + bridgeReplyName + `(id, true, value === undefined ? null : value);
+    }, function (err) {
+      window.` + bridgeReplyName + `(id, false, String(err && err.message || err));
+    });
+  };
+  window.glaze = glaze;
+})();`
+
+// bridge holds glaze's per-window JavaScript runtime state. It is created on
+// demand by helpers that need more than the fire-and-forget Eval and works
+// with any WebView implementation, since it only relies on Bind, Init, Eval
+// and Dispatch.
+type bridge struct {
+	w WebView
+
+	mu        sync.Mutex
+	installed bool
+	seq       uint64
+	pending   map[string]chan evalReply
+	hidden    map[string]bool
+	scripts   map[string]bool
+
+	// Set by IsolateRuntime.
+	isolated bool
+
+	// Whether window.glazeReady is installed.
+	readyInstalled bool
+
+	// Scripts run at the start of every page, in run order, the sequence
+	// numbers ordering them, and the engine handles of those registered, in
+	// registration order.
+	inits       []initScript
+	initSeq     uint64
+	engineInits []uintptr
+
+	// Window state left by RestoreWindow for the page at each URL, and
+	// the App the window belongs to, which session URLs are relative to.
+	restores map[string]WindowState
+	app      *App
+
+	// Types of the functions bound in the window, for Manifest, and the
+	// functions themselves, for MockBindings to restore.
+	bindings map[string]reflect.Type
+	funcs    map[string]any
+
+	// Names bound by BindMethods and RebindMethods, by prefix, and the
+	// options and BindInterface interface they were bound with, for
+	// ReplaceService.
+	services    map[string][]string
+	serviceOpts map[string]BindOptions
+	contracts   map[string]reflect.Type
+
+	// Aliases defined by BindAlias.
+	aliases map[string]aliasTarget
+
+	// Codecs set by SetCodec and BindingOptions.Codec, by binding name.
+	codec  Codec
+	codecs map[string]Codec
+
+	// Transactions set up by SetTransactions: the begin function, the open
+	// transactions by ID and the transaction of each call running in one.
+	txBegin func() (Transaction, error)
+	txSeq   uint64
+	txs     map[string]*Tx
+	txCalls map[string]*Tx
+
+	// Result compression set by SetCompression, and the document that last
+	// reported it can decompress.
+	compressMin int
+	compressDoc string
+
+	// Call statistics by binding name and the OnCall hook.
+	calls  map[string]*BindingStats
+	onCall func(CallRecord)
+
+	// Recent calls and emitted event counts, kept for DevToolsHandler once
+	// historySize is set.
+	history     []CallRecord
+	historySize int
+	topics      map[string]uint64
+
+	// Handler installed by OnPaste.
+	paste PasteFunc
+
+	// Handler installed by OnComposition.
+	composition func(CompositionEvent)
+
+	// Channels exposed with BindChannel, by name.
+	channels map[string]*boundChannel
+
+	// Scanner started by StartScanner.
+	scan *scanner
+
+	// Handler installed by OnEvalError.
+	evalError func(EvalError)
+
+	// Handler installed by OnConsole.
+	console func(ConsoleMessage)
+
+	// Handler installed by OnPageError.
+	pageError func(PageError)
+
+	// Remote started by StartRemote, which receives emitted events.
+	remote *Remote
+
+	// Policy set by SetFramePolicy, and the tag the top frame adds to its
+	// calls when the policy is TopFrameOnly.
+	frames   *FramePolicy
+	frameTag string
+
+	// Recording started by StartRecording.
+	recording *recorder
+
+	// Overlay state restored on each page by ShowLoading.
+	loading loadingState
+
+	// Page URL last reported for CallInfo.
+	pageURL string
+
+	// ID of the current document and the functions registered with
+	// onDocument, by key.
+	document string
+	docHooks map[string]func(string)
+
+	// Last NavigateWithErrorPage target, used by the error page retry button.
+	navURL  string
+	navPage ErrorPageFunc
+}
+
+// evalReply carries the outcome of one evaluation back to the waiting caller.
+type evalReply struct {
+	value json.RawMessage
+	err   error
+}
+
+// bridges maps each WebView to its bridge.
+var bridges sync.Map
+
+// bridgeFor returns the bridge attached to w, creating it if needed.
+func bridgeFor(w WebView) *bridge {
+	if b, ok := bridges.Load(w); ok {
+		return b.(*bridge)
+	}
+	b, _ := bridges.LoadOrStore(w, &bridge{w: w, pending: make(map[string]chan evalReply)})
+	return b.(*bridge)
+}
+
+// forgetBridge drops the bridge attached to w once the window is destroyed,
+// letting features release what the window held.
+func forgetBridge(w WebView) {
+	if b, ok := bridges.LoadAndDelete(w); ok {
+		b.(*bridge).runDocumentHooks("")
+	}
+}
+
+// install binds the reply function and injects the runtime. It must run on
+// the UI thread and is a no-op after the first successful call.
+func (b *bridge) install() error {
+	if err := b.bindHidden(bridgeReplyName, b.reply); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.installed {
+		return nil
+	}
+	b.setRuntimeScriptLocked("runtime", bridgeRuntimeJS)
+	b.w.Eval(bridgeRuntimeJS)
+	b.installed = true
+	b.resealLocked()
+	return nil
+}
+
+// reply is bound as bridgeReplyName and resolves a pending evaluation.
+func (b *bridge) reply(id string, ok bool, value json.RawMessage) {
+	b.mu.Lock()
+	ch, found := b.pending[id]
+	delete(b.pending, id)
+	b.mu.Unlock()
+	if !found {
+		return
+	}
+	if !ok {
+		var msg string
+		_ = json.Unmarshal(value, &msg)
+		ch <- evalReply{err: errors.New(msg)}
+		return
+	}
+	ch <- evalReply{value: value}
+}
+
+// eval evaluates js in the page and waits for its (awaited) value. It must not
+// be called from the UI thread, since the reply is delivered through it.
+func (b *bridge) eval(ctx context.Context, js string) (json.RawMessage, error) {
+	ch := make(chan evalReply, 1)
+	b.mu.Lock()
+	b.seq++
+	id := strconv.FormatUint(b.seq, 10)
+	b.pending[id] = ch
+	b.mu.Unlock()
+
+	script := "window.glaze._eval(" + marshalJSON(id) + ", " + marshalJSON(js) + ");"
+	installErr := make(chan error, 1)
+	b.w.Dispatch(func() {
+		if err := b.install(); err != nil {
+			installErr <- err
+			return
+		}
+		b.w.Eval(script)
+	})
+
+	select {
+	case r := <-ch:
+		return r.value, r.err
+	case err := <-installErr:
+		b.cancel(id)
+		return nil, err
+	case <-ctx.Done():
+		b.cancel(id)
+		return nil, ctx.Err()
+	}
+}
+
+// bindHidden binds an internal glaze function once per window. It must run
+// on the UI thread.
+func (b *bridge) bindHidden(name string, fn any) error {
+	b.mu.Lock()
+	bound := b.hidden[name]
+	b.mu.Unlock()
+	if bound {
+		return nil
+	}
+	// Bind records the binding on this bridge, so b.mu must not be held.
+	if err := b.w.Bind(name, fn); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.hidden == nil {
+		b.hidden = make(map[string]bool)
+	}
+	b.hidden[name] = true
+	b.resealLocked()
+	return nil
+}
+
+// injectScript registers js as the runtime script for name and evaluates it
+// into the current page, once per window and name. It must run on the UI
+// thread and js must be idempotent.
+func (b *bridge) injectScript(name, js string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.scripts[name] {
+		return
+	}
+	if b.scripts == nil {
+		b.scripts = make(map[string]bool)
+	}
+	b.scripts[name] = true
+	b.setRuntimeScriptLocked(name, js)
+	b.w.Eval(js)
+	b.resealLocked()
+}
+
+func (b *bridge) cancel(id string) {
+	b.mu.Lock()
+	delete(b.pending, id)
+	b.mu.Unlock()
+}
@@ -0,0 +1,242 @@
+package glaze
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// fakeWebView records the calls glaze helpers make and lets tests play the
+// role of the page by answering evaluations.
+type fakeWebView struct {
+	mu        sync.Mutex
+	bound     map[string]any
+	inits     []string
+	handles   []uintptr // of inits, as registered with addScript
+	nextInit  uintptr
+	evals     []string
+	navigated []string
+	html      string
+	title     string
+	onEval    func(js string)
+	failBind  string
+}
+
+func (f *fakeWebView) Run() {}
+
+func (f *fakeWebView) Terminate() {}
+
+func (f *fakeWebView) Dispatch(fn func()) { fn() }
+
+func (f *fakeWebView) Destroy() { forgetBridge(f) }
+
+func (f *fakeWebView) Window() unsafe.Pointer { return nil }
+
+func (f *fakeWebView) SetTitle(title string) {
+	f.mu.Lock()
+	f.title = title
+	f.mu.Unlock()
+}
+
+func (f *fakeWebView) SetSize(_, _ int, _ Hint) {}
+
+func (f *fakeWebView) Navigate(url string) {
+	f.mu.Lock()
+	f.navigated = append(f.navigated, url)
+	f.mu.Unlock()
+}
+
+func (f *fakeWebView) SetHtml(html string) {
+	f.mu.Lock()
+	f.html = html
+	f.mu.Unlock()
+}
+
+func (f *fakeWebView) Init(js string) { bridgeFor(f).initScript(js) }
+
+func (f *fakeWebView) initNative(js string) {
+	f.mu.Lock()
+	f.inits = append(f.inits, js)
+	f.handles = append(f.handles, 0)
+	f.mu.Unlock()
+}
+
+func (f *fakeWebView) addScript(js string) (uintptr, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.nextInit++
+	f.inits = append(f.inits, js)
+	f.handles = append(f.handles, f.nextInit)
+	return f.nextInit, nil
+}
+
+func (f *fakeWebView) removeScript(handle uintptr) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if i := slices.Index(f.handles, handle); i >= 0 {
+		f.inits = slices.Delete(f.inits, i, i+1)
+		f.handles = slices.Delete(f.handles, i, i+1)
+	}
+}
+
+func (f *fakeWebView) Eval(js string) {
+	f.mu.Lock()
+	f.evals = append(f.evals, js)
+	onEval := f.onEval
+	f.mu.Unlock()
+	if onEval != nil {
+		onEval(js)
+	}
+}
+
+func (f *fakeWebView) Bind(name string, fn any) error {
+	f.mu.Lock()
+	if f.bound == nil {
+		f.bound = make(map[string]any)
+	}
+	if _, exists := f.bound[name]; exists {
+		f.mu.Unlock()
+		return errors.New("function name already bound")
+	}
+	if name == f.failBind {
+		f.mu.Unlock()
+		return errors.New("bind refused")
+	}
+	f.bound[name] = fn
+	f.mu.Unlock()
+	bridgeFor(f).recordBinding(name, fn)
+	return nil
+}
+
+func (f *fakeWebView) Unbind(name string) error {
+	f.mu.Lock()
+	if _, exists := f.bound[name]; !exists {
+		f.mu.Unlock()
+		return errors.New("function name not bound")
+	}
+	delete(f.bound, name)
+	f.mu.Unlock()
+	bridgeFor(f).forgetBinding(name)
+	return nil
+}
+
+// call invokes a bound function the way the native bridge would, with the
+// arguments JSON-encoded into a request array.
+func (f *fakeWebView) call(t *testing.T, name string, args ...any) (any, error) {
+	t.Helper()
+	f.mu.Lock()
+	bound, ok := f.bound[name]
+	f.mu.Unlock()
+	if !ok {
+		t.Fatalf("%s is not bound", name)
+	}
+	fn, err := makeBinding(f, name, bound)
+	if err != nil {
+		t.Fatalf("makeBinding(%s) unexpected error: %v", name, err)
+	}
+	fn = bridgeFor(f).instrument(name, fn)
+	if args == nil {
+		args = []any{}
+	}
+	req, err := json.Marshal(args)
+	if err != nil {
+		t.Fatalf("marshal args: %v", err)
+	}
+	return fn("seq", string(req))
+}
+
+// answerEvals makes the fake page answer every glaze evaluation with the
+// value (or error) returned by page, which receives the evaluated source.
+func (f *fakeWebView) answerEvals(t *testing.T, page func(src string) (any, error)) {
+	t.Helper()
+	const prefix = "window.glaze._eval("
+	f.mu.Lock()
+	f.onEval = func(js string) {
+		if !strings.HasPrefix(js, prefix) {
+			return
+		}
+		var args [2]string
+		if err := json.Unmarshal([]byte("["+strings.TrimSuffix(js[len(prefix):], ");")+"]"), &args); err != nil {
+			t.Errorf("decode eval call: %v", err)
+			return
+		}
+		value, err := page(args[1])
+		go func() {
+			if err != nil {
+				_, _ = f.call(t, bridgeReplyName, args[0], false, err.Error())
+				return
+			}
+			_, _ = f.call(t, bridgeReplyName, args[0], true, value)
+		}()
+	}
+	f.mu.Unlock()
+}
+
+func TestBridgeEvalInstallsRuntimeOnce(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	w.answerEvals(t, func(string) (any, error) { return 1, nil })
+
+	for range 2 {
+		if _, err := bridgeFor(w).eval(context.Background(), "1"); err != nil {
+			t.Fatalf("eval() unexpected error: %v", err)
+		}
+	}
+	if len(w.inits) != 1 || w.inits[0] != bridgeRuntimeJS {
+		t.Fatalf("runtime init scripts = %d, want exactly one runtime", len(w.inits))
+	}
+	if _, ok := w.bound[bridgeReplyName]; !ok {
+		t.Fatalf("%s was not bound", bridgeReplyName)
+	}
+}
+
+func TestBridgeEvalReturnsValue(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	w.answerEvals(t, func(src string) (any, error) {
+		if src != "document.title" {
+			t.Errorf("evaluated source = %q, want %q", src, "document.title")
+		}
+		return "Hello", nil
+	})
+
+	raw, err := bridgeFor(w).eval(context.Background(), "document.title")
+	if err != nil {
+		t.Fatalf("eval() unexpected error: %v", err)
+	}
+	if string(raw) != `"Hello"` {
+		t.Fatalf("eval() = %s, want %q", raw, `"Hello"`)
+	}
+}
+
+func TestBridgeEvalReturnsPageError(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	w.answerEvals(t, func(string) (any, error) { return nil, errors.New("ReferenceError: x is not defined") })
+
+	_, err := bridgeFor(w).eval(context.Background(), "x")
+	if err == nil || err.Error() != "ReferenceError: x is not defined" {
+		t.Fatalf("eval() error = %v, want page error", err)
+	}
+}
+
+func TestBridgeEvalHonoursContext(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := bridgeFor(w).eval(ctx, "1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("eval() error = %v, want deadline exceeded", err)
+	}
+	if n := len(bridgeFor(w).pending); n != 0 {
+		t.Fatalf("pending evaluations = %d, want 0", n)
+	}
+}
@@ -0,0 +1,533 @@
+package glaze
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultCacheEntries is the default CacheOptions.MaxEntries.
+const defaultCacheEntries = 1000
+
+// CacheEntry is a cached result of a call.
+type CacheEntry struct {
+	// Value is the JSON encoding of the result.
+	Value json.RawMessage `json:"value"`
+
+	// Stored is when the result was received.
+	Stored time.Time `json:"stored"`
+}
+
+// CacheStore keeps cached results beyond the memory of a CallCache, such as
+// on disk or in a database table, so they survive restarts.
+// NewSQLCacheStore returns one backed by an SQLite table and
+// NewFileCacheStore one backed by a JSON file.
+type CacheStore interface {
+	// Get returns the entry stored under key.
+	Get(key string) (CacheEntry, bool, error)
+
+	// Put stores e under key.
+	Put(key string, e CacheEntry) error
+
+	// Delete removes the entries whose keys start with prefix.
+	Delete(prefix string) error
+}
+
+// CacheOptions configures BindCached.
+type CacheOptions struct {
+	// TTL is how long a result is served without calling the function
+	// again. Zero serves it until it is invalidated.
+	TTL time.Duration
+
+	// Stale is how long after TTL a result is still served while the
+	// function is called again in the background (stale-while-revalidate),
+	// so the page stays responsive when the remote API is slow or
+	// unreachable. Zero calls the function as soon as TTL elapses.
+	Stale time.Duration
+
+	// Store keeps results beyond memory. Nil keeps them in memory only.
+	Store CacheStore
+
+	// MaxEntries bounds the results kept in memory; the least recently
+	// used are dropped first, and stay in Store. Zero means 1000. Results
+	// past TTL and Stale are dropped from memory when next looked up.
+	MaxEntries int
+}
+
+// CallCache is a read-through cache in front of a bound function, created
+// by BindCached.
+type CallCache struct {
+	name  string
+	fn    reflect.Value
+	typ   reflect.Type
+	skip  []bool
+	opts  CacheOptions
+	now   func() time.Time
+	bound any
+
+	mu       sync.Mutex
+	entries  map[string]*list.Element // of *cacheItem, by key
+	recent   *list.List               // most recently used first
+	inflight map[string]bool
+}
+
+// cacheItem is a result a CallCache keeps in memory.
+type cacheItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// BindCached binds f as name in w, like w.Bind, with its results cached by
+// arguments, for wrappers of remote APIs that should stay responsive on
+// flaky networks:
+//
+//	notes, err := glaze.BindCached(w, "notes_list", api.ListNotes, glaze.CacheOptions{
+//		TTL:   time.Minute,
+//		Stale: time.Hour,
+//		Store: store,
+//	})
+//	// After a change:
+//	_ = notes.InvalidateAll()
+//
+// f must return a value, optionally followed by an error; errors are never
+// cached. Besides JavaScript arguments it may take a context.Context,
+// which a background refresh receives as context.Background, and CallInfo.
+// Results are kept in their JSON encoding. Like Bind, BindCached must be
+// called from the UI thread.
+func BindCached(w WebView, name string, f any, opts CacheOptions) (*CallCache, error) {
+	if w == nil {
+		return nil, errors.New("webview: BindCached requires a non-nil WebView")
+	}
+	c, err := newCallCache(name, f, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Bind(name, c.bound); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func newCallCache(name string, f any, opts CacheOptions) (*CallCache, error) {
+	v := reflect.ValueOf(f)
+	if v.Kind() != reflect.Func {
+		return nil, errors.New("webview: BindCached requires a function")
+	}
+	t := v.Type()
+	switch {
+	case t.NumOut() == 1 && !t.Out(0).Implements(errorType):
+	case t.NumOut() == 2 && !t.Out(0).Implements(errorType) && t.Out(1).Implements(errorType):
+	default:
+		return nil, fmt.Errorf("webview: BindCached requires a function returning a value, got %s", t)
+	}
+	skip := make([]bool, t.NumIn())
+	for i := range skip {
+		in := t.In(i)
+		if _, injected := injectedParams[in]; injected {
+			if in != contextType && in != reflect.TypeFor[CallInfo]() {
+				return nil, fmt.Errorf("webview: BindCached does not support %s parameters", in)
+			}
+			skip[i] = true
+		}
+	}
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = defaultCacheEntries
+	}
+	c := &CallCache{
+		name:     name,
+		fn:       v,
+		typ:      t,
+		skip:     skip,
+		opts:     opts,
+		now:      time.Now,
+		entries:  make(map[string]*list.Element),
+		recent:   list.New(),
+		inflight: make(map[string]bool),
+	}
+	c.bound = reflect.MakeFunc(t, c.call).Interface()
+	return c, nil
+}
+
+// call serves a call to the bound function.
+func (c *CallCache) call(args []reflect.Value) []reflect.Value {
+	key, err := c.key(args)
+	if err != nil {
+		return c.invoke(args)
+	}
+	if entry, ok := c.lookup(key); ok {
+		age := c.now().Sub(entry.Stored)
+		fresh := c.opts.TTL <= 0 || age <= c.opts.TTL
+		if fresh || age <= c.opts.TTL+c.opts.Stale {
+			if res, ok := c.decode(entry); ok {
+				if !fresh {
+					c.refresh(key, args)
+				}
+				return res
+			}
+		}
+	}
+	res := c.invoke(args)
+	c.keep(key, res)
+	return res
+}
+
+// key returns the cache key of a call with args.
+func (c *CallCache) key(args []reflect.Value) (string, error) {
+	values := make([]any, 0, len(args))
+	for i, a := range args {
+		if !c.skip[i] {
+			values = append(values, a.Interface())
+		}
+	}
+	return c.keyOf(values)
+}
+
+func (c *CallCache) keyOf(values []any) (string, error) {
+	if values == nil {
+		values = []any{}
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return c.name + "\x00" + string(data), nil
+}
+
+func (c *CallCache) invoke(args []reflect.Value) []reflect.Value {
+	if c.typ.IsVariadic() {
+		return c.fn.CallSlice(args)
+	}
+	return c.fn.Call(args)
+}
+
+// keep caches the results res of a call under key, unless it failed.
+func (c *CallCache) keep(key string, res []reflect.Value) {
+	if len(res) == 2 && !res[1].IsNil() {
+		return
+	}
+	data, err := json.Marshal(res[0].Interface())
+	if err != nil {
+		return
+	}
+	entry := CacheEntry{Value: data, Stored: c.now()}
+	c.remember(key, entry)
+	if c.opts.Store != nil {
+		// The store is only a second tier; the result is still served.
+		_ = c.opts.Store.Put(key, entry)
+	}
+}
+
+func (c *CallCache) lookup(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		item := el.Value.(*cacheItem)
+		if !c.expired(item.entry) {
+			c.recent.MoveToFront(el)
+			c.mu.Unlock()
+			return item.entry, true
+		}
+		c.recent.Remove(el)
+		delete(c.entries, key)
+	}
+	c.mu.Unlock()
+	if c.opts.Store == nil {
+		return CacheEntry{}, false
+	}
+	entry, ok, err := c.opts.Store.Get(key)
+	if err != nil || !ok || c.expired(entry) {
+		return CacheEntry{}, false
+	}
+	c.remember(key, entry)
+	return entry, true
+}
+
+// expired reports whether entry is past TTL and Stale and so no longer
+// served.
+func (c *CallCache) expired(entry CacheEntry) bool {
+	return c.opts.TTL > 0 && c.now().Sub(entry.Stored) > c.opts.TTL+c.opts.Stale
+}
+
+// remember keeps entry in memory under key, dropping the least recently
+// used entries past MaxEntries.
+func (c *CallCache) remember(key string, entry CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*cacheItem).entry = entry
+		c.recent.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.recent.PushFront(&cacheItem{key: key, entry: entry})
+	for c.recent.Len() > c.opts.MaxEntries {
+		oldest := c.recent.Back()
+		c.recent.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheItem).key)
+	}
+}
+
+// decode returns entry as the results of the function.
+func (c *CallCache) decode(entry CacheEntry) ([]reflect.Value, bool) {
+	v := reflect.New(c.typ.Out(0))
+	if err := json.Unmarshal(entry.Value, v.Interface()); err != nil {
+		return nil, false
+	}
+	res := []reflect.Value{v.Elem()}
+	if c.typ.NumOut() == 2 {
+		res = append(res, reflect.Zero(c.typ.Out(1)))
+	}
+	return res, true
+}
+
+// refresh calls the function again in the background to replace the stale
+// entry under key.
+func (c *CallCache) refresh(key string, args []reflect.Value) {
+	c.mu.Lock()
+	busy := c.inflight[key]
+	c.inflight[key] = true
+	c.mu.Unlock()
+	if busy {
+		return
+	}
+	args = append([]reflect.Value(nil), args...)
+	for i, a := range args {
+		if a.Type() == contextType {
+			args[i] = reflect.ValueOf(context.Background())
+		}
+	}
+	go func() {
+		defer func() {
+			c.mu.Lock()
+			delete(c.inflight, key)
+			c.mu.Unlock()
+		}()
+		c.keep(key, c.invoke(args))
+	}()
+}
+
+// Invalidate drops the result cached for a call with args, given as
+// JavaScript passes them, without injected parameters. Variadic arguments
+// are passed as one slice.
+func (c *CallCache) Invalidate(args ...any) error {
+	key, err := c.keyOf(args)
+	if err != nil {
+		return fmt.Errorf("webview: invalidate %s: %w", c.name, err)
+	}
+	return c.drop(key)
+}
+
+// InvalidateAll drops every cached result of the function.
+func (c *CallCache) InvalidateAll() error {
+	return c.drop(c.name + "\x00")
+}
+
+func (c *CallCache) drop(prefix string) error {
+	c.mu.Lock()
+	for key, el := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			c.recent.Remove(el)
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+	if c.opts.Store != nil {
+		if err := c.opts.Store.Delete(prefix); err != nil {
+			return fmt.Errorf("webview: invalidate %s: %w", c.name, err)
+		}
+	}
+	return nil
+}
+
+// sqlTableName is a table name NewSQLCacheStore accepts.
+var sqlTableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// sqlCacheStore is a CacheStore kept in an SQL table.
+type sqlCacheStore struct {
+	db                  *sql.DB
+	get, put, deleteSQL string
+}
+
+// NewSQLCacheStore returns a CacheStore kept in table of db, an SQLite
+// database opened with a driver of the app's choice, such as
+// modernc.org/sqlite or github.com/mattn/go-sqlite3; glaze imports none.
+// The table is created if it does not exist, with the key and value as
+// BLOBs and the time stored as Unix nanoseconds. Entries are written as
+// they come, so the cache scales to large result sets.
+func NewSQLCacheStore(db *sql.DB, table string) (CacheStore, error) {
+	if db == nil {
+		return nil, errors.New("webview: NewSQLCacheStore requires a non-nil database")
+	}
+	if !sqlTableName.MatchString(table) {
+		return nil, fmt.Errorf("webview: invalid cache table name %q", table)
+	}
+	create := "CREATE TABLE IF NOT EXISTS " + table + " (key BLOB PRIMARY KEY, value BLOB NOT NULL, stored INTEGER NOT NULL)"
+	if _, err := db.Exec(create); err != nil {
+		return nil, fmt.Errorf("webview: create cache table: %w", err)
+	}
+	return &sqlCacheStore{
+		db:  db,
+		get: "SELECT value, stored FROM " + table + " WHERE key = ?",
+		put: "INSERT INTO " + table + " (key, value, stored) VALUES (?, ?, ?) " +
+			"ON CONFLICT (key) DO UPDATE SET value = excluded.value, stored = excluded.stored",
+		// substr counts bytes on BLOBs, so the prefix needs no escaping as
+		// it would with LIKE.
+		deleteSQL: "DELETE FROM " + table + " WHERE substr(key, 1, ?) = ?",
+	}, nil
+}
+
+func (s *sqlCacheStore) Get(key string) (CacheEntry, bool, error) {
+	var value []byte
+	var stored int64
+	err := s.db.QueryRow(s.get, []byte(key)).Scan(&value, &stored)
+	if errors.Is(err, sql.ErrNoRows) {
+		return CacheEntry{}, false, nil
+	}
+	if err != nil {
+		return CacheEntry{}, false, fmt.Errorf("webview: read cache: %w", err)
+	}
+	return CacheEntry{Value: value, Stored: time.Unix(0, stored)}, true, nil
+}
+
+func (s *sqlCacheStore) Put(key string, e CacheEntry) error {
+	if _, err := s.db.Exec(s.put, []byte(key), []byte(e.Value), e.Stored.UnixNano()); err != nil {
+		return fmt.Errorf("webview: store cache: %w", err)
+	}
+	return nil
+}
+
+func (s *sqlCacheStore) Delete(prefix string) error {
+	if _, err := s.db.Exec(s.deleteSQL, len(prefix), []byte(prefix)); err != nil {
+		return fmt.Errorf("webview: delete cache: %w", err)
+	}
+	return nil
+}
+
+// fileCacheDelay is how long FileCacheStore gathers changes before writing
+// them.
+var fileCacheDelay = time.Second
+
+// FileCacheStore is a CacheStore kept in one JSON file, created by
+// NewFileCacheStore. Changes are gathered and written at most once per
+// second, since each write rewrites the whole file; call Flush before the
+// app exits to write the last ones.
+type FileCacheStore struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string]CacheEntry
+	dirty   bool
+	timer   *time.Timer
+	err     error // of the last background write
+}
+
+// NewFileCacheStore returns a CacheStore kept in the JSON file at path,
+// which suits caches of up to a few thousand entries; use NewSQLCacheStore
+// beyond that. A missing or damaged file starts an empty cache.
+func NewFileCacheStore(path string) (*FileCacheStore, error) {
+	s := &FileCacheStore{path: path, entries: make(map[string]CacheEntry)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("webview: read cache: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.entries); err != nil || s.entries == nil {
+		// A damaged cache is dropped; it is only a convenience.
+		s.entries = make(map[string]CacheEntry)
+	}
+	return s, nil
+}
+
+func (s *FileCacheStore) Get(key string) (CacheEntry, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[key]
+	return e, ok, nil
+}
+
+// Put stores e under key. The file is written later; an error from an
+// earlier write is returned.
+func (s *FileCacheStore) Put(key string, e CacheEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = e
+	return s.changedLocked()
+}
+
+// Delete removes the entries whose keys start with prefix. The file is
+// written later; an error from an earlier write is returned.
+func (s *FileCacheStore) Delete(prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.entries, key)
+		}
+	}
+	return s.changedLocked()
+}
+
+// Flush writes pending changes to the file now.
+func (s *FileCacheStore) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.timer != nil {
+		s.timer.Stop()
+		s.timer = nil
+	}
+	err := s.err
+	s.err = nil
+	if s.dirty {
+		err = s.storeLocked()
+	}
+	return err
+}
+
+// changedLocked schedules a write and returns the error of the last one.
+// s.mu must be held.
+func (s *FileCacheStore) changedLocked() error {
+	s.dirty = true
+	if s.timer == nil {
+		s.timer = time.AfterFunc(fileCacheDelay, func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.timer = nil
+			if s.dirty {
+				s.err = s.storeLocked()
+			}
+		})
+	}
+	err := s.err
+	s.err = nil
+	return err
+}
+
+// storeLocked writes the entries to the file. s.mu must be held.
+func (s *FileCacheStore) storeLocked() error {
+	data, err := json.Marshal(s.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("webview: store cache: %w", err)
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("webview: store cache: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("webview: store cache: %w", err)
+	}
+	s.dirty = false
+	return nil
+}
@@ -0,0 +1,343 @@
+package glaze
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBindCached(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	var calls atomic.Int64
+	var failing atomic.Bool
+	refreshed := make(chan struct{}, 1)
+	type note struct {
+		ID      int   `json:"id"`
+		Version int64 `json:"version"`
+	}
+	get := func(ctx context.Context, id int) (note, error) {
+		n := calls.Add(1)
+		if ctx.Err() != nil {
+			t.Error("function received a cancelled context")
+		}
+		if failing.Load() {
+			return note{}, errors.New("offline")
+		}
+		select {
+		case refreshed <- struct{}{}:
+		default:
+		}
+		return note{ID: id, Version: n}, nil
+	}
+
+	cache, err := BindCached(w, "notes_get", get, CacheOptions{TTL: time.Minute, Stale: time.Hour})
+	if err != nil {
+		t.Fatalf("BindCached() unexpected error: %v", err)
+	}
+	var clock atomic.Int64
+	start := time.Unix(1_700_000_000, 0)
+	cache.now = func() time.Time { return start.Add(time.Duration(clock.Load())) }
+
+	version := func(args ...any) int64 {
+		t.Helper()
+		got, err := w.call(t, "notes_get", args...)
+		if err != nil {
+			t.Fatalf("notes_get(%v) unexpected error: %v", args, err)
+		}
+		return got.(note).Version
+	}
+	if v := version(1); v != 1 {
+		t.Fatalf("first call version = %v, want 1", v)
+	}
+	if v := version(1); v != 1 || calls.Load() != 1 {
+		t.Fatalf("cached call version = %v after %d calls, want 1 from the cache", v, calls.Load())
+	}
+	if v := version(2); v != 2 {
+		t.Fatalf("other arguments version = %v, want a new call", v)
+	}
+
+	// Stale: served from the cache while refreshed in the background.
+	select {
+	case <-refreshed:
+	default:
+	}
+	clock.Store(int64(2 * time.Minute))
+	if v := version(1); v != 1 {
+		t.Fatalf("stale call version = %v, want the cached 1", v)
+	}
+	select {
+	case <-refreshed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stale entry was not refreshed")
+	}
+	waitFor(t, func() bool { return version(1) == 3 })
+
+	// Expired: called again; errors are not cached.
+	clock.Store(int64(3 * time.Hour))
+	failing.Store(true)
+	if _, err := w.call(t, "notes_get", 1); err == nil {
+		t.Fatal("expired call returned no error while the function fails")
+	}
+	failing.Store(false)
+
+	if err := cache.Invalidate(2); err != nil {
+		t.Fatalf("Invalidate() unexpected error: %v", err)
+	}
+	before := calls.Load()
+	version(2)
+	if calls.Load() != before+1 {
+		t.Error("invalidated entry was served from the cache")
+	}
+	if err := cache.InvalidateAll(); err != nil {
+		t.Fatalf("InvalidateAll() unexpected error: %v", err)
+	}
+	if len(cache.entries) != 0 {
+		t.Errorf("entries after InvalidateAll = %d, want 0", len(cache.entries))
+	}
+}
+
+func TestBindCachedStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	store, err := NewFileCacheStore(path)
+	if err != nil {
+		t.Fatalf("NewFileCacheStore() unexpected error: %v", err)
+	}
+	w := &fakeWebView{}
+	defer w.Destroy()
+	if _, err := BindCached(w, "answer", func(q string) string { return "42 for " + q }, CacheOptions{Store: store}); err != nil {
+		t.Fatalf("BindCached() unexpected error: %v", err)
+	}
+	if _, err := w.call(t, "answer", "life"); err != nil {
+		t.Fatal(err)
+	}
+	// Writes are batched; Flush writes them now.
+	if reloaded, _ := NewFileCacheStore(path); len(reloaded.entries) != 0 {
+		t.Error("file written on every Put, want batched writes")
+	}
+	if err := store.Flush(); err != nil {
+		t.Fatalf("Flush() unexpected error: %v", err)
+	}
+
+	// A new window and cache read the result from the file.
+	reloaded, err := NewFileCacheStore(path)
+	if err != nil {
+		t.Fatalf("NewFileCacheStore() reload unexpected error: %v", err)
+	}
+	w2 := &fakeWebView{}
+	defer w2.Destroy()
+	if _, err := BindCached(w2, "answer", func(string) string { t.Error("function called despite stored result"); return "" }, CacheOptions{Store: reloaded}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := w2.call(t, "answer", "life")
+	if err != nil || got != "42 for life" {
+		t.Fatalf("stored call = %v, %v; want the stored result", got, err)
+	}
+}
+
+func TestBindCachedRejects(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	for name, f := range map[string]any{
+		"no result":    func() {},
+		"only error":   func() error { return nil },
+		"not function": 1,
+		"progress":     func(*Progress) int { return 0 },
+	} {
+		if _, err := BindCached(w, "f", f, CacheOptions{}); err == nil {
+			t.Errorf("%s: BindCached() returned no error", name)
+		}
+	}
+}
+
+func TestFileCacheStoreWritesLater(t *testing.T) {
+	old := fileCacheDelay
+	fileCacheDelay = 10 * time.Millisecond
+	defer func() { fileCacheDelay = old }()
+
+	path := filepath.Join(t.TempDir(), "cache.json")
+	store, _ := NewFileCacheStore(path)
+	for i := range 100 {
+		if err := store.Put(strings.Repeat("k", i+1), CacheEntry{Value: []byte("1")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor(t, func() bool {
+		reloaded, _ := NewFileCacheStore(path)
+		return len(reloaded.entries) == 100
+	})
+}
+
+func TestBindCachedBoundsMemory(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	store := newTestSQLStore(t)
+	var calls atomic.Int64
+	cache, err := BindCached(w, "square", func(n int) int { calls.Add(1); return n * n }, CacheOptions{TTL: time.Minute, MaxEntries: 2, Store: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var clock atomic.Int64
+	start := time.Unix(1_700_000_000, 0)
+	cache.now = func() time.Time { return start.Add(time.Duration(clock.Load())) }
+
+	for _, n := range []int{1, 2, 3} {
+		_, _ = w.call(t, "square", n)
+	}
+	if len(cache.entries) != 2 || cache.entries[`square`+"\x00"+`[1]`] != nil {
+		t.Fatalf("entries = %d, want the 2 most recent", len(cache.entries))
+	}
+	// The dropped result is still in the store.
+	if got, _ := w.call(t, "square", 1); got != 1 || calls.Load() != 3 {
+		t.Fatalf("square(1) = %v after %d calls, want it from the store", got, calls.Load())
+	}
+
+	// Expired results leave memory when looked up.
+	clock.Store(int64(2 * time.Minute))
+	_, _ = w.call(t, "square", 3)
+	if calls.Load() != 4 {
+		t.Fatalf("calls = %d, want the expired result called again", calls.Load())
+	}
+	if err := cache.InvalidateAll(); err != nil {
+		t.Fatal(err)
+	}
+	if len(cache.entries) != 0 || cache.recent.Len() != 0 {
+		t.Error("InvalidateAll() left entries in memory")
+	}
+}
+
+func TestSQLCacheStore(t *testing.T) {
+	store := newTestSQLStore(t)
+	stored := time.Unix(1_700_000_000, 5)
+	for _, key := range []string{"a\x00[1]", "a\x00[2]", "b\x00[1]"} {
+		if err := store.Put(key, CacheEntry{Value: []byte(`"` + key[:1] + `"`), Stored: stored}); err != nil {
+			t.Fatalf("Put(%q) unexpected error: %v", key, err)
+		}
+	}
+	e, ok, err := store.Get("a\x00[2]")
+	if err != nil || !ok || string(e.Value) != `"a"` || !e.Stored.Equal(stored) {
+		t.Fatalf("Get() = %+v, %v, %v", e, ok, err)
+	}
+	if err := store.Delete("a\x00"); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+	if _, ok, _ := store.Get("a\x00[1]"); ok {
+		t.Error("Delete() kept an entry with the prefix")
+	}
+	if _, ok, _ := store.Get("b\x00[1]"); !ok {
+		t.Error("Delete() removed an entry without the prefix")
+	}
+
+	db, _ := sql.Open("glaze-test-sql", t.Name()+"-bad")
+	defer db.Close()
+	if _, err := NewSQLCacheStore(db, "cache; DROP TABLE x"); err == nil {
+		t.Error("NewSQLCacheStore() accepted an invalid table name")
+	}
+}
+
+// newTestSQLStore returns a CacheStore on an in-memory fake of the SQL the
+// store runs.
+func newTestSQLStore(t *testing.T) CacheStore {
+	t.Helper()
+	registerTestSQL.Do(func() { sql.Register("glaze-test-sql", testSQLDriver{}) })
+	db, err := sql.Open("glaze-test-sql", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := NewSQLCacheStore(db, "cache")
+	if err != nil {
+		t.Fatalf("NewSQLCacheStore() unexpected error: %v", err)
+	}
+	return store
+}
+
+var (
+	registerTestSQL sync.Once
+	testSQLMu       sync.Mutex
+	testSQLTables   = map[string]map[string][2]driver.Value{} // by DSN
+)
+
+type testSQLDriver struct{}
+
+func (testSQLDriver) Open(dsn string) (driver.Conn, error) {
+	testSQLMu.Lock()
+	defer testSQLMu.Unlock()
+	if testSQLTables[dsn] == nil {
+		testSQLTables[dsn] = map[string][2]driver.Value{}
+	}
+	return testSQLConn{dsn}, nil
+}
+
+type testSQLConn struct{ dsn string }
+
+func (c testSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return testSQLStmt{c.dsn, query}, nil
+}
+func (testSQLConn) Close() error              { return nil }
+func (testSQLConn) Begin() (driver.Tx, error) { return nil, errors.New("no transactions") }
+
+type testSQLStmt struct{ dsn, query string }
+
+func (testSQLStmt) Close() error  { return nil }
+func (testSQLStmt) NumInput() int { return -1 }
+
+func (s testSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	testSQLMu.Lock()
+	defer testSQLMu.Unlock()
+	rows := testSQLTables[s.dsn]
+	switch {
+	case strings.HasPrefix(s.query, "CREATE TABLE IF NOT EXISTS cache "):
+	case strings.HasPrefix(s.query, "INSERT INTO cache ") && strings.Contains(s.query, "ON CONFLICT (key) DO UPDATE"):
+		rows[string(args[0].([]byte))] = [2]driver.Value{args[1], args[2]}
+	case strings.HasPrefix(s.query, "DELETE FROM cache WHERE substr(key, 1, ?) = ?"):
+		prefix := args[1].([]byte)
+		if args[0].(int64) != int64(len(prefix)) {
+			return nil, errors.New("prefix length mismatch")
+		}
+		for key := range rows {
+			if strings.HasPrefix(key, string(prefix)) {
+				delete(rows, key)
+			}
+		}
+	default:
+		return nil, errors.New("unexpected statement: " + s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s testSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if s.query != "SELECT value, stored FROM cache WHERE key = ?" {
+		return nil, errors.New("unexpected query: " + s.query)
+	}
+	testSQLMu.Lock()
+	defer testSQLMu.Unlock()
+	row, ok := testSQLTables[s.dsn][string(args[0].([]byte))]
+	if !ok {
+		return &testSQLRows{}, nil
+	}
+	return &testSQLRows{rows: [][2]driver.Value{row}}, nil
+}
+
+type testSQLRows struct{ rows [][2]driver.Value }
+
+func (*testSQLRows) Columns() []string { return []string{"value", "stored"} }
+func (*testSQLRows) Close() error      { return nil }
+
+func (r *testSQLRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	dest[0], dest[1] = r.rows[0][0], r.rows[0][1]
+	r.rows = r.rows[1:]
+	return nil
+}
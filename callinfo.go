@@ -0,0 +1,86 @@
+package glaze
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// pageURLName is the hidden binding pages report their URL through, for
+// CallInfo.
+const pageURLName = "__glaze_page_url"
+
+// pageURLJS reports the page URL on load and on every same-document
+// navigation, so calls can be attributed to the page that made them.
+const pageURLJS = `(function () {
+  'use strict';
+  if (window.__glaze_page_url_installed) { return; }
+  window.__glaze_page_url_installed = true;
+  var last = "";
+  function report() {
+    if (location.href === last || typeof window.` + pageURLName + ` !== "function") { return; }
+    last = location.href;
+    window.` + pageURLName + `(last);
+  }
+  ["pushState", "replaceState"].forEach(function (m) {
+    var orig = history[m];
+    history[m] = function () {
+      var r = orig.apply(this, arguments);
+      report();
+      return r;
+    };
+  });
+  window.addEventListener("popstate", report);
+  window.addEventListener("hashchange", report);
+  if (document.readyState === "loading") {
+    document.addEventListener("DOMContentLoaded", report);
+  } else {
+    report();
+  }
+})();`
+
+// CallInfo describes the call a bound function is serving. Declare a
+// CallInfo parameter anywhere in the function's parameter list to receive
+// it; like *Progress and context.Context, it is not sent by JavaScript.
+//
+//	w.Bind("notes_save", func(call glaze.CallInfo, n Note) error {
+//		log.Printf("save %s from %s (call %s)", n.ID, call.URL, call.ID)
+//		return store.Save(n)
+//	})
+type CallInfo struct {
+	// ID identifies the call, as generated by the page.
+	ID string
+
+	// WebView is the window that made the call.
+	WebView WebView
+
+	// Window is the native window handle of WebView, as returned by its
+	// Window method, for telling windows apart in logs.
+	Window unsafe.Pointer
+
+	// URL is the page URL last reported by the window: the document that
+	// made the call, except around a navigation. It is empty until the
+	// first page has loaded.
+	URL string
+}
+
+func setupCallInfo(w WebView) {
+	b := bridgeFor(w)
+	_ = b.bindHidden(pageURLName, func(url string) {
+		b.mu.Lock()
+		b.pageURL = url
+		b.mu.Unlock()
+	})
+	b.injectScript("page-url", pageURLJS)
+}
+
+func callInfoParam(w WebView, id string) reflect.Value {
+	info := CallInfo{ID: id, WebView: w}
+	if w != nil {
+		info.Window = w.Window()
+		b := bridgeFor(w)
+		b.mu.Lock()
+		info.URL = b.pageURL
+		b.mu.Unlock()
+	}
+	return reflect.ValueOf(info)
+}
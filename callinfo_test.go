@@ -0,0 +1,41 @@
+package glaze
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCallInfoParam(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	var got CallInfo
+	_ = w.Bind("notes_save", func(call CallInfo, text string) string {
+		got = call
+		return text
+	})
+
+	// The first call installs the page reporter.
+	if res, err := w.call(t, "notes_save", "a"); err != nil || res != "a" {
+		t.Fatalf("notes_save = %v, %v", res, err)
+	}
+	if got.ID != "seq" || got.WebView != w || got.URL != "" {
+		t.Fatalf("CallInfo before the page reported = %+v", got)
+	}
+	if len(w.inits) != 1 || !strings.Contains(w.inits[0], pageURLName) {
+		t.Fatalf("init scripts = %q, want the page URL reporter", w.inits)
+	}
+
+	if _, err := w.call(t, pageURLName, "http://127.0.0.1:8080/notes#2"); err != nil {
+		t.Fatalf("reporting the page URL: %v", err)
+	}
+	_, _ = w.call(t, "notes_save", "b")
+	if got.URL != "http://127.0.0.1:8080/notes#2" {
+		t.Fatalf("CallInfo.URL = %q, want the reported page", got.URL)
+	}
+
+	// CallInfo is injected, not part of the JavaScript signature.
+	if m := Manifest(w); len(m.Methods) != 1 || len(m.Methods[0].Params) != 1 {
+		t.Fatalf("manifest = %+v, want notes_save with one parameter", m.Methods)
+	}
+}
@@ -0,0 +1,126 @@
+package glaze
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+)
+
+// captureJS renders the visible part of the page into a PNG and resolves to
+// its base64 encoding. The document is serialized into an SVG foreignObject
+// with same-origin stylesheets inlined, canvases replaced by their pixels and
+// form values preserved, then drawn onto a canvas at the device pixel ratio.
+const captureJS = `(function () {
+  var doc = document.documentElement;
+  var width = window.innerWidth, height = window.innerHeight;
+  var ratio = window.devicePixelRatio || 1;
+  var clone = doc.cloneNode(true);
+
+  var css = "";
+  Array.prototype.forEach.call(document.styleSheets, function (sheet) {
+    try {
+      Array.prototype.forEach.call(sheet.cssRules, function (rule) { css += rule.cssText + "\n"; });
+    } catch (e) { /* cross-origin stylesheet */ }
+  });
+  Array.prototype.forEach.call(clone.querySelectorAll("script, link[rel=stylesheet], style"), function (el) {
+    el.parentNode.removeChild(el);
+  });
+  var style = document.createElement("style");
+  style.textContent = css;
+  (clone.querySelector("head") || clone).appendChild(style);
+
+  var canvases = doc.querySelectorAll("canvas");
+  Array.prototype.forEach.call(clone.querySelectorAll("canvas"), function (el, i) {
+    try {
+      var img = document.createElement("img");
+      img.src = canvases[i].toDataURL();
+      img.setAttribute("style", el.getAttribute("style") || "");
+      img.width = canvases[i].clientWidth;
+      img.height = canvases[i].clientHeight;
+      el.parentNode.replaceChild(img, el);
+    } catch (e) { /* tainted canvas */ }
+  });
+  var fields = doc.querySelectorAll("input, textarea, select");
+  Array.prototype.forEach.call(clone.querySelectorAll("input, textarea, select"), function (el, i) {
+    var src = fields[i];
+    if (src.type === "checkbox" || src.type === "radio") {
+      if (src.checked) { el.setAttribute("checked", ""); } else { el.removeAttribute("checked"); }
+    } else if (el.tagName === "TEXTAREA") {
+      el.textContent = src.value;
+    } else {
+      el.setAttribute("value", src.value);
+    }
+  });
+  clone.style.transform = "translate(" + (-window.scrollX) + "px," + (-window.scrollY) + "px)";
+
+  var svg = '<svg xmlns="http://www.w3.org/2000/svg" width="' + width + '" height="' + height + '">' +
+    '<foreignObject x="0" y="0" width="100%" height="100%">' +
+    new XMLSerializer().serializeToString(clone) +
+    '</foreignObject></svg>';
+
+  return new Promise(function (resolve, reject) {
+    var img = new Image();
+    img.onload = function () {
+      var canvas = document.createElement("canvas");
+      canvas.width = Math.round(width * ratio);
+      canvas.height = Math.round(height * ratio);
+      var ctx = canvas.getContext("2d");
+      ctx.scale(ratio, ratio);
+      ctx.drawImage(img, 0, 0);
+      try {
+        resolve(canvas.toDataURL("image/png").split(",")[1]);
+      } catch (e) {
+        reject(e);
+      }
+    };
+    img.onerror = function () { reject(new Error("failed to render page snapshot")); };
+    img.src = "data:image/svg+xml;charset=utf-8," + encodeURIComponent(svg);
+  });
+})()`
+
+// CapturePNG returns a PNG snapshot of the visible part of the current page,
+// at the display's pixel density.
+//
+// The snapshot is rendered by the page itself: the DOM is drawn through an
+// SVG foreignObject, so cross-origin images, stylesheets and iframes may be
+// missing and native window chrome is not included. It is meant for "share
+// screenshot" features and visual checks of glaze UIs, not pixel-exact
+// screen capture.
+//
+// It must be called from a background goroutine, never from the UI thread.
+func CapturePNG(w WebView) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultEvalTimeout)
+	defer cancel()
+
+	raw, err := bridgeFor(w).eval(ctx, captureJS)
+	if err != nil {
+		return nil, fmt.Errorf("webview: capture: %w", err)
+	}
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		return nil, fmt.Errorf("webview: capture: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("webview: capture: %w", err)
+	}
+	return data, nil
+}
+
+// Capture returns a snapshot of the visible part of the current page as an
+// image. See CapturePNG for how the snapshot is produced.
+func Capture(w WebView) (image.Image, error) {
+	data, err := CapturePNG(w)
+	if err != nil {
+		return nil, err
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("webview: capture: %w", err)
+	}
+	return img, nil
+}
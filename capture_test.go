@@ -0,0 +1,60 @@
+package glaze
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestCapture(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	src.Set(1, 1, color.RGBA{R: 255, A: 255})
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, src); err != nil {
+		t.Fatal(err)
+	}
+
+	w := &fakeWebView{}
+	defer w.Destroy()
+	w.answerEvals(t, func(js string) (any, error) {
+		if js != captureJS {
+			t.Errorf("unexpected capture source")
+		}
+		return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+	})
+
+	img, err := Capture(w)
+	if err != nil {
+		t.Fatalf("Capture() unexpected error: %v", err)
+	}
+	if img.Bounds() != src.Bounds() {
+		t.Fatalf("Capture() bounds = %v, want %v", img.Bounds(), src.Bounds())
+	}
+	if r, _, _, _ := img.At(1, 1).RGBA(); r != 0xffff {
+		t.Fatalf("Capture() pixel red = %#x, want 0xffff", r)
+	}
+}
+
+func TestCapturePageError(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	w.answerEvals(t, func(string) (any, error) { return nil, errors.New("failed to render page snapshot") })
+
+	if _, err := CapturePNG(w); err == nil {
+		t.Fatal("CapturePNG() expected error")
+	}
+}
+
+func TestCaptureInvalidPNG(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	w.answerEvals(t, func(string) (any, error) { return base64.StdEncoding.EncodeToString([]byte("nope")), nil })
+
+	if _, err := Capture(w); err == nil {
+		t.Fatal("Capture() expected decode error")
+	}
+}
@@ -0,0 +1,279 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Hidden bindings behind BindChannel streams.
+const (
+	channelNextName   = "__glaze_channel_next"
+	channelCancelName = "__glaze_channel_cancel"
+)
+
+// channelJS adds the stream factory used by BindChannel to the glaze
+// runtime. Every stream pulls one value at a time from Go, and all streams of
+// a page are cancelled when it unloads. Stream IDs start with the document
+// ID, so Go can forget the streams of documents that are gone.
+const channelJS = `(function () {
+  'use strict';
+  var glaze = window.glaze = window.glaze || {};
+  if (glaze._channel) { return; }
+  var open = {};
+  var seq = 0;
+  function newStream(name) {
+    var id = (glaze._document || "") + "/" + Date.now().toString(36) + "-" + Math.random().toString(36).slice(2) + "-" + (++seq);
+    var done = false;
+    open[id] = name;
+    function finish() {
+      if (done) { return; }
+      done = true;
+      delete open[id];
+      window.` + channelCancelName + `(name, id);
+    }
+    return {
+      next: function () {
+        if (done) { return Promise.resolve({ value: undefined, done: true }); }
+        return window.` + channelNextName + `(name, id).then(function (item) {
+          if (item.done) { done = true; delete open[id]; }
+          return { value: item.done ? undefined : item.value, done: item.done };
+        });
+      },
+      return: function () {
+        finish();
+        return Promise.resolve({ value: undefined, done: true });
+      }
+    };
+  }
+  glaze._channel = function (name) {
+    var listeners = { message: [], close: [] };
+    var pump = null;
+    var onmessage = null;
+    var source = {
+      readyState: 0,
+      onclose: null,
+      next: function () { return (pump || start()).next(); },
+      addEventListener: function (type, cb) {
+        (listeners[type] = listeners[type] || []).push(cb);
+        if (type === "message") { start(); }
+      },
+      removeEventListener: function (type, cb) {
+        var list = listeners[type] || [];
+        var i = list.indexOf(cb);
+        if (i >= 0) { list.splice(i, 1); }
+      },
+      close: function () {
+        if (pump) { pump.return(); }
+        source.readyState = 2;
+      }
+    };
+    source[Symbol.asyncIterator] = function () { return newStream(name); };
+    function fire(type, event) {
+      var handler = source["on" + type];
+      if (handler) { handler.call(source, event); }
+      (listeners[type] || []).slice().forEach(function (cb) { cb.call(source, event); });
+    }
+    function start() {
+      if (pump) { return pump; }
+      pump = newStream(name);
+      source.readyState = 1;
+      (function loop() {
+        if (source.readyState === 2) { return; }
+        pump.next().then(function (item) {
+          if (item.done) {
+            source.readyState = 2;
+            fire("close", {});
+            return;
+          }
+          fire("message", { data: item.value });
+          loop();
+        }, function () {
+          source.readyState = 2;
+          fire("close", {});
+        });
+      })();
+      return pump;
+    }
+    Object.defineProperty(source, "onmessage", {
+      get: function () { return onmessage; },
+      set: function (cb) { onmessage = cb; if (cb) { start(); } }
+    });
+    return source;
+  };
+  window.addEventListener("pagehide", function () {
+    Object.keys(open).forEach(function (id) {
+      window.` + channelCancelName + `(open[id], id);
+    });
+    open = {};
+  });
+})();`
+
+// boundChannel is the Go side of a channel bound with BindChannel.
+type boundChannel struct {
+	recv func(cancel <-chan struct{}) (value any, ok bool)
+
+	mu      sync.Mutex
+	streams map[string]chan struct{}
+}
+
+// channelItem is one step of a JavaScript iterator.
+type channelItem struct {
+	Value any  `json:"value"`
+	Done  bool `json:"done"`
+}
+
+// BindChannel exposes ch to JavaScript as window[name], an object that is
+// both an async iterable and an EventSource-like source:
+//
+//	for await (const line of logs) { output.append(line); }
+//
+//	logs.onmessage = (e) => output.append(e.data);
+//	logs.close();
+//
+// Values are encoded as JSON and pulled one at a time: Go receives from ch
+// only when the page asks for the next value, so a slow page applies
+// backpressure to the sender instead of buffering without bound. Closing ch
+// ends every stream. Streams are cancelled when the page unloads, so no
+// value is taken from ch for a page that is gone; a value already in flight
+// when the page unloads is lost. Go forgets the streams of a page once the
+// next one loads or w is destroyed.
+//
+// Each stream competes for values with the others, like several goroutines
+// receiving from one channel. It must be called from the UI thread, like
+// Bind, and name must not already be bound.
+func BindChannel[T any](w WebView, name string, ch <-chan T) error {
+	if w == nil {
+		return errors.New("webview: BindChannel requires a non-nil WebView")
+	}
+	if name == "" {
+		return errors.New("webview: channel name must not be empty")
+	}
+	if ch == nil {
+		return errors.New("webview: BindChannel requires a non-nil channel")
+	}
+	bc := &boundChannel{
+		recv: func(cancel <-chan struct{}) (any, bool) {
+			select {
+			case v, ok := <-ch:
+				return v, ok
+			case <-cancel:
+				return nil, false
+			}
+		},
+		streams: make(map[string]chan struct{}),
+	}
+
+	b := bridgeFor(w)
+	b.mu.Lock()
+	if _, exists := b.channels[name]; exists {
+		b.mu.Unlock()
+		return fmt.Errorf("webview: channel %q is already bound", name)
+	}
+	if b.channels == nil {
+		b.channels = make(map[string]*boundChannel)
+	}
+	b.channels[name] = bc
+	b.mu.Unlock()
+
+	if err := b.onDocument("channels", b.dropStreams); err != nil {
+		return err
+	}
+	if err := b.bindHidden(channelNextName, b.channelNext); err != nil {
+		return err
+	}
+	if err := b.bindHidden(channelCancelName, b.channelCancel); err != nil {
+		return err
+	}
+	b.injectScript("channels", channelJS)
+	b.injectScript("channel:"+name, "window["+marshalJSON(name)+"] = window.glaze._channel("+marshalJSON(name)+");")
+	return nil
+}
+
+// channelNext is bound as channelNextName and waits for the next value of a
+// stream.
+func (b *bridge) channelNext(name, stream string) (channelItem, error) {
+	bc, err := b.channel(name)
+	if err != nil {
+		return channelItem{}, err
+	}
+	v, ok := bc.recv(bc.stream(stream))
+	if !ok {
+		return channelItem{Done: true}, nil
+	}
+	return channelItem{Value: v}, nil
+}
+
+// channelCancel is bound as channelCancelName and ends a stream, releasing
+// any receive it is waiting on.
+func (b *bridge) channelCancel(name, stream string) error {
+	bc, err := b.channel(name)
+	if err != nil {
+		return err
+	}
+	cancel := bc.stream(stream)
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	select {
+	case <-cancel:
+	default:
+		close(cancel)
+	}
+	return nil
+}
+
+// dropStreams cancels and forgets the streams of every document but doc,
+// on each new document and, with doc "", once the window is destroyed.
+func (b *bridge) dropStreams(doc string) {
+	b.mu.Lock()
+	channels := make([]*boundChannel, 0, len(b.channels))
+	for _, bc := range b.channels {
+		channels = append(channels, bc)
+	}
+	b.mu.Unlock()
+	for _, bc := range channels {
+		bc.drop(doc)
+	}
+}
+
+func (b *bridge) channel(name string) (*boundChannel, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	bc, ok := b.channels[name]
+	if !ok {
+		return nil, fmt.Errorf("webview: channel %q is not bound", name)
+	}
+	return bc, nil
+}
+
+// stream returns the cancel channel of a stream, creating it on first use.
+// Cancelled streams keep their closed channel until their document is gone,
+// so late calls end at once.
+func (bc *boundChannel) stream(id string) chan struct{} {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	cancel, ok := bc.streams[id]
+	if !ok {
+		cancel = make(chan struct{})
+		bc.streams[id] = cancel
+	}
+	return cancel
+}
+
+// drop cancels and forgets the streams of documents other than doc.
+func (bc *boundChannel) drop(doc string) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+	for id, cancel := range bc.streams {
+		if doc != "" && strings.HasPrefix(id, doc+"/") {
+			continue
+		}
+		select {
+		case <-cancel:
+		default:
+			close(cancel)
+		}
+		delete(bc.streams, id)
+	}
+}
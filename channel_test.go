@@ -0,0 +1,127 @@
+package glaze
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBindChannelPullsValues(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	ch := make(chan string, 2)
+	if err := BindChannel(w, "logs", ch); err != nil {
+		t.Fatalf("BindChannel() unexpected error: %v", err)
+	}
+	if len(w.inits) != 3 || w.inits[0] != documentJS || w.inits[1] != channelJS {
+		t.Fatalf("init scripts = %d, want document, runtime and logs stream", len(w.inits))
+	}
+
+	ch <- "one"
+	got, err := w.call(t, channelNextName, "logs", "s1")
+	if err != nil {
+		t.Fatalf("next unexpected error: %v", err)
+	}
+	if item := got.(channelItem); item.Done || item.Value != "one" {
+		t.Fatalf("next = %+v, want one", item)
+	}
+
+	close(ch)
+	got, _ = w.call(t, channelNextName, "logs", "s1")
+	if item := got.(channelItem); !item.Done {
+		t.Fatalf("next after close = %+v, want done", item)
+	}
+}
+
+func TestBindChannelCancelReleasesReceive(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	ch := make(chan int)
+	if err := BindChannel(w, "ticks", ch); err != nil {
+		t.Fatalf("BindChannel() unexpected error: %v", err)
+	}
+
+	done := make(chan channelItem, 1)
+	go func() {
+		got, _ := w.call(t, channelNextName, "ticks", "s1")
+		done <- got.(channelItem)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	if _, err := w.call(t, channelCancelName, "ticks", "s1"); err != nil {
+		t.Fatalf("cancel unexpected error: %v", err)
+	}
+	select {
+	case item := <-done:
+		if !item.Done {
+			t.Fatalf("cancelled next = %+v, want done", item)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("cancel did not release the pending receive")
+	}
+
+	// The value must stay in the channel for other streams.
+	go func() { ch <- 7 }()
+	got, _ := w.call(t, channelNextName, "ticks", "s2")
+	if item := got.(channelItem); item.Value != 7 {
+		t.Fatalf("next on new stream = %+v, want 7", item)
+	}
+	if _, err := w.call(t, channelCancelName, "ticks", "s1"); err != nil {
+		t.Fatalf("second cancel unexpected error: %v", err)
+	}
+}
+
+func TestBindChannelDropsStreamsOfGonePages(t *testing.T) {
+	w := &fakeWebView{}
+	ch := make(chan int)
+	if err := BindChannel(w, "ticks", ch); err != nil {
+		t.Fatalf("BindChannel() unexpected error: %v", err)
+	}
+	bc, _ := bridgeFor(w).channel("ticks")
+
+	w.call(t, documentName, "doc-1")
+	_, _ = w.call(t, channelCancelName, "ticks", "doc-1/a")
+	pending := bc.stream("doc-1/b")
+	newer := bc.stream("doc-2/c") // announced before its document
+	w.call(t, documentName, "doc-2")
+	select {
+	case <-pending:
+	default:
+		t.Error("stream of the previous page not cancelled")
+	}
+	if len(bc.streams) != 1 || bc.streams["doc-2/c"] == nil {
+		t.Errorf("streams = %v, want only those of the current page", bc.streams)
+	}
+
+	w.Destroy()
+	select {
+	case <-newer:
+	default:
+		t.Error("stream not cancelled when the window was destroyed")
+	}
+	if len(bc.streams) != 0 {
+		t.Errorf("streams after Destroy = %v, want none", bc.streams)
+	}
+}
+
+func TestBindChannelErrors(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	ch := make(chan int)
+	if err := BindChannel(w, "", ch); err == nil {
+		t.Error("BindChannel() expected error for empty name")
+	}
+	if err := BindChannel[int](w, "nil", nil); err == nil {
+		t.Error("BindChannel() expected error for nil channel")
+	}
+	if err := BindChannel(w, "dup", ch); err != nil {
+		t.Fatalf("BindChannel() unexpected error: %v", err)
+	}
+	if err := BindChannel(w, "dup", ch); err == nil {
+		t.Error("BindChannel() expected error for duplicate name")
+	}
+	if _, err := w.call(t, channelNextName, "missing", "s1"); err == nil {
+		t.Error("next expected error for unknown channel")
+	}
+}
@@ -0,0 +1,49 @@
+package glaze
+
+import "encoding/json"
+
+// Codec encodes and decodes the values exchanged with bound functions: each
+// JavaScript argument and the function's result. The request framing
+// itself is always plain JSON, and results must encode to valid JSON.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec is the default Codec, backed by encoding/json.
+var JSONCodec Codec = stdJSONCodec{}
+
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+func (stdJSONCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// SetCodec makes the functions bound in w encode and decode values with c,
+// for example to decode numbers as json.Number, apply custom time formats
+// or use a faster JSON library. BindingOptions.Codec overrides it for a
+// single binding. A nil c restores JSONCodec. It takes effect from the next
+// call and may be called from any goroutine.
+func SetCodec(w WebView, c Codec) {
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.codec = c
+	b.mu.Unlock()
+}
+
+// bindingCodec returns the codec for calls to name in w.
+func bindingCodec(w WebView, name string) Codec {
+	if w == nil {
+		return JSONCodec
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c := b.codecs[name]; c != nil {
+		return c
+	}
+	if b.codec != nil {
+		return b.codec
+	}
+	return JSONCodec
+}
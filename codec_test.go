@@ -0,0 +1,61 @@
+package glaze
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// numberCodec decodes numbers as json.Number and tags encoded results.
+type numberCodec struct{ tag string }
+
+func (c numberCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(map[string]any{c.tag: v})
+}
+
+func (numberCodec) Unmarshal(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+func TestCodecPerWindowAndBinding(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	echo := func(v any) any { return v }
+	_ = w.Bind("plain", echo)
+	if err := BindWithOptions(w, "custom", echo, BindingOptions{Codec: numberCodec{tag: "binding"}}); err != nil {
+		t.Fatalf("BindWithOptions() unexpected error: %v", err)
+	}
+
+	if got, _ := w.call(t, "plain", json.RawMessage("12345678901234567890")); got != float64(12345678901234567890) {
+		t.Fatalf("default codec result = %#v, want float64", got)
+	}
+
+	SetCodec(w, numberCodec{tag: "window"})
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"plain", `{"window":12345678901234567890}`},
+		{"custom", `{"binding":12345678901234567890}`},
+	}
+	for _, tt := range tests {
+		got, err := w.call(t, tt.name, json.RawMessage("12345678901234567890"))
+		if err != nil {
+			t.Fatalf("%s unexpected error: %v", tt.name, err)
+		}
+		raw, ok := got.(json.RawMessage)
+		if !ok || string(raw) != tt.want {
+			t.Errorf("%s = %#v, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	SetCodec(w, nil)
+	_ = w.Unbind("custom")
+	_ = w.Bind("custom", echo)
+	if got, _ := w.call(t, "custom", "x"); got != "x" {
+		t.Fatalf("after reset result = %#v, want default codec", got)
+	}
+}
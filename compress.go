@@ -0,0 +1,144 @@
+package glaze
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// gzipKey marks a gzip-compressed result in JSON.
+const gzipKey = "__glaze_gzip"
+
+// compressionSupportName is the hidden binding each document confirms it
+// can decompress results through, with its ID.
+const compressionSupportName = "__glaze_gzip_supported"
+
+// compressJS inflates compressed results before the promise of the call
+// resolves. Each document reports support once its decoder is installed;
+// pages whose engine lacks DecompressionStream never do, so they keep
+// receiving plain JSON.
+const compressJS = `(function () {
+  'use strict';
+  var glaze = window.glaze = window.glaze || {};
+  if (glaze._gzipInstalled) { return; }
+  glaze._gzipInstalled = true;
+  if (typeof DecompressionStream !== "function") { return; }
+  function inflate(v) {
+    if (!v || typeof v !== "object" || Array.isArray(v)) { return v; }
+    var keys = Object.keys(v);
+    if (keys.length !== 1 || keys[0] !== "` + gzipKey + `") { return v; }
+    var s = atob(v.` + gzipKey + `);
+    var bytes = new Uint8Array(s.length);
+    for (var i = 0; i < s.length; i++) { bytes[i] = s.charCodeAt(i); }
+    var stream = new Blob([bytes]).stream().pipeThrough(new DecompressionStream("gzip"));
+    return new Response(stream).text().then(function (text) {
+      var value = JSON.parse(text);
+      return glaze._decodeBinary ? glaze._decodeBinary(value) : value;
+    });
+  }
+  function patch() {
+    if (!window.__webview__) { return false; }
+    var proto = Object.getPrototypeOf(window.__webview__);
+    if (proto._glazeGzip || Object.isFrozen(proto)) { return true; }
+    proto._glazeGzip = true;
+    var call = proto.call;
+    proto.call = function () {
+      var inner = call.apply(this, arguments);
+      var promise = inner.then(inflate);
+      Object.defineProperty(promise, "onprogress", {
+        get: function () { return inner.onprogress; },
+        set: function (fn) { inner.onprogress = fn; }
+      });
+      return promise;
+    };
+    window.` + compressionSupportName + `(glaze._document || "");
+    return true;
+  }
+  if (!patch()) { document.addEventListener("DOMContentLoaded", patch); }
+})();`
+
+// SetCompression gzips the results of calls to functions bound in w whose
+// JSON encoding is at least minSize bytes, and has the page inflate them
+// before the promise resolves. Multi-megabyte results, such as large
+// listings or documents, then cross the bridge and the UI thread at a
+// fraction of their size. Results stay uncompressed for pages whose engine
+// lacks DecompressionStream, and for each new page until it has confirmed
+// it can inflate them. A minSize of zero or less turns compression off.
+//
+//	_ = glaze.SetCompression(w, 256<<10) // results of 256 KiB and more
+//
+// Like Bind, SetCompression must be called from the UI thread.
+func SetCompression(w WebView, minSize int) error {
+	if w == nil {
+		return errors.New("webview: SetCompression requires a non-nil WebView")
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.compressMin = max(minSize, 0)
+	b.mu.Unlock()
+	if minSize <= 0 {
+		return nil
+	}
+	// The document runtime goes first, so each page announces itself
+	// before it confirms support.
+	if err := b.onDocument("compress", b.compressionDocument); err != nil {
+		return err
+	}
+	if err := b.bindHidden(compressionSupportName, b.compressionSupported); err != nil {
+		return err
+	}
+	b.injectScript("gzip", compressJS)
+	return nil
+}
+
+// compressionSupported is bound as compressionSupportName.
+func (b *bridge) compressionSupported(doc string) {
+	if doc == "" {
+		return
+	}
+	b.mu.Lock()
+	b.compressDoc = doc
+	b.mu.Unlock()
+}
+
+// compressionDocument forgets the confirmation of the previous document
+// once another replaces it, until the new one confirms in turn.
+func (b *bridge) compressionDocument(doc string) {
+	b.mu.Lock()
+	if doc != b.compressDoc {
+		b.compressDoc = ""
+	}
+	b.mu.Unlock()
+}
+
+// compressionThreshold returns the size from which results in w are
+// compressed, or zero when they are not.
+func compressionThreshold(w WebView) int {
+	if w == nil {
+		return 0
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.compressDoc == "" || b.compressDoc != b.document {
+		return 0
+	}
+	return b.compressMin
+}
+
+// compressResult wraps the gzip-compressed JSON data for the page to
+// inflate.
+func compressResult(data []byte) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	zw, _ := gzip.NewWriterLevel(&buf, gzip.BestSpeed) // the level is valid
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	enc := base64.StdEncoding.EncodeToString(buf.Bytes())
+	return json.RawMessage(`{"` + gzipKey + `":"` + enc + `"}`), nil
+}
@@ -0,0 +1,105 @@
+package glaze
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSetCompression(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	big := strings.Repeat("note ", 200)
+	_ = w.Bind("notes_export", func() string { return big })
+	_ = w.Bind("notes_count", func() int { return 3 })
+
+	if err := SetCompression(w, 100); err != nil {
+		t.Fatalf("SetCompression() unexpected error: %v", err)
+	}
+	if len(w.inits) != 2 || w.inits[0] != documentJS || !strings.Contains(w.inits[1], "DecompressionStream") {
+		t.Fatalf("init scripts = %d, want the document and decompression runtimes", len(w.inits))
+	}
+
+	// Until the page reports support, results are sent as they are.
+	_, _ = w.call(t, documentName, "doc-1")
+	if got, _ := w.call(t, "notes_export"); got != big {
+		t.Fatalf("result before the page reported support = %T", got)
+	}
+	if _, err := w.call(t, compressionSupportName, "doc-1"); err != nil {
+		t.Fatalf("reporting support: %v", err)
+	}
+
+	got, err := w.call(t, "notes_export")
+	if err != nil {
+		t.Fatalf("notes_export error: %v", err)
+	}
+	raw, ok := got.(json.RawMessage)
+	if !ok {
+		t.Fatalf("large result = %T, want a compressed message", got)
+	}
+	var wrapped map[string]string
+	if err := json.Unmarshal(raw, &wrapped); err != nil || len(wrapped) != 1 {
+		t.Fatalf("compressed message = %s", raw)
+	}
+	if len(raw) >= len(big) {
+		t.Errorf("compressed size %d, want less than %d", len(raw), len(big))
+	}
+	if inflated := gunzipBase64(t, wrapped[gzipKey]); inflated != marshalJSON(big) {
+		t.Errorf("inflated result = %.40s..., want the JSON of the original", inflated)
+	}
+
+	if got, _ := w.call(t, "notes_count"); string(got.(json.RawMessage)) != "3" {
+		t.Errorf("small result = %s, want plain JSON", got)
+	}
+
+	// A new page gets plain results until it confirms it has the decoder.
+	_, _ = w.call(t, documentName, "doc-2")
+	if got, _ := w.call(t, "notes_export"); got != big {
+		t.Errorf("result on a new page before it reported support = %T", got)
+	}
+	_, _ = w.call(t, compressionSupportName, "doc-2")
+	if got, _ := w.call(t, "notes_export"); reflect.TypeOf(got) != reflect.TypeFor[json.RawMessage]() {
+		t.Errorf("result after the new page reported support = %T, want compressed", got)
+	}
+
+	// A confirmation that arrives before its page's announcement counts
+	// once the announcement arrives.
+	_, _ = w.call(t, compressionSupportName, "doc-3")
+	if got, _ := w.call(t, "notes_export"); got != big {
+		t.Errorf("result for an unannounced page = %T, want plain", got)
+	}
+	_, _ = w.call(t, documentName, "doc-3")
+	if got, _ := w.call(t, "notes_export"); reflect.TypeOf(got) != reflect.TypeFor[json.RawMessage]() {
+		t.Errorf("result after the announcement = %T, want compressed", got)
+	}
+
+	if err := SetCompression(w, 0); err != nil {
+		t.Fatalf("SetCompression(0) unexpected error: %v", err)
+	}
+	if got, _ := w.call(t, "notes_export"); got != big {
+		t.Errorf("result with compression off = %T, want the plain value", got)
+	}
+}
+
+func gunzipBase64(t *testing.T, s string) string {
+	t.Helper()
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decode base64: %v", err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	out, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("gunzip: %v", err)
+	}
+	return string(out)
+}
@@ -0,0 +1,142 @@
+package glaze
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+)
+
+// consoleName is the hidden binding pages forward console output through.
+const consoleName = "__glaze_console"
+
+// consoleJS wraps console.debug, log, info, warn and error so each message
+// is forwarded to Go as well as printed. The source is the first stack frame
+// outside this script, when the engine provides stacks.
+const consoleJS = `(function () {
+  'use strict';
+  if (window.__glaze_console_installed) { return; }
+  window.__glaze_console_installed = true;
+  function text(v) {
+    if (typeof v === "string") { return v; }
+    if (v instanceof Error) { return String(v.stack || v); }
+    try {
+      var s = JSON.stringify(v);
+      return s === undefined ? String(v) : s;
+    } catch (e) {
+      return String(v);
+    }
+  }
+  var frame = /([a-z][a-z0-9+.-]*:[^\s()]*?):(\d+):(\d+)/;
+  function frames() {
+    return String(new Error().stack || "").split("\n").map(function (line) {
+      return frame.exec(line);
+    }).filter(Boolean);
+  }
+  // Frames of this script are skipped to find the caller.
+  var self = (frames()[0] || [])[1];
+  function source() {
+    var all = frames();
+    for (var i = 0; i < all.length; i++) {
+      if (all[i][1] !== self) { return all[i][1] + ":" + all[i][2] + ":" + all[i][3]; }
+    }
+    return "";
+  }
+  ["debug", "log", "info", "warn", "error"].forEach(function (level) {
+    var orig = console[level];
+    console[level] = function () {
+      if (orig) { orig.apply(console, arguments); }
+      if (typeof window.` + consoleName + ` !== "function") { return; }
+      var msg = Array.prototype.map.call(arguments, text).join(" ");
+      window.` + consoleName + `({ level: level, message: msg, source: source(), url: location.href }).catch(function () {});
+    };
+  });
+})();`
+
+// ConsoleMessage is a message a page wrote with console.log and friends.
+type ConsoleMessage struct {
+	// Level is the console method: "debug", "log", "info", "warn" or
+	// "error".
+	Level string `json:"level"`
+
+	// Message is the arguments, strings as they are and other values
+	// JSON-encoded, joined by spaces.
+	Message string `json:"message"`
+
+	// Source is the script location of the call, as "url:line:column",
+	// when the engine reports it.
+	Source string `json:"source,omitempty"`
+
+	// URL is the page that wrote the message.
+	URL string `json:"url"`
+}
+
+// OnConsole forwards the console output of pages in w to fn, so builds
+// without devtools still produce frontend logs. The messages keep reaching
+// the engine console. Calling it again replaces the handler; nil stops
+// forwarding. It must be called from the UI thread, like Bind.
+//
+//	_ = glaze.OnConsole(w, glaze.ConsoleLogger(slog.Default()))
+func OnConsole(w WebView, fn func(ConsoleMessage)) error {
+	if w == nil {
+		return errors.New("webview: OnConsole requires a non-nil WebView")
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.console = fn
+	b.mu.Unlock()
+	if err := b.bindHidden(consoleName, b.handleConsole); err != nil {
+		return err
+	}
+	b.injectScript("console", consoleJS)
+	return nil
+}
+
+// handleConsole is bound as consoleName.
+func (b *bridge) handleConsole(m ConsoleMessage) {
+	b.mu.Lock()
+	fn := b.console
+	b.mu.Unlock()
+	if fn != nil {
+		fn(m)
+	}
+}
+
+// ConsoleLogger returns an OnConsole handler logging to l, at Debug for
+// console.debug, Warn for console.warn, Error for console.error and Info
+// otherwise, with the source and URL as attributes.
+func ConsoleLogger(l *slog.Logger) func(ConsoleMessage) {
+	return func(m ConsoleMessage) {
+		level := slog.LevelInfo
+		switch m.Level {
+		case "debug":
+			level = slog.LevelDebug
+		case "warn":
+			level = slog.LevelWarn
+		case "error":
+			level = slog.LevelError
+		}
+		attrs := []slog.Attr{slog.String("url", m.URL)}
+		if m.Source != "" {
+			attrs = append(attrs, slog.String("source", m.Source))
+		}
+		l.LogAttrs(context.Background(), level, m.Message, attrs...)
+	}
+}
+
+// ConsoleWriter returns an OnConsole handler writing each message to out
+// as one line: "[level] message (source)".
+func ConsoleWriter(out io.Writer) func(ConsoleMessage) {
+	var mu sync.Mutex
+	return func(m ConsoleMessage) {
+		line := "[" + m.Level + "] " + m.Message
+		if m.Source != "" {
+			line += " (" + m.Source + ")"
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = fmt.Fprintln(out, line)
+	}
+}
@@ -0,0 +1,56 @@
+package glaze
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestOnConsole(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	var got []ConsoleMessage
+	if err := OnConsole(w, func(m ConsoleMessage) { got = append(got, m) }); err != nil {
+		t.Fatalf("OnConsole() unexpected error: %v", err)
+	}
+	if err := OnConsole(w, func(m ConsoleMessage) { got = append(got, m) }); err != nil {
+		t.Fatalf("OnConsole() again unexpected error: %v", err)
+	}
+	if len(w.inits) != 1 || w.inits[0] != consoleJS {
+		t.Fatalf("inits = %d scripts, want the console script once", len(w.inits))
+	}
+
+	msg := ConsoleMessage{Level: "warn", Message: "low disk", Source: "http://127.0.0.1/app.js:10:3", URL: "http://127.0.0.1/"}
+	if _, err := w.call(t, consoleName, msg); err != nil {
+		t.Fatalf("console call unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0] != msg {
+		t.Errorf("handler received %+v, want %+v", got, msg)
+	}
+
+	_ = OnConsole(w, nil)
+	if _, err := w.call(t, consoleName, msg); err != nil || len(got) != 1 {
+		t.Errorf("message forwarded after OnConsole(nil): %+v, %v", got, err)
+	}
+}
+
+func TestConsoleHandlers(t *testing.T) {
+	msg := ConsoleMessage{Level: "error", Message: "boom", Source: "app.js:1:2", URL: "http://127.0.0.1/"}
+
+	var buf bytes.Buffer
+	ConsoleWriter(&buf)(msg)
+	if buf.String() != "[error] boom (app.js:1:2)\n" {
+		t.Errorf("ConsoleWriter wrote %q", buf.String())
+	}
+
+	buf.Reset()
+	ConsoleLogger(slog.New(slog.NewTextHandler(&buf, nil)))(msg)
+	out := buf.String()
+	for _, want := range []string{"level=ERROR", "msg=boom", "source=app.js:1:2", "url=http://127.0.0.1/"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("ConsoleLogger wrote %q, want %q", out, want)
+		}
+	}
+}
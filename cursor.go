@@ -0,0 +1,146 @@
+package glaze
+
+import "time"
+
+// defaultEdgePollInterval and defaultEdgeThreshold are used by OnScreenEdge
+// when ScreenEdgeOptions leaves them zero.
+const (
+	defaultEdgePollInterval = 100 * time.Millisecond
+	defaultEdgeThreshold    = 2
+)
+
+// ScreenRect is a rectangle in global screen coordinates.
+type ScreenRect struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// CursorInfo is the mouse pointer position in global screen coordinates,
+// whose origin is the top-left corner of the primary screen, together with
+// the bounds of the screen the pointer is on. Coordinates are in the
+// platform's desktop units: points on macOS, pixels elsewhere.
+type CursorInfo struct {
+	X      int        `json:"x"`
+	Y      int        `json:"y"`
+	Screen ScreenRect `json:"screen"`
+}
+
+// ScreenEdge identifies the screen edge or corner the pointer rests on.
+type ScreenEdge int
+
+// Screen edges and corners reported by OnScreenEdge.
+const (
+	EdgeNone ScreenEdge = iota
+	EdgeTop
+	EdgeBottom
+	EdgeLeft
+	EdgeRight
+	CornerTopLeft
+	CornerTopRight
+	CornerBottomLeft
+	CornerBottomRight
+)
+
+// String returns the edge name as used in JavaScript, e.g. "top-left".
+func (e ScreenEdge) String() string {
+	switch e {
+	case EdgeTop:
+		return "top"
+	case EdgeBottom:
+		return "bottom"
+	case EdgeLeft:
+		return "left"
+	case EdgeRight:
+		return "right"
+	case CornerTopLeft:
+		return "top-left"
+	case CornerTopRight:
+		return "top-right"
+	case CornerBottomLeft:
+		return "bottom-left"
+	case CornerBottomRight:
+		return "bottom-right"
+	default:
+		return "none"
+	}
+}
+
+// MarshalText encodes the edge by name, so it reaches JavaScript as a string.
+func (e ScreenEdge) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+// ScreenEdgeOptions configures OnScreenEdge.
+type ScreenEdgeOptions struct {
+	// Interval is how often the pointer is sampled. Zero means 100ms.
+	Interval time.Duration
+
+	// Threshold is how close to an edge, in screen units, the pointer must be
+	// to count as touching it. Zero means 2.
+	Threshold int
+}
+
+// CursorPosition returns the current mouse pointer position and the screen
+// it is on. It works anywhere on the desktop, not just over the window.
+// Its signature makes it directly bindable:
+// w.Bind("cursor_position", glaze.CursorPosition).
+//
+// On Linux it needs an X11 display; Wayland sessions do not expose the global
+// pointer position and return an error.
+func CursorPosition() (CursorInfo, error) {
+	return readCursor()
+}
+
+// OnScreenEdge samples the pointer and calls fn, from a background
+// goroutine, whenever it moves onto a screen edge or corner of the screen it
+// is on, and with EdgeNone when it leaves it. fn is also called once with the
+// initial state. Dock-like and slide-in panels use it to show themselves when
+// the pointer is pushed against an edge. The returned function stops
+// sampling.
+func OnScreenEdge(opts ScreenEdgeOptions, fn func(ScreenEdge)) (stop func()) {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultEdgePollInterval
+	}
+	if opts.Threshold <= 0 {
+		opts.Threshold = defaultEdgeThreshold
+	}
+	read := func() (ScreenEdge, error) {
+		c, err := readCursor()
+		if err != nil {
+			return EdgeNone, err
+		}
+		return edgeAt(c, opts.Threshold), nil
+	}
+	return watchChanges(opts.Interval, read, func(a, b ScreenEdge) bool { return a == b }, fn)
+}
+
+// edgeAt classifies a pointer position relative to its screen.
+func edgeAt(c CursorInfo, threshold int) ScreenEdge {
+	s := c.Screen
+	top := c.Y < s.Y+threshold
+	bottom := c.Y >= s.Y+s.Height-threshold
+	left := c.X < s.X+threshold
+	right := c.X >= s.X+s.Width-threshold
+	switch {
+	case top && left:
+		return CornerTopLeft
+	case top && right:
+		return CornerTopRight
+	case bottom && left:
+		return CornerBottomLeft
+	case bottom && right:
+		return CornerBottomRight
+	case top:
+		return EdgeTop
+	case bottom:
+		return EdgeBottom
+	case left:
+		return EdgeLeft
+	case right:
+		return EdgeRight
+	default:
+		return EdgeNone
+	}
+}
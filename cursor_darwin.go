@@ -0,0 +1,63 @@
+package glaze
+
+import (
+	"errors"
+	"sync"
+)
+
+type cgPoint struct{ X, Y float64 }
+
+type cgRect struct {
+	Origin cgPoint
+	Width  float64
+	Height float64
+}
+
+// coreGraphics holds the Quartz entry points used to read the pointer. Quartz
+// global coordinates already have their origin at the top-left corner of the
+// primary display.
+var coreGraphics struct {
+	once sync.Once
+	err  error
+
+	eventCreate       func(source uintptr) uintptr
+	eventGetLocation  func(event uintptr) cgPoint
+	displaysWithPoint func(p cgPoint, max uint32, displays *uint32, count *uint32) int32
+	displayBounds     func(display uint32) cgRect
+	release           func(ref uintptr)
+}
+
+func readCursor() (CursorInfo, error) {
+	coreGraphics.once.Do(func() {
+		coreGraphics.err = openNative("/System/Library/Frameworks/CoreGraphics.framework/CoreGraphics", []nativeFunc{
+			{&coreGraphics.eventCreate, "CGEventCreate"},
+			{&coreGraphics.eventGetLocation, "CGEventGetLocation"},
+			{&coreGraphics.displaysWithPoint, "CGGetDisplaysWithPoint"},
+			{&coreGraphics.displayBounds, "CGDisplayBounds"},
+		})
+		if coreGraphics.err == nil {
+			coreGraphics.err = openNative("/System/Library/Frameworks/CoreFoundation.framework/CoreFoundation", []nativeFunc{
+				{&coreGraphics.release, "CFRelease"},
+			})
+		}
+	})
+	if coreGraphics.err != nil {
+		return CursorInfo{}, coreGraphics.err
+	}
+
+	event := coreGraphics.eventCreate(0)
+	if event == 0 {
+		return CursorInfo{}, errors.New("webview: CGEventCreate failed")
+	}
+	p := coreGraphics.eventGetLocation(event)
+	coreGraphics.release(event)
+	c := CursorInfo{X: int(p.X), Y: int(p.Y)}
+
+	var display, count uint32
+	if coreGraphics.displaysWithPoint(p, 1, &display, &count) != 0 || count == 0 {
+		return CursorInfo{}, errors.New("webview: no display under the pointer")
+	}
+	b := coreGraphics.displayBounds(display)
+	c.Screen = ScreenRect{X: int(b.Origin.X), Y: int(b.Origin.Y), Width: int(b.Width), Height: int(b.Height)}
+	return c, nil
+}
@@ -0,0 +1,66 @@
+package glaze
+
+import (
+	"errors"
+	"sync"
+)
+
+// xlib holds the Xlib entry points and the private display connection used
+// to read the pointer. The connection is only used under mu, since Xlib is
+// not thread-safe by default.
+var xlib struct {
+	once sync.Once
+	err  error
+
+	mu      sync.Mutex
+	display uintptr
+	root    uintptr
+
+	openDisplay   func(name *byte) uintptr
+	defaultRoot   func(display uintptr) uintptr
+	queryPointer  func(display, window uintptr, root, child *uintptr, rootX, rootY, winX, winY *int32, mask *uint32) int32
+	displayWidth  func(display uintptr, screen int32) int32
+	displayHeight func(display uintptr, screen int32) int32
+}
+
+func readCursor() (CursorInfo, error) {
+	xlib.once.Do(func() {
+		xlib.err = openNative("libX11.so.6", []nativeFunc{
+			{&xlib.openDisplay, "XOpenDisplay"},
+			{&xlib.defaultRoot, "XDefaultRootWindow"},
+			{&xlib.queryPointer, "XQueryPointer"},
+			{&xlib.displayWidth, "XDisplayWidth"},
+			{&xlib.displayHeight, "XDisplayHeight"},
+		})
+		if xlib.err != nil {
+			return
+		}
+		if xlib.display = xlib.openDisplay(nil); xlib.display == 0 {
+			xlib.err = errors.New("webview: cannot open X display; the global pointer is not available on Wayland")
+			return
+		}
+		xlib.root = xlib.defaultRoot(xlib.display)
+	})
+	if xlib.err != nil {
+		return CursorInfo{}, xlib.err
+	}
+
+	xlib.mu.Lock()
+	defer xlib.mu.Unlock()
+	var root, child uintptr
+	var x, y, winX, winY int32
+	var mask uint32
+	if xlib.queryPointer(xlib.display, xlib.root, &root, &child, &x, &y, &winX, &winY, &mask) == 0 {
+		return CursorInfo{}, errors.New("webview: pointer is on another X screen")
+	}
+	// Without XRandR the root window spans every monitor, so edges are those
+	// of the whole desktop.
+	return CursorInfo{
+		X: int(x),
+		Y: int(y),
+		Screen: ScreenRect{
+			Width:  int(xlib.displayWidth(xlib.display, 0)),
+			Height: int(xlib.displayHeight(xlib.display, 0)),
+		},
+	}, nil
+}
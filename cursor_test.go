@@ -0,0 +1,48 @@
+package glaze
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEdgeAt(t *testing.T) {
+	screen := ScreenRect{X: 1920, Y: 0, Width: 1280, Height: 800}
+	tests := []struct {
+		name string
+		x, y int
+		want ScreenEdge
+	}{
+		{"center", 2500, 400, EdgeNone},
+		{"top", 2500, 0, EdgeTop},
+		{"bottom", 2500, 799, EdgeBottom},
+		{"left", 1920, 400, EdgeLeft},
+		{"left within threshold", 1921, 400, EdgeLeft},
+		{"just inside left", 1922, 400, EdgeNone},
+		{"right", 3199, 400, EdgeRight},
+		{"top left", 1920, 1, CornerTopLeft},
+		{"top right", 3199, 0, CornerTopRight},
+		{"bottom left", 1920, 799, CornerBottomLeft},
+		{"bottom right", 3198, 798, CornerBottomRight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := edgeAt(CursorInfo{X: tt.x, Y: tt.y, Screen: screen}, 2)
+			if got != tt.want {
+				t.Errorf("edgeAt(%d, %d) = %v, want %v", tt.x, tt.y, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScreenEdgeJSON(t *testing.T) {
+	data, err := json.Marshal(map[string]ScreenEdge{"edge": CornerBottomRight})
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error: %v", err)
+	}
+	if string(data) != `{"edge":"bottom-right"}` {
+		t.Fatalf("Marshal() = %s", data)
+	}
+	if EdgeNone.String() != "none" || ScreenEdge(99).String() != "none" {
+		t.Fatal("unknown edges must be named none")
+	}
+}
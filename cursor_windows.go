@@ -0,0 +1,44 @@
+package glaze
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+var (
+	procGetCursorPos     = user32.NewProc("GetCursorPos")
+	procMonitorFromPoint = user32.NewProc("MonitorFromPoint")
+	procGetMonitorInfo   = user32.NewProc("GetMonitorInfoW")
+)
+
+const monitorDefaultToNearest = 2
+
+type winPoint struct{ X, Y int32 }
+
+type winRect struct{ Left, Top, Right, Bottom int32 }
+
+type monitorInfo struct {
+	Size    uint32
+	Monitor winRect
+	Work    winRect
+	Flags   uint32
+}
+
+func readCursor() (CursorInfo, error) {
+	var pt winPoint
+	if r, _, err := procGetCursorPos.Call(uintptr(unsafe.Pointer(&pt))); r == 0 {
+		return CursorInfo{}, fmt.Errorf("webview: GetCursorPos failed: %w", err)
+	}
+	c := CursorInfo{X: int(pt.X), Y: int(pt.Y)}
+
+	// POINT is passed by value, packed into a single register.
+	packed := uintptr(uint32(pt.X)) | uintptr(uint32(pt.Y))<<32
+	monitor, _, _ := procMonitorFromPoint.Call(packed, monitorDefaultToNearest)
+	info := monitorInfo{Size: uint32(unsafe.Sizeof(monitorInfo{}))}
+	if r, _, err := procGetMonitorInfo.Call(monitor, uintptr(unsafe.Pointer(&info))); r == 0 {
+		return CursorInfo{}, fmt.Errorf("webview: GetMonitorInfoW failed: %w", err)
+	}
+	m := info.Monitor
+	c.Screen = ScreenRect{X: int(m.Left), Y: int(m.Top), Width: int(m.Right - m.Left), Height: int(m.Bottom - m.Top)}
+	return c, nil
+}
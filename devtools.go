@@ -0,0 +1,280 @@
+package glaze
+
+import (
+	"encoding/json"
+	"net/http"
+	"path"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// devtoolsHeader must be sent with evaluation requests, which keeps plain
+// cross-site form posts from reaching the Eval hook.
+const devtoolsHeader = "X-Glaze-Devtools"
+
+// DevToolsOptions configures DevToolsHandler.
+type DevToolsOptions struct {
+	// History is the number of recent calls listed. Defaults to 100.
+	History int
+
+	// Eval, when set, backs the panel's console: each expression typed
+	// there is passed to Eval and its result or error shown. glaze does not
+	// evaluate Go itself; plug in an interpreter or a command dispatcher.
+	Eval func(expr string) (any, error)
+}
+
+// devtoolsState is the snapshot the panel polls.
+type devtoolsState struct {
+	Bindings []devtoolsBinding `json:"bindings"`
+	Calls    []devtoolsCall    `json:"calls"`
+	Events   []devtoolsTopic   `json:"events"`
+	Runtime  devtoolsRuntime   `json:"runtime"`
+	Eval     bool              `json:"eval"`
+}
+
+type devtoolsBinding struct {
+	Name      string  `json:"name"`
+	Signature string  `json:"signature"`
+	Calls     uint64  `json:"calls"`
+	Errors    uint64  `json:"errors"`
+	AvgMillis float64 `json:"avgMs"`
+	MaxMillis float64 `json:"maxMs"`
+}
+
+type devtoolsCall struct {
+	Name     string    `json:"name"`
+	ID       string    `json:"id"`
+	Start    time.Time `json:"start"`
+	Millis   float64   `json:"ms"`
+	Error    string    `json:"error,omitempty"`
+	Internal bool      `json:"internal,omitempty"`
+}
+
+type devtoolsTopic struct {
+	Topic string `json:"topic"`
+	Count uint64 `json:"count"`
+}
+
+type devtoolsRuntime struct {
+	Goroutines int    `json:"goroutines"`
+	HeapAlloc  uint64 `json:"heapAlloc"`
+	HeapSys    uint64 `json:"heapSys"`
+	NumGC      uint32 `json:"numGC"`
+	GoVersion  string `json:"goVersion"`
+}
+
+type devtoolsEvalReply struct {
+	Value any    `json:"value,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// DevToolsHandler serves a developer panel for the Go side of w, to use
+// alongside the browser developer tools: the bound functions with their
+// signatures and call statistics, the most recent calls, the events sent
+// with Emit, goroutine and memory figures, and a console backed by
+// opts.Eval. Mount it under a path of its own in development builds only:
+//
+//	mux.Handle("/_glaze/devtools/", glaze.DevToolsHandler(w, glaze.DevToolsOptions{}))
+//
+// and open that path in a browser or a second window. The handler starts
+// keeping the call history when it is created.
+func DevToolsHandler(w WebView, opts DevToolsOptions) http.Handler {
+	if opts.History <= 0 {
+		opts.History = 100
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.historySize = max(b.historySize, opts.History)
+	b.mu.Unlock()
+
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Cache-Control", "no-store")
+		switch path.Base(r.URL.Path) {
+		case "state":
+			writeDevtoolsJSON(rw, b.devtoolsState(opts))
+		case "eval":
+			if r.Method != http.MethodPost || r.Header.Get(devtoolsHeader) == "" {
+				http.Error(rw, "evaluation requires a POST from the panel", http.StatusForbidden)
+				return
+			}
+			if opts.Eval == nil {
+				http.Error(rw, "no Eval hook configured", http.StatusNotFound)
+				return
+			}
+			var req struct {
+				Expr string `json:"expr"`
+			}
+			if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, 1<<20)).Decode(&req); err != nil {
+				http.Error(rw, err.Error(), http.StatusBadRequest)
+				return
+			}
+			var reply devtoolsEvalReply
+			value, err := opts.Eval(req.Expr)
+			if err != nil {
+				reply.Error = err.Error()
+			} else {
+				reply.Value = value
+			}
+			writeDevtoolsJSON(rw, reply)
+		default:
+			rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = rw.Write([]byte(devtoolsPage))
+		}
+	})
+}
+
+func writeDevtoolsJSON(rw http.ResponseWriter, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	_, _ = rw.Write(data)
+}
+
+// devtoolsState takes the snapshot served to the panel.
+func (b *bridge) devtoolsState(opts DevToolsOptions) devtoolsState {
+	state := devtoolsState{Eval: opts.Eval != nil}
+	stats := make(map[string]BindingStats)
+	for _, s := range Stats(b.w) {
+		stats[s.Name] = s
+	}
+
+	b.mu.Lock()
+	for name, t := range b.bindings {
+		if b.hidden[name] {
+			continue
+		}
+		s := stats[name]
+		entry := devtoolsBinding{Name: name, Signature: funcSignature(t), Calls: s.Calls, Errors: s.Errors, MaxMillis: millis(s.Max)}
+		if s.Calls > 0 {
+			entry.AvgMillis = millis(s.Total) / float64(s.Calls)
+		}
+		state.Bindings = append(state.Bindings, entry)
+	}
+	for i := len(b.history) - 1; i >= 0; i-- {
+		r := b.history[i]
+		call := devtoolsCall{Name: r.Name, ID: r.ID, Start: r.Start, Millis: millis(r.Duration), Internal: b.hidden[r.Name]}
+		if r.Err != nil {
+			call.Error = r.Err.Error()
+		}
+		state.Calls = append(state.Calls, call)
+	}
+	for topic, count := range b.topics {
+		state.Events = append(state.Events, devtoolsTopic{Topic: topic, Count: count})
+	}
+	b.mu.Unlock()
+
+	sort.Slice(state.Bindings, func(i, j int) bool { return state.Bindings[i].Name < state.Bindings[j].Name })
+	sort.Slice(state.Events, func(i, j int) bool { return state.Events[i].Topic < state.Events[j].Topic })
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	state.Runtime = devtoolsRuntime{
+		Goroutines: runtime.NumGoroutine(),
+		HeapAlloc:  mem.HeapAlloc,
+		HeapSys:    mem.HeapSys,
+		NumGC:      mem.NumGC,
+		GoVersion:  runtime.Version(),
+	}
+	return state
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+const devtoolsPage = `<!doctype html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>glaze devtools</title>
+  <style>
+    body { margin: 0; padding: 16px; font: 13px -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif; background: #111827; color: #e5e7eb; }
+    h2 { font-size: 14px; margin: 20px 0 8px 0; color: #9ca3af; text-transform: uppercase; letter-spacing: .05em; }
+    table { width: 100%; border-collapse: collapse; }
+    th, td { text-align: left; padding: 4px 8px; border-bottom: 1px solid #374151; vertical-align: top; }
+    td.num { text-align: right; font-variant-numeric: tabular-nums; }
+    code, pre, input { font: 12px ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; }
+    .err { color: #f87171; }
+    .muted { color: #6b7280; }
+    #runtime span { margin-right: 16px; }
+    #console { display: none; }
+    #console input { width: 100%; box-sizing: border-box; padding: 6px; background: #1f2937; color: #e5e7eb; border: 1px solid #374151; border-radius: 4px; }
+    #output { max-height: 240px; overflow: auto; white-space: pre-wrap; }
+  </style>
+</head>
+<body>
+  <div id="runtime"></div>
+  <h2>Bindings</h2>
+  <table><thead><tr><th>Name</th><th>Signature</th><th>Calls</th><th>Errors</th><th>Avg ms</th><th>Max ms</th></tr></thead><tbody id="bindings"></tbody></table>
+  <h2>Recent calls</h2>
+  <table><thead><tr><th>Time</th><th>Name</th><th>ms</th><th>Error</th></tr></thead><tbody id="calls"></tbody></table>
+  <h2>Events</h2>
+  <table><thead><tr><th>Topic</th><th>Emitted</th></tr></thead><tbody id="events"></tbody></table>
+  <section id="console">
+    <h2>Console</h2>
+    <pre id="output"></pre>
+    <input id="expr" placeholder="Expression, Enter to evaluate" autocomplete="off">
+  </section>
+  <script>
+  (function () {
+    'use strict';
+    function row(cells) {
+      var tr = document.createElement("tr");
+      cells.forEach(function (c) {
+        var td = document.createElement("td");
+        if (typeof c === "number") { td.className = "num"; c = Math.round(c * 100) / 100; }
+        if (c && c.cls) { td.className = c.cls; c = c.text; }
+        td.textContent = c === undefined ? "" : String(c);
+        tr.appendChild(td);
+      });
+      return tr;
+    }
+    function fill(id, rows) {
+      var body = document.getElementById(id);
+      body.replaceChildren.apply(body, rows);
+    }
+    function mb(n) { return (n / 1048576).toFixed(1) + " MB"; }
+    function refresh() {
+      fetch("state", { cache: "no-store" }).then(function (r) { return r.json(); }).then(function (s) {
+        var rt = s.runtime;
+        document.getElementById("runtime").innerHTML = "";
+        [rt.goVersion, rt.goroutines + " goroutines", "heap " + mb(rt.heapAlloc) + " / " + mb(rt.heapSys), rt.numGC + " GCs"].forEach(function (t) {
+          var span = document.createElement("span");
+          span.textContent = t;
+          document.getElementById("runtime").appendChild(span);
+        });
+        fill("bindings", (s.bindings || []).map(function (b) {
+          return row([b.name, { cls: "muted", text: b.signature }, b.calls, b.errors, b.avgMs, b.maxMs]);
+        }));
+        fill("calls", (s.calls || []).map(function (c) {
+          var name = c.internal ? { cls: "muted", text: c.name } : c.name;
+          return row([new Date(c.start).toLocaleTimeString(), name, c.ms, { cls: "err", text: c.error || "" }]);
+        }));
+        fill("events", (s.events || []).map(function (e) { return row([e.topic, e.count]); }));
+        document.getElementById("console").style.display = s.eval ? "block" : "none";
+      }).catch(function () {});
+    }
+    document.getElementById("expr").addEventListener("keydown", function (e) {
+      if (e.key !== "Enter" || !this.value) { return; }
+      var expr = this.value, out = document.getElementById("output");
+      this.value = "";
+      fetch("eval", {
+        method: "POST",
+        headers: { "Content-Type": "application/json", "` + devtoolsHeader + `": "1" },
+        body: JSON.stringify({ expr: expr })
+      }).then(function (r) { return r.json(); }).then(function (reply) {
+        out.textContent += "> " + expr + "\n" + (reply.error ? "error: " + reply.error : JSON.stringify(reply.value, null, 2)) + "\n";
+        out.scrollTop = out.scrollHeight;
+      });
+    });
+    refresh();
+    setInterval(refresh, 1000);
+  })();
+  </script>
+</body>
+</html>`
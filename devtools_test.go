@@ -0,0 +1,104 @@
+package glaze
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDevToolsHandlerState(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	h := DevToolsHandler(w, DevToolsOptions{History: 2})
+	_ = w.Bind("notes_add", func(s string) (string, error) {
+		if s == "" {
+			return "", errors.New("empty note")
+		}
+		return s, nil
+	})
+	for _, arg := range []string{"a", "", "b"} {
+		_, _ = w.call(t, "notes_add", arg)
+	}
+	_ = Emit(w, "saved", nil)
+	_ = Emit(w, "saved", nil)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/_glaze/devtools/state", nil))
+	var state devtoolsState
+	if err := json.Unmarshal(rec.Body.Bytes(), &state); err != nil {
+		t.Fatalf("state is not JSON: %v\n%s", err, rec.Body)
+	}
+
+	if len(state.Bindings) != 1 || state.Bindings[0].Name != "notes_add" || state.Bindings[0].Calls != 3 || state.Bindings[0].Errors != 1 {
+		t.Errorf("bindings = %+v, want notes_add with 3 calls and 1 error", state.Bindings)
+	}
+	if state.Bindings[0].Signature != "func(string) (string, error)" {
+		t.Errorf("signature = %q", state.Bindings[0].Signature)
+	}
+	if len(state.Calls) != 2 || state.Calls[1].Error != "empty note" {
+		t.Errorf("calls = %+v, want the last 2, newest first", state.Calls)
+	}
+	if len(state.Events) != 1 || state.Events[0] != (devtoolsTopic{Topic: "saved", Count: 2}) {
+		t.Errorf("events = %+v, want saved twice", state.Events)
+	}
+	if state.Runtime.Goroutines == 0 || state.Eval {
+		t.Errorf("runtime = %+v, eval = %v", state.Runtime, state.Eval)
+	}
+}
+
+func TestDevToolsHandlerEval(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	h := DevToolsHandler(w, DevToolsOptions{Eval: func(expr string) (any, error) {
+		if expr == "fail" {
+			return nil, errors.New("boom")
+		}
+		return strings.ToUpper(expr), nil
+	}})
+
+	tests := []struct {
+		name   string
+		method string
+		header bool
+		expr   string
+		status int
+		want   string
+	}{
+		{name: "value", method: http.MethodPost, header: true, expr: "hi", status: http.StatusOK, want: `{"value":"HI"}`},
+		{name: "error", method: http.MethodPost, header: true, expr: "fail", status: http.StatusOK, want: `{"error":"boom"}`},
+		{name: "missing header", method: http.MethodPost, expr: "hi", status: http.StatusForbidden},
+		{name: "get", method: http.MethodGet, header: true, expr: "hi", status: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/eval", strings.NewReader(`{"expr":"`+tt.expr+`"}`))
+			if tt.header {
+				req.Header.Set(devtoolsHeader, "1")
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.status)
+			}
+			if tt.want != "" && rec.Body.String() != tt.want {
+				t.Errorf("body = %s, want %s", rec.Body, tt.want)
+			}
+		})
+	}
+}
+
+func TestDevToolsHandlerPage(t *testing.T) {
+	rec := httptest.NewRecorder()
+	DevToolsHandler(&fakeWebView{}, DevToolsOptions{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Fatalf("Content-Type = %q, want HTML", ct)
+	}
+	if !strings.Contains(rec.Body.String(), `fetch("state"`) {
+		t.Error("page does not poll the state endpoint")
+	}
+}
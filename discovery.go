@@ -0,0 +1,637 @@
+package glaze
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// mDNS constants (RFC 6762, RFC 6763).
+const (
+	mdnsPort = 5353
+
+	dnsTypeA   = 1
+	dnsTypePTR = 12
+	dnsTypeTXT = 16
+	dnsTypeSRV = 33
+	dnsTypeANY = 255
+
+	dnsClassIN    = 1
+	dnsCacheFlush = 0x8000
+	dnsUnicast    = 0x8000
+
+	// mdnsTTL is the lifetime of the records Discovery advertises.
+	mdnsTTL = 120
+)
+
+// mdnsGroup is the IPv4 mDNS multicast address.
+var mdnsGroup = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: mdnsPort}
+
+// defaultDiscoveryInterval is how often Discovery looks for peers when
+// DiscoveryOptions.Interval is zero.
+const defaultDiscoveryInterval = 10 * time.Second
+
+// serviceType matches DNS-SD service types such as "_notes._tcp".
+var serviceType = regexp.MustCompile(`^_[a-z0-9][a-z0-9-]{0,14}\._(tcp|udp)$`)
+
+// Peer is an instance of the service found on the local network.
+type Peer struct {
+	// Instance is the name the peer advertises, e.g. "Alice's laptop".
+	Instance string `json:"instance"`
+
+	// Host is the peer's mDNS host name, e.g. "alice.local".
+	Host string `json:"host"`
+
+	// Addrs are the IPv4 addresses of Host.
+	Addrs []string `json:"addrs"`
+
+	// Port is the port the peer's service listens on.
+	Port int `json:"port"`
+
+	// Text holds the key/value pairs of the peer's TXT record.
+	Text map[string]string `json:"text,omitempty"`
+}
+
+// DiscoveryOptions configures StartDiscovery.
+type DiscoveryOptions struct {
+	// Service is the DNS-SD service type shared by the app's instances,
+	// such as "_notes._tcp". Required.
+	Service string
+
+	// Instance names this instance. Defaults to the host name.
+	Instance string
+
+	// Port is the port this instance's service listens on, e.g. an
+	// AppWindow or peer server. Zero only browses, without advertising.
+	Port int
+
+	// Text is published in the TXT record, e.g. a protocol version.
+	Text map[string]string
+
+	// Interval is how often peers are looked for. Defaults to 10 seconds.
+	Interval time.Duration
+
+	// OnPeers receives the peers found, sorted by instance, each time the
+	// list changes, from a background goroutine.
+	OnPeers func([]Peer)
+
+	// Prefix, when set, binds {Prefix}_peers for the page and emits
+	// "{Prefix}:peers" events with the peer list.
+	Prefix string
+}
+
+// Discovery advertises an app on the local network with multicast DNS
+// (Bonjour) and finds other instances of it, for local collaboration such as
+// sending a note to another machine. Only IPv4 is used.
+type Discovery struct {
+	w    WebView
+	opts DiscoveryOptions
+	conn *net.UDPConn
+	host string
+
+	mu    sync.Mutex
+	peers map[string]*peerEntry
+
+	done   chan struct{}
+	closed sync.Once
+	wg     sync.WaitGroup
+}
+
+// peerEntry is a peer with the expiry of its records.
+type peerEntry struct {
+	peer    Peer
+	expires time.Time
+	ready   bool
+}
+
+// StartDiscovery starts advertising this instance, when opts.Port is set,
+// and looking for peers:
+//
+//	d, err := glaze.StartDiscovery(w, glaze.DiscoveryOptions{
+//		Service: "_notes._tcp",
+//		Port:    port,
+//		Prefix:  "lan",
+//	})
+//	defer d.Close()
+//
+//	// JavaScript
+//	glaze.on("lan:peers", (peers) => renderPeers(peers));
+//
+// w may be nil when opts.Prefix is empty. Like Bind, StartDiscovery must be
+// called from the UI thread when opts.Prefix is set.
+func StartDiscovery(w WebView, opts DiscoveryOptions) (*Discovery, error) {
+	if !serviceType.MatchString(opts.Service) {
+		return nil, fmt.Errorf("webview: invalid service type %q", opts.Service)
+	}
+	if opts.Prefix != "" && w == nil {
+		return nil, errors.New("webview: StartDiscovery requires a non-nil WebView with a Prefix")
+	}
+	host, _ := os.Hostname()
+	host = dnsLabel(strings.Split(host, ".")[0])
+	if host == "" {
+		host = "glaze"
+	}
+	if opts.Instance == "" {
+		opts.Instance = host
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultDiscoveryInterval
+	}
+	d := &Discovery{
+		w:     w,
+		opts:  opts,
+		host:  host + ".local",
+		peers: make(map[string]*peerEntry),
+		done:  make(chan struct{}),
+	}
+	conn, err := net.ListenMulticastUDP("udp4", nil, mdnsGroup)
+	if err != nil {
+		return nil, fmt.Errorf("webview: start discovery: %w", err)
+	}
+	d.conn = conn
+	if opts.Prefix != "" {
+		if err := w.Bind(opts.Prefix+"_peers", d.Peers); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	d.wg.Add(2)
+	go d.read()
+	go d.browse()
+	return d, nil
+}
+
+// Peers returns the peers currently known, sorted by instance.
+func (d *Discovery) Peers() []Peer {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.peersLocked()
+}
+
+func (d *Discovery) peersLocked() []Peer {
+	peers := make([]Peer, 0, len(d.peers))
+	for _, e := range d.peers {
+		if e.ready {
+			peers = append(peers, e.peer)
+		}
+	}
+	slices.SortFunc(peers, func(a, b Peer) int { return strings.Compare(a.Instance, b.Instance) })
+	return peers
+}
+
+// Close withdraws the advertisement and stops looking for peers.
+func (d *Discovery) Close() error {
+	var err error
+	d.closed.Do(func() {
+		close(d.done)
+		if d.opts.Port > 0 {
+			// A zero TTL tells peers the instance is gone.
+			_, _ = d.conn.WriteToUDP(d.announcement(0), mdnsGroup)
+		}
+		err = d.conn.Close()
+		d.wg.Wait()
+	})
+	return err
+}
+
+func (d *Discovery) read() {
+	defer d.wg.Done()
+	buf := make([]byte, 9000)
+	for {
+		n, from, err := d.conn.ReadFromUDP(buf)
+		if err != nil {
+			select {
+			case <-d.done:
+				return
+			default:
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		if reply, unicast := d.handle(buf[:n], time.Now()); reply != nil {
+			dst := mdnsGroup
+			if unicast {
+				dst = from
+			}
+			_, _ = d.conn.WriteToUDP(reply, dst)
+		}
+	}
+}
+
+func (d *Discovery) browse() {
+	defer d.wg.Done()
+	if d.opts.Port > 0 {
+		_, _ = d.conn.WriteToUDP(d.announcement(mdnsTTL), mdnsGroup)
+	}
+	ticker := time.NewTicker(d.opts.Interval)
+	defer ticker.Stop()
+	for {
+		_, _ = d.conn.WriteToUDP(d.query(), mdnsGroup)
+		d.expire(time.Now())
+		select {
+		case <-d.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// serviceName is the DNS-SD name of the service, e.g. "_notes._tcp.local".
+func (d *Discovery) serviceName() string {
+	return d.opts.Service + ".local"
+}
+
+// instanceName is the DNS-SD name of this instance.
+func (d *Discovery) instanceName() string {
+	return dnsLabel(d.opts.Instance) + "." + d.serviceName()
+}
+
+// query returns a query for instances of the service.
+func (d *Discovery) query() []byte {
+	var m dnsMessage
+	m.questions = []dnsQuestion{{name: d.serviceName(), typ: dnsTypePTR, class: dnsClassIN}}
+	return m.pack()
+}
+
+// announcement returns the records advertising this instance with ttl.
+func (d *Discovery) announcement(ttl uint32) []byte {
+	m := dnsMessage{response: true}
+	instance := d.instanceName()
+	m.answers = []dnsRecord{{name: d.serviceName(), typ: dnsTypePTR, class: dnsClassIN, ttl: ttl, target: instance}}
+	m.extra = append(m.extra,
+		dnsRecord{name: instance, typ: dnsTypeSRV, class: dnsClassIN | dnsCacheFlush, ttl: ttl, port: uint16(d.opts.Port), target: d.host},
+		dnsRecord{name: instance, typ: dnsTypeTXT, class: dnsClassIN | dnsCacheFlush, ttl: ttl, text: textRecord(d.opts.Text)},
+	)
+	for _, ip := range localIPv4() {
+		m.extra = append(m.extra, dnsRecord{name: d.host, typ: dnsTypeA, class: dnsClassIN | dnsCacheFlush, ttl: ttl, ip: ip})
+	}
+	return m.pack()
+}
+
+// handle processes a received packet at now and returns the reply to send,
+// if any, and whether the query asked for it by unicast.
+func (d *Discovery) handle(packet []byte, now time.Time) ([]byte, bool) {
+	m, err := parseDNS(packet)
+	if err != nil {
+		return nil, false
+	}
+	if m.response {
+		d.learn(m, now)
+		return nil, false
+	}
+	if d.opts.Port <= 0 {
+		return nil, false
+	}
+	for _, q := range m.questions {
+		if !strings.EqualFold(q.name, d.serviceName()) && !strings.EqualFold(q.name, d.instanceName()) {
+			continue
+		}
+		switch q.typ {
+		case dnsTypePTR, dnsTypeSRV, dnsTypeTXT, dnsTypeANY:
+			return d.announcement(mdnsTTL), q.class&dnsUnicast != 0
+		}
+	}
+	return nil, false
+}
+
+// learn records the peers described by the response m.
+func (d *Discovery) learn(m *dnsMessage, now time.Time) {
+	records := append(slices.Clone(m.answers), m.extra...)
+	self := d.instanceName()
+	d.mu.Lock()
+	before := d.peersLocked()
+	for _, r := range records {
+		if r.typ != dnsTypePTR || !strings.EqualFold(r.name, d.serviceName()) || strings.EqualFold(r.target, self) {
+			continue
+		}
+		key := strings.ToLower(r.target)
+		if r.ttl == 0 {
+			delete(d.peers, key)
+			continue
+		}
+		e := d.peers[key]
+		if e == nil {
+			instance, _, _ := strings.Cut(r.target, "."+d.serviceName())
+			e = &peerEntry{peer: Peer{Instance: instance}}
+			d.peers[key] = e
+		}
+		e.expires = now.Add(time.Duration(r.ttl) * time.Second)
+	}
+	for key, e := range d.peers {
+		for _, r := range records {
+			if !strings.EqualFold(r.name, key) {
+				continue
+			}
+			switch r.typ {
+			case dnsTypeSRV:
+				e.peer.Host = strings.TrimSuffix(r.target, ".")
+				e.peer.Port = int(r.port)
+				e.ready = true
+			case dnsTypeTXT:
+				e.peer.Text = parseTextRecord(r.text)
+			}
+		}
+		if e.peer.Host == "" {
+			continue
+		}
+		var addrs []string
+		for _, r := range records {
+			if r.typ == dnsTypeA && strings.EqualFold(r.name, e.peer.Host) {
+				addrs = append(addrs, r.ip.String())
+			}
+		}
+		if len(addrs) > 0 {
+			slices.Sort(addrs)
+			e.peer.Addrs = slices.Compact(addrs)
+		}
+	}
+	d.changedLocked(before)
+}
+
+// expire drops the peers whose records have expired at now.
+func (d *Discovery) expire(now time.Time) {
+	d.mu.Lock()
+	before := d.peersLocked()
+	maps.DeleteFunc(d.peers, func(_ string, e *peerEntry) bool { return now.After(e.expires) })
+	d.changedLocked(before)
+}
+
+// changedLocked reports the peers if they differ from before and unlocks
+// d.mu.
+func (d *Discovery) changedLocked(before []Peer) {
+	after := d.peersLocked()
+	d.mu.Unlock()
+	if slices.EqualFunc(before, after, peerEqual) {
+		return
+	}
+	if d.opts.Prefix != "" {
+		_ = Emit(d.w, d.opts.Prefix+":peers", after)
+	}
+	if d.opts.OnPeers != nil {
+		d.opts.OnPeers(after)
+	}
+}
+
+func peerEqual(a, b Peer) bool {
+	return a.Instance == b.Instance && a.Host == b.Host && a.Port == b.Port &&
+		slices.Equal(a.Addrs, b.Addrs) && maps.Equal(a.Text, b.Text)
+}
+
+// dnsLabel makes s usable as one DNS label.
+func dnsLabel(s string) string {
+	s = strings.ReplaceAll(s, ".", "-")
+	if len(s) > 63 {
+		s = s[:63]
+	}
+	return s
+}
+
+// localIPv4 returns the non-loopback IPv4 addresses of this machine.
+func localIPv4() []net.IP {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+	var ips []net.IP
+	for _, a := range addrs {
+		if n, ok := a.(*net.IPNet); ok && !n.IP.IsLoopback() && n.IP.To4() != nil {
+			ips = append(ips, n.IP.To4())
+		}
+	}
+	return ips
+}
+
+// textRecord encodes kv as TXT strings, sorted by key.
+func textRecord(kv map[string]string) []string {
+	if len(kv) == 0 {
+		// A TXT record must hold at least one string.
+		return []string{""}
+	}
+	text := make([]string, 0, len(kv))
+	for _, k := range slices.Sorted(maps.Keys(kv)) {
+		text = append(text, k+"="+kv[k])
+	}
+	return text
+}
+
+func parseTextRecord(text []string) map[string]string {
+	kv := make(map[string]string)
+	for _, s := range text {
+		if s == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(s, "=")
+		kv[k] = v
+	}
+	if len(kv) == 0 {
+		return nil
+	}
+	return kv
+}
+
+// dnsMessage is the subset of a DNS message mDNS service discovery uses.
+type dnsMessage struct {
+	response  bool
+	questions []dnsQuestion
+	answers   []dnsRecord
+	extra     []dnsRecord // authority and additional records
+}
+
+type dnsQuestion struct {
+	name  string
+	typ   uint16
+	class uint16
+}
+
+type dnsRecord struct {
+	name  string
+	typ   uint16
+	class uint16
+	ttl   uint32
+
+	target string   // PTR, SRV
+	port   uint16   // SRV
+	text   []string // TXT
+	ip     net.IP   // A
+	raw    []byte   // other types
+}
+
+var errDNSFormat = errors.New("malformed DNS message")
+
+func (m *dnsMessage) pack() []byte {
+	b := make([]byte, 12, 512)
+	if m.response {
+		binary.BigEndian.PutUint16(b[2:], 0x8400) // response, authoritative
+	}
+	binary.BigEndian.PutUint16(b[4:], uint16(len(m.questions)))
+	binary.BigEndian.PutUint16(b[6:], uint16(len(m.answers)))
+	binary.BigEndian.PutUint16(b[10:], uint16(len(m.extra)))
+	for _, q := range m.questions {
+		b = appendDNSName(b, q.name)
+		b = binary.BigEndian.AppendUint16(b, q.typ)
+		b = binary.BigEndian.AppendUint16(b, q.class)
+	}
+	for _, r := range append(slices.Clone(m.answers), m.extra...) {
+		b = appendDNSName(b, r.name)
+		b = binary.BigEndian.AppendUint16(b, r.typ)
+		b = binary.BigEndian.AppendUint16(b, r.class)
+		b = binary.BigEndian.AppendUint32(b, r.ttl)
+		var data []byte
+		switch r.typ {
+		case dnsTypePTR:
+			data = appendDNSName(nil, r.target)
+		case dnsTypeSRV:
+			data = make([]byte, 6) // priority and weight zero
+			binary.BigEndian.PutUint16(data[4:], r.port)
+			data = appendDNSName(data, r.target)
+		case dnsTypeTXT:
+			for _, s := range r.text {
+				if len(s) > 255 {
+					s = s[:255]
+				}
+				data = append(data, byte(len(s)))
+				data = append(data, s...)
+			}
+		case dnsTypeA:
+			data = r.ip.To4()
+		default:
+			data = r.raw
+		}
+		b = binary.BigEndian.AppendUint16(b, uint16(len(data)))
+		b = append(b, data...)
+	}
+	return b
+}
+
+func appendDNSName(b []byte, name string) []byte {
+	for label := range strings.SplitSeq(strings.TrimSuffix(name, "."), ".") {
+		if label == "" {
+			continue
+		}
+		b = append(b, byte(len(label)))
+		b = append(b, label...)
+	}
+	return append(b, 0)
+}
+
+func parseDNS(b []byte) (*dnsMessage, error) {
+	if len(b) < 12 {
+		return nil, errDNSFormat
+	}
+	m := &dnsMessage{response: b[2]&0x80 != 0}
+	qd := int(binary.BigEndian.Uint16(b[4:]))
+	rr := int(binary.BigEndian.Uint16(b[6:])) + int(binary.BigEndian.Uint16(b[8:])) + int(binary.BigEndian.Uint16(b[10:]))
+	an := int(binary.BigEndian.Uint16(b[6:]))
+	off := 12
+	for range qd {
+		name, n, err := readDNSName(b, off)
+		if err != nil || n+4 > len(b) {
+			return nil, errDNSFormat
+		}
+		m.questions = append(m.questions, dnsQuestion{
+			name:  name,
+			typ:   binary.BigEndian.Uint16(b[n:]),
+			class: binary.BigEndian.Uint16(b[n+2:]),
+		})
+		off = n + 4
+	}
+	for i := range rr {
+		name, n, err := readDNSName(b, off)
+		if err != nil || n+10 > len(b) {
+			return nil, errDNSFormat
+		}
+		r := dnsRecord{
+			name:  name,
+			typ:   binary.BigEndian.Uint16(b[n:]),
+			class: binary.BigEndian.Uint16(b[n+2:]),
+			ttl:   binary.BigEndian.Uint32(b[n+4:]),
+		}
+		size := int(binary.BigEndian.Uint16(b[n+8:]))
+		start := n + 10
+		if start+size > len(b) {
+			return nil, errDNSFormat
+		}
+		data := b[start : start+size]
+		switch r.typ {
+		case dnsTypePTR:
+			if r.target, _, err = readDNSName(b, start); err != nil {
+				return nil, err
+			}
+		case dnsTypeSRV:
+			if size < 7 {
+				return nil, errDNSFormat
+			}
+			r.port = binary.BigEndian.Uint16(data[4:])
+			if r.target, _, err = readDNSName(b, start+6); err != nil {
+				return nil, err
+			}
+		case dnsTypeTXT:
+			for len(data) > 0 {
+				l := int(data[0])
+				if 1+l > len(data) {
+					return nil, errDNSFormat
+				}
+				r.text = append(r.text, string(data[1:1+l]))
+				data = data[1+l:]
+			}
+		case dnsTypeA:
+			if size == 4 {
+				r.ip = net.IP(slices.Clone(data))
+			}
+		default:
+			r.raw = slices.Clone(data)
+		}
+		if i < an {
+			m.answers = append(m.answers, r)
+		} else {
+			m.extra = append(m.extra, r)
+		}
+		off = start + size
+	}
+	return m, nil
+}
+
+// readDNSName reads the possibly compressed name at off in b, returning it
+// and the offset after it.
+func readDNSName(b []byte, off int) (string, int, error) {
+	var labels []string
+	end := -1
+	for jumps := 0; ; {
+		if off >= len(b) {
+			return "", 0, errDNSFormat
+		}
+		l := int(b[off])
+		switch {
+		case l == 0:
+			if end < 0 {
+				end = off + 1
+			}
+			return strings.Join(labels, "."), end, nil
+		case l&0xc0 == 0xc0:
+			if off+1 >= len(b) || jumps > 10 {
+				return "", 0, errDNSFormat
+			}
+			if end < 0 {
+				end = off + 2
+			}
+			off = int(binary.BigEndian.Uint16(b[off:]) & 0x3fff)
+			jumps++
+		default:
+			if off+1+l > len(b) {
+				return "", 0, errDNSFormat
+			}
+			labels = append(labels, string(b[off+1:off+1+l]))
+			off += 1 + l
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package glaze
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func testDiscovery(instance string, port int) *Discovery {
+	return &Discovery{
+		opts:  DiscoveryOptions{Service: "_notes._tcp", Instance: instance, Port: port, Text: map[string]string{"v": "1"}},
+		host:  instance + ".local",
+		peers: make(map[string]*peerEntry),
+	}
+}
+
+func TestDiscoveryFindsPeer(t *testing.T) {
+	alice := testDiscovery("alice", 8080)
+	bob := testDiscovery("bob", 0)
+	var seen [][]Peer
+	bob.opts.OnPeers = func(p []Peer) { seen = append(seen, p) }
+	now := time.Unix(1_700_000_000, 0)
+
+	// Bob asks; Alice answers; Bob learns.
+	reply, unicast := alice.handle(bob.query(), now)
+	if reply == nil || unicast {
+		t.Fatalf("handle(query) = %v, %v; want a multicast announcement", reply, unicast)
+	}
+	if r, _ := bob.handle(reply, now); r != nil {
+		t.Error("a response was answered")
+	}
+	peers := bob.Peers()
+	if len(peers) != 1 {
+		t.Fatalf("Peers() = %+v, want alice", peers)
+	}
+	p := peers[0]
+	if p.Instance != "alice" || p.Host != "alice.local" || p.Port != 8080 || p.Text["v"] != "1" {
+		t.Errorf("peer = %+v", p)
+	}
+	if want := localIPv4(); len(want) > 0 && len(p.Addrs) == 0 {
+		t.Errorf("peer addresses = %v, want the announced ones", p.Addrs)
+	}
+	if len(seen) != 1 {
+		t.Errorf("OnPeers called %d times, want 1", len(seen))
+	}
+
+	// Hearing the same announcement changes nothing.
+	bob.handle(reply, now)
+	if len(seen) != 1 {
+		t.Errorf("OnPeers called %d times after a repeat, want 1", len(seen))
+	}
+
+	// Bob does not answer: it only browses. Nor does it list itself.
+	if r, _ := bob.handle(alice.query(), now); r != nil {
+		t.Error("browse-only instance answered a query")
+	}
+	alice.handle(reply, now)
+	if len(alice.Peers()) != 0 {
+		t.Error("instance listed itself as a peer")
+	}
+
+	// Goodbye and expiry.
+	bob.handle(alice.announcement(0), now)
+	if len(bob.Peers()) != 0 || len(seen) != 2 || len(seen[1]) != 0 {
+		t.Errorf("after goodbye Peers() = %+v, OnPeers calls %d", bob.Peers(), len(seen))
+	}
+	bob.handle(reply, now)
+	bob.expire(now.Add((mdnsTTL + 1) * time.Second))
+	if len(bob.Peers()) != 0 {
+		t.Error("expired peer still listed")
+	}
+}
+
+func TestDNSMessageRoundTrip(t *testing.T) {
+	m := dnsMessage{
+		response: true,
+		answers:  []dnsRecord{{name: "_notes._tcp.local", typ: dnsTypePTR, class: dnsClassIN, ttl: 120, target: "alice._notes._tcp.local"}},
+		extra: []dnsRecord{
+			{name: "alice._notes._tcp.local", typ: dnsTypeSRV, class: dnsClassIN, ttl: 120, port: 9000, target: "alice.local"},
+			{name: "alice._notes._tcp.local", typ: dnsTypeTXT, class: dnsClassIN, ttl: 120, text: []string{"a=1", "b="}},
+			{name: "alice.local", typ: dnsTypeA, class: dnsClassIN, ttl: 120, ip: []byte{192, 168, 1, 7}},
+		},
+	}
+	got, err := parseDNS(m.pack())
+	if err != nil {
+		t.Fatalf("parseDNS() unexpected error: %v", err)
+	}
+	if !got.response || len(got.answers) != 1 || len(got.extra) != 3 {
+		t.Fatalf("parseDNS() = %+v", got)
+	}
+	if got.answers[0].target != "alice._notes._tcp.local" || got.extra[0].port != 9000 || got.extra[0].target != "alice.local" {
+		t.Errorf("PTR/SRV = %+v %+v", got.answers[0], got.extra[0])
+	}
+	if !slices.Equal(got.extra[1].text, []string{"a=1", "b="}) || got.extra[2].ip.String() != "192.168.1.7" {
+		t.Errorf("TXT/A = %+v %+v", got.extra[1], got.extra[2])
+	}
+
+	// Compressed names, as other responders send them.
+	packet := []byte{0, 0, 0x84, 0, 0, 0, 0, 1, 0, 0, 0, 0}
+	packet = appendDNSName(packet, "_notes._tcp.local")
+	packet = append(packet, 0, dnsTypePTR, 0, 1, 0, 0, 0, 120, 0, 8, 5, 'c', 'a', 'r', 'o', 'l', 0xc0, 12)
+	got, err = parseDNS(packet)
+	if err != nil || got.answers[0].target != "carol._notes._tcp.local" {
+		t.Errorf("compressed PTR = %+v, %v", got, err)
+	}
+
+	for _, bad := range [][]byte{nil, packet[:20], append(packet[:len(packet)-2:len(packet)-2], 0xc0, 0xff)} {
+		if _, err := parseDNS(bad); err == nil {
+			t.Errorf("parseDNS(%v) returned no error", bad)
+		}
+	}
+}
+
+func TestStartDiscoveryRejects(t *testing.T) {
+	for _, service := range []string{"", "notes", "_notes._xyz", "_Notes._tcp"} {
+		if _, err := StartDiscovery(nil, DiscoveryOptions{Service: service}); err == nil {
+			t.Errorf("StartDiscovery(%q) returned no error", service)
+		}
+	}
+	if _, err := StartDiscovery(nil, DiscoveryOptions{Service: "_notes._tcp", Prefix: "lan"}); err == nil {
+		t.Error("StartDiscovery() with a Prefix and no WebView returned no error")
+	}
+}
@@ -0,0 +1,35 @@
+package glaze
+
+// DispatchSync runs fn on the UI thread of w, like Dispatch, and waits for
+// its result, so background goroutines can read native state before going
+// on:
+//
+//	handle := glaze.DispatchSync(w, func() unsafe.Pointer { return w.Window() })
+//
+// A panic in fn is raised again in the caller instead of on the UI thread.
+// DispatchSync must not be called from the UI thread, where it would wait
+// for itself forever, nor after the main loop has stopped.
+func DispatchSync[T any](w WebView, fn func() T) T {
+	type result struct {
+		value  T
+		panicv any
+		failed bool
+	}
+	done := make(chan result, 1)
+	w.Dispatch(func() {
+		r := result{failed: true}
+		defer func() {
+			if r.failed {
+				r.panicv = recover()
+			}
+			done <- r
+		}()
+		r.value = fn()
+		r.failed = false
+	})
+	r := <-done
+	if r.failed {
+		panic(r.panicv)
+	}
+	return r.value
+}
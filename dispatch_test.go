@@ -0,0 +1,29 @@
+package glaze
+
+import "testing"
+
+// asyncWebView runs dispatched functions on another goroutine, as the
+// native main loop does.
+type asyncWebView struct {
+	fakeWebView
+}
+
+func (a *asyncWebView) Dispatch(fn func()) { go fn() }
+
+func TestDispatchSync(t *testing.T) {
+	w := &asyncWebView{}
+	defer w.Destroy()
+
+	w.SetTitle("Notes")
+	if got := DispatchSync(w, func() string { return w.title }); got != "Notes" {
+		t.Errorf("DispatchSync() = %q, want Notes", got)
+	}
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recovered %v, want the panic of fn", r)
+		}
+	}()
+	DispatchSync(w, func() int { panic("boom") })
+	t.Error("DispatchSync() returned after fn panicked")
+}
@@ -0,0 +1,42 @@
+package glaze
+
+// DockMenuItem is an entry of the macOS dock menu.
+type DockMenuItem struct {
+	// Title is the text shown for the item.
+	Title string
+
+	// Action is called on the UI thread when the item is chosen.
+	Action func()
+
+	// Disabled greys the item out.
+	Disabled bool
+
+	// Separator makes the item a separator line; other fields are ignored.
+	Separator bool
+
+	// Submenu, when non-empty, turns the item into a submenu such as
+	// "Recent Notes".
+	Submenu []DockMenuItem
+}
+
+// dockActions assigns a tag to every item with an Action, depth first, and
+// returns the actions indexed by tag. The native menu stores the tag on each
+// item so a single Objective-C target can route clicks back to Go.
+func dockActions(items []DockMenuItem) []func() {
+	var actions []func()
+	var walk func([]DockMenuItem)
+	walk = func(items []DockMenuItem) {
+		for _, item := range items {
+			if item.Separator {
+				continue
+			}
+			if len(item.Submenu) > 0 {
+				walk(item.Submenu)
+				continue
+			}
+			actions = append(actions, item.Action)
+		}
+	}
+	walk(items)
+	return actions
+}
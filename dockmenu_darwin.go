@@ -0,0 +1,121 @@
+package glaze
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ebitengine/purego/objc"
+)
+
+var dock struct {
+	once   sync.Once
+	err    error
+	target objc.ID
+
+	mu      sync.Mutex
+	menu    objc.ID
+	actions []func()
+}
+
+// SetDockMenu replaces the items shown above the standard entries when the
+// user right-clicks the app's dock icon, such as recent items, "New Window"
+// or "Pause Sync". Pass nil to remove the custom items.
+//
+// It must be called from the UI thread after the first window was created.
+func SetDockMenu(items []DockMenuItem) error {
+	dock.once.Do(installDockMenu)
+	if dock.err != nil {
+		return dock.err
+	}
+
+	var menu objc.ID
+	if len(items) > 0 {
+		tag := 0
+		menu = buildDockMenu(items, &tag)
+	}
+
+	dock.mu.Lock()
+	old := dock.menu
+	dock.menu = menu
+	dock.actions = dockActions(items)
+	dock.mu.Unlock()
+	if old != 0 {
+		old.Send(objc.RegisterName("release"))
+	}
+	return nil
+}
+
+// installDockMenu teaches the application delegate created by the native
+// webview library to answer applicationDockMenu: and registers the target
+// class that routes menu clicks to Go.
+func installDockMenu() {
+	app := objc.ID(objc.GetClass("NSApplication")).Send(objc.RegisterName("sharedApplication"))
+	delegate := app.Send(objc.RegisterName("delegate"))
+	if delegate == 0 {
+		dock.err = errors.New("webview: SetDockMenu requires a window to be created first")
+		return
+	}
+
+	dockMenu := func(_ objc.ID, _ objc.SEL, _ objc.ID) objc.ID {
+		dock.mu.Lock()
+		defer dock.mu.Unlock()
+		return dock.menu
+	}
+	if !delegate.Class().AddMethod(objc.RegisterName("applicationDockMenu:"), objc.NewIMP(dockMenu), "@@:@") {
+		dock.err = errors.New("webview: application delegate already provides a dock menu")
+		return
+	}
+
+	class, err := objc.RegisterClass("GlazeDockMenuTarget", objc.GetClass("NSObject"), nil, nil, []objc.MethodDef{{
+		Cmd: objc.RegisterName("glazeDockAction:"),
+		Fn: func(_ objc.ID, _ objc.SEL, sender objc.ID) {
+			tag := objc.Send[int](sender, objc.RegisterName("tag"))
+			dock.mu.Lock()
+			var action func()
+			if tag >= 0 && tag < len(dock.actions) {
+				action = dock.actions[tag]
+			}
+			dock.mu.Unlock()
+			if action != nil {
+				action()
+			}
+		},
+	}})
+	if err != nil {
+		dock.err = err
+		return
+	}
+	dock.target = objc.ID(class).Send(objc.RegisterName("new"))
+}
+
+// buildDockMenu creates a retained NSMenu for items, numbering actionable
+// items in the same order as dockActions.
+func buildDockMenu(items []DockMenuItem, tag *int) objc.ID {
+	menu := objc.ID(objc.GetClass("NSMenu")).Send(objc.RegisterName("alloc")).
+		Send(objc.RegisterName("initWithTitle:"), nsString(""))
+	menu.Send(objc.RegisterName("setAutoenablesItems:"), false)
+
+	for _, item := range items {
+		if item.Separator {
+			menu.Send(objc.RegisterName("addItem:"), objc.ID(objc.GetClass("NSMenuItem")).Send(objc.RegisterName("separatorItem")))
+			continue
+		}
+		mi := objc.ID(objc.GetClass("NSMenuItem")).Send(objc.RegisterName("alloc")).
+			Send(objc.RegisterName("initWithTitle:action:keyEquivalent:"), nsString(item.Title), objc.SEL(0), nsString(""))
+		mi.Send(objc.RegisterName("setEnabled:"), !item.Disabled)
+		if len(item.Submenu) > 0 {
+			sub := buildDockMenu(item.Submenu, tag)
+			sub.Send(objc.RegisterName("setTitle:"), nsString(item.Title))
+			mi.Send(objc.RegisterName("setSubmenu:"), sub)
+			sub.Send(objc.RegisterName("release"))
+		} else {
+			mi.Send(objc.RegisterName("setTarget:"), dock.target)
+			mi.Send(objc.RegisterName("setAction:"), objc.RegisterName("glazeDockAction:"))
+			mi.Send(objc.RegisterName("setTag:"), *tag)
+			*tag++
+		}
+		menu.Send(objc.RegisterName("addItem:"), mi)
+		mi.Send(objc.RegisterName("release"))
+	}
+	return menu
+}
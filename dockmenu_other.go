@@ -0,0 +1,11 @@
+//go:build !darwin
+
+package glaze
+
+import "errors"
+
+// SetDockMenu replaces the items of the macOS dock menu. Other platforms
+// have no dock menu, so it always returns an error there.
+func SetDockMenu(_ []DockMenuItem) error {
+	return errors.New("webview: dock menus are only supported on macOS")
+}
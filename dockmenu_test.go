@@ -0,0 +1,30 @@
+package glaze
+
+import "testing"
+
+func TestDockActionsOrder(t *testing.T) {
+	var got []string
+	record := func(name string) func() { return func() { got = append(got, name) } }
+
+	actions := dockActions([]DockMenuItem{
+		{Title: "New Window", Action: record("new")},
+		{Separator: true},
+		{Title: "Recent", Submenu: []DockMenuItem{
+			{Title: "a.txt", Action: record("a")},
+			{Title: "b.txt", Action: record("b")},
+		}},
+		{Title: "Pause Sync", Action: record("pause")},
+	})
+	if len(actions) != 4 {
+		t.Fatalf("actions = %d, want 4", len(actions))
+	}
+	for _, a := range actions {
+		a()
+	}
+	want := []string{"new", "a", "b", "pause"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("action order = %v, want %v", got, want)
+		}
+	}
+}
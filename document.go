@@ -0,0 +1,430 @@
+package glaze
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Errors returned by Document.
+var (
+	// ErrNoPath is returned by Save for a document that was never saved.
+	ErrNoPath = errors.New("webview: document has no file; use SaveAs")
+
+	// ErrDiscardVetoed is returned when a document with unsaved changes
+	// would be replaced or closed and DocumentOptions.ConfirmDiscard did
+	// not allow it.
+	ErrDiscardVetoed = errors.New("webview: unsaved changes were kept")
+)
+
+// defaultMaxRecent is the length of the recent files list when
+// DocumentOptions.MaxRecent is zero.
+const defaultMaxRecent = 10
+
+// DocumentOptions configures NewDocument.
+type DocumentOptions struct {
+	// ID names the app, e.g. "com.example.notes". The recent files list is
+	// kept in a directory of that name under os.UserConfigDir. With no ID
+	// and no RecentPath it is kept in memory only.
+	ID string
+
+	// RecentPath overrides the file the recent files list is kept in.
+	RecentPath string
+
+	// MaxRecent bounds the recent files list. Defaults to 10.
+	MaxRecent int
+
+	// Title is the app name shown after the document name in the window
+	// title, as in "notes.md - Notes". A leading "• " marks unsaved
+	// changes.
+	Title string
+
+	// Read loads the file at path into the app. Required by Open.
+	Read func(path string) error
+
+	// Write saves the app's document to path. Required.
+	Write func(path string) error
+
+	// Autosave saves a document that has a file once it has had unsaved
+	// changes for this long. Zero disables autosave.
+	Autosave time.Duration
+
+	// ConfirmDiscard is asked before unsaved changes would be lost to New,
+	// Open or Close; returning false vetoes the operation with
+	// ErrDiscardVetoed. When nil, documents with unsaved changes are never
+	// discarded.
+	ConfirmDiscard func() bool
+
+	// OnChange is called after the file, dirty flag or recent files list
+	// changes.
+	OnChange func(DocumentState)
+
+	// OnAutosaveError receives the errors of autosaves.
+	OnAutosaveError func(error)
+
+	// Prefix, when set, binds {Prefix}_state, {Prefix}_changed and
+	// {Prefix}_save for the page and emits "{Prefix}:change" events with
+	// the DocumentState, so the page can mark edits and show the state.
+	Prefix string
+}
+
+// DocumentState is a snapshot of a Document.
+type DocumentState struct {
+	// Path is the file of the document, or empty for a new one.
+	Path string `json:"path"`
+
+	// Name is the base name of Path, or "Untitled".
+	Name string `json:"name"`
+
+	// Dirty reports unsaved changes.
+	Dirty bool `json:"dirty"`
+
+	// Recent lists recently opened and saved files, newest first.
+	Recent []string `json:"recent"`
+}
+
+// Document manages the lifecycle of a file-based app's document: new, open,
+// save and save as, the unsaved-changes flag shown in the window title,
+// autosave and the recent files list. The app keeps its own model and
+// provides Read and Write; the page or Go code calls MarkDirty after each
+// edit:
+//
+//	doc, err := glaze.NewDocument(w, glaze.DocumentOptions{
+//		ID:             "com.example.notes",
+//		Title:          "Notes",
+//		Read:           editor.Load,
+//		Write:          editor.Store,
+//		Autosave:       30 * time.Second,
+//		ConfirmDiscard: func() bool { return askYesNo("Discard changes?") },
+//		Prefix:         "doc",
+//	})
+//
+//	// JavaScript
+//	editor.addEventListener("input", () => doc_changed());
+//	glaze.on("doc:change", (s) => saveButton.disabled = !s.dirty);
+//
+// Call Close from the app's quit path so unsaved changes can veto it. The
+// methods of a Document may be called from any goroutine.
+type Document struct {
+	w    WebView
+	opts DocumentOptions
+
+	mu     sync.Mutex
+	path   string
+	dirty  bool
+	edits  uint64
+	recent []string
+	timer  *time.Timer
+
+	// saveMu serializes writes.
+	saveMu sync.Mutex
+}
+
+// NewDocument returns an untitled Document shown in w. It must be called
+// from the UI thread when opts.Prefix is set, like Bind.
+func NewDocument(w WebView, opts DocumentOptions) (*Document, error) {
+	if w == nil {
+		return nil, errors.New("webview: NewDocument requires a non-nil WebView")
+	}
+	if opts.Write == nil {
+		return nil, errors.New("webview: NewDocument requires a Write function")
+	}
+	if opts.MaxRecent <= 0 {
+		opts.MaxRecent = defaultMaxRecent
+	}
+	d := &Document{w: w, opts: opts}
+	recent, err := d.loadRecent()
+	if err != nil {
+		return nil, err
+	}
+	d.recent = recent
+	if opts.Prefix != "" {
+		if _, err := BindAll(w, map[string]any{
+			opts.Prefix + "_state":   d.State,
+			opts.Prefix + "_changed": d.MarkDirty,
+			opts.Prefix + "_save":    d.Save,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	d.changed()
+	return d, nil
+}
+
+// State returns a snapshot of d.
+func (d *Document) State() DocumentState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stateLocked()
+}
+
+func (d *Document) stateLocked() DocumentState {
+	name := "Untitled"
+	if d.path != "" {
+		name = filepath.Base(d.path)
+	}
+	return DocumentState{Path: d.path, Name: name, Dirty: d.dirty, Recent: slices.Clone(d.recent)}
+}
+
+// Path returns the file of d, or empty for a document never saved.
+func (d *Document) Path() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.path
+}
+
+// Dirty reports whether d has unsaved changes.
+func (d *Document) Dirty() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dirty
+}
+
+// Recent returns the recent files, newest first.
+func (d *Document) Recent() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return slices.Clone(d.recent)
+}
+
+// MarkDirty records an unsaved change and schedules an autosave.
+func (d *Document) MarkDirty() {
+	d.mu.Lock()
+	d.edits++
+	wasDirty := d.dirty
+	d.dirty = true
+	if d.opts.Autosave > 0 && d.path != "" && d.timer == nil {
+		d.timer = time.AfterFunc(d.opts.Autosave, d.autosave)
+	}
+	d.mu.Unlock()
+	if !wasDirty {
+		d.changed()
+	}
+}
+
+// New replaces d with an untitled document, once unsaved changes may be
+// discarded.
+func (d *Document) New() error {
+	if err := d.confirmDiscard(); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.reset("")
+	d.mu.Unlock()
+	d.changed()
+	return nil
+}
+
+// Open reads the file at path into the app through DocumentOptions.Read,
+// once unsaved changes may be discarded, and adds it to the recent files.
+func (d *Document) Open(path string) error {
+	if d.opts.Read == nil {
+		return errors.New("webview: Document.Open requires a Read function")
+	}
+	if err := d.confirmDiscard(); err != nil {
+		return err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if err := d.opts.Read(abs); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.reset(abs)
+	d.mu.Unlock()
+	return d.addRecent(abs)
+}
+
+// Save writes d to its file. It returns ErrNoPath for a document never
+// saved; use SaveAs.
+func (d *Document) Save() error {
+	path := d.Path()
+	if path == "" {
+		return ErrNoPath
+	}
+	return d.write(path, false)
+}
+
+// SaveAs writes d to path, which becomes its file, and adds it to the
+// recent files.
+func (d *Document) SaveAs(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if err := d.write(abs, true); err != nil {
+		return err
+	}
+	return d.addRecent(abs)
+}
+
+// Close ends editing of d, once unsaved changes may be discarded, and stops
+// autosave. It returns ErrDiscardVetoed when the app should stay open.
+func (d *Document) Close() error {
+	if err := d.confirmDiscard(); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	d.stopTimer()
+	d.mu.Unlock()
+	return nil
+}
+
+// write saves d to path, which becomes its file when adopt is set. Edits
+// made while it runs keep d dirty.
+func (d *Document) write(path string, adopt bool) error {
+	d.saveMu.Lock()
+	defer d.saveMu.Unlock()
+	d.mu.Lock()
+	edits := d.edits
+	d.mu.Unlock()
+	if err := d.opts.Write(path); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	if adopt {
+		d.path = path
+	}
+	if d.edits == edits {
+		d.dirty = false
+		d.stopTimer()
+	}
+	d.mu.Unlock()
+	d.changed()
+	return nil
+}
+
+func (d *Document) autosave() {
+	d.mu.Lock()
+	d.timer = nil
+	d.mu.Unlock()
+	if err := d.Save(); err != nil && d.opts.OnAutosaveError != nil {
+		d.opts.OnAutosaveError(err)
+	}
+}
+
+// confirmDiscard returns nil when d has no unsaved changes or
+// ConfirmDiscard allows dropping them.
+func (d *Document) confirmDiscard() error {
+	if !d.Dirty() {
+		return nil
+	}
+	if d.opts.ConfirmDiscard == nil || !d.opts.ConfirmDiscard() {
+		return ErrDiscardVetoed
+	}
+	return nil
+}
+
+// reset makes d a clean document for path. d.mu must be held.
+func (d *Document) reset(path string) {
+	d.path = path
+	d.dirty = false
+	d.edits++
+	d.stopTimer()
+}
+
+// stopTimer cancels a pending autosave. d.mu must be held.
+func (d *Document) stopTimer() {
+	if d.timer != nil {
+		d.timer.Stop()
+		d.timer = nil
+	}
+}
+
+// changed shows the state of d in the window title and reports it.
+func (d *Document) changed() {
+	state := d.State()
+	title := state.Name
+	if d.opts.Title != "" {
+		title += " - " + d.opts.Title
+	}
+	if state.Dirty {
+		title = "• " + title
+	}
+	d.w.Dispatch(func() { d.w.SetTitle(title) })
+	if d.opts.Prefix != "" {
+		_ = Emit(d.w, d.opts.Prefix+":change", state)
+	}
+	if d.opts.OnChange != nil {
+		d.opts.OnChange(state)
+	}
+}
+
+// addRecent moves path to the front of the recent files and stores them.
+func (d *Document) addRecent(path string) error {
+	d.mu.Lock()
+	recent := slices.DeleteFunc(slices.Clone(d.recent), func(p string) bool { return p == path })
+	recent = append([]string{path}, recent...)
+	if len(recent) > d.opts.MaxRecent {
+		recent = recent[:d.opts.MaxRecent]
+	}
+	d.recent = recent
+	d.mu.Unlock()
+	d.changed()
+	return d.storeRecent(recent)
+}
+
+// recentPath returns the file the recent files are kept in, or empty to
+// keep them in memory.
+func (d *Document) recentPath() (string, error) {
+	if d.opts.RecentPath != "" || d.opts.ID == "" {
+		return d.opts.RecentPath, nil
+	}
+	id := d.opts.ID
+	if !validFileName(id) {
+		return "", fmt.Errorf("webview: invalid document ID %q", id)
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("webview: locate config directory: %w", err)
+	}
+	return filepath.Join(dir, id, "recent.json"), nil
+}
+
+func (d *Document) loadRecent() ([]string, error) {
+	path, err := d.recentPath()
+	if err != nil || path == "" {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("webview: read recent files: %w", err)
+	}
+	var recent []string
+	if err := json.Unmarshal(data, &recent); err != nil {
+		// A damaged list is dropped; it is only a convenience.
+		return nil, nil
+	}
+	if len(recent) > d.opts.MaxRecent {
+		recent = recent[:d.opts.MaxRecent]
+	}
+	return recent, nil
+}
+
+func (d *Document) storeRecent(recent []string) error {
+	path, err := d.recentPath()
+	if err != nil || path == "" {
+		return err
+	}
+	data, err := json.Marshal(recent)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("webview: create recent files directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("webview: store recent files: %w", err)
+	}
+	return nil
+}
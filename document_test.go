@@ -0,0 +1,176 @@
+package glaze
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// docStore is the app side of a Document in tests.
+type docStore struct {
+	mu      sync.Mutex
+	text    string
+	written map[string]string
+}
+
+func (s *docStore) read(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.text = string(data)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *docStore) write(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.written == nil {
+		s.written = make(map[string]string)
+	}
+	s.written[path] = s.text
+	return os.WriteFile(path, []byte(s.text), 0o600)
+}
+
+func (w *fakeWebView) currentTitle() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.title
+}
+
+func TestDocumentLifecycle(t *testing.T) {
+	dir := t.TempDir()
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	store := &docStore{}
+	allow := false
+	doc, err := NewDocument(w, DocumentOptions{
+		RecentPath:     filepath.Join(dir, "recent.json"),
+		Title:          "Notes",
+		Read:           store.read,
+		Write:          store.write,
+		ConfirmDiscard: func() bool { return allow },
+	})
+	if err != nil {
+		t.Fatalf("NewDocument() unexpected error: %v", err)
+	}
+	if got := w.currentTitle(); got != "Untitled - Notes" {
+		t.Errorf("title = %q, want Untitled - Notes", got)
+	}
+
+	store.text = "milk"
+	doc.MarkDirty()
+	if got := w.currentTitle(); got != "• Untitled - Notes" {
+		t.Errorf("dirty title = %q, want the unsaved marker", got)
+	}
+	if err := doc.Save(); !errors.Is(err, ErrNoPath) {
+		t.Errorf("Save() of an untitled document = %v, want ErrNoPath", err)
+	}
+	if err := doc.New(); !errors.Is(err, ErrDiscardVetoed) {
+		t.Errorf("New() with unsaved changes = %v, want ErrDiscardVetoed", err)
+	}
+
+	a := filepath.Join(dir, "a.txt")
+	if err := doc.SaveAs(a); err != nil {
+		t.Fatalf("SaveAs() unexpected error: %v", err)
+	}
+	if doc.Dirty() || doc.Path() != a || w.currentTitle() != "a.txt - Notes" {
+		t.Errorf("after SaveAs: dirty %v, path %q, title %q", doc.Dirty(), doc.Path(), w.currentTitle())
+	}
+
+	b := filepath.Join(dir, "b.txt")
+	if err := os.WriteFile(b, []byte("eggs"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	doc.MarkDirty()
+	if err := doc.Open(b); !errors.Is(err, ErrDiscardVetoed) {
+		t.Fatalf("Open() with unsaved changes = %v, want ErrDiscardVetoed", err)
+	}
+	allow = true
+	if err := doc.Open(b); err != nil {
+		t.Fatalf("Open() unexpected error: %v", err)
+	}
+	if store.text != "eggs" || doc.Dirty() {
+		t.Errorf("after Open: text %q, dirty %v", store.text, doc.Dirty())
+	}
+	if want := []string{b, a}; !slices.Equal(doc.Recent(), want) {
+		t.Errorf("Recent() = %v, want %v", doc.Recent(), want)
+	}
+
+	// The recent files list survives restarts.
+	again, err := NewDocument(&fakeWebView{}, DocumentOptions{RecentPath: filepath.Join(dir, "recent.json"), Write: store.write})
+	if err != nil {
+		t.Fatalf("NewDocument() unexpected error: %v", err)
+	}
+	if want := []string{b, a}; !slices.Equal(again.Recent(), want) {
+		t.Errorf("reloaded Recent() = %v, want %v", again.Recent(), want)
+	}
+}
+
+func TestDocumentAutosave(t *testing.T) {
+	dir := t.TempDir()
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	store := &docStore{text: "draft"}
+	saved := make(chan DocumentState, 4)
+	doc, err := NewDocument(w, DocumentOptions{
+		Write:    store.write,
+		Autosave: 10 * time.Millisecond,
+		OnChange: func(s DocumentState) {
+			if s.Path != "" && !s.Dirty {
+				saved <- s
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewDocument() unexpected error: %v", err)
+	}
+	path := filepath.Join(dir, "draft.txt")
+	if err := doc.SaveAs(path); err != nil {
+		t.Fatalf("SaveAs() unexpected error: %v", err)
+	}
+	for len(saved) > 0 {
+		<-saved
+	}
+
+	store.mu.Lock()
+	store.text = "final"
+	store.mu.Unlock()
+	doc.MarkDirty()
+	select {
+	case <-saved:
+	case <-time.After(2 * time.Second):
+		t.Fatal("autosave did not run")
+	}
+	if data, _ := os.ReadFile(path); string(data) != "final" {
+		t.Errorf("autosaved file = %q, want final", data)
+	}
+}
+
+func TestDocumentBindings(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	doc, err := NewDocument(w, DocumentOptions{Write: func(string) error { return nil }, Prefix: "doc"})
+	if err != nil {
+		t.Fatalf("NewDocument() unexpected error: %v", err)
+	}
+	if _, err := w.call(t, "doc_changed"); err != nil || !doc.Dirty() {
+		t.Fatalf("doc_changed: %v, dirty %v", err, doc.Dirty())
+	}
+	got, err := w.call(t, "doc_state")
+	if state, ok := got.(DocumentState); err != nil || !ok || !state.Dirty || state.Name != "Untitled" {
+		t.Errorf("doc_state = %#v, %v", got, err)
+	}
+	if _, err := w.call(t, "doc_save"); err == nil {
+		t.Error("doc_save of an untitled document returned no error")
+	}
+}
@@ -0,0 +1,69 @@
+package glaze
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// QueryText returns the textContent of the first element matching the CSS
+// selector, or an error if no element matches.
+//
+// Like all result-returning helpers it waits for the page to answer, so it
+// must be called from a background goroutine, never from the UI thread.
+func QueryText(w WebView, selector string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultEvalTimeout)
+	defer cancel()
+
+	js := `(function (sel) {
+  var el = document.querySelector(sel);
+  if (!el) { throw new Error("no element matches " + sel); }
+  return el.textContent;
+})(` + marshalJSON(selector) + `)`
+
+	raw, err := bridgeFor(w).eval(ctx, js)
+	if err != nil {
+		return "", fmt.Errorf("webview: query %q: %w", selector, err)
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return "", fmt.Errorf("webview: query %q: %w", selector, err)
+	}
+	return text, nil
+}
+
+// WaitForSelector blocks until an element matching the CSS selector is
+// present in the page or timeout elapses. It watches DOM mutations rather
+// than polling, so it returns as soon as the element is inserted.
+//
+// It must be called from a background goroutine, never from the UI thread.
+func WaitForSelector(w WebView, selector string, timeout time.Duration) error {
+	// Give the page-side timer a chance to report before the Go side gives
+	// up, so the error names the selector instead of a bare deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), timeout+time.Second)
+	defer cancel()
+
+	js := `new Promise(function (resolve, reject) {
+  var sel = ` + marshalJSON(selector) + `;
+  if (document.querySelector(sel)) { resolve(true); return; }
+  var timer;
+  var observer = new MutationObserver(function () {
+    if (document.querySelector(sel)) {
+      observer.disconnect();
+      clearTimeout(timer);
+      resolve(true);
+    }
+  });
+  observer.observe(document.documentElement, { childList: true, subtree: true, attributes: true });
+  timer = setTimeout(function () {
+    observer.disconnect();
+    reject(new Error("timeout waiting for " + sel));
+  }, ` + fmt.Sprint(timeout.Milliseconds()) + `);
+})`
+
+	if _, err := bridgeFor(w).eval(ctx, js); err != nil {
+		return fmt.Errorf("webview: wait for %q: %w", selector, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package glaze
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryText(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	w.answerEvals(t, func(src string) (any, error) {
+		if !strings.Contains(src, `"#title"`) {
+			t.Errorf("query source does not contain the selector: %s", src)
+		}
+		return "Inbox", nil
+	})
+
+	got, err := QueryText(w, "#title")
+	if err != nil {
+		t.Fatalf("QueryText() unexpected error: %v", err)
+	}
+	if got != "Inbox" {
+		t.Fatalf("QueryText() = %q, want %q", got, "Inbox")
+	}
+}
+
+func TestQueryTextNoMatch(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	w.answerEvals(t, func(string) (any, error) { return nil, errors.New("no element matches #missing") })
+
+	if _, err := QueryText(w, "#missing"); err == nil {
+		t.Fatal("QueryText() expected error for missing element")
+	}
+}
+
+func TestWaitForSelector(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	w.answerEvals(t, func(src string) (any, error) {
+		if !strings.Contains(src, "MutationObserver") || !strings.Contains(src, "1500") {
+			t.Errorf("unexpected wait source: %s", src)
+		}
+		return true, nil
+	})
+
+	if err := WaitForSelector(w, ".ready", 1500*time.Millisecond); err != nil {
+		t.Fatalf("WaitForSelector() unexpected error: %v", err)
+	}
+}
+
+func TestWaitForSelectorTimeout(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	w.answerEvals(t, func(string) (any, error) { return nil, errors.New("timeout waiting for .never") })
+
+	err := WaitForSelector(w, ".never", time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "timeout") {
+		t.Fatalf("WaitForSelector() error = %v, want timeout", err)
+	}
+}
@@ -0,0 +1,179 @@
+package glaze
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// NavigationError describes a navigation that could not reach its target,
+// such as a refused connection, a DNS failure or a TLS handshake error.
+type NavigationError struct {
+	// URL is the address that failed to load.
+	URL string
+
+	// Err is the underlying transport error.
+	Err error
+}
+
+func (e *NavigationError) Error() string {
+	return "navigate " + e.URL + ": " + e.Err.Error()
+}
+
+func (e *NavigationError) Unwrap() error { return e.Err }
+
+// ErrorPageFunc renders the HTML shown in place of a page that failed to load.
+// The page may call window.glazeRetry() to try the navigation again.
+type ErrorPageFunc func(nerr *NavigationError) string
+
+// navigateProbeTimeout bounds the reachability check done by
+// NavigateWithErrorPage before handing the URL to the engine.
+const navigateProbeTimeout = 10 * time.Second
+
+// navigateRetryName is the hidden binding behind window.glazeRetry() on error
+// pages rendered by NavigateWithErrorPage.
+const navigateRetryName = "__glaze_retry"
+
+var errorPageTemplate = template.Must(template.New("error").Parse(`<!doctype html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>Unable to load page</title>
+  <style>
+    body {
+      margin: 0;
+      min-height: 100vh;
+      display: flex;
+      align-items: center;
+      justify-content: center;
+      font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif;
+      background: #111827;
+      color: #e5e7eb;
+    }
+    .card { width: min(100% - 32px, 560px); background: #1f2937; border: 1px solid #374151; border-radius: 10px; padding: 24px; }
+    h1 { margin: 0 0 8px 0; font-size: 20px; }
+    p { margin: 0 0 16px 0; color: #9ca3af; }
+    details { margin-bottom: 16px; color: #9ca3af; font-size: 13px; }
+    code { display: block; margin-top: 8px; overflow-wrap: anywhere; color: #e5e7eb; }
+    button { border: 1px solid #374151; border-radius: 8px; background: #2563eb; color: #fff; padding: 8px 16px; cursor: pointer; }
+    .brand { margin-top: 16px; font-size: 12px; color: #6b7280; }
+  </style>
+</head>
+<body>
+  <div class="card">
+    <h1>Unable to load page</h1>
+    <p>The application could not reach the requested page. It may still be starting up.</p>
+    <details>
+      <summary>Details</summary>
+      <code>{{.URL}}</code>
+      <code>{{.Err}}</code>
+    </details>
+    <button id="retry" autofocus>Retry</button>
+    <div class="brand">glaze</div>
+  </div>
+  <script>
+    window.glazeRetry = window.glazeRetry || function () {
+      if (window.` + navigateRetryName + `) { window.` + navigateRetryName + `(); } else { location.reload(); }
+    };
+    document.getElementById("retry").addEventListener("click", function () { window.glazeRetry(); });
+  </script>
+</body>
+</html>`))
+
+// DefaultErrorPage renders the built-in glaze error page with a retry button
+// and the failing URL and error as diagnostic details.
+func DefaultErrorPage(nerr *NavigationError) string {
+	var buf bytes.Buffer
+	_ = errorPageTemplate.Execute(&buf, nerr) // template and data are trusted
+	return buf.String()
+}
+
+// NavigateWithErrorPage navigates w to rawURL after checking from Go that the
+// server is reachable. When the check fails with a transport error, the
+// window shows the page rendered by page (DefaultErrorPage if nil) instead of
+// the engine's blank or default error page; its retry button runs the check
+// again. Any HTTP response, including error statuses, counts as reachable,
+// and so does a server whose certificate Go does not trust: the check cannot
+// see what the engine trusts, such as the self-signed certificate of an App
+// or AppWindow with TLS, and leaves that verdict to the engine.
+//
+// Non-HTTP URLs (data:, file:) are navigated to directly. The check runs in
+// the background, so NavigateWithErrorPage may be called from any goroutine.
+func NavigateWithErrorPage(w WebView, rawURL string, page ErrorPageFunc) {
+	if page == nil {
+		page = DefaultErrorPage
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.navURL = rawURL
+	b.navPage = page
+	b.mu.Unlock()
+
+	go func() {
+		err := probeURL(rawURL)
+		w.Dispatch(func() {
+			if err == nil {
+				w.Navigate(rawURL)
+				return
+			}
+			_ = b.bindHidden(navigateRetryName, b.retryNavigation)
+			w.SetHtml(page(&NavigationError{URL: rawURL, Err: err}))
+		})
+	}()
+}
+
+// retryNavigation is bound as navigateRetryName for the current error page.
+func (b *bridge) retryNavigation() {
+	b.mu.Lock()
+	rawURL, page := b.navURL, b.navPage
+	b.mu.Unlock()
+	NavigateWithErrorPage(b.w, rawURL, page)
+}
+
+// probeURL reports the transport error, if any, of a HEAD request to rawURL.
+// A certificate that fails verification is not an error: the server answered
+// the handshake.
+func probeURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), navigateProbeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	var certErr *tls.CertificateVerificationError
+	if errors.As(err, &certErr) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// gatewayErrorHandler adapts an ErrorPageFunc to httputil.ReverseProxy so the
+// unix transport gateway renders it when the backend socket is unreachable.
+func gatewayErrorHandler(page ErrorPageFunc) func(http.ResponseWriter, *http.Request, error) {
+	if page == nil {
+		page = DefaultErrorPage
+	}
+	return func(w http.ResponseWriter, r *http.Request, err error) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(page(&NavigationError{URL: r.URL.String(), Err: err})))
+	}
+}
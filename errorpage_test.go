@@ -0,0 +1,145 @@
+package glaze
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultErrorPageEscapesDetails(t *testing.T) {
+	page := DefaultErrorPage(&NavigationError{
+		URL: "http://127.0.0.1:1/<script>",
+		Err: errors.New("connection refused"),
+	})
+	if strings.Contains(page, "/<script>") {
+		t.Fatal("DefaultErrorPage() did not escape the URL")
+	}
+	if !strings.Contains(page, "connection refused") {
+		t.Fatal("DefaultErrorPage() missing error details")
+	}
+	if !strings.Contains(page, "glazeRetry") {
+		t.Fatal("DefaultErrorPage() missing retry hook")
+	}
+}
+
+func TestNavigationErrorUnwrap(t *testing.T) {
+	cause := errors.New("no such host")
+	nerr := &NavigationError{URL: "http://example.invalid", Err: cause}
+	if !errors.Is(nerr, cause) {
+		t.Fatal("NavigationError does not unwrap to its cause")
+	}
+}
+
+func TestProbeURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	if err := probeURL(srv.URL); err != nil {
+		t.Fatalf("probeURL() unexpected error for reachable server: %v", err)
+	}
+	// A self-signed loopback certificate, as App and AppWindow serve with
+	// TLS, is reachable: the engine trusts it even though Go does not.
+	tlsSrv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsSrv.Close()
+	if err := probeURL(tlsSrv.URL); err != nil {
+		t.Fatalf("probeURL() unexpected error for self-signed server: %v", err)
+	}
+	if err := probeURL("data:text/html,hi"); err != nil {
+		t.Fatalf("probeURL() unexpected error for data URL: %v", err)
+	}
+	if err := probeURL(closedURL(t)); err == nil {
+		t.Fatal("probeURL() expected error for closed port")
+	}
+}
+
+func TestNavigateWithErrorPage(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	w := &fakeWebView{}
+	defer w.Destroy()
+	NavigateWithErrorPage(w, srv.URL, nil)
+	waitFor(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return len(w.navigated) == 1 && w.navigated[0] == srv.URL
+	})
+}
+
+func TestNavigateWithErrorPageRendersPage(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	target := closedURL(t)
+	NavigateWithErrorPage(w, target, func(nerr *NavigationError) string {
+		return "failed: " + nerr.URL
+	})
+	waitFor(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return w.html == "failed: "+target
+	})
+	if _, ok := w.bound[navigateRetryName]; !ok {
+		t.Fatal("retry binding was not installed")
+	}
+}
+
+func TestUnixGatewayRendersErrorPage(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix transport is not supported on windows")
+	}
+	setup, err := setupUnixTransport("", func(nerr *NavigationError) string {
+		return "custom error page"
+	}, nil)
+	if err != nil {
+		t.Fatalf("setupUnixTransport() unexpected error: %v", err)
+	}
+	defer func() { _ = setup.close() }()
+	setup.start()
+	// Close the backend so the gateway cannot reach it.
+	_ = setup.listener.Close()
+
+	resp, err := http.Get(setup.baseURL)
+	if err != nil {
+		t.Fatalf("GET gateway: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusBadGateway)
+	}
+	if string(body) != "custom error page" {
+		t.Fatalf("body = %q, want custom error page", body)
+	}
+}
+
+// closedURL returns an http URL on a loopback port nothing listens on.
+func closedURL(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+	return "http://" + addr
+}
+
+// waitFor polls cond until it holds or the test times out.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timeout waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
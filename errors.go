@@ -0,0 +1,49 @@
+package glaze
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// Error is an error a bound function can return to reject the JavaScript
+// promise with structured fields instead of a plain message, so frontends
+// can branch on the kind of failure:
+//
+//	// Go
+//	return Note{}, &glaze.Error{Code: "not_found", Message: "note not found", Data: id}
+//
+//	// JavaScript
+//	try { await notes_get(id); } catch (e) { if (e.code === "not_found") { ... } }
+//
+// The promise rejects with an object holding code, message and data. Errors
+// that wrap an *Error, for example through fmt.Errorf with %w, are sent the
+// same way; any other error rejects with its message as a string.
+type Error struct {
+	// Code classifies the failure, such as "validation" or "not_found".
+	Code string `json:"code"`
+
+	// Message is a human-readable description.
+	Message string `json:"message"`
+
+	// Data carries optional details, such as the invalid fields.
+	Data any `json:"data,omitempty"`
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return e.Message
+	}
+	return e.Code + ": " + e.Message
+}
+
+// marshalError encodes err as the rejection value of a binding call.
+func marshalError(err error) string {
+	var structured *Error
+	if errors.As(err, &structured) {
+		if data, e := json.Marshal(structured); e == nil {
+			return string(data)
+		}
+	}
+	return marshalJSON(err.Error())
+}
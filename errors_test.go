@@ -0,0 +1,38 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestCallAndMarshalErrors(t *testing.T) {
+	notFound := &Error{Code: "not_found", Message: "note not found", Data: 7}
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"plain", errors.New("boom"), `"boom"`},
+		{"structured", notFound, `{"code":"not_found","message":"note not found","data":7}`},
+		{"wrapped", fmt.Errorf("loading: %w", notFound), `{"code":"not_found","message":"note not found","data":7}`},
+		{"no data", &Error{Code: "validation", Message: "empty"}, `{"code":"validation","message":"empty"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, got := callAndMarshal(func(_, _ string) (any, error) { return nil, tt.err }, "1", "[]")
+			if status != -1 || got != tt.want {
+				t.Fatalf("callAndMarshal() = %d, %s; want -1, %s", status, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	if got := (&Error{Code: "internal", Message: "db closed"}).Error(); got != "internal: db closed" {
+		t.Errorf("Error() = %q", got)
+	}
+	if got := (&Error{Message: "db closed"}).Error(); got != "db closed" {
+		t.Errorf("Error() without code = %q", got)
+	}
+}
@@ -0,0 +1,50 @@
+package glaze
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// jsPath matches a dotted path of JavaScript identifiers, such as
+// "app.render".
+var jsPath = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*(\.[A-Za-z_$][A-Za-z0-9_$]*)*$`)
+
+// EvalCall calls the page function fn with args, each JSON-encoded, instead
+// of a script assembled with fmt.Sprintf that breaks on quotes or lets data
+// inject code:
+//
+//	_ = glaze.EvalCall(w, "app.showNote", note.ID, note.Title)
+//
+// fn must be a dotted path of identifiers. Like Eval, EvalCall must be
+// called from the UI thread and does not wait for the call; wrap it in
+// Dispatch from background goroutines.
+func EvalCall(w WebView, fn string, args ...any) error {
+	if w == nil {
+		return errors.New("webview: EvalCall requires a non-nil WebView")
+	}
+	js, err := callJS(fn, args)
+	if err != nil {
+		return err
+	}
+	w.Eval(js)
+	return nil
+}
+
+// callJS returns the script calling fn with args.
+func callJS(fn string, args []any) (string, error) {
+	if !jsPath.MatchString(fn) {
+		return "", fmt.Errorf("webview: invalid JavaScript function name %q", fn)
+	}
+	encoded := make([]string, len(args))
+	for i, arg := range args {
+		data, err := json.Marshal(arg)
+		if err != nil {
+			return "", fmt.Errorf("webview: encode argument %d of %s: %w", i, fn, err)
+		}
+		encoded[i] = string(data)
+	}
+	return fn + "(" + strings.Join(encoded, ", ") + ");", nil
+}
@@ -0,0 +1,34 @@
+package glaze
+
+import "testing"
+
+func TestEvalCall(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	if err := EvalCall(w, "app.showNote", 7, `it's "quoted" </script>`, nil); err != nil {
+		t.Fatalf("EvalCall() unexpected error: %v", err)
+	}
+	want := `app.showNote(7, "it's \"quoted\" \u003c/script\u003e", null);`
+	if len(w.evals) != 1 || w.evals[0] != want {
+		t.Fatalf("evals = %q, want %q", w.evals, want)
+	}
+
+	for _, tt := range []struct {
+		name string
+		fn   string
+		args []any
+	}{
+		{"empty name", "", nil},
+		{"expression", "alert(1);render", nil},
+		{"trailing dot", "app.", nil},
+		{"unencodable argument", "render", []any{func() {}}},
+	} {
+		if err := EvalCall(w, tt.fn, tt.args...); err == nil {
+			t.Errorf("%s: EvalCall() returned no error", tt.name)
+		}
+	}
+	if len(w.evals) != 1 {
+		t.Errorf("invalid calls evaluated %q", w.evals[1:])
+	}
+}
@@ -0,0 +1,92 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// evalErrorBindingName is the hidden binding SafeEval reports errors through.
+const evalErrorBindingName = "__glaze_eval_error"
+
+// evalSnippetLength bounds the script excerpt carried by an EvalError.
+const evalSnippetLength = 120
+
+// safeEvalJS wraps a script evaluated by SafeEval. It is formatted with the
+// JSON-encoded source and snippet.
+const safeEvalJS = `(function (src, snippet) {
+  function report(err) {
+    var message = String(err && err.message || err);
+    var stack = String(err && err.stack || '');
+    if (typeof window.` + evalErrorBindingName + ` === 'function') {
+      window.` + evalErrorBindingName + `({ message: message, stack: stack, script: snippet });
+    } else {
+      console.error(err);
+    }
+  }
+  try {
+    var result = (0, eval)(src);
+    if (result && typeof result.then === 'function') { result.then(null, report); }
+  } catch (err) {
+    report(err);
+  }
+})(%s, %s);`
+
+// EvalError describes an exception thrown by a script run with SafeEval.
+type EvalError struct {
+	// Message is the exception message.
+	Message string `json:"message"`
+
+	// Stack is the JavaScript stack trace, when the engine provides one.
+	Stack string `json:"stack,omitempty"`
+
+	// Script is the beginning of the script that threw.
+	Script string `json:"script"`
+}
+
+// Error implements error.
+func (e EvalError) Error() string {
+	return "webview: eval " + strings.TrimSpace(e.Script) + ": " + e.Message
+}
+
+// SafeEval evaluates js like Eval, but catches exceptions and rejected
+// promises it produces and reports them to the handler installed with
+// OnEvalError, along with the start of the script, instead of leaving them
+// in the engine console. Without a handler the error is logged to the
+// console as before. SafeEval may be called from any goroutine.
+func SafeEval(w WebView, js string) {
+	script := fmt.Sprintf(safeEvalJS, marshalJSON(js), marshalJSON(evalSnippet(js)))
+	w.Dispatch(func() { w.Eval(script) })
+}
+
+// OnEvalError installs fn as the receiver of exceptions caught by SafeEval
+// in w. Calling it again replaces the handler; nil stops reporting. It must
+// be called from the UI thread, like Bind.
+func OnEvalError(w WebView, fn func(EvalError)) error {
+	if w == nil {
+		return errors.New("webview: OnEvalError requires a non-nil WebView")
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.evalError = fn
+	b.mu.Unlock()
+	return b.bindHidden(evalErrorBindingName, b.handleEvalError)
+}
+
+// handleEvalError is bound as evalErrorBindingName.
+func (b *bridge) handleEvalError(e EvalError) {
+	b.mu.Lock()
+	fn := b.evalError
+	b.mu.Unlock()
+	if fn != nil {
+		fn(e)
+	}
+}
+
+// evalSnippet returns the first evalSnippetLength runes of js.
+func evalSnippet(js string) string {
+	if r := []rune(js); len(r) > evalSnippetLength {
+		return string(r[:evalSnippetLength]) + "…"
+	}
+	return js
+}
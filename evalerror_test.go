@@ -0,0 +1,65 @@
+package glaze
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSafeEvalWrapsScript(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	SafeEval(w, `document.title = "%s"`)
+	if len(w.evals) != 1 {
+		t.Fatalf("evals = %d, want 1", len(w.evals))
+	}
+	got := w.evals[0]
+	for _, want := range []string{"try {", `"document.title = \"%s\""`, evalErrorBindingName} {
+		if !strings.Contains(got, want) {
+			t.Errorf("wrapped script missing %q:\n%s", want, got)
+		}
+	}
+	if want := `})("document.title = \"%s\"", "document.title = \"%s\"");`; !strings.HasSuffix(got, want) {
+		t.Errorf("wrapped script does not end with %s:\n%s", want, got)
+	}
+}
+
+func TestOnEvalErrorReports(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	var got []EvalError
+	if err := OnEvalError(w, func(e EvalError) { got = append(got, e) }); err != nil {
+		t.Fatalf("OnEvalError() unexpected error: %v", err)
+	}
+	if err := OnEvalError(w, func(e EvalError) { got = append(got, e, e) }); err != nil {
+		t.Fatalf("second OnEvalError() unexpected error: %v", err)
+	}
+
+	report := map[string]string{"message": "x is not defined", "stack": "at <anonymous>", "script": "x()"}
+	if _, err := w.call(t, evalErrorBindingName, report); err != nil {
+		t.Fatalf("eval error binding unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].Message != "x is not defined" || got[0].Script != "x()" {
+		t.Fatalf("handler got %+v, want the replaced handler called once", got)
+	}
+	if msg := got[0].Error(); msg != "webview: eval x(): x is not defined" {
+		t.Errorf("Error() = %q", msg)
+	}
+
+	_ = OnEvalError(w, nil)
+	if _, err := w.call(t, evalErrorBindingName, report); err != nil || len(got) != 2 {
+		t.Fatalf("cleared handler still called: %v, %d", err, len(got))
+	}
+}
+
+func TestEvalSnippet(t *testing.T) {
+	short := "alert(1)"
+	if got := evalSnippet(short); got != short {
+		t.Errorf("evalSnippet(%q) = %q", short, got)
+	}
+	long := strings.Repeat("é", evalSnippetLength+5)
+	if got := []rune(evalSnippet(long)); len(got) != evalSnippetLength+1 || got[len(got)-1] != '…' {
+		t.Errorf("evalSnippet(long) has %d runes, want truncated with ellipsis", len(got))
+	}
+}
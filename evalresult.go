@@ -0,0 +1,29 @@
+package glaze
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// EvalResult evaluates js in the page of w and returns its value as JSON.
+// A promise is awaited; a thrown exception or rejection is returned as the
+// error. Values JSON cannot represent, such as DOM nodes, come back as
+// null or {}:
+//
+//	raw, err := glaze.EvalResult(ctx, w, `document.title`)
+//	raw, err = glaze.EvalResult(ctx, w, `fetch("/api/state").then(r => r.json())`)
+//
+// It waits for the page until ctx is done, so it must be called from a
+// background goroutine, never from the UI thread.
+func EvalResult(ctx context.Context, w WebView, js string) (json.RawMessage, error) {
+	if w == nil {
+		return nil, errors.New("webview: EvalResult requires a non-nil WebView")
+	}
+	raw, err := bridgeFor(w).eval(ctx, js)
+	if err != nil {
+		return nil, fmt.Errorf("webview: eval %q: %w", evalSnippet(js), err)
+	}
+	return raw, nil
+}
@@ -0,0 +1,39 @@
+package glaze
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEvalResult(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	w.answerEvals(t, func(src string) (any, error) {
+		if src == "document.title" {
+			return "Notes", nil
+		}
+		return nil, errors.New("ReferenceError: x is not defined")
+	})
+
+	raw, err := EvalResult(context.Background(), w, "document.title")
+	if err != nil || string(raw) != `"Notes"` {
+		t.Fatalf("EvalResult() = %s, %v; want \"Notes\"", raw, err)
+	}
+
+	_, err = EvalResult(context.Background(), w, "x")
+	if err == nil || !strings.Contains(err.Error(), "ReferenceError: x is not defined") {
+		t.Errorf("EvalResult() error = %v, want the page error", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	w.mu.Lock()
+	w.onEval = nil // The page never answers.
+	w.mu.Unlock()
+	if _, err := EvalResult(ctx, w, "new Promise(() => {})"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("EvalResult() error = %v, want context.DeadlineExceeded", err)
+	}
+}
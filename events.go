@@ -0,0 +1,99 @@
+package glaze
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// eventsJS adds a small event emitter to the glaze runtime. Go pushes events
+// with Emit; pages subscribe with glaze.on, glaze.once and glaze.off.
+const eventsJS = `(function () {
+  'use strict';
+  var glaze = window.glaze = window.glaze || {};
+  if (glaze.on) { return; }
+  var listeners = {};
+  glaze.on = function (event, cb) {
+    (listeners[event] = listeners[event] || []).push(cb);
+    return function () { glaze.off(event, cb); };
+  };
+  glaze.once = function (event, cb) {
+    var off = glaze.on(event, function (payload) {
+      off();
+      cb(payload);
+    });
+    return off;
+  };
+  glaze.off = function (event, cb) {
+    if (cb === undefined) { delete listeners[event]; return; }
+    var list = listeners[event] || [];
+    for (var i = 0; i < list.length; i++) {
+      if (list[i] === cb) { list.splice(i, 1); break; }
+    }
+  };
+  glaze._emit = function (event, payload) {
+    var list = (listeners[event] || []).slice();
+    for (var i = 0; i < list.length; i++) {
+      try {
+        list[i](payload);
+      } catch (err) {
+        setTimeout(function () { throw err; });
+      }
+    }
+  };
+})();`
+
+// Emit pushes an event to the page, where every callback subscribed with
+// glaze.on(event, cb) receives payload decoded from its JSON encoding. It lets
+// Go report state changes such as job progress or file watcher events instead
+// of having the frontend poll bound methods.
+//
+//	// Go
+//	glaze.Emit(w, "job:progress", map[string]int{"done": 3, "total": 10})
+//
+//	// JavaScript
+//	const off = glaze.on("job:progress", (p) => render(p.done, p.total));
+//
+// glaze.on is defined in every page from the window's first Bind, so pages
+// can subscribe while they load. Events emitted while no page is subscribed
+// are dropped. Emit may be called from any goroutine.
+func Emit(w WebView, event string, payload any) error {
+	if event == "" {
+		return errors.New("webview: event name must not be empty")
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("webview: encode %q payload: %w", event, err)
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	if b.historySize > 0 {
+		if b.topics == nil {
+			b.topics = make(map[string]uint64)
+		}
+		b.topics[event]++
+	}
+	remote := b.remote
+	b.mu.Unlock()
+	if remote != nil {
+		remote.forward(event, data, nil)
+	}
+	b.emitPage(event, data)
+	return nil
+}
+
+// installEvents defines glaze.on, glaze.once and glaze.off in every page of
+// the window, so pages can subscribe while they load, before the first
+// Emit. The native WebView does so on its first Bind.
+func (b *bridge) installEvents() {
+	b.injectScript("events", eventsJS)
+}
+
+// emitPage delivers event, with its payload encoded as JSON, to the page.
+func (b *bridge) emitPage(event string, data []byte) {
+	js := "window.glaze._emit(" + marshalJSON(event) + ", " + string(data) + ");"
+	b.w.Dispatch(func() {
+		b.installEvents() // for WebViews that never bound anything
+		b.w.Eval(js)
+	})
+}
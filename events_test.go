@@ -0,0 +1,50 @@
+package glaze
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEmit(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	if err := Emit(w, "job:progress", map[string]int{"done": 3}); err != nil {
+		t.Fatalf("Emit() unexpected error: %v", err)
+	}
+	if err := Emit(w, "job:done", nil); err != nil {
+		t.Fatalf("Emit() unexpected error: %v", err)
+	}
+
+	if len(w.inits) != 1 || w.inits[0] != eventsJS {
+		t.Fatalf("init scripts = %d, want the events runtime once", len(w.inits))
+	}
+	want := []string{
+		eventsJS,
+		`window.glaze._emit("job:progress", {"done":3});`,
+		`window.glaze._emit("job:done", null);`,
+	}
+	if len(w.evals) != len(want) {
+		t.Fatalf("evals = %q, want %q", w.evals, want)
+	}
+	for i := range want {
+		if w.evals[i] != want[i] {
+			t.Errorf("eval[%d] = %q, want %q", i, w.evals[i], want[i])
+		}
+	}
+}
+
+func TestEmitErrors(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	if err := Emit(w, "", 1); err == nil {
+		t.Error("Emit() expected error for empty event name")
+	}
+	if err := Emit(w, "bad", math.Inf(1)); err == nil {
+		t.Error("Emit() expected error for unencodable payload")
+	}
+	if len(w.evals) != 0 {
+		t.Errorf("evals = %q, want none", w.evals)
+	}
+}
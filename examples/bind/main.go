@@ -108,7 +108,11 @@ const html = `<!doctype html>
     ui.compute.addEventListener("click", async () => {
       ui.compute.disabled = true;
       ui.computeResult.textContent = "running";
-      ui.computeResult.textContent = await window.compute(6, 7);
+      const job = window.compute(6, 7);
+      job.onprogress = (e) => {
+        ui.computeResult.textContent = "running " + Math.round(100 * e.done / e.total) + "%";
+      };
+      ui.computeResult.textContent = await job;
       ui.compute.disabled = false;
     });
   </script>
@@ -137,9 +141,14 @@ func main() {
 		log.Fatal(err)
 	}
 
-	// Binding for compute which simulates a long computation.
-	err = w.Bind("compute", func(a, b int) int {
-		time.Sleep(1 * time.Second)
+	// Binding for compute which simulates a long computation and reports
+	// its progress to the page.
+	err = w.Bind("compute", func(a, b int, p *glaze.Progress) int {
+		for i := range 10 {
+			p.Report(float64(i), 10, "")
+			time.Sleep(100 * time.Millisecond)
+		}
+		p.Report(10, 10, "")
 		return a * b
 	})
 	if err != nil {
@@ -3,7 +3,6 @@ package main
 import (
 	"embed"
 	"fmt"
-	"io/fs"
 	"log"
 	"net"
 	"net/http"
@@ -23,13 +22,8 @@ func startServer() (string, error) {
 		return "", fmt.Errorf("listen: %w", err)
 	}
 
-	sub, err := fs.Sub(uiFS, "ui")
-	if err != nil {
-		return "", fmt.Errorf("sub fs: %w", err)
-	}
-
 	mux := http.NewServeMux()
-	mux.Handle("/", http.FileServer(http.FS(sub)))
+	mux.Handle("/", glaze.StaticHandler(uiFS, glaze.StaticOptions{Dir: "ui"}))
 
 	go func() {
 		srv := &http.Server{Handler: mux}
@@ -5,7 +5,6 @@ import (
 	"embed"
 	"errors"
 	"fmt"
-	"io/fs"
 	"log"
 	"math"
 	"net"
@@ -296,14 +295,9 @@ func startServer() (string, error) {
 		return "", fmt.Errorf("listen: %w", err)
 	}
 
-	sub, err := fs.Sub(uiFS, "ui")
-	if err != nil {
-		return "", fmt.Errorf("sub fs: %w", err)
-	}
-
 	mux := http.NewServeMux()
 	mux.HandleFunc("/render", renderHandler)
-	mux.Handle("/", http.FileServer(http.FS(sub)))
+	mux.Handle("/", glaze.StaticHandler(uiFS, glaze.StaticOptions{Dir: "ui"}))
 
 	go func() {
 		srv := &http.Server{Handler: mux}
@@ -0,0 +1,53 @@
+package glaze
+
+import "errors"
+
+// EngineFeatures switches off engine features for one window, for viewers
+// that show untrusted HTML such as e-mail or file previews. The zero value
+// leaves every feature on, as windows start.
+type EngineFeatures struct {
+	// DisableJavaScript stops pages from running their own scripts, inline
+	// or loaded. Scripts glaze injects keep running, so bindings, Eval and
+	// Init scripts still work.
+	DisableJavaScript bool
+
+	// DisableImages stops pages from loading images. Linux only.
+	DisableImages bool
+
+	// DisableSmoothScrolling scrolls in steps instead of animating. Linux
+	// only.
+	DisableSmoothScrolling bool
+}
+
+// browserControllerer is implemented by WebViews backed by the native
+// library.
+type browserControllerer interface {
+	browserController() uintptr
+}
+
+// SetEngineFeatures applies f to w, for pages loaded from now on; reload the
+// current page for it to apply there too.
+//
+//	w, _ := glaze.New(false)
+//	err := glaze.SetEngineFeatures(w, glaze.EngineFeatures{
+//		DisableJavaScript: true,
+//		DisableImages:     true, // no tracking pixels
+//	})
+//
+// It supports Linux and, for DisableJavaScript, Windows; a feature the
+// platform cannot switch off makes it fail without changing anything. It
+// must be called from the UI thread.
+func SetEngineFeatures(w WebView, f EngineFeatures) error {
+	if w == nil {
+		return errors.New("webview: SetEngineFeatures requires a non-nil WebView")
+	}
+	c, ok := w.(browserControllerer)
+	if !ok {
+		return errors.New("webview: SetEngineFeatures needs a native window")
+	}
+	browser := c.browserController()
+	if browser == 0 {
+		return errors.New("webview: the native library does not expose the browser; SetEngineFeatures needs a newer build")
+	}
+	return applyEngineFeatures(browser, f)
+}
@@ -0,0 +1,9 @@
+package glaze
+
+import "errors"
+
+// applyEngineFeatures fails on macOS: WKWebView decides on scripts per
+// navigation, through a delegate the embedded library owns.
+func applyEngineFeatures(uintptr, EngineFeatures) error {
+	return errors.New("webview: SetEngineFeatures is not supported on macOS")
+}
@@ -0,0 +1,37 @@
+package glaze
+
+import "sync"
+
+// webkitSettings holds the WebKitGTK entry points used by SetEngineFeatures.
+var webkitSettings struct {
+	once sync.Once
+	err  error
+
+	get             func(view uintptr) uintptr
+	setScriptMarkup func(settings uintptr, enabled bool)
+	setImages       func(settings uintptr, enabled bool)
+	setSmooth       func(settings uintptr, enabled bool)
+}
+
+// applyEngineFeatures sets the WebKitSettings of the WebKitWebView view.
+// Scripts in markup are disabled rather than JavaScript as a whole, which
+// would stop injected scripts too.
+func applyEngineFeatures(view uintptr, f EngineFeatures) error {
+	s := &webkitSettings
+	s.once.Do(func() {
+		s.err = openNative("libwebkit2gtk-4.1.so.0", []nativeFunc{
+			{&s.get, "webkit_web_view_get_settings"},
+			{&s.setScriptMarkup, "webkit_settings_set_enable_javascript_markup"},
+			{&s.setImages, "webkit_settings_set_auto_load_images"},
+			{&s.setSmooth, "webkit_settings_set_enable_smooth_scrolling"},
+		})
+	})
+	if s.err != nil {
+		return s.err
+	}
+	settings := s.get(view)
+	s.setScriptMarkup(settings, !f.DisableJavaScript)
+	s.setImages(settings, !f.DisableImages)
+	s.setSmooth(settings, !f.DisableSmoothScrolling)
+	return nil
+}
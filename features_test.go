@@ -0,0 +1,27 @@
+package glaze
+
+import (
+	"strings"
+	"testing"
+)
+
+// controllerWebView reports a browser object, as native windows do.
+type controllerWebView struct {
+	fakeWebView
+	browser uintptr
+}
+
+func (w *controllerWebView) browserController() uintptr { return w.browser }
+
+func TestSetEngineFeaturesErrors(t *testing.T) {
+	off := EngineFeatures{DisableJavaScript: true}
+	if err := SetEngineFeatures(nil, off); err == nil {
+		t.Error("SetEngineFeatures(nil) expected error")
+	}
+	if err := SetEngineFeatures(&fakeWebView{}, off); err == nil || !strings.Contains(err.Error(), "native window") {
+		t.Errorf("SetEngineFeatures(fake) = %v, want a native window error", err)
+	}
+	if err := SetEngineFeatures(&controllerWebView{}, off); err == nil || !strings.Contains(err.Error(), "newer build") {
+		t.Errorf("SetEngineFeatures() without a browser = %v, want a library error", err)
+	}
+}
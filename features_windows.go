@@ -0,0 +1,54 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// Vtable slots of the WebView2 COM methods used by SetEngineFeatures,
+// counting the three IUnknown methods.
+const (
+	controllerGetCoreWebView2 = 25 // ICoreWebView2Controller::get_CoreWebView2
+	webViewGetSettings        = 3  // ICoreWebView2::get_Settings
+	settingsPutScriptEnabled  = 4  // ICoreWebView2Settings::put_IsScriptEnabled
+	comRelease                = 2  // IUnknown::Release
+)
+
+// comCall calls method slot of the COM object obj.
+func comCall(obj uintptr, slot int, args ...uintptr) uintptr {
+	// Dereference through &obj, as Window does, so vet sees no uintptr
+	// conversion.
+	vtbl := **(**unsafe.Pointer)(unsafe.Pointer(&obj))
+	fn := *(*uintptr)(unsafe.Add(vtbl, slot*int(unsafe.Sizeof(uintptr(0)))))
+	r, _, _ := syscall.SyscallN(fn, append([]uintptr{obj}, args...)...)
+	return r
+}
+
+// applyEngineFeatures sets IsScriptEnabled on the settings of the WebView2
+// behind controller. WebView2 still runs scripts injected by the host, so
+// glaze keeps working. It has no switch for images or smooth scrolling.
+func applyEngineFeatures(controller uintptr, f EngineFeatures) error {
+	if f.DisableImages || f.DisableSmoothScrolling {
+		return errors.New("webview: only DisableJavaScript is supported on windows")
+	}
+	var core uintptr
+	if hr := comCall(controller, controllerGetCoreWebView2, uintptr(unsafe.Pointer(&core))); hr != 0 || core == 0 {
+		return fmt.Errorf("webview: get CoreWebView2: HRESULT %#x", uint32(hr))
+	}
+	defer comCall(core, comRelease)
+	var settings uintptr
+	if hr := comCall(core, webViewGetSettings, uintptr(unsafe.Pointer(&settings))); hr != 0 || settings == 0 {
+		return fmt.Errorf("webview: get WebView2 settings: HRESULT %#x", uint32(hr))
+	}
+	defer comCall(settings, comRelease)
+	var enabled uintptr
+	if !f.DisableJavaScript {
+		enabled = 1
+	}
+	if hr := comCall(settings, settingsPutScriptEnabled, enabled); hr != 0 {
+		return fmt.Errorf("webview: set IsScriptEnabled: HRESULT %#x", uint32(hr))
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package glaze
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// frameBindingName is the hidden binding the frame policy reports through.
+const frameBindingName = "__glaze_frame"
+
+// frameTagKey is the key of the object the top frame prepends to the
+// arguments of every call when the policy is TopFrameOnly.
+const frameTagKey = "__glaze_frame"
+
+// frameJS enforces a FramePolicy. It is called with the policy flags and runs
+// in every frame the engine injects init scripts into. In the top frame it
+// tags every message to the native bridge, so Go can tell its calls from
+// those posted by frames, and watches iframe elements, removing third-party
+// ones and reporting loads to Go. Inside a frame it cuts the frame off from
+// the bridge early, so calls fail fast rather than being refused by Go.
+const frameJS = `(function (policy) {
+  'use strict';
+  if (window !== window.top) {
+    if (!policy.topFrameOnly) { return; }
+    var deny = function () {
+      return Promise.reject(new Error("glaze: bindings are not available in frames"));
+    };
+    var cut = function () {
+      if (!window.__webview__) { return false; }
+      var proto = Object.getPrototypeOf(window.__webview__);
+      if (Object.isFrozen(proto)) { return true; }
+      proto.call = deny;
+      proto.post = function () {};
+      return true;
+    };
+    if (!cut()) { document.addEventListener("DOMContentLoaded", cut); }
+    return;
+  }
+  if (policy.tag) {
+    var tag = function () {
+      if (!window.__webview__) { return false; }
+      var proto = Object.getPrototypeOf(window.__webview__);
+      if (proto._glazeFrameTag || Object.isFrozen(proto)) { return true; }
+      Object.defineProperty(proto, "_glazeFrameTag", { value: true });
+      var post = proto.post;
+      proto.post = function (message) {
+        var m;
+        try { m = JSON.parse(message); } catch (e) { m = null; }
+        if (m && Array.isArray(m.params)) {
+          m.params = [{ "` + frameTagKey + `": policy.tag }].concat(m.params);
+          message = JSON.stringify(m);
+        }
+        return post.call(this, message);
+      };
+      return true;
+    };
+    if (!tag()) { document.addEventListener("DOMContentLoaded", tag); }
+  }
+  var glaze = window.glaze = window.glaze || {};
+  if (glaze._framesInstalled) { return; }
+  glaze._framesInstalled = true;
+  function report(frame, url, blocked) {
+    if (!policy.report) { return; }
+    window.` + frameBindingName + `({ url: url, name: frame.name || frame.id || "", blocked: blocked });
+  }
+  function frameURL(frame) {
+    try {
+      return frame.contentWindow.location.href;
+    } catch (e) {
+      return frame.src || "about:blank";
+    }
+  }
+  function thirdParty(frame) {
+    var src = frame.getAttribute("src");
+    if (!src) { return false; }
+    var url;
+    try { url = new URL(src, location.href); } catch (e) { return true; }
+    return /^https?:$/.test(url.protocol) && url.origin !== location.origin;
+  }
+  function check(frame) {
+    if (policy.blockThirdParty && thirdParty(frame)) {
+      var url = frame.src;
+      frame.remove();
+      report(frame, url, true);
+      return;
+    }
+    if (!frame._glazeWatched) {
+      frame._glazeWatched = true;
+      frame.addEventListener("load", function () { report(frame, frameURL(frame), false); });
+    }
+  }
+  function scan(node) {
+    if (node.nodeType !== 1) { return; }
+    if (node.tagName === "IFRAME" || node.tagName === "FRAME") { check(node); }
+    var frames = node.querySelectorAll ? node.querySelectorAll("iframe, frame") : [];
+    for (var i = 0; i < frames.length; i++) { check(frames[i]); }
+  }
+  new MutationObserver(function (records) {
+    records.forEach(function (r) {
+      if (r.type === "attributes") { check(r.target); return; }
+      r.addedNodes.forEach(scan);
+    });
+  }).observe(document, { childList: true, subtree: true, attributes: true, attributeFilter: ["src"] });
+  scan(document.documentElement || document);
+})(`
+
+// FramePolicy controls what iframes in a window may do.
+type FramePolicy struct {
+	// BlockThirdParty removes iframes whose src has a different origin than
+	// the page. Frames without a src, about:blank and srcdoc frames are
+	// allowed.
+	BlockThirdParty bool
+
+	// TopFrameOnly makes bound functions reject when called from inside a
+	// frame, so embedded widgets cannot reach the Go API. Go refuses every
+	// call that does not carry a secret tag known only to the top frame's
+	// scripts, whatever the frame posts to the native bridge. A same-origin
+	// frame can still reach the top frame's functions through
+	// window.parent, as it can reach the rest of the page; serve untrusted
+	// content from another origin.
+	TopFrameOnly bool
+
+	// OnNavigate, when set, is called for every frame load in the top-level
+	// page and for every frame blocked by BlockThirdParty.
+	OnNavigate func(FrameNavigation)
+}
+
+// FrameNavigation reports a frame load or a blocked frame.
+type FrameNavigation struct {
+	// URL is the frame's location, or its src when it is cross-origin.
+	URL string `json:"url"`
+
+	// Name is the frame element's name or id.
+	Name string `json:"name,omitempty"`
+
+	// Blocked reports whether the frame was removed by BlockThirdParty.
+	Blocked bool `json:"blocked"`
+}
+
+// SetFramePolicy applies policy to every page loaded in w. Set it once,
+// before navigating; later calls return an error.
+//
+// The top-level page removes and watches frame elements. TopFrameOnly is
+// enforced in Go, when a call arrives, so it holds on engines that run
+// glaze's scripts inside frames and on those that do not. It must be called
+// from the UI thread, like Bind.
+func SetFramePolicy(w WebView, policy FramePolicy) error {
+	if w == nil {
+		return errors.New("webview: SetFramePolicy requires a non-nil WebView")
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	if b.frames != nil {
+		b.mu.Unlock()
+		return errors.New("webview: frame policy already set")
+	}
+	var tag string
+	if policy.TopFrameOnly {
+		var key [16]byte
+		if _, err := rand.Read(key[:]); err != nil {
+			b.mu.Unlock()
+			return fmt.Errorf("webview: frame policy: %w", err)
+		}
+		tag = hex.EncodeToString(key[:])
+	}
+	b.frames = &policy
+	b.frameTag = tag
+	b.mu.Unlock()
+
+	flags, _ := json.Marshal(map[string]any{
+		"blockThirdParty": policy.BlockThirdParty,
+		"topFrameOnly":    policy.TopFrameOnly,
+		"report":          policy.OnNavigate != nil,
+		"tag":             tag,
+	})
+	if policy.OnNavigate != nil {
+		if err := b.bindHidden(frameBindingName, b.handleFrame); err != nil {
+			return err
+		}
+	}
+	b.injectScript("frames", frameJS+string(flags)+");")
+	return nil
+}
+
+// handleFrame is bound as frameBindingName.
+func (b *bridge) handleFrame(nav FrameNavigation) {
+	b.mu.Lock()
+	policy := b.frames
+	b.mu.Unlock()
+	if policy != nil && policy.OnNavigate != nil {
+		policy.OnNavigate(nav)
+	}
+}
+
+// checkFrame enforces TopFrameOnly on the arguments of a call: it returns
+// them without the top frame's tag, or an error when the tag is missing or
+// wrong. Without the policy req is returned unchanged.
+func (b *bridge) checkFrame(req string) (string, error) {
+	b.mu.Lock()
+	want := b.frameTag
+	b.mu.Unlock()
+	if want == "" {
+		return req, nil
+	}
+	var args []json.RawMessage
+	if err := json.Unmarshal([]byte(req), &args); err != nil || len(args) == 0 {
+		return "", errFrameCall
+	}
+	var tag map[string]string
+	if err := json.Unmarshal(args[0], &tag); err != nil || len(tag) != 1 ||
+		subtle.ConstantTimeCompare([]byte(tag[frameTagKey]), []byte(want)) != 1 {
+		return "", errFrameCall
+	}
+	rest, err := json.Marshal(args[1:])
+	if err != nil {
+		return "", err
+	}
+	return string(rest), nil
+}
+
+// errFrameCall is returned for calls refused by TopFrameOnly.
+var errFrameCall = errors.New("webview: bindings are not available in frames")
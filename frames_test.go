@@ -0,0 +1,80 @@
+package glaze
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSetFramePolicy(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	var got []FrameNavigation
+	err := SetFramePolicy(w, FramePolicy{
+		BlockThirdParty: true,
+		OnNavigate:      func(n FrameNavigation) { got = append(got, n) },
+	})
+	if err != nil {
+		t.Fatalf("SetFramePolicy() unexpected error: %v", err)
+	}
+	if len(w.inits) != 1 || !strings.HasSuffix(w.inits[0], `({"blockThirdParty":true,"report":true,"tag":"","topFrameOnly":false});`) {
+		t.Fatalf("inits = %q, want the frame script with policy flags", w.inits)
+	}
+
+	if _, err := w.call(t, frameBindingName, map[string]any{"url": "https://ads.example/", "blocked": true}); err != nil {
+		t.Fatalf("frame binding unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0].URL != "https://ads.example/" || !got[0].Blocked {
+		t.Fatalf("OnNavigate got %+v", got)
+	}
+
+	if err := SetFramePolicy(w, FramePolicy{}); err == nil {
+		t.Fatal("second SetFramePolicy() expected error")
+	}
+}
+
+func TestSetFramePolicyWithoutReporting(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	if err := SetFramePolicy(w, FramePolicy{TopFrameOnly: true}); err != nil {
+		t.Fatalf("SetFramePolicy() unexpected error: %v", err)
+	}
+	if _, bound := w.bound[frameBindingName]; bound {
+		t.Fatal("frame binding bound without OnNavigate")
+	}
+	if !strings.Contains(w.inits[0], `"topFrameOnly":true`) {
+		t.Fatalf("init script missing topFrameOnly flag")
+	}
+}
+
+func TestSetFramePolicyTopFrameOnly(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	if err := w.Bind("add", func(a, b int) int { return a + b }); err != nil {
+		t.Fatalf("Bind() unexpected error: %v", err)
+	}
+	if err := SetFramePolicy(w, FramePolicy{TopFrameOnly: true}); err != nil {
+		t.Fatalf("SetFramePolicy() unexpected error: %v", err)
+	}
+	tag := bridgeFor(w).frameTag
+	if tag == "" || !strings.Contains(w.inits[len(w.inits)-1], tag) {
+		t.Fatal("the top frame script does not carry the call tag")
+	}
+
+	// A frame posting straight to the native bridge has no tag.
+	if _, err := w.call(t, "add", 1, 2); err == nil {
+		t.Fatal("untagged call expected error")
+	}
+	if _, err := w.call(t, "add", map[string]string{frameTagKey: "guess"}, 1, 2); err == nil {
+		t.Fatal("call with a wrong tag expected error")
+	}
+	got, err := w.call(t, "add", map[string]string{frameTagKey: tag}, 1, 2)
+	if err != nil {
+		t.Fatalf("tagged call unexpected error: %v", err)
+	}
+	if got != 3 {
+		t.Fatalf("add() = %v, want 3", got)
+	}
+}
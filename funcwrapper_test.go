@@ -2,6 +2,7 @@ package glaze
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -126,12 +127,46 @@ func TestMakeFuncWrapperArgMismatch(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	_, err = fn("id", `[1]`)
+	_, err = fn("id", `[1, 2, 3]`)
 	if err == nil {
 		t.Fatal("expected error for argument mismatch")
 	}
 }
 
+func TestMakeFuncWrapperOmittedArgs(t *testing.T) {
+	type options struct {
+		Limit int `json:"limit"`
+	}
+	fn, err := makeFuncWrapper(func(q string, opts *options, n int) string {
+		if opts == nil {
+			return fmt.Sprintf("%s:nil:%d", q, n)
+		}
+		return fmt.Sprintf("%s:%d:%d", q, opts.Limit, n)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		req  string
+		want string
+	}{
+		{`["a", {"limit": 5}, 2]`, "a:5:2"},
+		{`["a", null, 2]`, "a:nil:2"},
+		{`["a", {"limit": 5}]`, "a:5:0"},
+		{`["a"]`, "a:nil:0"},
+		{`[]`, ":nil:0"},
+	}
+	for _, tt := range tests {
+		val, err := fn("id", tt.req)
+		if err != nil {
+			t.Fatalf("fn(%s) unexpected error: %v", tt.req, err)
+		}
+		if val != tt.want {
+			t.Errorf("fn(%s) = %v, want %s", tt.req, val, tt.want)
+		}
+	}
+}
+
 func TestMakeFuncWrapperBadJSON(t *testing.T) {
 	fn, err := makeFuncWrapper(func() {})
 	if err != nil {
@@ -185,3 +220,25 @@ func TestMakeFuncWrapperReturnsStruct(t *testing.T) {
 		t.Fatalf("expected Alice, got %s", r.Name)
 	}
 }
+
+func TestMakeFuncWrapperMultipleReturns(t *testing.T) {
+	fn, err := makeFuncWrapper(func(page int) ([]string, int, error) {
+		if page < 0 {
+			return nil, 0, errors.New("bad page")
+		}
+		return []string{"a", "b"}, 7, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	val, err := fn("id", `[1]`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := fmt.Sprint(val); got != "[[a b] 7]" {
+		t.Fatalf("expected [[a b] 7], got %v", got)
+	}
+	if _, err := fn("id", `[-1]`); err == nil || err.Error() != "bad page" {
+		t.Fatalf("expected bad page error, got %v", err)
+	}
+}
@@ -5,6 +5,12 @@ go 1.26
 require github.com/ebitengine/purego v0.10.0
 
 require (
+	github.com/makiuchi-d/gozxing v0.1.1
 	golang.org/x/crypto v0.49.0
-	golang.org/x/sys v0.42.0 // indirect
+	golang.org/x/sys v0.42.0
+)
+
+require (
+	golang.org/x/text v0.35.0 // indirect
+	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
 )
@@ -2,9 +2,11 @@ package glaze
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"html/template"
 	"reflect"
+	"sort"
 	"strings"
 	"unicode"
 )
@@ -16,42 +18,296 @@ import (
 //
 // Returns the list of bound function names and the first error encountered.
 func BindMethods(w WebView, prefix string, obj any) ([]string, error) {
+	return BindMethodsWithOptions(w, prefix, obj, BindOptions{})
+}
+
+// BindOptions configures BindMethodsWithOptions.
+//
+// Methods can also be excluded by the bound type itself, with a glaze struct
+// tag listing them on any field, which keeps the rule next to the methods.
+// Tag exclusions apply to BindMethods as well:
+//
+//	type Notes struct {
+//		_  struct{} `glaze:"exclude=Close,Migrate"`
+//		db *sql.DB
+//	}
+type BindOptions struct {
+	// Namespace exposes the methods as members of a single object,
+	// window.{prefix}.{methodName}(args...), with lowerCamelCase method
+	// names, instead of one snake_case global per method. The functions
+	// behind the object are bound as window["{prefix}.{methodName}"].
+	Namespace bool
+
+	// Include, when not empty, limits binding to the listed Go method names.
+	Include []string
+
+	// Exclude lists Go method names that are never bound.
+	Exclude []string
+
+	// Name maps a Go method name to the JavaScript name used after the
+	// prefix, replacing the default snake_case (or lowerCamelCase in
+	// Namespace mode) conversion.
+	Name func(method string) string
+
+	// Calls configures how calls to each bound method execute, as
+	// BindWithOptions does for a single function. With Calls.Serial each
+	// method gets its own queue.
+	Calls BindingOptions
+
+	// FieldNames names the JSON fields of structs without json tags in the
+	// methods' arguments and results, such as FieldNamesCamel for {userID}
+	// instead of {UserID}. It sets Calls.Codec to FieldNamingCodec unless
+	// a codec is given there, and GenerateTypes follows it.
+	FieldNames FieldNaming
+}
+
+// callOptions returns the BindingOptions of each method bound with opts.
+func (opts BindOptions) callOptions() BindingOptions {
+	calls := opts.Calls
+	if calls.Codec == nil && opts.FieldNames != FieldNamesGo {
+		calls.Codec = FieldNamingCodec(opts.FieldNames)
+	}
+	return calls
+}
+
+// glazeTagExclude introduces the method list of a glaze struct tag.
+const glazeTagExclude = "exclude="
+
+// BindMethodsWithOptions is BindMethods with options controlling how the
+// methods are exposed. It returns the JavaScript names of the bound methods,
+// such as "notes.add" in Namespace mode.
+func BindMethodsWithOptions(w WebView, prefix string, obj any, opts BindOptions) ([]string, error) {
 	if w == nil {
 		return nil, fmt.Errorf("webview: BindMethods requires a non-nil WebView")
 	}
+	methods, err := serviceMethods("BindMethods", prefix, obj, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var bound, members []string
+	for _, m := range methods {
+		if err = BindWithOptions(w, m.name, m.fn, opts.callOptions()); err != nil {
+			err = fmt.Errorf("binding %s: %w", m.name, err)
+			break
+		}
+		bound = append(bound, m.name)
+		members = append(members, m.member)
+	}
+	if opts.Namespace && len(members) > 0 {
+		installNamespace(w, prefix, members)
+	}
+	bridgeFor(w).recordService(prefix, bound, opts, nil)
+	return bound, err
+}
+
+// UnbindMethods removes the bindings registered under prefix: the methods
+// bound by BindMethods or RebindMethods for it and any other binding named
+// {prefix}_... or {prefix}.... In Namespace mode the window[prefix] object is
+// removed from the current page and from pages loaded later. It returns the
+// unbound names in order. Like Unbind, it must be called from the UI thread.
+func UnbindMethods(w WebView, prefix string) ([]string, error) {
+	if w == nil {
+		return nil, fmt.Errorf("webview: UnbindMethods requires a non-nil WebView")
+	}
+
+	b := bridgeFor(w)
+	b.mu.Lock()
+	names := make(map[string]bool)
+	namespace := false
+	for _, name := range b.services[prefix] {
+		namespace = namespace || strings.HasPrefix(name, prefix+".")
+		// Skip names unbound one by one since.
+		if _, bound := b.funcs[name]; bound {
+			names[name] = true
+		}
+	}
+	if prefix != "" {
+		for name := range b.funcs {
+			if !b.hidden[name] && (strings.HasPrefix(name, prefix+"_") || strings.HasPrefix(name, prefix+".")) {
+				names[name] = true
+				namespace = namespace || strings.HasPrefix(name, prefix+".")
+			}
+		}
+	}
+	delete(b.services, prefix)
+	delete(b.serviceOpts, prefix)
+	delete(b.contracts, prefix)
+	b.mu.Unlock()
+
+	unbound := make([]string, 0, len(names))
+	for name := range names {
+		unbound = append(unbound, name)
+	}
+	sort.Strings(unbound)
+
+	var errs []error
+	for _, name := range unbound {
+		if err := w.Unbind(name); err != nil {
+			errs = append(errs, fmt.Errorf("unbinding %s: %w", name, err))
+		}
+	}
+	if namespace {
+		b.mu.Lock()
+		b.dropRuntimeScriptLocked("namespace:" + prefix)
+		b.mu.Unlock()
+		w.Eval("delete window[" + marshalJSON(prefix) + "];")
+	}
+	return unbound, errors.Join(errs...)
+}
+
+// serviceMethod is one method of a bound object: its JavaScript name, its
+// member name in Namespace mode and the method value.
+type serviceMethod struct {
+	name   string
+	member string
+	fn     any
+}
+
+// serviceMethods lists the methods of obj that BindMethodsWithOptions binds,
+// reporting problems as coming from the caller named op.
+func serviceMethods(op, prefix string, obj any, opts BindOptions) ([]serviceMethod, error) {
 	if obj == nil {
-		return nil, fmt.Errorf("webview: BindMethods requires a non-nil object")
+		return nil, fmt.Errorf("webview: %s requires a non-nil object", op)
+	}
+	if opts.Namespace && prefix == "" {
+		return nil, fmt.Errorf("webview: %s requires a prefix to use as namespace", op)
 	}
 
 	v := reflect.ValueOf(obj)
 	if !v.IsValid() {
-		return nil, fmt.Errorf("webview: BindMethods received an invalid object")
+		return nil, fmt.Errorf("webview: %s received an invalid object", op)
 	}
 	if v.Kind() == reflect.Pointer && v.IsNil() {
-		return nil, fmt.Errorf("webview: BindMethods requires a non-nil object")
+		return nil, fmt.Errorf("webview: %s requires a non-nil object", op)
+	}
+	return serviceMethodsOf(op, prefix, v, opts)
+}
+
+// serviceMethodsOf lists the bindable methods of v, a value checked by the
+// caller. An interface value contributes the methods of its interface type
+// only.
+func serviceMethodsOf(op, prefix string, v reflect.Value, opts BindOptions) ([]serviceMethod, error) {
+	if opts.Namespace && prefix == "" {
+		return nil, fmt.Errorf("webview: %s requires a prefix to use as namespace", op)
+	}
+	methods, err := bindableMethods(v.Type(), opts)
+	if err != nil {
+		return nil, err
 	}
+	out := make([]serviceMethod, 0, len(methods))
+	for _, method := range methods {
+		name, member := methodJSName(prefix, method.Name, opts)
+		out = append(out, serviceMethod{name: name, member: member, fn: v.Method(method.Index).Interface()})
+	}
+	return out, nil
+}
 
-	t := v.Type()
+// bindableMethods returns the exported methods of t that opts and t's glaze
+// struct tags allow to be bound, in method order.
+func bindableMethods(t reflect.Type, opts BindOptions) ([]reflect.Method, error) {
+	excluded := make(map[string]bool)
+	for _, name := range opts.Exclude {
+		excluded[name] = true
+	}
+	for _, name := range taggedExclusions(t) {
+		excluded[name] = true
+	}
+	var included map[string]bool
+	if len(opts.Include) > 0 {
+		included = make(map[string]bool)
+		for _, name := range opts.Include {
+			if _, ok := t.MethodByName(name); !ok {
+				return nil, fmt.Errorf("webview: BindMethods: %s has no exported method %s", t, name)
+			}
+			included[name] = true
+		}
+	}
 
-	var bound []string
+	var methods []reflect.Method
 	for i := range t.NumMethod() {
 		method := t.Method(i)
 
 		// Skip unexported methods.
-		if !method.IsExported() {
+		if !method.IsExported() || excluded[method.Name] {
 			continue
 		}
+		if included != nil && !included[method.Name] {
+			continue
+		}
+		methods = append(methods, method)
+	}
+	return methods, nil
+}
 
-		// Build the JS function name: {prefix}_{snake_case_method}.
-		name := prefix + "_" + camelToSnake(method.Name)
-
-		fn := v.Method(i).Interface()
-		if err := w.Bind(name, fn); err != nil {
-			return bound, fmt.Errorf("binding %s: %w", name, err)
+// taggedExclusions collects the method names listed in glaze:"exclude=..."
+// tags on the fields of t's underlying struct.
+func taggedExclusions(t reflect.Type) []string {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	var names []string
+	for i := range t.NumField() {
+		tag := t.Field(i).Tag.Get("glaze")
+		for _, part := range strings.Split(tag, ";") {
+			if list, ok := strings.CutPrefix(strings.TrimSpace(part), glazeTagExclude); ok {
+				for _, name := range strings.Split(list, ",") {
+					if name = strings.TrimSpace(name); name != "" {
+						names = append(names, name)
+					}
+				}
+			}
 		}
-		bound = append(bound, name)
 	}
-	return bound, nil
+	return names
+}
+
+// methodJSName returns the global name a method is bound as and, in
+// Namespace mode, its member name: {prefix}_{snake_case_method}, or
+// {prefix}.{lowerCamelMethod} behind a namespace object. opts.Name replaces
+// the case conversion.
+func methodJSName(prefix, method string, opts BindOptions) (name, member string) {
+	switch {
+	case opts.Name != nil:
+		member = opts.Name(method)
+	case opts.Namespace:
+		member = camelToLowerCamel(method)
+	default:
+		member = camelToSnake(method)
+	}
+	if opts.Namespace {
+		return prefix + "." + member, member
+	}
+	return prefix + "_" + member, member
+}
+
+// installNamespace exposes bound members as methods of window[prefix], in
+// the current page and every page loaded later. Installing prefix again
+// replaces the members pages loaded later get.
+func installNamespace(w WebView, prefix string, members []string) {
+	js := namespaceJS(prefix, members)
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.setRuntimeScriptLocked("namespace:"+prefix, js)
+	b.mu.Unlock()
+	w.Eval(js)
+}
+
+// namespaceJS builds the script that exposes the bindings
+// window["{prefix}.{member}"] as methods of window[prefix]. Members forward
+// at call time, so they keep working when a binding is replaced.
+func namespaceJS(prefix string, members []string) string {
+	var b strings.Builder
+	b.WriteString("(function () {\n  var ns = window[" + marshalJSON(prefix) + "] = window[" + marshalJSON(prefix) + "] || {};\n")
+	for _, m := range members {
+		global := marshalJSON(prefix + "." + m)
+		b.WriteString("  ns[" + marshalJSON(m) + "] = function () { return window[" + global + "].apply(window, arguments); };\n")
+	}
+	b.WriteString("})();")
+	return b.String()
 }
 
 // camelToSnake converts a CamelCase name to snake_case for JavaScript.
@@ -83,6 +339,25 @@ func camelToSnake(s string) string {
 	return b.String()
 }
 
+// camelToLowerCamel converts an exported Go name to lowerCamelCase for
+// JavaScript, lowering a leading initialism as a whole.
+// Example: "GetUserByID" -> "getUserByID", "HTMLTitle" -> "htmlTitle"
+func camelToLowerCamel(s string) string {
+	runes := []rune(s)
+	for i, r := range runes {
+		if !unicode.IsUpper(r) {
+			break
+		}
+		// Keep the last capital of an initialism followed by a lowercase
+		// letter, as it starts the next word.
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(r)
+	}
+	return string(runes)
+}
+
 // RenderHTML executes a named template to a string, suitable for SetHtml().
 // This allows reusing Go html/template definitions without an HTTP server.
 func RenderHTML(tpl *template.Template, name string, data any) (string, error) {
@@ -3,6 +3,7 @@ package glaze
 import (
 	"errors"
 	"html/template"
+	"strings"
 	"testing"
 	"unsafe"
 )
@@ -157,6 +158,175 @@ func TestBindMethodsBindError(t *testing.T) {
 	}
 }
 
+func TestCamelToLowerCamel(t *testing.T) {
+	tests := map[string]string{
+		"Ping":        "ping",
+		"GetUserByID": "getUserByID",
+		"HTMLTitle":   "htmlTitle",
+		"ID":          "id",
+		"A":           "a",
+		"already":     "already",
+	}
+	for in, want := range tests {
+		if got := camelToLowerCamel(in); got != want {
+			t.Errorf("camelToLowerCamel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBindMethodsNamespace(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	names, err := BindMethodsWithOptions(w, "api", bindMethodsService{}, BindOptions{Namespace: true})
+	if err != nil {
+		t.Fatalf("BindMethodsWithOptions() unexpected error: %v", err)
+	}
+	if len(names) != 2 || names[0] != "api.getUserByID" || names[1] != "api.ping" {
+		t.Fatalf("BindMethodsWithOptions() names = %q", names)
+	}
+	if _, ok := w.bound["api_ping"]; ok {
+		t.Fatal("BindMethodsWithOptions() bound a flat global in namespace mode")
+	}
+	if len(w.inits) != 1 || len(w.evals) != 1 || w.inits[0] != w.evals[0] {
+		t.Fatalf("namespace script inits = %d, evals = %d; want one of each", len(w.inits), len(w.evals))
+	}
+	js := w.inits[0]
+	for _, want := range []string{
+		`window["api"] = window["api"] || {}`,
+		`ns["getUserByID"] = function () { return window["api.getUserByID"].apply(window, arguments); };`,
+		`ns["ping"] = function () { return window["api.ping"].apply(window, arguments); };`,
+	} {
+		if !strings.Contains(js, want) {
+			t.Errorf("namespace script missing %s:\n%s", want, js)
+		}
+	}
+}
+
+func TestBindMethodsNamespaceRequiresPrefix(t *testing.T) {
+	w := &bindMethodsWebViewStub{}
+	if _, err := BindMethodsWithOptions(w, "", bindMethodsService{}, BindOptions{Namespace: true}); err == nil {
+		t.Fatal("BindMethodsWithOptions() expected error for empty namespace")
+	}
+	if w.bindCalls != 0 {
+		t.Fatalf("bind calls = %d, want 0", w.bindCalls)
+	}
+}
+
+func TestUnbindMethods(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	if _, err := BindMethods(w, "api", bindMethodsService{}); err != nil {
+		t.Fatalf("BindMethods() unexpected error: %v", err)
+	}
+	_ = w.Bind("api_extra", func() {})
+	_ = w.Bind("apiary", func() {})
+
+	names, err := UnbindMethods(w, "api")
+	if err != nil {
+		t.Fatalf("UnbindMethods() unexpected error: %v", err)
+	}
+	if want := []string{"api_extra", "api_get_user_by_id", "api_ping"}; strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Fatalf("UnbindMethods() = %q, want %q", names, want)
+	}
+	if len(w.bound) != 1 || w.bound["apiary"] == nil {
+		t.Fatalf("bindings left = %v, want only apiary", w.bound)
+	}
+	if len(w.inits) != 0 {
+		t.Fatalf("UnbindMethods() injected %q for flat bindings", w.inits)
+	}
+
+	// Nothing is left to unbind the second time.
+	if names, err := UnbindMethods(w, "api"); err != nil || len(names) != 0 {
+		t.Fatalf("second UnbindMethods() = %q, %v; want nothing", names, err)
+	}
+}
+
+func TestUnbindMethodsNamespace(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	if _, err := BindMethodsWithOptions(w, "api", bindMethodsService{}, BindOptions{Namespace: true}); err != nil {
+		t.Fatalf("BindMethodsWithOptions() unexpected error: %v", err)
+	}
+	if _, err := UnbindMethods(w, "api"); err != nil {
+		t.Fatalf("UnbindMethods() unexpected error: %v", err)
+	}
+	if len(w.bound) != 0 {
+		t.Fatalf("bindings left = %v", w.bound)
+	}
+	const want = `delete window["api"];`
+	if w.evals[len(w.evals)-1] != want {
+		t.Fatalf("last eval = %q, want %q", w.evals[len(w.evals)-1], want)
+	}
+	for _, js := range w.inits {
+		if strings.Contains(js, `window["api"]`) {
+			t.Fatalf("namespace still registered for new pages: %q", js)
+		}
+	}
+}
+
+func TestUnbindMethodsNilWebView(t *testing.T) {
+	if _, err := UnbindMethods(nil, "api"); err == nil {
+		t.Fatal("UnbindMethods(nil) expected error")
+	}
+}
+
+type bindFilterService struct {
+	_ struct{} `glaze:"exclude=Close, Reset"`
+}
+
+func (*bindFilterService) Add()   {}
+func (*bindFilterService) List()  {}
+func (*bindFilterService) Close() {}
+func (*bindFilterService) Reset() {}
+
+func TestBindMethodsWithOptionsFilters(t *testing.T) {
+	tests := []struct {
+		name string
+		opts BindOptions
+		want []string
+	}{
+		{"tag exclusions", BindOptions{}, []string{"notes_add", "notes_list"}},
+		{"exclude", BindOptions{Exclude: []string{"List"}}, []string{"notes_add"}},
+		{"include", BindOptions{Include: []string{"List"}}, []string{"notes_list"}},
+		{"include cannot bypass tags", BindOptions{Include: []string{"List", "Close"}}, []string{"notes_list"}},
+		{"name mapper", BindOptions{Name: strings.ToLower}, []string{"notes_add", "notes_list"}},
+		{"name mapper in namespace", BindOptions{Namespace: true, Name: strings.ToUpper}, []string{"notes.ADD", "notes.LIST"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &bindMethodsWebViewStub{}
+			names, err := BindMethodsWithOptions(w, "notes", &bindFilterService{}, tt.opts)
+			if err != nil {
+				t.Fatalf("BindMethodsWithOptions() unexpected error: %v", err)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("BindMethodsWithOptions() names = %q, want %q", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindMethodsWithOptionsUnknownInclude(t *testing.T) {
+	w := &bindMethodsWebViewStub{}
+	if _, err := BindMethodsWithOptions(w, "notes", &bindFilterService{}, BindOptions{Include: []string{"Missing"}}); err == nil {
+		t.Fatal("BindMethodsWithOptions() expected error for unknown included method")
+	}
+	if w.bindCalls != 0 {
+		t.Fatalf("bind calls = %d, want 0", w.bindCalls)
+	}
+}
+
+func TestBindMethodsHonorsTagExclusions(t *testing.T) {
+	w := &bindMethodsWebViewStub{}
+	names, err := BindMethods(w, "notes", &bindFilterService{})
+	if err != nil || len(names) != 2 {
+		t.Fatalf("BindMethods() = %q, %v; want add and list", names, err)
+	}
+}
+
 func TestRenderHTML(t *testing.T) {
 	tpl := template.Must(template.New("test").Parse(
 		`{{define "hello"}}Hello, {{.Name}}!{{end}}`,
@@ -0,0 +1,110 @@
+package glaze
+
+import "errors"
+
+// compositionBindingName is the hidden binding the page uses to report IME
+// composition events to Go.
+const compositionBindingName = "__glaze_composition"
+
+// imeJS tracks IME composition in the page, reports it to Go and to glaze.on
+// subscribers, and works around engine quirks that break CJK input:
+//
+//   - WebKit delivers the keydown that commits a composition (usually Enter)
+//     after compositionend, so page shortcuts such as "Enter submits" fire
+//     on a half-typed word. Such keydowns are hidden from page listeners.
+//   - WebKitGTK and WKWebView may not reattach the input method to the
+//     focused field when the window regains focus. The field is re-focused so
+//     the engine rebinds it.
+const imeJS = `(function () {
+  'use strict';
+  var glaze = window.glaze = window.glaze || {};
+  if (glaze._imeInstalled) { return; }
+  glaze._imeInstalled = true;
+  glaze.composing = false;
+  var justEnded = false;
+
+  function describe(el) {
+    return el && el.tagName ? el.tagName.toLowerCase() + (el.id ? "#" + el.id : "") : "";
+  }
+  function report(phase, event) {
+    var e = { phase: phase, data: event.data || "", target: describe(event.target) };
+    if (glaze._emit) { glaze._emit("composition", e); }
+    if (window.` + compositionBindingName + `) { window.` + compositionBindingName + `(e); }
+  }
+  document.addEventListener("compositionstart", function (event) {
+    glaze.composing = true;
+    report("start", event);
+  }, true);
+  document.addEventListener("compositionupdate", function (event) {
+    report("update", event);
+  }, true);
+  document.addEventListener("compositionend", function (event) {
+    glaze.composing = false;
+    justEnded = true;
+    setTimeout(function () { justEnded = false; }, 0);
+    report("end", event);
+  }, true);
+  document.addEventListener("keydown", function (event) {
+    if (glaze.composing || event.isComposing || (justEnded && event.keyCode === 229)) {
+      event.stopImmediatePropagation();
+    }
+  }, true);
+  window.addEventListener("focus", function () {
+    var el = document.activeElement;
+    if (!el || el === document.body) { return; }
+    if (el.isContentEditable || el.tagName === "INPUT" || el.tagName === "TEXTAREA") {
+      el.blur();
+      el.focus({ preventScroll: true });
+    }
+  });
+})();`
+
+// CompositionEvent reports a change in IME composition state.
+type CompositionEvent struct {
+	// Phase is "start", "update" or "end".
+	Phase string `json:"phase"`
+
+	// Data is the text being composed; on "end" it is the committed text.
+	Data string `json:"data"`
+
+	// Target identifies the focused element as "tag#id".
+	Target string `json:"target"`
+}
+
+// OnComposition installs glaze's IME support in the window and calls fn for
+// every composition event. The page sees the same events through
+// glaze.on("composition", cb) and the current state in glaze.composing.
+//
+// Installing it also applies fixes for embedded engines that make text
+// editors unusable with CJK input methods: keydowns that commit a
+// composition no longer reach page handlers, and the focused field is
+// re-focused when the window is activated so the input method rebinds to it.
+// Pass a nil fn to get only the fixes and the JavaScript state.
+//
+// It must be called from the UI thread, like Bind.
+func OnComposition(w WebView, fn func(CompositionEvent)) error {
+	if w == nil {
+		return errors.New("webview: OnComposition requires a non-nil WebView")
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.composition = fn
+	b.mu.Unlock()
+
+	if err := b.bindHidden(compositionBindingName, b.handleComposition); err != nil {
+		return err
+	}
+	b.injectScript("events", eventsJS)
+	b.injectScript("ime", imeJS)
+	return nil
+}
+
+// handleComposition is bound as compositionBindingName.
+func (b *bridge) handleComposition(e CompositionEvent) {
+	b.mu.Lock()
+	fn := b.composition
+	b.mu.Unlock()
+	if fn != nil {
+		fn(e)
+	}
+}
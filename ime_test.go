@@ -0,0 +1,36 @@
+package glaze
+
+import "testing"
+
+func TestOnComposition(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	var got []CompositionEvent
+	if err := OnComposition(w, func(e CompositionEvent) { got = append(got, e) }); err != nil {
+		t.Fatalf("OnComposition() unexpected error: %v", err)
+	}
+	if err := OnComposition(w, func(e CompositionEvent) { got = append(got, e) }); err != nil {
+		t.Fatalf("second OnComposition() unexpected error: %v", err)
+	}
+	if len(w.inits) != 2 || w.inits[0] != eventsJS || w.inits[1] != imeJS {
+		t.Fatalf("init scripts = %d, want events and IME runtimes once", len(w.inits))
+	}
+
+	for _, phase := range []string{"start", "update", "end"} {
+		if _, err := w.call(t, compositionBindingName, map[string]string{"phase": phase, "data": "日本", "target": "textarea"}); err != nil {
+			t.Fatalf("composition binding unexpected error: %v", err)
+		}
+	}
+	if len(got) != 3 || got[0].Phase != "start" || got[2].Phase != "end" || got[2].Data != "日本" {
+		t.Fatalf("events = %+v, want start/update/end", got)
+	}
+
+	_ = OnComposition(w, nil)
+	if _, err := w.call(t, compositionBindingName, map[string]string{"phase": "start"}); err != nil {
+		t.Fatalf("composition binding unexpected error: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("events after clearing handler = %d, want 3", len(got))
+	}
+}
@@ -0,0 +1,269 @@
+package glaze
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// scriptEngine is implemented by WebViews that can unregister the scripts
+// they run at the start of new pages. The native webview implements it with
+// the engine's user script API, so the scripts of a window live in one
+// registry that can be listed, reordered and cleared without leaving
+// anything behind in the engine.
+type scriptEngine interface {
+	// addScript registers js and returns a non-zero handle for
+	// removeScript.
+	addScript(js string) (uintptr, error)
+	removeScript(handle uintptr)
+
+	// initNative registers js for good, as Init does without the
+	// registry, for when addScript fails.
+	initNative(js string)
+}
+
+// glazeScriptPrefix starts the names of glaze's own runtime scripts.
+const glazeScriptPrefix = "glaze:"
+
+// InitScript is a script run at the start of every page a window loads.
+type InitScript struct {
+	// Name is the name the script was added under with AddInitScript, ""
+	// for scripts passed to WebView.Init, and "glaze:" followed by the
+	// feature for glaze's own runtime.
+	Name string
+
+	// Order is the order the script runs in; only AddInitScript sets it.
+	Order int
+
+	// Source is the script.
+	Source string
+}
+
+// initScript is an entry of a window's init script registry.
+type initScript struct {
+	InitScript
+
+	// js is the source registered with the engine.
+	js  string
+	seq uint64
+
+	// handle is the engine's handle while the script is registered, and
+	// fixed marks a script registered with Init instead, which the engine
+	// cannot forget.
+	handle uintptr
+	fixed  bool
+}
+
+// AddInitScript adds js under name to the scripts run in every page w loads
+// from now on, before the page's own scripts, so libraries such as an event
+// bridge, theming and analytics can each manage their own injection. Scripts
+// run in ascending order, and those of equal order in the order they were
+// added; one that throws does not stop the others. Adding a name again
+// replaces its script, and RemoveInitScript removes it.
+//
+//	_ = glaze.AddInitScript(w, "theme", themeJS, -10)
+//	_ = glaze.AddInitScript(w, "analytics", analyticsJS, 0)
+//
+// Each script runs as the body of a function called with this set to
+// window, so its top-level declarations stay local: assign to window for
+// globals. Scripts passed to Init have order 0. Like Init, AddInitScript
+// must be called from the UI thread.
+func AddInitScript(w WebView, name, js string, order int) error {
+	if w == nil {
+		return errors.New("webview: AddInitScript requires a non-nil WebView")
+	}
+	if name == "" || strings.HasPrefix(name, glazeScriptPrefix) {
+		return fmt.Errorf("webview: invalid init script name %q", name)
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.putInitLocked(InitScript{Name: name, Order: order, Source: js},
+		"(function () {\n"+js+"\n}).call(window);")
+}
+
+// RemoveInitScript stops running the script added as name in pages w loads
+// from now on. Pages already loaded keep what it did. It must be called
+// from the UI thread.
+func RemoveInitScript(w WebView, name string) error {
+	if w == nil {
+		return errors.New("webview: RemoveInitScript requires a non-nil WebView")
+	}
+	if name == "" || strings.HasPrefix(name, glazeScriptPrefix) {
+		return fmt.Errorf("webview: no init script named %q", name)
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.dropInitLocked(name)
+}
+
+// ClearInitScripts removes every script added to w with AddInitScript or
+// Init, and returns them in the order they ran. glaze's own runtime stays,
+// since the helpers in use rely on it. Scripts belong to the window they
+// were added to: other windows, including those opened later with
+// App.OpenWindow, are not affected. Nothing is registered with the engine
+// to do it. It must be called from the UI thread.
+func ClearInitScripts(w WebView) ([]InitScript, error) {
+	if w == nil {
+		return nil, errors.New("webview: ClearInitScripts requires a non-nil WebView")
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var cleared []InitScript
+	var fixed int
+	kept := b.inits[:0:0]
+	for _, s := range b.inits {
+		switch {
+		case strings.HasPrefix(s.Name, glazeScriptPrefix):
+			kept = append(kept, s)
+		case s.fixed:
+			kept = append(kept, s)
+			fixed++
+		default:
+			cleared = append(cleared, s.InitScript)
+		}
+	}
+	b.inits = kept
+	b.syncInitsLocked(false)
+	if fixed > 0 {
+		return cleared, fmt.Errorf("webview: %d init scripts cannot be removed: the engine does not support it", fixed)
+	}
+	return cleared, nil
+}
+
+// InitScripts returns every script run at the start of the pages w loads,
+// in the order they run: those added with AddInitScript and Init, and
+// glaze's own runtime.
+func InitScripts(w WebView) []InitScript {
+	b := bridgeFor(w)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	scripts := make([]InitScript, len(b.inits))
+	for i, s := range b.inits {
+		scripts[i] = s.InitScript
+	}
+	return scripts
+}
+
+// initScript registers js to run at the start of every page w loads, as
+// WebView.Init does, in the registry of w.
+func (b *bridge) initScript(js string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_ = b.putInitLocked(InitScript{Source: js}, js) // unnamed scripts replace nothing
+}
+
+// setRuntimeScriptLocked registers js as glaze's runtime script for
+// feature, replacing the previous one. b.mu must be held.
+func (b *bridge) setRuntimeScriptLocked(feature, js string) {
+	_ = b.putInitLocked(InitScript{Name: glazeScriptPrefix + feature, Source: js}, js) // runtime scripts are always replaced
+}
+
+// dropRuntimeScriptLocked removes glaze's runtime script for feature, if
+// any. b.mu must be held.
+func (b *bridge) dropRuntimeScriptLocked(feature string) {
+	_ = b.dropInitLocked(glazeScriptPrefix + feature)
+}
+
+// putInitLocked adds s, registered as js, to the registry, replacing the
+// script of the same name unless s is unnamed, and registers it with the
+// engine. A replaced script keeps its place among scripts of its order.
+// b.mu must be held.
+func (b *bridge) putInitLocked(s InitScript, js string) error {
+	b.initSeq++
+	entry := initScript{InitScript: s, js: js, seq: b.initSeq}
+	if s.Name != "" {
+		if i := slices.IndexFunc(b.inits, func(e initScript) bool { return e.Name == s.Name }); i >= 0 {
+			// A fixed runtime script keeps running too, before the new
+			// version; a fixed user script cannot be replaced.
+			if b.inits[i].fixed && !strings.HasPrefix(s.Name, glazeScriptPrefix) {
+				return fmt.Errorf("webview: init script %q cannot be replaced: the engine does not support it", s.Name)
+			}
+			if b.inits[i].Order == s.Order {
+				entry.seq = b.inits[i].seq
+			}
+			b.inits = slices.Delete(b.inits, i, i+1)
+		}
+	}
+	i, _ := slices.BinarySearchFunc(b.inits, entry, compareInits)
+	b.inits = slices.Insert(b.inits, i, entry)
+	b.syncInitsLocked(false)
+	return nil
+}
+
+// dropInitLocked removes the script named name from the registry and the
+// engine. b.mu must be held.
+func (b *bridge) dropInitLocked(name string) error {
+	i := slices.IndexFunc(b.inits, func(e initScript) bool { return e.Name == name })
+	if i < 0 {
+		return fmt.Errorf("webview: no init script named %q", name)
+	}
+	if b.inits[i].fixed {
+		return fmt.Errorf("webview: init script %q cannot be removed: the engine does not support it", name)
+	}
+	b.inits = slices.Delete(b.inits, i, i+1)
+	b.syncInitsLocked(false)
+	return nil
+}
+
+// reregisterInits registers every script with the engine again, after the
+// native library has replaced its own, which it does on each Bind and
+// Unbind: some engines then drop every script, and the others would run
+// the library's bridge after glaze's scripts.
+func (b *bridge) reregisterInits() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.syncInitsLocked(true)
+}
+
+// syncInitsLocked brings the engine in line with the registry. Scripts
+// registered in the right place stay; from the first one out of place on,
+// or from the start when all is set, they are unregistered and the rest of
+// the registry is registered in order. With an engine that cannot
+// unregister scripts, new ones are registered with Init. b.mu must be held.
+func (b *bridge) syncInitsLocked(all bool) {
+	eng, ok := b.w.(scriptEngine)
+	var live []*initScript
+	for i := range b.inits {
+		if !b.inits[i].fixed {
+			live = append(live, &b.inits[i])
+		}
+	}
+	if !ok {
+		for _, s := range live {
+			b.w.Init(s.js)
+			s.fixed = true
+		}
+		return
+	}
+	keep := 0
+	if !all {
+		for keep < len(live) && keep < len(b.engineInits) && live[keep].handle == b.engineInits[keep] {
+			keep++
+		}
+	}
+	for _, h := range b.engineInits[keep:] {
+		eng.removeScript(h)
+	}
+	b.engineInits = b.engineInits[:keep]
+	for _, s := range live[keep:] {
+		h, err := eng.addScript(s.js)
+		if err != nil {
+			// The engine is out of reach: fall back to Init, for good.
+			eng.initNative(s.js)
+			s.handle, s.fixed = 0, true
+			continue
+		}
+		s.handle = h
+		b.engineInits = append(b.engineInits, h)
+	}
+}
+
+// compareInits orders scripts as they run.
+func compareInits(x, y initScript) int {
+	return cmp.Or(cmp.Compare(x.Order, y.Order), cmp.Compare(x.seq, y.seq))
+}
@@ -0,0 +1,38 @@
+package glaze
+
+import "github.com/ebitengine/purego/objc"
+
+// userContentController returns the WKUserContentController of the
+// WKWebView view.
+func userContentController(view uintptr) objc.ID {
+	config := objc.ID(view).Send(objc.RegisterName("configuration"))
+	return config.Send(objc.RegisterName("userContentController"))
+}
+
+// addEngineScript adds js to the user scripts of the WKWebView view, for
+// the main frame at document start as Init does. The handle is the
+// WKUserScript, which glaze holds until it is removed.
+func addEngineScript(view uintptr, js string) (uintptr, error) {
+	const atDocumentStart = 0 // WKUserScriptInjectionTimeAtDocumentStart
+	script := objc.ID(objc.GetClass("WKUserScript")).Send(objc.RegisterName("alloc"))
+	script = script.Send(objc.RegisterName("initWithSource:injectionTime:forMainFrameOnly:"), nsString(js), atDocumentStart, true)
+	userContentController(view).Send(objc.RegisterName("addUserScript:"), script)
+	return uintptr(script), nil
+}
+
+// removeEngineScript removes the script added as handle. WebKit only
+// removes user scripts all at once, so the others are added back in their
+// order.
+func removeEngineScript(view, handle uintptr) {
+	ucc := userContentController(view)
+	scripts := ucc.Send(objc.RegisterName("userScripts")).Send(objc.RegisterName("retain"))
+	ucc.Send(objc.RegisterName("removeAllUserScripts"))
+	n := objc.Send[uint](scripts, objc.RegisterName("count"))
+	for i := range n {
+		if s := scripts.Send(objc.RegisterName("objectAtIndex:"), i); uintptr(s) != handle {
+			ucc.Send(objc.RegisterName("addUserScript:"), s)
+		}
+	}
+	scripts.Send(objc.RegisterName("release"))
+	objc.ID(handle).Send(objc.RegisterName("release"))
+}
@@ -0,0 +1,61 @@
+package glaze
+
+import (
+	"errors"
+	"sync"
+)
+
+// webkitScripts holds the WebKitGTK entry points of the init script
+// registry.
+var webkitScripts struct {
+	once sync.Once
+	err  error
+
+	manager func(view uintptr) uintptr
+	newUser func(source string, frames, time int, allow, block uintptr) uintptr
+	add     func(manager, script uintptr)
+	remove  func(manager, script uintptr)
+	unref   func(script uintptr)
+}
+
+// loadWebkitScripts binds the WebKitUserContentManager functions.
+func loadWebkitScripts() error {
+	s := &webkitScripts
+	s.once.Do(func() {
+		s.err = openNative("libwebkit2gtk-4.1.so.0", []nativeFunc{
+			{&s.manager, "webkit_web_view_get_user_content_manager"},
+			{&s.newUser, "webkit_user_script_new"},
+			{&s.add, "webkit_user_content_manager_add_script"},
+			{&s.remove, "webkit_user_content_manager_remove_script"},
+			{&s.unref, "webkit_user_script_unref"},
+		})
+	})
+	return s.err
+}
+
+// addEngineScript adds js to the user content manager of the WebKitWebView
+// view, for the top frame at document start as Init does. The handle is
+// the WebKitUserScript, which glaze holds a reference to until it is
+// removed.
+func addEngineScript(view uintptr, js string) (uintptr, error) {
+	if err := loadWebkitScripts(); err != nil {
+		return 0, err
+	}
+	s := &webkitScripts
+	const topFrame, documentStart = 1, 0 // WEBKIT_USER_CONTENT_INJECT_TOP_FRAME, WEBKIT_USER_SCRIPT_INJECT_AT_DOCUMENT_START
+	script := s.newUser(js, topFrame, documentStart, 0, 0)
+	if script == 0 {
+		return 0, errors.New("webview: webkit_user_script_new failed")
+	}
+	s.add(s.manager(view), script)
+	return script, nil
+}
+
+// removeEngineScript removes the script added as handle. The native
+// library may have removed it already, with every other script, which
+// makes this a no-op.
+func removeEngineScript(view, handle uintptr) {
+	s := &webkitScripts
+	s.remove(s.manager(view), handle)
+	s.unref(handle)
+}
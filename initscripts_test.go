@@ -0,0 +1,188 @@
+package glaze
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// userInits returns the names of the scripts of w that are not glaze's own.
+func userInits(w WebView) []string {
+	var names []string
+	for _, s := range InitScripts(w) {
+		if !strings.HasPrefix(s.Name, glazeScriptPrefix) {
+			names = append(names, s.Name)
+		}
+	}
+	return names
+}
+
+func TestAddInitScript(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	steps := []struct {
+		name  string
+		apply func() error
+		want  []string
+		inits []string
+	}{
+		{
+			"add",
+			func() error { return AddInitScript(w, "analytics", "a()", 0) },
+			[]string{"analytics"},
+			[]string{"a()"},
+		},
+		{
+			"add earlier",
+			func() error { return AddInitScript(w, "theme", "t()", -10) },
+			[]string{"theme", "analytics"},
+			[]string{"t()", "a()"},
+		},
+		{
+			"add same order",
+			func() error { return AddInitScript(w, "bridge", "b()", 0) },
+			[]string{"theme", "analytics", "bridge"},
+			[]string{"t()", "a()", "b()"},
+		},
+		{
+			"replace in place",
+			func() error { return AddInitScript(w, "analytics", "a2()", 0) },
+			[]string{"theme", "analytics", "bridge"},
+			[]string{"t()", "a2()", "b()"},
+		},
+		{
+			"reorder",
+			func() error { return AddInitScript(w, "bridge", "b()", -20) },
+			[]string{"bridge", "theme", "analytics"},
+			[]string{"b()", "t()", "a2()"},
+		},
+		{
+			"remove",
+			func() error { return RemoveInitScript(w, "theme") },
+			[]string{"bridge", "analytics"},
+			[]string{"b()", "a2()"},
+		},
+	}
+	for _, step := range steps {
+		if err := step.apply(); err != nil {
+			t.Fatalf("%s: unexpected error: %v", step.name, err)
+		}
+		if got := userInits(w); !slices.Equal(got, step.want) {
+			t.Errorf("%s: InitScripts() = %q, want %q", step.name, got, step.want)
+		}
+		// The engine holds exactly the registry, in order.
+		var inits []string
+		for _, js := range w.inits {
+			inits = append(inits, strings.TrimSuffix(strings.TrimPrefix(js, "(function () {\n"), "\n}).call(window);"))
+		}
+		if !slices.Equal(inits, step.inits) {
+			t.Errorf("%s: registered %q, want %q", step.name, inits, step.inits)
+		}
+	}
+	if len(w.evals) != 0 {
+		t.Errorf("evals = %q, want the current page left alone", w.evals)
+	}
+
+	if err := RemoveInitScript(w, "theme"); err == nil {
+		t.Error("RemoveInitScript() of a missing script returned no error")
+	}
+	for _, name := range []string{"", "glaze:runtime"} {
+		if err := AddInitScript(w, name, "x()", 0); err == nil {
+			t.Errorf("AddInitScript(%q) returned no error", name)
+		}
+	}
+}
+
+func TestInitScriptsListsEverything(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	w.Init("raw()")
+	_ = AddInitScript(w, "theme", "t()", -10)
+	if err := BindSync(w, "config", map[string]int{"v": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, s := range InitScripts(w) {
+		names = append(names, s.Name)
+	}
+	if want := []string{"theme", "", "glaze:sync:config"}; !slices.Equal(names, want) {
+		t.Errorf("InitScripts() names = %q, want %q", names, want)
+	}
+	if got := InitScripts(w)[1].Source; got != "raw()" {
+		t.Errorf("Init script source = %q, want raw()", got)
+	}
+	if len(w.inits) != 3 {
+		t.Errorf("registered %d scripts, want 3", len(w.inits))
+	}
+}
+
+func TestClearInitScripts(t *testing.T) {
+	w, other := &fakeWebView{}, &fakeWebView{}
+	defer w.Destroy()
+	defer other.Destroy()
+
+	_ = AddInitScript(w, "analytics", "a()", 0)
+	_ = AddInitScript(w, "theme", "t()", -10)
+	w.Init("raw()")
+	if err := BindSync(w, "config", 1); err != nil {
+		t.Fatal(err)
+	}
+	_ = AddInitScript(other, "theme", "o()", 0)
+
+	cleared, err := ClearInitScripts(w)
+	if err != nil {
+		t.Fatalf("ClearInitScripts() unexpected error: %v", err)
+	}
+	var names []string
+	for _, s := range cleared {
+		names = append(names, s.Name+"="+s.Source)
+	}
+	if want := []string{"theme=t()", "analytics=a()", "=raw()"}; !slices.Equal(names, want) {
+		t.Errorf("ClearInitScripts() = %q, want %q", names, want)
+	}
+	if got := userInits(w); len(got) != 0 {
+		t.Errorf("InitScripts() after clear = %q, want none", got)
+	}
+	// Only glaze's runtime is left in the engine, and nothing was added.
+	if len(w.inits) != 1 || !strings.HasPrefix(w.inits[0], syncJS) {
+		t.Errorf("registered %q after clear, want the BindSync runtime only", w.inits)
+	}
+	if got := userInits(other); !slices.Equal(got, []string{"theme"}) {
+		t.Errorf("other window InitScripts() = %q, want its own script kept", got)
+	}
+
+	if cleared, _ := ClearInitScripts(w); len(cleared) != 0 || len(w.inits) != 1 {
+		t.Errorf("second ClearInitScripts() = %v and left %d scripts", cleared, len(w.inits))
+	}
+	if _, err := ClearInitScripts(nil); err == nil {
+		t.Error("ClearInitScripts(nil) expected error")
+	}
+}
+
+// initOnlyWebView is a WebView whose engine cannot remove scripts.
+type initOnlyWebView struct {
+	WebView
+	fake *fakeWebView
+}
+
+func (w initOnlyWebView) Init(js string) { w.fake.initNative(js) }
+
+func TestClearInitScriptsWithoutEngine(t *testing.T) {
+	fake := &fakeWebView{}
+	var w WebView = initOnlyWebView{fake, fake}
+	defer forgetBridge(w)
+
+	_ = AddInitScript(w, "theme", "t()", 0)
+	if _, err := ClearInitScripts(w); err == nil {
+		t.Error("ClearInitScripts() without an engine returned no error")
+	}
+	if got := userInits(w); !slices.Equal(got, []string{"theme"}) {
+		t.Errorf("InitScripts() = %q, want the script still listed", got)
+	}
+	if err := AddInitScript(w, "theme", "t2()", 0); err == nil {
+		t.Error("replacing a script without an engine returned no error")
+	}
+}
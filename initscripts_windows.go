@@ -0,0 +1,144 @@
+package glaze
+
+import (
+	"fmt"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Vtable slots of the WebView2 COM methods used by the init script
+// registry, counting the three IUnknown methods.
+const (
+	webViewAddScriptOnDocumentCreated    = 27 // ICoreWebView2::AddScriptToExecuteOnDocumentCreated
+	webViewRemoveScriptOnDocumentCreated = 28 // ICoreWebView2::RemoveScriptToExecuteOnDocumentCreated
+)
+
+var (
+	iidAddScriptCompleted  = windows.GUID{Data1: 0xB99369F3, Data2: 0x9B11, Data3: 0x47B5, Data4: [8]byte{0xBC, 0x6F, 0x8E, 0x78, 0x95, 0xFC, 0xEA, 0x17}}
+	addScriptCompletedVtbl [4]uintptr
+	addScriptCompletedOnce sync.Once
+)
+
+// engineScripts maps the handles glaze gives out to the IDs WebView2
+// returns, which arrive asynchronously.
+var engineScripts struct {
+	mu      sync.Mutex
+	next    uintptr
+	ids     map[uintptr]string
+	pending map[uintptr]*addScriptCompleted
+}
+
+// addScriptCompleted is the ICoreWebView2AddScriptToExecuteOnDocument
+// CreatedCompletedHandler of one script. It is a static COM object kept in
+// engineScripts.pending until WebView2 calls it; its reference count is
+// never used.
+type addScriptCompleted struct {
+	vtbl    *[4]uintptr
+	core    uintptr
+	handle  uintptr
+	dropped bool
+}
+
+// addEngineScript adds js to the scripts WebView2 runs when a document is
+// created in the view of controller. The handle is glaze's own: WebView2
+// reports the script's ID later, on the UI thread.
+func addEngineScript(controller uintptr, js string) (uintptr, error) {
+	src, err := windows.UTF16PtrFromString(js)
+	if err != nil {
+		return 0, fmt.Errorf("webview: init script: %w", err)
+	}
+	var core uintptr
+	if hr := comCall(controller, controllerGetCoreWebView2, uintptr(unsafe.Pointer(&core))); hr != 0 || core == 0 {
+		return 0, fmt.Errorf("webview: get CoreWebView2: HRESULT %#x", uint32(hr))
+	}
+	engineScripts.mu.Lock()
+	engineScripts.next++
+	c := &addScriptCompleted{vtbl: addScriptCompletedTable(), core: core, handle: engineScripts.next}
+	if engineScripts.pending == nil {
+		engineScripts.pending = make(map[uintptr]*addScriptCompleted)
+		engineScripts.ids = make(map[uintptr]string)
+	}
+	engineScripts.pending[c.handle] = c
+	engineScripts.mu.Unlock()
+	// core is released once the handler has been called.
+	if hr := comCall(core, webViewAddScriptOnDocumentCreated, uintptr(unsafe.Pointer(src)), uintptr(unsafe.Pointer(c))); hr != 0 {
+		engineScripts.mu.Lock()
+		delete(engineScripts.pending, c.handle)
+		engineScripts.mu.Unlock()
+		comCall(core, comRelease)
+		return 0, fmt.Errorf("webview: add init script: HRESULT %#x", uint32(hr))
+	}
+	return c.handle, nil
+}
+
+// removeEngineScript removes the script added as handle, or marks it to be
+// removed as soon as WebView2 reports its ID.
+func removeEngineScript(controller, handle uintptr) {
+	engineScripts.mu.Lock()
+	id, ok := engineScripts.ids[handle]
+	delete(engineScripts.ids, handle)
+	if c := engineScripts.pending[handle]; c != nil {
+		c.dropped = true
+	}
+	engineScripts.mu.Unlock()
+	if !ok {
+		return
+	}
+	var core uintptr
+	if hr := comCall(controller, controllerGetCoreWebView2, uintptr(unsafe.Pointer(&core))); hr != 0 || core == 0 {
+		return
+	}
+	removeScriptID(core, id)
+	comCall(core, comRelease)
+}
+
+// removeScriptID removes the script WebView2 knows as id.
+func removeScriptID(core uintptr, id string) {
+	p, err := windows.UTF16PtrFromString(id)
+	if err != nil {
+		return
+	}
+	comCall(core, webViewRemoveScriptOnDocumentCreated, uintptr(unsafe.Pointer(p)))
+}
+
+// addScriptCompletedTable returns the vtable shared by every
+// addScriptCompleted.
+func addScriptCompletedTable() *[4]uintptr {
+	addScriptCompletedOnce.Do(func() {
+		addScriptCompletedVtbl = [4]uintptr{
+			syscall.NewCallback(func(this, riid, ppv uintptr) uintptr {
+				iid := *(**windows.GUID)(unsafe.Pointer(&riid))
+				out := *(**uintptr)(unsafe.Pointer(&ppv))
+				if *iid != iidUnknown && *iid != iidAddScriptCompleted {
+					*out = 0
+					return hresultNoInterface
+				}
+				*out = this
+				return 0
+			}),
+			syscall.NewCallback(func(uintptr) uintptr { return 1 }),
+			syscall.NewCallback(func(uintptr) uintptr { return 1 }),
+			syscall.NewCallback(func(this, hr, id uintptr) uintptr {
+				c := *(**addScriptCompleted)(unsafe.Pointer(&this))
+				// id belongs to WebView2 and is only read here.
+				script := windows.UTF16PtrToString(*(**uint16)(unsafe.Pointer(&id)))
+				engineScripts.mu.Lock()
+				delete(engineScripts.pending, c.handle)
+				dropped := c.dropped
+				if hr == 0 && !dropped {
+					engineScripts.ids[c.handle] = script
+				}
+				engineScripts.mu.Unlock()
+				if hr == 0 && dropped {
+					removeScriptID(c.core, script)
+				}
+				comCall(c.core, comRelease)
+				return 0
+			}),
+		}
+	})
+	return &addScriptCompletedVtbl
+}
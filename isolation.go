@@ -0,0 +1,184 @@
+package glaze
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// isolationJS locks the glaze runtime against page scripts. It is called with
+// the names bound, kept up to date for new pages as bindings change.
+// glaze._seal makes window.glaze, every internal glaze._* function and every
+// hidden __glaze_* binding non-configurable and read-only, and freezes the
+// bridge the webview library installs as window.__webview__; writes to
+// window.glaze are ignored rather than thrown, since runtime scripts
+// reassign it. Bindings, hidden or not, become non-configurable accessors
+// whose setters are ignored, backed by a private table that only the
+// library's onBind and onUnbind change, so Unbind and binding again keep
+// working.
+//
+// On a new page the script runs before the scripts registered after it, so
+// it only guards onBind there and seals at the first DOM mutation, which the
+// parser makes before it runs any page script, once glaze's other scripts
+// have patched the bridge. Evaluated into a loaded page, it seals at once.
+const isolationJS = `(function (names) {
+  'use strict';
+  var glaze = window.glaze = window.glaze || {};
+  if (glaze._seal) { glaze._seal(); return; }
+  var hasOwn = Object.prototype.hasOwnProperty;
+  var live = {};
+  var guarded = {};
+  function lock(obj, key) {
+    var d = Object.getOwnPropertyDescriptor(obj, key);
+    if (!d || !d.configurable || typeof d.value !== 'function') { return; }
+    Object.defineProperty(obj, key, { value: d.value, writable: false, enumerable: d.enumerable, configurable: false });
+  }
+  function guard(name) {
+    if (guarded[name]) { return; }
+    guarded[name] = true;
+    Object.defineProperty(window, name, {
+      get: function () { return live[name]; },
+      set: function () {},
+      enumerable: true,
+      configurable: false
+    });
+  }
+  function adopt(name) {
+    var d = Object.getOwnPropertyDescriptor(window, name);
+    if (guarded[name] || !d || !d.configurable || typeof d.value !== 'function') { return; }
+    live[name] = d.value;
+    guard(name);
+  }
+  function guardBridge() {
+    var wv = window.__webview__;
+    if (!wv) { return; }
+    var proto = Object.getPrototypeOf(wv);
+    if (Object.isFrozen(proto) || proto._glazeGuarded) { return; }
+    Object.defineProperty(proto, '_glazeGuarded', { value: true });
+    proto.onBind = function (name) {
+      if (hasOwn.call(live, name) || (!guarded[name] && hasOwn.call(window, name))) {
+        throw new Error('Property "' + name + '" already exists');
+      }
+      var self = this;
+      live[name] = function () {
+        return proto.call.apply(self, [name].concat(Array.prototype.slice.call(arguments)));
+      };
+      guard(name);
+    };
+    proto.onUnbind = function (name) {
+      if (!hasOwn.call(live, name)) {
+        throw new Error('Property "' + name + '" does not exist');
+      }
+      delete live[name];
+    };
+  }
+  function seal() {
+    var d = Object.getOwnPropertyDescriptor(window, 'glaze');
+    if (d && d.configurable) {
+      Object.defineProperty(window, 'glaze', {
+        get: function () { return glaze; },
+        set: function () {},
+        enumerable: true,
+        configurable: false
+      });
+    }
+    Object.getOwnPropertyNames(glaze).forEach(function (k) {
+      if (k.charAt(0) === '_') { lock(glaze, k); }
+    });
+    Object.getOwnPropertyNames(window).forEach(function (k) {
+      if (k.indexOf('__glaze_') === 0) { adopt(k); }
+    });
+    names.forEach(adopt);
+    var wv = window.__webview__;
+    if (wv) {
+      guardBridge();
+      Object.freeze(Object.getPrototypeOf(wv));
+      Object.freeze(wv);
+      d = Object.getOwnPropertyDescriptor(window, '__webview__');
+      if (d && d.configurable) {
+        Object.defineProperty(window, '__webview__', { value: wv, writable: false, enumerable: d.enumerable, configurable: false });
+      }
+    }
+  }
+  Object.defineProperty(glaze, '_seal', { value: seal, writable: false, configurable: false });
+  guardBridge();
+  if (document.readyState !== 'loading') {
+    seal();
+    return;
+  }
+  var observer = new MutationObserver(function () {
+    observer.disconnect();
+    seal();
+  });
+  observer.observe(document, { childList: true, subtree: true });
+  document.addEventListener('DOMContentLoaded', seal);
+})(`
+
+// sealJS re-seals the runtime after glaze injects more of it into the
+// current page. New pages seal themselves once their init scripts have run.
+const sealJS = `window.glaze && window.glaze._seal && window.glaze._seal();`
+
+// IsolateRuntime protects glaze's injected runtime in w from hostile or
+// buggy page scripts: window.glaze cannot be replaced, and its internal
+// functions and hidden bindings cannot be overwritten or deleted, so pages
+// cannot intercept evaluation results, pastes or channel traffic. Runtime
+// injected later by other helpers is sealed as it arrives. Bindings and the
+// webview library's bridge object are locked too: pages can neither replace
+// a bound function nor intercept the calls made through it.
+//
+// The engines glaze drives expose no isolated script worlds through the
+// webview library, so this is closure and property-descriptor hardening in
+// the page's own world: it protects against tampering after the runtime is
+// installed, not against scripts that run before it. Public members such as
+// glaze.on stay writable. Helpers that patch the bridge, such as
+// SetTransactions and SetCompression, take effect from the next page load
+// when enabled after the current page was sealed. It must be called from the UI thread, like Bind.
+func IsolateRuntime(w WebView) error {
+	if w == nil {
+		return errors.New("webview: IsolateRuntime requires a non-nil WebView")
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	js := b.isolationScriptLocked()
+	b.mu.Unlock()
+	b.injectScript("isolation", js)
+	b.mu.Lock()
+	b.isolated = true
+	b.mu.Unlock()
+	return nil
+}
+
+// isolationScriptLocked returns the isolation script adopting the bindings
+// of w as they are now. Hidden bindings are adopted by their prefix. b.mu
+// must be held.
+func (b *bridge) isolationScriptLocked() string {
+	names := []string{}
+	for _, name := range slices.Sorted(maps.Keys(b.bindings)) {
+		if !strings.HasPrefix(name, "__glaze_") {
+			names = append(names, name)
+		}
+	}
+	data, _ := json.Marshal(names) // a []string always encodes
+	return isolationJS + string(data) + ");"
+}
+
+// bindingChangedLocked updates the isolation script for new pages after the
+// binding name was added or removed: the library defines bindings before
+// the isolation script runs, which adopts them by name. b.mu must be held.
+func (b *bridge) bindingChangedLocked(name string) {
+	if b.isolated && !strings.HasPrefix(name, "__glaze_") {
+		b.setRuntimeScriptLocked("isolation", b.isolationScriptLocked())
+	}
+}
+
+// resealLocked seals runtime injected into the current page after
+// IsolateRuntime. Future pages seal themselves, so nothing is registered
+// with the engine. b.mu must be held.
+func (b *bridge) resealLocked() {
+	if !b.isolated {
+		return
+	}
+	b.w.Eval(sealJS)
+}
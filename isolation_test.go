@@ -0,0 +1,67 @@
+package glaze
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIsolateRuntimeSealsLaterInjections(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	_ = w.Bind("save", func(string) error { return nil })
+
+	if err := IsolateRuntime(w); err != nil {
+		t.Fatalf("IsolateRuntime() unexpected error: %v", err)
+	}
+	if err := IsolateRuntime(w); err != nil {
+		t.Fatalf("second IsolateRuntime() unexpected error: %v", err)
+	}
+	isolation := isolationJS + `["save"]);`
+	if !slices.Equal(w.inits, []string{isolation}) {
+		t.Fatalf("inits = %q, want only the isolation script guarding the bindings", w.inits)
+	}
+
+	if err := OnPaste(w, func(in PasteData) (PasteData, error) { return in, nil }); err != nil {
+		t.Fatalf("OnPaste() unexpected error: %v", err)
+	}
+	// The hidden paste and document bindings and the document and paste
+	// runtimes are each sealed in the current page; new pages seal
+	// themselves, so no seal is registered with the engine.
+	want := []string{isolation, documentJS, pasteJS}
+	if !slices.Equal(w.inits, want) {
+		t.Fatalf("inits = %q, want %q", w.inits, want)
+	}
+	if last := w.evals[len(w.evals)-1]; last != sealJS {
+		t.Fatalf("last eval = %q, want the current page sealed", last)
+	}
+	seals := 0
+	for _, js := range w.evals {
+		if js == sealJS {
+			seals++
+		}
+	}
+	if seals != 4 {
+		t.Errorf("current page sealed %d times, want 4", seals)
+	}
+
+	// New pages adopt bindings added later, which the library defines
+	// before the isolation script runs.
+	_ = w.Bind("load", func() error { return nil })
+	if !slices.Contains(w.inits, isolationJS+`["load","save"]);`) || slices.Contains(w.inits, isolation) {
+		t.Errorf("inits = %q, want the isolation script to adopt load", w.inits)
+	}
+	_ = w.Unbind("load")
+	if !slices.Contains(w.inits, isolation) {
+		t.Errorf("inits = %q, want load no longer adopted after Unbind", w.inits)
+	}
+}
+
+func TestRuntimeNotSealedByDefault(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	_ = OnPaste(w, func(in PasteData) (PasteData, error) { return in, nil })
+	if slices.Contains(w.inits, sealJS) {
+		t.Fatal("runtime sealed without IsolateRuntime")
+	}
+}
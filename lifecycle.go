@@ -0,0 +1,75 @@
+package glaze
+
+// documentName is the hidden binding each document announces itself
+// through.
+const documentName = "__glaze_document"
+
+// documentJS gives each document an ID, glaze._document, and announces it
+// to Go as soon as the bridge is up, so state the previous document left in
+// Go can be released.
+const documentJS = `(function () {
+  'use strict';
+  var glaze = window.glaze = window.glaze || {};
+  if (glaze._document) { return; }
+  glaze._document = Date.now().toString(36) + "-" + Math.random().toString(36).slice(2);
+  function announce() {
+    if (!window.__webview__ || typeof window.` + documentName + ` !== "function") {
+      setTimeout(announce, 5);
+      return;
+    }
+    window.` + documentName + `(glaze._document).catch(function () {});
+  }
+  announce();
+})();`
+
+// onDocument registers fn under key to be called with the ID of each new
+// document loaded in the window, and with "" once the window is destroyed,
+// so features can release what belonged to the document before. The ID is
+// also available to the page as glaze._document for tagging state.
+// Registering a key again replaces its function. It must run on the UI
+// thread.
+func (b *bridge) onDocument(key string, fn func(doc string)) error {
+	b.mu.Lock()
+	if b.docHooks == nil {
+		b.docHooks = make(map[string]func(string))
+	}
+	b.docHooks[key] = fn
+	b.mu.Unlock()
+	if err := b.bindHidden(documentName, b.documentLoaded); err != nil {
+		return err
+	}
+	b.injectScript("document", documentJS)
+	return nil
+}
+
+// documentLoaded is bound as documentName.
+func (b *bridge) documentLoaded(doc string) {
+	b.mu.Lock()
+	if doc == "" || doc == b.document {
+		b.mu.Unlock()
+		return
+	}
+	b.document = doc
+	b.mu.Unlock()
+	b.runDocumentHooks(doc)
+}
+
+// currentDocument returns the ID of the document last announced.
+func (b *bridge) currentDocument() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.document
+}
+
+// runDocumentHooks calls the functions registered with onDocument.
+func (b *bridge) runDocumentHooks(doc string) {
+	b.mu.Lock()
+	hooks := make([]func(string), 0, len(b.docHooks))
+	for _, fn := range b.docHooks {
+		hooks = append(hooks, fn)
+	}
+	b.mu.Unlock()
+	for _, fn := range hooks {
+		fn(doc)
+	}
+}
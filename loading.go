@@ -0,0 +1,101 @@
+package glaze
+
+// loadingStateName is the hidden binding a freshly loaded page uses to ask
+// whether the loading overlay should still be shown.
+const loadingStateName = "__glaze_loading_state"
+
+// loadingJS adds showLoading/hideLoading to the glaze runtime and restores
+// the overlay on every new page while ShowLoading is in effect, so it stays
+// up across the navigation it is covering.
+const loadingJS = `(function () {
+  'use strict';
+  var glaze = window.glaze = window.glaze || {};
+  if (glaze.showLoading) { return; }
+  var id = "glaze-loading";
+  glaze.showLoading = function (message) {
+    var el = document.getElementById(id);
+    if (!el) {
+      el = document.createElement("div");
+      el.id = id;
+      el.setAttribute("role", "progressbar");
+      el.setAttribute("aria-busy", "true");
+      el.style.cssText = "position:fixed;inset:0;z-index:2147483647;display:flex;flex-direction:column;" +
+        "align-items:center;justify-content:center;gap:16px;background:rgba(17,24,39,0.72);color:#e5e7eb;" +
+        "font:14px -apple-system,BlinkMacSystemFont,'Segoe UI',Roboto,sans-serif;";
+      var spinner = document.createElement("div");
+      spinner.style.cssText = "width:36px;height:36px;border:4px solid rgba(229,231,235,0.25);" +
+        "border-top-color:#e5e7eb;border-radius:50%;";
+      if (spinner.animate) {
+        spinner.animate([{ transform: "rotate(0deg)" }, { transform: "rotate(360deg)" }], { duration: 800, iterations: Infinity });
+      }
+      var text = document.createElement("div");
+      text.className = "glaze-loading-message";
+      el.appendChild(spinner);
+      el.appendChild(text);
+      document.documentElement.appendChild(el);
+    }
+    el.querySelector(".glaze-loading-message").textContent = message || "";
+  };
+  glaze.hideLoading = function () {
+    var el = document.getElementById(id);
+    if (el) { el.parentNode.removeChild(el); }
+  };
+  // The binding may be installed by a later init script, so fall back to
+  // DOMContentLoaded when it is not there yet.
+  function restore() {
+    if (!window.` + loadingStateName + `) { return false; }
+    window.` + loadingStateName + `().then(function (state) {
+      if (state && state.active) { glaze.showLoading(state.message); }
+    });
+    return true;
+  }
+  if (!restore()) { document.addEventListener("DOMContentLoaded", restore); }
+})();`
+
+// loadingState is returned to pages asking whether to show the overlay.
+type loadingState struct {
+	Active  bool   `json:"active"`
+	Message string `json:"message"`
+}
+
+// ShowLoading overlays a spinner with msg over the window content until
+// HideLoading is called. The overlay is restored on every page loaded in the
+// meantime, so it can cover a slow navigation or a backend warmup that ends
+// with one. Calling ShowLoading again only updates the message.
+//
+// It may be called from any goroutine.
+func ShowLoading(w WebView, msg string) {
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.loading = loadingState{Active: true, Message: msg}
+	b.mu.Unlock()
+
+	w.Dispatch(func() {
+		b.installLoading()
+		w.Eval("window.glaze.showLoading(" + marshalJSON(msg) + ");")
+	})
+}
+
+// HideLoading removes the overlay shown by ShowLoading.
+//
+// It may be called from any goroutine.
+func HideLoading(w WebView) {
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.loading = loadingState{}
+	b.mu.Unlock()
+
+	w.Dispatch(func() {
+		b.installLoading()
+		w.Eval("window.glaze.hideLoading();")
+	})
+}
+
+func (b *bridge) installLoading() {
+	_ = b.bindHidden(loadingStateName, func() loadingState {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return b.loading
+	})
+	b.injectScript("loading", loadingJS)
+}
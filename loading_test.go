@@ -0,0 +1,48 @@
+package glaze
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShowLoading(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	ShowLoading(w, `Warming "cache"`)
+	if len(w.inits) != 1 || w.inits[0] != loadingJS {
+		t.Fatalf("init scripts = %d, want the loading runtime once", len(w.inits))
+	}
+	last := w.evals[len(w.evals)-1]
+	if last != `window.glaze.showLoading("Warming \"cache\"");` {
+		t.Fatalf("last eval = %q", last)
+	}
+
+	got, err := w.call(t, loadingStateName)
+	if err != nil {
+		t.Fatalf("loading state unexpected error: %v", err)
+	}
+	if state := got.(loadingState); !state.Active || state.Message != `Warming "cache"` {
+		t.Fatalf("loading state = %+v, want active with message", state)
+	}
+}
+
+func TestHideLoading(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	ShowLoading(w, "one")
+	ShowLoading(w, "two")
+	HideLoading(w)
+
+	if len(w.inits) != 1 {
+		t.Fatalf("init scripts = %d, want 1", len(w.inits))
+	}
+	if last := w.evals[len(w.evals)-1]; !strings.Contains(last, "hideLoading") {
+		t.Fatalf("last eval = %q, want hideLoading", last)
+	}
+	got, _ := w.call(t, loadingStateName)
+	if got.(loadingState).Active {
+		t.Fatal("loading state still active after HideLoading")
+	}
+}
@@ -0,0 +1,150 @@
+package glaze
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocaleInfo describes the user's locale settings as BCP 47 language tags.
+type LocaleInfo struct {
+	// Tag is the locale used for formatting (e.g. "pt-BR").
+	Tag string `json:"tag"`
+
+	// Languages lists the preferred UI languages, most preferred first.
+	// It always contains at least one entry.
+	Languages []string `json:"languages"`
+}
+
+// defaultLocalePollInterval is used by OnLocaleChange when no interval is given.
+const defaultLocalePollInterval = time.Minute
+
+// Locale returns the user's locale and preferred languages, read from
+// AppleLocale/AppleLanguages on macOS, the user default locale and preferred
+// UI languages on Windows, and LC_ALL/LC_MESSAGES/LANG/LANGUAGE on Linux.
+// Its signature makes it directly bindable: w.Bind("locale", glaze.Locale).
+func Locale() (LocaleInfo, error) {
+	return readLocale()
+}
+
+// OnLocaleChange polls the locale every interval (one minute if zero) and
+// calls fn, from a background goroutine, with the initial value and whenever
+// the user changes their language or region settings. The returned function
+// stops polling.
+//
+// On Linux the locale comes from the process environment, which does not
+// change while the process runs, so fn is only called with the initial
+// value; a new desktop locale applies from the next login.
+func OnLocaleChange(interval time.Duration, fn func(LocaleInfo)) (stop func()) {
+	if interval <= 0 {
+		interval = defaultLocalePollInterval
+	}
+	equal := func(a, b LocaleInfo) bool { return a.Tag == b.Tag && slices.Equal(a.Languages, b.Languages) }
+	return watchChanges(interval, readLocale, equal, fn)
+}
+
+// AcceptSystemLanguages wraps next so every request carries an
+// Accept-Language header built from the system's preferred languages.
+// Server-rendered AppWindow pages can then localize with the usual header
+// negotiation instead of guessing from what the engine sends on loopback.
+func AcceptSystemLanguages(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if info, err := readLocale(); err == nil {
+			r.Header.Set("Accept-Language", acceptLanguage(info.Languages))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// acceptLanguage formats languages as an Accept-Language value with
+// decreasing quality factors.
+func acceptLanguage(languages []string) string {
+	parts := make([]string, 0, len(languages))
+	for i, lang := range languages {
+		if i == 0 {
+			parts = append(parts, lang)
+			continue
+		}
+		q := max(10-i, 1)
+		parts = append(parts, lang+";q=0."+strconv.Itoa(q))
+	}
+	return strings.Join(parts, ",")
+}
+
+var bcp47Re = regexp.MustCompile(`^[A-Za-z]{2,8}(-[A-Za-z0-9]{1,8})*$`)
+
+// normalizeLocale converts a POSIX (pt_BR.UTF-8@euro) or Apple (en_US@rg=...)
+// locale name to a BCP 47 tag. It returns "" for names it cannot convert.
+// The C and POSIX locales map to "en-US".
+func normalizeLocale(name string) string {
+	name = strings.TrimSpace(name)
+	if i := strings.IndexAny(name, ".@"); i >= 0 {
+		name = name[:i]
+	}
+	if name == "C" || name == "POSIX" {
+		return "en-US"
+	}
+	name = strings.ReplaceAll(name, "_", "-")
+	if !bcp47Re.MatchString(name) {
+		return ""
+	}
+	parts := strings.Split(name, "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		switch len(parts[i]) {
+		case 2:
+			parts[i] = strings.ToUpper(parts[i]) // region
+		case 4:
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:]) // script
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// localeFromEnv resolves the locale from POSIX environment variables, as
+// returned by getenv.
+func localeFromEnv(getenv func(string) string) LocaleInfo {
+	var tag string
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if tag = normalizeLocale(getenv(key)); tag != "" {
+			break
+		}
+	}
+	if tag == "" {
+		tag = "en-US"
+	}
+
+	var languages []string
+	for _, lang := range strings.Split(getenv("LANGUAGE"), ":") {
+		if l := normalizeLocale(lang); l != "" && !slices.Contains(languages, l) {
+			languages = append(languages, l)
+		}
+	}
+	if len(languages) == 0 {
+		languages = []string{tag}
+	}
+	return LocaleInfo{Tag: tag, Languages: languages}
+}
+
+var appleListItemRe = regexp.MustCompile(`"?([A-Za-z0-9_@=-]+)"?,?\s*$`)
+
+// parseAppleLanguages parses the property list printed by
+// "defaults read -g AppleLanguages".
+func parseAppleLanguages(out string) []string {
+	var languages []string
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line == "(" || line == ")" {
+			continue
+		}
+		if m := appleListItemRe.FindStringSubmatch(line); m != nil {
+			if l := normalizeLocale(m[1]); l != "" {
+				languages = append(languages, l)
+			}
+		}
+	}
+	return languages
+}
@@ -0,0 +1,66 @@
+package glaze
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// appleLocale caches the locale read with defaults, which AcceptSystemLanguages
+// would otherwise run on every request. It is read again when the global
+// preferences file changes.
+var appleLocale struct {
+	mu   sync.Mutex
+	mod  time.Time
+	info LocaleInfo
+}
+
+func readLocale() (LocaleInfo, error) {
+	mod := globalPreferencesModTime()
+	appleLocale.mu.Lock()
+	defer appleLocale.mu.Unlock()
+	if mod.IsZero() || !mod.Equal(appleLocale.mod) {
+		appleLocale.info = readAppleLocale()
+		appleLocale.mod = mod
+	}
+	info := appleLocale.info
+	info.Languages = slices.Clone(info.Languages)
+	return info, nil
+}
+
+// globalPreferencesModTime returns when the preferences AppleLocale and
+// AppleLanguages live in were last written, or the zero time if unknown.
+func globalPreferencesModTime() time.Time {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return time.Time{}
+	}
+	fi, err := os.Stat(filepath.Join(home, "Library", "Preferences", ".GlobalPreferences.plist"))
+	if err != nil {
+		return time.Time{}
+	}
+	return fi.ModTime()
+}
+
+func readAppleLocale() LocaleInfo {
+	info := LocaleInfo{}
+	if out, err := exec.Command("defaults", "read", "-g", "AppleLocale").Output(); err == nil {
+		info.Tag = normalizeLocale(strings.TrimSpace(string(out)))
+	}
+	if out, err := exec.Command("defaults", "read", "-g", "AppleLanguages").Output(); err == nil {
+		info.Languages = parseAppleLanguages(string(out))
+	}
+	// Fall back to the environment, e.g. when running outside a user session.
+	env := localeFromEnv(os.Getenv)
+	if info.Tag == "" {
+		info.Tag = env.Tag
+	}
+	if len(info.Languages) == 0 {
+		info.Languages = env.Languages
+	}
+	return info
+}
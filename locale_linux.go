@@ -0,0 +1,7 @@
+package glaze
+
+import "os"
+
+func readLocale() (LocaleInfo, error) {
+	return localeFromEnv(os.Getenv), nil
+}
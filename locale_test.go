@@ -0,0 +1,96 @@
+package glaze
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"pt_BR.UTF-8", "pt-BR"},
+		{"de_DE@euro", "de-DE"},
+		{"en_US@rg=brzzzz", "en-US"},
+		{"zh-hant-tw", "zh-Hant-TW"},
+		{"sr_Latn_RS", "sr-Latn-RS"},
+		{"es-419", "es-419"},
+		{"C", "en-US"},
+		{"POSIX", "en-US"},
+		{"C.UTF-8", "en-US"},
+		{"", ""},
+		{"not a locale", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := normalizeLocale(tt.input); got != tt.want {
+				t.Errorf("normalizeLocale(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocaleFromEnv(t *testing.T) {
+	env := map[string]string{
+		"LANG":     "pt_BR.UTF-8",
+		"LANGUAGE": "pt_BR:pt:en_US:pt_BR",
+	}
+	got := localeFromEnv(func(k string) string { return env[k] })
+	if got.Tag != "pt-BR" {
+		t.Fatalf("Tag = %q, want pt-BR", got.Tag)
+	}
+	if want := []string{"pt-BR", "pt", "en-US"}; !slices.Equal(got.Languages, want) {
+		t.Fatalf("Languages = %v, want %v", got.Languages, want)
+	}
+
+	env = map[string]string{"LC_ALL": "fr_FR.UTF-8", "LANG": "de_DE.UTF-8"}
+	got = localeFromEnv(func(k string) string { return env[k] })
+	if got.Tag != "fr-FR" || !slices.Equal(got.Languages, []string{"fr-FR"}) {
+		t.Fatalf("localeFromEnv() = %+v, want LC_ALL to win", got)
+	}
+
+	got = localeFromEnv(func(string) string { return "" })
+	if got.Tag != "en-US" || len(got.Languages) != 1 {
+		t.Fatalf("localeFromEnv() = %+v, want en-US default", got)
+	}
+}
+
+func TestParseAppleLanguages(t *testing.T) {
+	out := "(\n    \"en-US\",\n    \"pt-BR\",\n    ja\n)\n"
+	want := []string{"en-US", "pt-BR", "ja"}
+	if got := parseAppleLanguages(out); !slices.Equal(got, want) {
+		t.Fatalf("parseAppleLanguages() = %v, want %v", got, want)
+	}
+}
+
+func TestAcceptLanguage(t *testing.T) {
+	got := acceptLanguage([]string{"pt-BR", "pt", "en-US"})
+	if want := "pt-BR,pt;q=0.9,en-US;q=0.8"; got != want {
+		t.Fatalf("acceptLanguage() = %q, want %q", got, want)
+	}
+}
+
+func TestAcceptSystemLanguages(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("locale is read from the environment only on linux")
+	}
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+	t.Setenv("LANG", "pt_BR.UTF-8")
+	t.Setenv("LANGUAGE", "pt_BR:en")
+
+	var got string
+	h := AcceptSystemLanguages(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get("Accept-Language")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Language", "en-US")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if got != "pt-BR,en;q=0.9" {
+		t.Fatalf("Accept-Language = %q, want %q", got, "pt-BR,en;q=0.9")
+	}
+}
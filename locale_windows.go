@@ -0,0 +1,52 @@
+package glaze
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procGetUserDefaultLocaleName    = kernel32.NewProc("GetUserDefaultLocaleName")
+	procGetUserPreferredUILanguages = kernel32.NewProc("GetUserPreferredUILanguages")
+)
+
+const (
+	localeNameMaxLength = 85
+	muiLanguageName     = 0x8
+)
+
+func readLocale() (LocaleInfo, error) {
+	buf := make([]uint16, localeNameMaxLength)
+	if r, _, err := procGetUserDefaultLocaleName.Call(uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf))); r == 0 {
+		return LocaleInfo{}, fmt.Errorf("webview: GetUserDefaultLocaleName failed: %w", err)
+	}
+	info := LocaleInfo{Tag: normalizeLocale(syscall.UTF16ToString(buf))}
+
+	var count, size uint32
+	procGetUserPreferredUILanguages.Call(muiLanguageName, uintptr(unsafe.Pointer(&count)), 0, uintptr(unsafe.Pointer(&size)))
+	if size > 0 {
+		list := make([]uint16, size)
+		if r, _, _ := procGetUserPreferredUILanguages.Call(muiLanguageName, uintptr(unsafe.Pointer(&count)), uintptr(unsafe.Pointer(&list[0])), uintptr(unsafe.Pointer(&size))); r != 0 {
+			// The list is a sequence of NUL-terminated strings ending with an
+			// empty one.
+			for start := 0; start < len(list) && list[start] != 0; {
+				end := start
+				for end < len(list) && list[end] != 0 {
+					end++
+				}
+				if l := normalizeLocale(syscall.UTF16ToString(list[start:end])); l != "" {
+					info.Languages = append(info.Languages, l)
+				}
+				start = end + 1
+			}
+		}
+	}
+	if info.Tag == "" {
+		info.Tag = "en-US"
+	}
+	if len(info.Languages) == 0 {
+		info.Languages = []string{info.Tag}
+	}
+	return info, nil
+}
@@ -0,0 +1,13 @@
+package glaze
+
+import (
+	"crypto/tls"
+	"errors"
+)
+
+// trustLoopbackCertificate fails on macOS: WKWebView only accepts a
+// self-signed certificate from its navigation delegate, which the embedded
+// library owns.
+func trustLoopbackCertificate(tls.Certificate) (func(WebView) error, error) {
+	return nil, errors.New("webview: AppOptions.TLS is not supported on macOS")
+}
@@ -0,0 +1,54 @@
+package glaze
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"errors"
+	"sync"
+)
+
+// webkitTrust holds the GIO and WebKitGTK entry points used to trust the
+// loopback certificate. The embedded library creates its views in the
+// default web context, so trust is granted there.
+var webkitTrust struct {
+	once sync.Once
+	err  error
+
+	certificateFromPEM func(data string, length int, gerror *uintptr) uintptr
+	defaultContext     func() uintptr
+	allowCertificate   func(context, certificate uintptr, host string)
+	unref              func(object uintptr)
+}
+
+func trustLoopbackCertificate(cert tls.Certificate) (func(WebView) error, error) {
+	webkitTrust.once.Do(func() {
+		webkitTrust.err = openNative("libgio-2.0.so.0", []nativeFunc{
+			{&webkitTrust.certificateFromPEM, "g_tls_certificate_new_from_pem"},
+			{&webkitTrust.unref, "g_object_unref"},
+		})
+		if webkitTrust.err != nil {
+			return
+		}
+		webkitTrust.err = openNative("libwebkit2gtk-4.1.so.0", []nativeFunc{
+			{&webkitTrust.defaultContext, "webkit_web_context_get_default"},
+			{&webkitTrust.allowCertificate, "webkit_web_context_allow_tls_certificate_for_host"},
+		})
+	})
+	if webkitTrust.err != nil {
+		return nil, webkitTrust.err
+	}
+	data := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}))
+	return func(WebView) error {
+		var gerror uintptr
+		gcert := webkitTrust.certificateFromPEM(data, -1, &gerror)
+		if gcert == 0 {
+			return errors.New("webview: WebKit rejected the loopback certificate")
+		}
+		defer webkitTrust.unref(gcert)
+		ctx := webkitTrust.defaultContext()
+		for _, host := range []string{"127.0.0.1", "localhost"} {
+			webkitTrust.allowCertificate(ctx, gcert, host)
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,27 @@
+package glaze
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// webView2ArgsEnv holds extra command-line switches WebView2 passes to the
+// browser process it starts.
+const webView2ArgsEnv = "WEBVIEW2_ADDITIONAL_BROWSER_ARGUMENTS"
+
+// trustLoopbackCertificate tells WebView2 to accept the certificate's
+// public key. The switch is read when the browser process starts, so it is
+// set before the window is created and applies to this process only.
+func trustLoopbackCertificate(cert tls.Certificate) (func(WebView) error, error) {
+	sum := sha256.Sum256(cert.Leaf.RawSubjectPublicKeyInfo)
+	arg := "--ignore-certificate-errors-spki-list=" + base64.StdEncoding.EncodeToString(sum[:])
+	args := strings.TrimSpace(os.Getenv(webView2ArgsEnv) + " " + arg)
+	if err := os.Setenv(webView2ArgsEnv, args); err != nil {
+		return nil, fmt.Errorf("webview: %w", err)
+	}
+	return func(WebView) error { return nil }, nil
+}
@@ -0,0 +1,272 @@
+package glaze
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+)
+
+// APIManifest is an OpenRPC document describing the functions bound in a
+// window, with JSON Schemas for their parameters and results. External
+// tools and test harnesses can read it to discover the Go API surface.
+type APIManifest struct {
+	OpenRPC    string        `json:"openrpc"`
+	Info       APIInfo       `json:"info"`
+	Methods    []APIMethod   `json:"methods"`
+	Components APIComponents `json:"components"`
+}
+
+// APIInfo is the manifest's info object.
+type APIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// APIMethod describes one bound function. Its Name is the JavaScript name
+// the function is called by.
+type APIMethod struct {
+	Name           string     `json:"name"`
+	ParamStructure string     `json:"paramStructure"`
+	Params         []APIParam `json:"params"`
+	Result         *APIParam  `json:"result,omitempty"`
+}
+
+// APIParam describes a parameter or result. Variadic marks a trailing
+// parameter that takes any number of values of Schema's item type.
+type APIParam struct {
+	Name     string     `json:"name"`
+	Required bool       `json:"required,omitempty"`
+	Variadic bool       `json:"x-variadic,omitempty"`
+	Schema   JSONSchema `json:"schema"`
+}
+
+// APIComponents holds the schemas of named Go structs, referenced from
+// method schemas as "#/components/schemas/{Name}".
+type APIComponents struct {
+	Schemas map[string]JSONSchema `json:"schemas,omitempty"`
+}
+
+// JSONSchema is a JSON Schema object.
+type JSONSchema map[string]any
+
+// Manifest returns the API manifest of the functions bound in w with Bind
+// and the helpers built on it, excluding glaze's internal bindings. It
+// reflects the bindings at the time of the call. Struct properties are named
+// as each binding's codec names them on the wire, so bindings using
+// FieldNamingCodec or BindOptions.FieldNames get schemas of their own.
+func Manifest(w WebView) APIManifest {
+	b := bridgeFor(w)
+	b.mu.Lock()
+	bindings := make(map[string]reflect.Type, len(b.bindings))
+	namings := make(map[string]FieldNaming, len(b.bindings))
+	for name, t := range b.bindings {
+		if !b.hidden[name] {
+			bindings[name] = t
+			namings[name] = codecNaming(cmp.Or(b.codecs[name], b.codec))
+		}
+	}
+	b.mu.Unlock()
+
+	names := make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	g := &schemaGenerator{names: make(map[namingKey]string), schemas: make(map[string]JSONSchema)}
+	m := APIManifest{
+		OpenRPC: "1.3.2",
+		Info:    APIInfo{Title: "glaze bindings", Version: "1.0.0"},
+		Methods: make([]APIMethod, 0, len(names)),
+	}
+	for _, name := range names {
+		g.naming = namings[name]
+		m.Methods = append(m.Methods, g.method(name, bindings[name]))
+	}
+	if len(g.schemas) > 0 {
+		m.Components.Schemas = g.schemas
+	}
+	return m
+}
+
+// ManifestHandler serves the current Manifest of w as JSON, for example at
+// "/.well-known/glaze-api.json" of an AppWindow handler.
+func ManifestHandler(w WebView) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, _ *http.Request) {
+		data, err := json.MarshalIndent(Manifest(w), "", "  ")
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		rw.Header().Set("Cache-Control", "no-store")
+		_, _ = rw.Write(data)
+	})
+}
+
+// recordBinding remembers the type of a function bound as name, for
+// Manifest.
+func (b *bridge) recordBinding(name string, f any) {
+	t := reflect.TypeOf(f)
+	if t == nil || t.Kind() != reflect.Func {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.bindings == nil {
+		b.bindings = make(map[string]reflect.Type)
+		b.funcs = make(map[string]any)
+	}
+	b.bindings[name] = t
+	b.funcs[name] = f
+	b.bindingChangedLocked(name)
+}
+
+// forgetBinding drops a binding recorded by recordBinding.
+func (b *bridge) forgetBinding(name string) {
+	b.mu.Lock()
+	delete(b.bindings, name)
+	delete(b.funcs, name)
+	delete(b.codecs, name)
+	b.bindingChangedLocked(name)
+	b.mu.Unlock()
+}
+
+// schemaGenerator converts Go types to JSON Schema, collecting a component
+// schema for every named struct it meets under each field naming. Fields
+// are named by naming.
+type schemaGenerator struct {
+	names   map[namingKey]string
+	schemas map[string]JSONSchema
+	naming  FieldNaming
+}
+
+func (g *schemaGenerator) method(name string, ft reflect.Type) APIMethod {
+	m := APIMethod{Name: name, ParamStructure: "by-position", Params: []APIParam{}}
+	var types []reflect.Type
+	for i := range ft.NumIn() {
+		in := ft.In(i)
+		if _, injected := injectedParams[in]; injected {
+			continue
+		}
+		p := APIParam{Name: fmt.Sprintf("arg%d", len(m.Params)), Required: true, Schema: g.schema(in)}
+		if ft.IsVariadic() && i == ft.NumIn()-1 {
+			p.Required = false
+			p.Variadic = true
+		}
+		m.Params = append(m.Params, p)
+		types = append(types, in)
+	}
+	// Trailing pointer and Optional parameters may be omitted by the page.
+	for i := len(m.Params) - 1; i >= 0 && !m.Params[i].Variadic; i-- {
+		if !omittable(types[i]) {
+			break
+		}
+		m.Params[i].Required = false
+	}
+	switch outs := resultTypes(ft); len(outs) {
+	case 0:
+	case 1:
+		m.Result = &APIParam{Name: "result", Schema: g.schema(outs[0])}
+	default:
+		// Several results arrive as an array, one item each.
+		items := make([]JSONSchema, len(outs))
+		for i, out := range outs {
+			items[i] = g.schema(out)
+		}
+		m.Result = &APIParam{Name: "result", Schema: JSONSchema{
+			"type": "array", "prefixItems": items, "minItems": len(outs), "maxItems": len(outs),
+		}}
+	}
+	return m
+}
+
+//nolint:cyclop
+func (g *schemaGenerator) schema(t reflect.Type) JSONSchema {
+	if elem, ok := optionalElem(t); ok {
+		return JSONSchema{"anyOf": []JSONSchema{g.schema(elem), {"type": "null"}}}
+	}
+	switch {
+	case t == timeType:
+		return JSONSchema{"type": "string", "format": "date-time"}
+	case t == rawMessageType:
+		return JSONSchema{}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return JSONSchema{}
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return JSONSchema{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return JSONSchema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return JSONSchema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return JSONSchema{"type": "number"}
+	case reflect.String:
+		return JSONSchema{"type": "string"}
+	case reflect.Pointer:
+		return JSONSchema{"anyOf": []JSONSchema{g.schema(t.Elem()), {"type": "null"}}}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return JSONSchema{"type": "string", "contentEncoding": "base64"}
+		}
+		return JSONSchema{"type": []string{"array", "null"}, "items": g.schema(t.Elem())}
+	case reflect.Array:
+		return JSONSchema{"type": "array", "items": g.schema(t.Elem()), "minItems": t.Len(), "maxItems": t.Len()}
+	case reflect.Map:
+		return JSONSchema{"type": []string{"object", "null"}, "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		return JSONSchema{"$ref": "#/components/schemas/" + g.named(t)}
+	default:
+		return JSONSchema{}
+	}
+}
+
+// named returns the component name of a named struct, generating its schema
+// on first use. The name is reserved first so recursive types terminate.
+func (g *schemaGenerator) named(t reflect.Type) string {
+	key := namingKey{g.naming, t}
+	if name, ok := g.names[key]; ok {
+		return name
+	}
+	base := typeIdent(t)
+	name := base
+	for n := 2; g.schemas[name] != nil; n++ {
+		name = fmt.Sprintf("%s%d", base, n)
+	}
+	g.names[key] = name
+	g.schemas[name] = JSONSchema{}
+	g.schemas[name] = g.object(t)
+	return name
+}
+
+// object renders a struct as an object schema, with the fields and names
+// the binding's codec encodes.
+func (g *schemaGenerator) object(t reflect.Type) JSONSchema {
+	props := JSONSchema{}
+	var required []string
+	for _, f := range (namingCodec{naming: g.naming}).fields(t) {
+		if f.quoted {
+			props[f.name] = JSONSchema{"type": "string"}
+		} else {
+			props[f.name] = g.schema(t.FieldByIndex(f.index).Type)
+		}
+		if !f.omitEmpty && !f.omitZero {
+			required = append(required, f.name)
+		}
+	}
+	s := JSONSchema{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
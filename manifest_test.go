@@ -0,0 +1,106 @@
+package glaze
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type manifestItem struct {
+	ID    int      `json:"id"`
+	Name  string   `json:"name"`
+	Tags  []string `json:"tags,omitempty"`
+	Child *manifestItem
+}
+
+func TestManifest(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	_ = w.Bind("items_get", func(id int) (manifestItem, error) { return manifestItem{}, nil })
+	_ = w.Bind("items_tag", func(id int, p *Progress, tags ...string) error { return nil })
+	_ = w.Bind("items_gone", func() {})
+	_ = w.Unbind("items_gone")
+	bridgeFor(w).installLoading() // internal binding, must not be listed
+
+	m := Manifest(w)
+	if m.OpenRPC == "" || len(m.Methods) != 2 {
+		t.Fatalf("Manifest() = %+v, want two methods", m)
+	}
+
+	get := m.Methods[0]
+	if get.Name != "items_get" || len(get.Params) != 1 || get.Params[0].Schema["type"] != "integer" {
+		t.Fatalf("items_get = %+v", get)
+	}
+	if get.Result == nil || get.Result.Schema["$ref"] != "#/components/schemas/manifestItem" {
+		t.Fatalf("items_get result = %+v", get.Result)
+	}
+
+	tag := m.Methods[1]
+	if len(tag.Params) != 2 || !tag.Params[1].Variadic || tag.Result != nil {
+		t.Fatalf("items_tag = %+v, want id and variadic tags without result", tag)
+	}
+
+	w2 := &fakeWebView{}
+	_ = w2.Bind("items_page", func(page int) ([]manifestItem, int, error) { return nil, 0, nil })
+	page := Manifest(w2).Methods[0].Result
+	if page == nil || page.Schema["type"] != "array" || len(page.Schema["prefixItems"].([]JSONSchema)) != 2 {
+		t.Fatalf("items_page result = %+v, want a two-item array", page)
+	}
+	if got := schemaTypeName(page.Schema); got != "[manifestItem[] | null, number]" {
+		t.Errorf("items_page result type = %q", got)
+	}
+
+	item := m.Components.Schemas["manifestItem"]
+	props := item["properties"].(JSONSchema)
+	if props["Child"].(JSONSchema)["anyOf"] == nil {
+		t.Errorf("Child schema = %v, want nullable reference", props["Child"])
+	}
+	required := item["required"].([]string)
+	if len(required) != 3 {
+		t.Errorf("required = %v, want id, name and Child", required)
+	}
+}
+
+func TestManifestFieldNames(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	_ = w.Bind("items_get", func(id int) manifestItem { return manifestItem{} })
+	_ = BindWithOptions(w, "items_snake", func(id int) manifestItem { return manifestItem{} },
+		BindingOptions{Codec: FieldNamingCodec(FieldNamesSnake)})
+
+	m := Manifest(w)
+	plain := m.Methods[0].Result.Schema["$ref"]
+	snake := m.Methods[1].Result.Schema["$ref"]
+	if plain != "#/components/schemas/manifestItem" || snake != "#/components/schemas/manifestItem2" {
+		t.Fatalf("result schemas = %v and %v, want one per naming", plain, snake)
+	}
+	props := m.Components.Schemas["manifestItem2"]["properties"].(JSONSchema)
+	if _, ok := props["child"]; !ok {
+		t.Errorf("snake_case properties = %v, want child as on the wire", props)
+	}
+	if _, ok := m.Components.Schemas["manifestItem"]["properties"].(JSONSchema)["Child"]; !ok {
+		t.Error("default naming lost the Go field name")
+	}
+}
+
+func TestManifestHandler(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	_ = w.Bind("ping", func() string { return "pong" })
+
+	rec := httptest.NewRecorder()
+	ManifestHandler(w).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/.well-known/glaze-api.json", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/json" {
+		t.Fatalf("status = %d, content type = %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	var m APIManifest
+	if err := json.Unmarshal(rec.Body.Bytes(), &m); err != nil {
+		t.Fatalf("decode manifest: %v", err)
+	}
+	if len(m.Methods) != 1 || m.Methods[0].Name != "ping" {
+		t.Fatalf("methods = %+v, want ping", m.Methods)
+	}
+}
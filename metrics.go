@@ -0,0 +1,143 @@
+package glaze
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// latencyBounds are the upper bounds of the BindingStats latency histogram.
+var latencyBounds = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	5 * time.Second,
+	time.Duration(math.MaxInt64),
+}
+
+// BindingStats summarises the calls made to one bound function.
+type BindingStats struct {
+	Name   string `json:"name"`
+	Calls  uint64 `json:"calls"`
+	Errors uint64 `json:"errors"`
+
+	// Total is the summed duration of all calls, for computing averages.
+	Total time.Duration `json:"total"`
+
+	// Max is the slowest call seen.
+	Max time.Duration `json:"max"`
+
+	// Latency counts calls by duration. Each bucket counts the calls slower
+	// than the previous bucket's bound and no slower than its own; the last
+	// bound is the maximum Duration.
+	Latency []LatencyBucket `json:"latency"`
+}
+
+// LatencyBucket is one bucket of the BindingStats latency histogram.
+type LatencyBucket struct {
+	UpperBound time.Duration `json:"upperBound"`
+	Count      uint64        `json:"count"`
+}
+
+// CallRecord describes one completed call to a bound function, as passed to
+// the OnCall hook.
+type CallRecord struct {
+	Name     string
+	ID       string
+	Start    time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// Stats returns call counters and latency histograms for the functions
+// bound in w, sorted by name, to find slow or failing bindings in
+// production builds. Functions that were never called are included with
+// zero counts; glaze's internal bindings are not. It is safe to call from
+// any goroutine, including from a bound function.
+func Stats(w WebView) []BindingStats {
+	b := bridgeFor(w)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := make([]BindingStats, 0, len(b.bindings))
+	for name := range b.bindings {
+		if b.hidden[name] {
+			continue
+		}
+		s := BindingStats{Name: name, Latency: make([]LatencyBucket, len(latencyBounds))}
+		if c := b.calls[name]; c != nil {
+			s = *c
+			s.Latency = append([]LatencyBucket(nil), c.Latency...)
+		}
+		stats = append(stats, s)
+	}
+	for i := range stats {
+		for j := range stats[i].Latency {
+			stats[i].Latency[j].UpperBound = latencyBounds[j]
+		}
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// OnCall installs fn to be called, from the goroutine that ran the call,
+// after every call to a function bound in w, for tracing or exporting
+// metrics to an external system. Calling OnCall again replaces the hook;
+// nil removes it. fn must not block, since the call's reply waits for it.
+func OnCall(w WebView, fn func(CallRecord)) {
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.onCall = fn
+	b.mu.Unlock()
+}
+
+// instrument wraps the binding of name so each call is checked against the
+// frame policy, recorded for Stats and reported to the OnCall hook.
+func (b *bridge) instrument(name string, fn func(id, req string) (any, error)) func(id, req string) (any, error) {
+	return func(id, req string) (any, error) {
+		start := time.Now()
+		req, err := b.checkFrame(req)
+		var value any
+		if err == nil {
+			value, err = fn(id, req)
+		}
+		b.recordCall(CallRecord{Name: name, ID: id, Start: start, Duration: time.Since(start), Err: err})
+		return value, err
+	}
+}
+
+func (b *bridge) recordCall(r CallRecord) {
+	b.mu.Lock()
+	if b.calls == nil {
+		b.calls = make(map[string]*BindingStats)
+	}
+	s := b.calls[r.Name]
+	if s == nil {
+		s = &BindingStats{Name: r.Name, Latency: make([]LatencyBucket, len(latencyBounds))}
+		b.calls[r.Name] = s
+	}
+	s.Calls++
+	if r.Err != nil {
+		s.Errors++
+	}
+	s.Total += r.Duration
+	s.Max = max(s.Max, r.Duration)
+	i := sort.Search(len(latencyBounds), func(i int) bool { return r.Duration <= latencyBounds[i] })
+	s.Latency[i].Count++
+	if b.historySize > 0 {
+		if len(b.history) >= b.historySize {
+			b.history = append(b.history[:0], b.history[len(b.history)-b.historySize+1:]...)
+		}
+		b.history = append(b.history, r)
+	}
+	hook := b.onCall
+	b.mu.Unlock()
+
+	if hook != nil {
+		hook(r)
+	}
+}
@@ -0,0 +1,64 @@
+package glaze
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStatsCountsCalls(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	_ = w.Bind("ok", func(n int) int { return n })
+	_ = w.Bind("fail", func() error { return errors.New("boom") })
+	_ = w.Bind("idle", func() {})
+	if err := OnPaste(w, nil); err != nil { // hidden binding
+		t.Fatalf("OnPaste() unexpected error: %v", err)
+	}
+
+	var records []CallRecord
+	OnCall(w, func(r CallRecord) { records = append(records, r) })
+
+	_, _ = w.call(t, "ok", 1)
+	_, _ = w.call(t, "ok", 2)
+	_, _ = w.call(t, "fail")
+
+	stats := Stats(w)
+	if len(stats) != 3 || stats[0].Name != "fail" || stats[1].Name != "idle" || stats[2].Name != "ok" {
+		t.Fatalf("Stats() names = %+v, want fail, idle, ok", stats)
+	}
+	fail, idle, ok := stats[0], stats[1], stats[2]
+	if ok.Calls != 2 || ok.Errors != 0 || fail.Calls != 1 || fail.Errors != 1 || idle.Calls != 0 {
+		t.Fatalf("counters: ok=%+v fail=%+v idle=%+v", ok, fail, idle)
+	}
+	var bucketed uint64
+	for i, bucket := range ok.Latency {
+		if bucket.UpperBound != latencyBounds[i] {
+			t.Fatalf("bucket %d bound = %v, want %v", i, bucket.UpperBound, latencyBounds[i])
+		}
+		bucketed += bucket.Count
+	}
+	if bucketed != 2 || ok.Max > ok.Total {
+		t.Fatalf("histogram holds %d calls, max %v, total %v", bucketed, ok.Max, ok.Total)
+	}
+
+	if len(records) != 3 || records[2].Name != "fail" || records[2].Err == nil || records[0].ID == "" {
+		t.Fatalf("OnCall records = %+v", records)
+	}
+	OnCall(w, nil)
+	_, _ = w.call(t, "ok", 3)
+	if len(records) != 3 {
+		t.Fatal("OnCall hook still called after removal")
+	}
+}
+
+func TestRecordCallBuckets(t *testing.T) {
+	b := &bridge{}
+	b.recordCall(CallRecord{Name: "f", Duration: 3 * time.Millisecond})
+	b.recordCall(CallRecord{Name: "f", Duration: time.Minute})
+	s := b.calls["f"]
+	if s.Latency[1].Count != 1 || s.Latency[len(latencyBounds)-1].Count != 1 || s.Max != time.Minute {
+		t.Fatalf("stats = %+v", s)
+	}
+}
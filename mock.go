@@ -0,0 +1,86 @@
+package glaze
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// MockBindings replaces the named bindings of w with canned responses, so
+// frontend work can proceed against realistic data before the Go services
+// exist or while they are unavailable. Each mock is either a function, bound
+// in place of the original, or a value every call resolves to whatever the
+// arguments; an error value makes every call reject. Names that are not
+// bound yet are simply bound. Mocks are meant for development builds, for
+// example behind a command-line flag.
+//
+//	restore, err := glaze.MockBindings(w, map[string]any{
+//		"notes_list":   []Note{{ID: 1, Text: "Buy milk"}},
+//		"notes_delete": errors.New("read-only demo"),
+//	})
+//
+// restore unbinds the mocks and rebinds the original functions. Both
+// MockBindings and restore must be called from the UI thread, like Bind.
+func MockBindings(w WebView, mocks map[string]any) (restore func() error, err error) {
+	if w == nil {
+		return nil, errors.New("webview: MockBindings requires a non-nil WebView")
+	}
+	names := make([]string, 0, len(mocks))
+	for name := range mocks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b := bridgeFor(w)
+	originals := make(map[string]any, len(names))
+	var mocked []string
+	restore = func() error {
+		var errs []error
+		for _, name := range mocked {
+			if err := w.Unbind(name); err != nil {
+				errs = append(errs, fmt.Errorf("unbinding mock %s: %w", name, err))
+				continue
+			}
+			if original := originals[name]; original != nil {
+				if err := w.Bind(name, original); err != nil {
+					errs = append(errs, fmt.Errorf("restoring %s: %w", name, err))
+				}
+			}
+		}
+		mocked = nil
+		return errors.Join(errs...)
+	}
+
+	for _, name := range names {
+		b.mu.Lock()
+		original := b.funcs[name]
+		b.mu.Unlock()
+		if original != nil {
+			if err := w.Unbind(name); err != nil {
+				return nil, errors.Join(fmt.Errorf("unbinding %s: %w", name, err), restore())
+			}
+		}
+		originals[name] = original
+		if err := w.Bind(name, mockFunc(mocks[name])); err != nil {
+			if original != nil {
+				_ = w.Bind(name, original)
+			}
+			return nil, errors.Join(fmt.Errorf("binding mock %s: %w", name, err), restore())
+		}
+		mocked = append(mocked, name)
+	}
+	return restore, nil
+}
+
+// mockFunc returns the function bound for a mock.
+func mockFunc(mock any) any {
+	if v := reflect.ValueOf(mock); v.Kind() == reflect.Func {
+		return mock
+	}
+	if err, ok := mock.(error); ok {
+		return func(...json.RawMessage) error { return err }
+	}
+	return func(...json.RawMessage) any { return mock }
+}
@@ -0,0 +1,59 @@
+package glaze
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMockBindings(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	_ = w.Bind("notes_count", func() int { return 42 })
+	_ = w.Bind("notes_add", func(s string) string { return "real " + s })
+
+	restore, err := MockBindings(w, map[string]any{
+		"notes_count":  7,
+		"notes_add":    func(s string) string { return "mock " + s },
+		"notes_delete": errors.New("read-only demo"),
+	})
+	if err != nil {
+		t.Fatalf("MockBindings() unexpected error: %v", err)
+	}
+
+	if got, err := w.call(t, "notes_count", "ignored", 1); err != nil || got != 7 {
+		t.Errorf("notes_count = %v, %v; want canned 7", got, err)
+	}
+	if got, _ := w.call(t, "notes_add", "x"); got != "mock x" {
+		t.Errorf("notes_add = %v, want mock function result", got)
+	}
+	if _, err := w.call(t, "notes_delete", 3); err == nil || err.Error() != "read-only demo" {
+		t.Errorf("notes_delete error = %v, want canned error", err)
+	}
+
+	if err := restore(); err != nil {
+		t.Fatalf("restore() unexpected error: %v", err)
+	}
+	if got, _ := w.call(t, "notes_count"); got != 42 {
+		t.Errorf("restored notes_count = %v, want 42", got)
+	}
+	if got, _ := w.call(t, "notes_add", "x"); got != "real x" {
+		t.Errorf("restored notes_add = %v, want real function", got)
+	}
+	if _, bound := w.bound["notes_delete"]; bound {
+		t.Error("mock-only binding still bound after restore")
+	}
+}
+
+func TestMockBindingsRollsBack(t *testing.T) {
+	w := &fakeWebView{failBind: "b"}
+	defer w.Destroy()
+
+	_ = w.Bind("a", func() string { return "real" })
+	if _, err := MockBindings(w, map[string]any{"a": "mock", "b": 1}); err == nil {
+		t.Fatal("MockBindings() expected error")
+	}
+	if got, _ := w.call(t, "a"); got != "real" {
+		t.Fatalf("a = %v after failed mocking, want the original restored", got)
+	}
+}
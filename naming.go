@@ -0,0 +1,421 @@
+package glaze
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// FieldNaming selects the JSON names of struct fields without a json tag
+// name.
+type FieldNaming int
+
+const (
+	// FieldNamesGo keeps the Go field name, as encoding/json does: "UserID".
+	FieldNamesGo FieldNaming = iota
+
+	// FieldNamesCamel uses lowerCamelCase: "userID".
+	FieldNamesCamel
+
+	// FieldNamesSnake uses snake_case: "user_id".
+	FieldNamesSnake
+)
+
+// name returns the JSON name of a field named goName.
+func (n FieldNaming) name(goName string) string {
+	switch n {
+	case FieldNamesCamel:
+		return camelToLowerCamel(goName)
+	case FieldNamesSnake:
+		return camelToSnake(goName)
+	default:
+		return goName
+	}
+}
+
+// FieldNamingCodec returns a Codec that follows encoding/json, except that
+// struct fields without a json tag name are named by naming, so untagged Go
+// structs reach the page as {userID: 1} or {user_id: 1} rather than
+// {UserID: 1}. Tagged fields keep their tag names, and types implementing
+// json.Marshaler or encoding.TextMarshaler encode themselves. Fields whose
+// names clash are settled by encoding/json's rules, applied to the names
+// after conversion. Arguments from the page are matched the same way, and
+// Manifest describes the names this codec uses. Set it for a window with
+// SetCodec, for one binding with BindingOptions.Codec, or for a bound
+// object with BindOptions.FieldNames.
+func FieldNamingCodec(naming FieldNaming) Codec {
+	if naming == FieldNamesGo {
+		return JSONCodec
+	}
+	return namingCodec{naming: naming}
+}
+
+type namingCodec struct {
+	naming FieldNaming
+}
+
+// codecNaming returns the field naming c encodes structs with.
+func codecNaming(c Codec) FieldNaming {
+	if nc, ok := c.(namingCodec); ok {
+		return nc.naming
+	}
+	return FieldNamesGo
+}
+
+// namingField is a field encoded by a namingCodec.
+type namingField struct {
+	index     []int
+	name      string // JSON name under the codec's naming
+	goName    string // JSON name under encoding/json
+	tagged    bool   // named by its json tag
+	omitEmpty bool
+	omitZero  bool
+	quoted    bool
+}
+
+// namingFieldCache holds the fields of struct types by naming and type.
+var namingFieldCache sync.Map // map[namingKey][]namingField
+
+type namingKey struct {
+	naming FieldNaming
+	t      reflect.Type
+}
+
+// fields returns the fields of struct type t that encoding/json encodes, in
+// its order, with embedded struct fields inlined. Name clashes are settled
+// as encoding/json does: the shallowest fields win, and among several at
+// that depth the one tagged with the name, if only one is; otherwise the
+// name is dropped altogether.
+func (c namingCodec) fields(t reflect.Type) []namingField {
+	key := namingKey{c.naming, t}
+	if f, ok := namingFieldCache.Load(key); ok {
+		return f.([]namingField)
+	}
+	var fields []namingField
+	seen := make(map[string]bool)
+	level := []struct {
+		t     reflect.Type
+		index []int
+	}{{t, nil}}
+	visited := map[reflect.Type]bool{t: true}
+	for len(level) > 0 {
+		var next = level[:0:0]
+		var found []namingField
+		for _, s := range level {
+			for i := range s.t.NumField() {
+				f := s.t.Field(i)
+				tag := f.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts, _ := strings.Cut(tag, ",")
+				index := append(slices.Clone(s.index), i)
+				ft := f.Type
+				if f.Anonymous && name == "" {
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						// A type embedded twice at one depth is walked
+						// twice, so its fields clash and drop out.
+						if !visited[ft] {
+							next = append(next, struct {
+								t     reflect.Type
+								index []int
+							}{ft, index})
+						}
+						continue
+					}
+				}
+				if !f.IsExported() {
+					continue
+				}
+				nf := namingField{index: index, name: name, goName: name, tagged: name != ""}
+				if name == "" {
+					nf.name, nf.goName = c.naming.name(f.Name), f.Name
+				}
+				for opt := range strings.SplitSeq(opts, ",") {
+					switch opt {
+					case "omitempty":
+						nf.omitEmpty = true
+					case "omitzero":
+						nf.omitZero = true
+					case "string":
+						nf.quoted = true
+					}
+				}
+				found = append(found, nf)
+			}
+		}
+		for _, f := range found {
+			if seen[f.name] {
+				continue
+			}
+			seen[f.name] = true
+			var clash []namingField
+			for _, g := range found {
+				if g.name == f.name {
+					clash = append(clash, g)
+				}
+			}
+			if len(clash) > 1 {
+				clash = slices.DeleteFunc(clash, func(g namingField) bool { return !g.tagged })
+			}
+			if len(clash) == 1 {
+				fields = append(fields, clash[0])
+			}
+		}
+		for _, s := range next {
+			visited[s.t] = true
+		}
+		level = next
+	}
+	slices.SortFunc(fields, func(x, y namingField) int { return slices.Compare(x.index, y.index) })
+	namingFieldCache.Store(key, fields)
+	return fields
+}
+
+func (c namingCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := c.encode(&buf, reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// selfEncoding reports whether values of t encode themselves.
+func selfEncoding(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType)
+}
+
+func (c namingCodec) encode(buf *bytes.Buffer, v reflect.Value) error {
+	if !v.IsValid() {
+		buf.WriteString("null")
+		return nil
+	}
+	t := v.Type()
+	if selfEncoding(t) || (v.CanAddr() && selfEncoding(reflect.PointerTo(t))) {
+		if v.CanAddr() && !selfEncoding(t) {
+			v = v.Addr()
+		}
+		return c.leaf(buf, v)
+	}
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return c.encode(buf, v.Elem())
+	case reflect.Struct:
+		if !v.CanAddr() {
+			// Addressable fields find pointer-receiver marshalers, as
+			// encoding/json does for values reached through pointers.
+			p := reflect.New(t)
+			p.Elem().Set(v)
+			v = p.Elem()
+		}
+		buf.WriteByte('{')
+		first := true
+		for _, f := range c.fields(t) {
+			fv, err := v.FieldByIndexErr(f.index)
+			if err != nil {
+				continue // field of a nil embedded pointer
+			}
+			if (f.omitEmpty && isEmptyValue(fv)) || (f.omitZero && fv.IsZero()) {
+				continue
+			}
+			if !first {
+				buf.WriteByte(',')
+			}
+			first = false
+			key, _ := json.Marshal(f.name)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if f.quoted {
+				raw, err := json.Marshal(fv.Interface())
+				if err != nil {
+					return err
+				}
+				quoted, _ := json.Marshal(string(raw))
+				buf.Write(quoted)
+				continue
+			}
+			if err := c.encode(buf, fv); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case reflect.Map:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		type entry struct {
+			key string
+			v   reflect.Value
+		}
+		entries := make([]entry, 0, v.Len())
+		for it := v.MapRange(); it.Next(); {
+			k, err := mapKeyString(it.Key())
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry{k, it.Value()})
+		}
+		slices.SortFunc(entries, func(a, b entry) int { return strings.Compare(a.key, b.key) })
+		buf.WriteByte('{')
+		for i, e := range entries {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(e.key)
+			buf.Write(key)
+			buf.WriteByte(':')
+			if err := c.encode(buf, e.v); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case reflect.Slice:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			return c.leaf(buf, v)
+		}
+		fallthrough
+	case reflect.Array:
+		buf.WriteByte('[')
+		for i := range v.Len() {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := c.encode(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	default:
+		return c.leaf(buf, v)
+	}
+}
+
+// leaf encodes v with encoding/json.
+func (c namingCodec) leaf(buf *bytes.Buffer, v reflect.Value) error {
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+// mapKeyString returns the JSON object key of a map key, as encoding/json
+// writes it.
+func mapKeyString(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		text, err := tm.MarshalText()
+		return string(text), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	default:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+}
+
+// isEmptyValue reports whether v is empty under the omitempty option.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return v.IsZero()
+	}
+	return false
+}
+
+// Unmarshal decodes data into v, renaming the object keys meant for
+// struct fields to the names encoding/json expects.
+func (c namingCodec) Unmarshal(data []byte, v any) error {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Pointer {
+		return json.Unmarshal(data, v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var x any
+	if err := dec.Decode(&x); err != nil {
+		return json.Unmarshal(data, v) // report the error encoding/json gives
+	}
+	renamed, err := json.Marshal(c.rename(x, t.Elem()))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(renamed, v)
+}
+
+// rename renames the keys of the objects in x decoded for type t.
+func (c namingCodec) rename(x any, t reflect.Type) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	pt := reflect.PointerTo(t)
+	if pt.Implements(jsonUnmarshalerType) || pt.Implements(textUnmarshalerType) {
+		return x
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := x.(map[string]any)
+		if !ok {
+			return x
+		}
+		fields := c.fields(t)
+		out := make(map[string]any, len(obj))
+		for k, val := range obj {
+			i := slices.IndexFunc(fields, func(f namingField) bool { return f.name == k })
+			if i < 0 {
+				// Like encoding/json, fall back to a case-insensitive match.
+				i = slices.IndexFunc(fields, func(f namingField) bool { return strings.EqualFold(f.name, k) })
+			}
+			if i < 0 {
+				out[k] = val
+				continue
+			}
+			f := fields[i]
+			out[f.goName] = c.rename(val, t.FieldByIndex(f.index).Type)
+		}
+		return out
+	case reflect.Map:
+		if obj, ok := x.(map[string]any); ok {
+			for k, val := range obj {
+				obj[k] = c.rename(val, t.Elem())
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if list, ok := x.([]any); ok {
+			for i, val := range list {
+				list[i] = c.rename(val, t.Elem())
+			}
+		}
+	}
+	return x
+}
@@ -0,0 +1,146 @@
+package glaze
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type namingBase struct {
+	CreatedAt time.Time
+}
+
+type namingUser struct {
+	namingBase
+	UserID   int
+	Name     string `json:"displayName"`
+	HTMLBio  string `json:",omitempty"`
+	Tags     []namingTag
+	Manager  *namingUser
+	Scores   map[string]namingTag
+	internal int
+}
+
+type namingTag struct {
+	TagName string
+}
+
+func TestFieldNamingCodecMarshal(t *testing.T) {
+	u := namingUser{
+		namingBase: namingBase{CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		UserID:     7,
+		Name:       "Ada",
+		Tags:       []namingTag{{TagName: "x"}},
+		Scores:     map[string]namingTag{"b": {"y"}, "a": {"z"}},
+	}
+	for naming, want := range map[FieldNaming]string{
+		FieldNamesCamel: `{"createdAt":"2024-01-02T03:04:05Z","userID":7,"displayName":"Ada","tags":[{"tagName":"x"}],"manager":null,"scores":{"a":{"tagName":"z"},"b":{"tagName":"y"}}}`,
+		FieldNamesSnake: `{"created_at":"2024-01-02T03:04:05Z","user_id":7,"displayName":"Ada","tags":[{"tag_name":"x"}],"manager":null,"scores":{"a":{"tag_name":"z"},"b":{"tag_name":"y"}}}`,
+	} {
+		got, err := FieldNamingCodec(naming).Marshal(u)
+		if err != nil {
+			t.Fatalf("Marshal: %v", err)
+		}
+		if string(got) != want {
+			t.Errorf("naming %d:\n got %s\nwant %s", naming, got, want)
+		}
+	}
+	if FieldNamingCodec(FieldNamesGo) != JSONCodec {
+		t.Error("FieldNamesGo does not use JSONCodec")
+	}
+}
+
+type namingLeft struct {
+	ID   int
+	Note string
+}
+
+type namingRight struct {
+	ID   int
+	Note string `json:"note"`
+}
+
+type namingDeep struct {
+	namingLeft
+}
+
+type namingClash struct {
+	namingLeft
+	namingRight
+	namingDeep
+	Title string
+}
+
+type namingTwice struct {
+	namingLeft
+	*namingDeep
+	Inner struct{ namingLeft }
+}
+
+func TestFieldNamingCodecClashes(t *testing.T) {
+	// Clashes between the encoded names are settled as encoding/json
+	// settles them: the untagged IDs at one depth drop out, the tagged
+	// note wins over the one named by the naming, and deeper fields never
+	// reach a name the shallower ones claimed.
+	v := namingClash{namingLeft{1, "left"}, namingRight{2, "right"}, namingDeep{namingLeft{3, "deep"}}, "T"}
+	got, err := FieldNamingCodec(FieldNamesCamel).Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if want := `{"note":"right","title":"T"}`; string(got) != want {
+		t.Errorf("clashing fields = %s, want %s", got, want)
+	}
+
+	w := namingTwice{namingLeft: namingLeft{1, "a"}}
+	got, _ = FieldNamingCodec(FieldNamesSnake).Marshal(w)
+	if want := `{"id":1,"note":"a","inner":{"id":0,"note":""}}`; string(got) != want {
+		t.Errorf("shallow fields = %s, want %s", got, want)
+	}
+}
+
+func TestFieldNamingCodecUnmarshal(t *testing.T) {
+	var u namingUser
+	err := FieldNamingCodec(FieldNamesSnake).Unmarshal(
+		[]byte(`{"user_id":7,"displayName":"Ada","html_bio":"<b>","tags":[{"tag_name":"x"}],"manager":{"user_id":8},"created_at":"2024-01-02T03:04:05Z"}`), &u)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if u.UserID != 7 || u.Name != "Ada" || u.HTMLBio != "<b>" || len(u.Tags) != 1 || u.Tags[0].TagName != "x" ||
+		u.Manager == nil || u.Manager.UserID != 8 || u.CreatedAt.Year() != 2024 {
+		t.Fatalf("decoded %+v", u)
+	}
+	var n int64
+	if err := FieldNamingCodec(FieldNamesSnake).Unmarshal([]byte(`9007199254740993`), &n); err != nil || n != 9007199254740993 {
+		t.Fatalf("large integer = %d, %v", n, err)
+	}
+	u = namingUser{}
+	if err := FieldNamingCodec(FieldNamesSnake).Unmarshal([]byte(`{"USER_ID":9}`), &u); err != nil || u.UserID != 9 {
+		t.Fatalf("case-insensitive key: %+v, %v", u, err)
+	}
+	if err := FieldNamingCodec(FieldNamesCamel).Unmarshal([]byte(`{`), &u); err == nil {
+		t.Fatal("Unmarshal accepted invalid JSON")
+	}
+}
+
+type namingService struct{}
+
+func (namingService) Get(id int) namingTag { return namingTag{TagName: "t"} }
+
+func TestBindOptionsFieldNames(t *testing.T) {
+	w := &fakeWebView{}
+	opts := BindOptions{FieldNames: FieldNamesSnake}
+	if _, err := BindMethodsWithOptions(w, "tags", namingService{}, opts); err != nil {
+		t.Fatal(err)
+	}
+	data, err := bindingCodec(w, "tags_get").Marshal(namingTag{TagName: "t"})
+	if err != nil || string(data) != `{"tag_name":"t"}` {
+		t.Fatalf("codec of tags_get = %s, %v", data, err)
+	}
+	ts, err := GenerateTypes("tags", namingService{}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(ts, "tag_name: string;") {
+		t.Fatalf("GenerateTypes ignores FieldNames:\n%s", ts)
+	}
+}
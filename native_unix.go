@@ -0,0 +1,33 @@
+//go:build darwin || linux
+
+package glaze
+
+import (
+	"fmt"
+
+	"github.com/ebitengine/purego"
+)
+
+// nativeFunc pairs a Go function pointer with the C symbol it is bound to.
+type nativeFunc struct {
+	ptr  any
+	name string
+}
+
+// openNative opens a system library and binds each nativeFunc to its symbol.
+// Unlike purego.RegisterLibFunc it reports missing symbols as errors instead
+// of panicking, so optional desktop integrations can degrade gracefully.
+func openNative(path string, funcs []nativeFunc) error {
+	lib, err := purego.Dlopen(path, purego.RTLD_NOW|purego.RTLD_GLOBAL)
+	if err != nil {
+		return fmt.Errorf("webview: failed to load %s: %w", path, err)
+	}
+	for _, f := range funcs {
+		sym, err := purego.Dlsym(lib, f.name)
+		if err != nil {
+			return fmt.Errorf("webview: failed to load symbol %s: %w", f.name, err)
+		}
+		purego.RegisterFunc(f.ptr, sym)
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package glaze
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+// Optional is a value the page may leave out. As a trailing parameter of a
+// bound function it lets JavaScript callers omit the argument or pass null,
+// and tells those cases apart from a zero value that was sent:
+//
+//	w.Bind("search", func(q string, limit glaze.Optional[int]) []Result {
+//		return index.Search(q, limit.Or(20))
+//	})
+//	// JavaScript: await search("go"); await search("go", 5);
+//
+// The value is decoded like any other argument and checked against the
+// validate tags of T when set. As a struct field it is set when the key is
+// present and not null; it encodes as its value, or null when not set, so
+// tag it omitzero to leave it out instead.
+type Optional[T any] struct {
+	Value T
+	Set   bool
+}
+
+// Some returns an Optional set to v.
+func Some[T any](v T) Optional[T] {
+	return Optional[T]{Value: v, Set: true}
+}
+
+// Get returns the value and whether it was set.
+func (o Optional[T]) Get() (T, bool) {
+	return o.Value, o.Set
+}
+
+// Or returns the value if it was set and def otherwise.
+func (o Optional[T]) Or(def T) T {
+	if o.Set {
+		return o.Value
+	}
+	return def
+}
+
+func (o Optional[T]) MarshalJSON() ([]byte, error) {
+	if !o.Set {
+		return []byte("null"), nil
+	}
+	return json.Marshal(o.Value)
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		*o = Optional[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &o.Value); err != nil {
+		return err
+	}
+	o.Set = true
+	return nil
+}
+
+// optionalType is implemented by every Optional.
+type optionalType interface {
+	optionalElem() reflect.Type
+	optionalValue() (reflect.Value, bool)
+}
+
+func (Optional[T]) optionalElem() reflect.Type { return reflect.TypeFor[T]() }
+
+func (o Optional[T]) optionalValue() (reflect.Value, bool) {
+	return reflect.ValueOf(&o.Value).Elem(), o.Set
+}
+
+// decodeArg decodes a JavaScript argument into o, with the conversions
+// decodeArg applies to T.
+func (o *Optional[T]) decodeArg(codec Codec, raw json.RawMessage) error {
+	*o = Optional[T]{}
+	if raw = bytes.TrimSpace(raw); len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil
+	}
+	v, err := decodeArg(codec, raw, reflect.TypeFor[T]())
+	if err != nil {
+		return err
+	}
+	o.Value, o.Set = v.Interface().(T), true
+	return nil
+}
+
+// optionalArg is implemented by pointers to Optional.
+type optionalArg interface {
+	decodeArg(codec Codec, raw json.RawMessage) error
+}
+
+var optionalTypeType = reflect.TypeFor[optionalType]()
+
+// optionalElem returns the type T of an Optional[T].
+func optionalElem(t reflect.Type) (reflect.Type, bool) {
+	if !t.Implements(optionalTypeType) || t.Kind() != reflect.Struct {
+		return nil, false
+	}
+	return reflect.Zero(t).Interface().(optionalType).optionalElem(), true
+}
+
+// omittable reports whether a trailing parameter of type t may be left out
+// by the page: pointers and Optional values.
+func omittable(t reflect.Type) bool {
+	_, ok := optionalElem(t)
+	return ok || t.Kind() == reflect.Pointer
+}
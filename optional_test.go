@@ -0,0 +1,109 @@
+package glaze
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOptionalArguments(t *testing.T) {
+	w := &fakeWebView{}
+	if err := w.Bind("search", func(q string, limit Optional[int]) int { return limit.Or(20) }); err != nil {
+		t.Fatal(err)
+	}
+	if err := Bind2(w, "wait", func(name string, d Optional[time.Duration]) (string, error) {
+		if v, ok := d.Get(); ok {
+			return name + " " + v.String(), nil
+		}
+		return name + " unset", nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		name string
+		args []any
+		want any
+	}{
+		{"search", []any{"go"}, 20},
+		{"search", []any{"go", nil}, 20},
+		{"search", []any{"go", 0}, 0},
+		{"search", []any{"go", 5}, 5},
+		{"wait", []any{"a"}, "a unset"},
+		{"wait", []any{"a", "1.5s"}, "a 1.5s"}, // decoded like a time.Duration argument
+	} {
+		got, err := w.call(t, tt.name, tt.args...)
+		if err != nil || got != tt.want {
+			t.Errorf("%s%v = %v, %v; want %v", tt.name, tt.args, got, err, tt.want)
+		}
+	}
+	if _, err := w.call(t, "search", "go", "many"); err == nil || !strings.HasPrefix(err.Error(), "argument 1 (glaze.Optional[int])") {
+		t.Fatalf("bad optional error = %v", err)
+	}
+}
+
+func TestOptionalValidation(t *testing.T) {
+	type filter struct {
+		Tag string `json:"tag" validate:"required"`
+	}
+	w := &fakeWebView{}
+	if err := w.Bind("list", func(f Optional[filter]) bool { return f.Set }); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := w.call(t, "list"); err != nil || got != false {
+		t.Fatalf("list() = %v, %v", got, err)
+	}
+	_, err := w.call(t, "list", map[string]string{"tag": ""})
+	var gerr *Error
+	if !errors.As(err, &gerr) || gerr.Code != ValidationFailed {
+		t.Fatalf("list({tag: ''}) error = %v", err)
+	}
+	if fields := gerr.Data.([]FieldError); len(fields) != 1 || fields[0].Field != "tag" {
+		t.Fatalf("invalid fields = %+v", fields)
+	}
+}
+
+func TestOptionalJSON(t *testing.T) {
+	type settings struct {
+		Zoom  Optional[float64] `json:"zoom"`
+		Theme Optional[string]  `json:"theme,omitzero"`
+	}
+	var s settings
+	if err := json.Unmarshal([]byte(`{"zoom":0}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Zoom.Set || s.Theme.Set {
+		t.Fatalf("decoded %+v", s)
+	}
+	if err := json.Unmarshal([]byte(`{"zoom":null}`), &s); err != nil || s.Zoom.Set {
+		t.Fatalf("null decoded %+v, %v", s, err)
+	}
+	data, _ := json.Marshal(settings{Theme: Some("dark")})
+	if string(data) != `{"zoom":null,"theme":"dark"}` {
+		t.Fatalf("encoded %s", data)
+	}
+}
+
+type optionalService struct{}
+
+func (optionalService) Find(q string, limit Optional[int], tag *string) []string { return nil }
+
+func TestOptionalSignatures(t *testing.T) {
+	src, err := GenerateTypes("svc", optionalService{}, BindOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(src, "(arg0: string, arg1?: number | null, arg2?: string | null)") {
+		t.Fatalf("types:\n%s", src)
+	}
+
+	w := &fakeWebView{}
+	if err := w.Bind("find", optionalService{}.Find); err != nil {
+		t.Fatal(err)
+	}
+	params := Manifest(w).Methods[0].Params
+	if !params[0].Required || params[1].Required || params[2].Required {
+		t.Fatalf("required = %v %v %v", params[0].Required, params[1].Required, params[2].Required)
+	}
+}
@@ -0,0 +1,116 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// pageErrorName is the hidden binding pages report uncaught errors through.
+const pageErrorName = "__glaze_page_error"
+
+// pageErrorJS reports uncaught exceptions and unhandled promise rejections
+// to Go.
+const pageErrorJS = `(function () {
+  'use strict';
+  if (window.__glaze_page_error_installed) { return; }
+  window.__glaze_page_error_installed = true;
+  function report(e) {
+    if (typeof window.` + pageErrorName + ` !== "function") { return; }
+    e.url = location.href;
+    window.` + pageErrorName + `(e).catch(function () {});
+  }
+  window.addEventListener("error", function (ev) {
+    if (ev.target && ev.target !== window) { return; /* failed resource load */ }
+    var err = ev.error;
+    report({
+      kind: "error",
+      message: String(ev.message || (err && err.message) || err),
+      stack: String(err && err.stack || ""),
+      source: String(ev.filename || ""),
+      line: ev.lineno || 0,
+      column: ev.colno || 0
+    });
+  });
+  window.addEventListener("unhandledrejection", function (ev) {
+    var err = ev.reason;
+    report({
+      kind: "unhandledrejection",
+      message: String(err && err.message || err),
+      stack: String(err && err.stack || "")
+    });
+  });
+})();`
+
+// PageError is an exception a page did not catch: a thrown error or a
+// rejected promise nothing handled.
+type PageError struct {
+	// Kind is "error" for thrown exceptions and "unhandledrejection" for
+	// rejected promises.
+	Kind string `json:"kind"`
+
+	// Message is the error message.
+	Message string `json:"message"`
+
+	// Stack is the JavaScript stack trace, when the engine provides one.
+	Stack string `json:"stack,omitempty"`
+
+	// Source, Line and Column locate a thrown exception in its script.
+	Source string `json:"source,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+
+	// URL is the page the error happened in.
+	URL string `json:"url"`
+}
+
+// Error implements error.
+func (e PageError) Error() string {
+	var b strings.Builder
+	b.WriteString("webview: uncaught ")
+	if e.Kind == "unhandledrejection" {
+		b.WriteString("rejection")
+	} else {
+		b.WriteString("error")
+	}
+	if e.Source != "" {
+		fmt.Fprintf(&b, " at %s:%d:%d", e.Source, e.Line, e.Column)
+	}
+	b.WriteString(": ")
+	b.WriteString(e.Message)
+	return b.String()
+}
+
+// OnPageError installs fn as the receiver of uncaught exceptions and
+// unhandled promise rejections in the pages of w, so production apps can
+// log and report frontend crashes:
+//
+//	_ = glaze.OnPageError(w, func(e glaze.PageError) { slog.Error("frontend", "err", e, "stack", e.Stack) })
+//
+// Errors are still shown in the engine console. Calling it again replaces
+// the handler; nil stops reporting. It must be called from the UI thread,
+// like Bind.
+func OnPageError(w WebView, fn func(PageError)) error {
+	if w == nil {
+		return errors.New("webview: OnPageError requires a non-nil WebView")
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.pageError = fn
+	b.mu.Unlock()
+	if err := b.bindHidden(pageErrorName, b.handlePageError); err != nil {
+		return err
+	}
+	b.injectScript("page-error", pageErrorJS)
+	return nil
+}
+
+// handlePageError is bound as pageErrorName.
+func (b *bridge) handlePageError(e PageError) {
+	b.mu.Lock()
+	fn := b.pageError
+	b.mu.Unlock()
+	if fn != nil {
+		fn(e)
+	}
+}
@@ -0,0 +1,50 @@
+package glaze
+
+import "testing"
+
+func TestOnPageError(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	var got []PageError
+	if err := OnPageError(w, func(e PageError) { got = append(got, e) }); err != nil {
+		t.Fatalf("OnPageError() unexpected error: %v", err)
+	}
+	if len(w.inits) != 1 || w.inits[0] != pageErrorJS {
+		t.Fatalf("inits = %d scripts, want the error script", len(w.inits))
+	}
+
+	e := PageError{Kind: "error", Message: "x is not defined", Source: "http://127.0.0.1/app.js", Line: 4, Column: 9, URL: "http://127.0.0.1/"}
+	if _, err := w.call(t, pageErrorName, e); err != nil {
+		t.Fatalf("report call unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0] != e {
+		t.Fatalf("handler received %+v, want %+v", got, e)
+	}
+
+	_ = OnPageError(w, nil)
+	if _, err := w.call(t, pageErrorName, e); err != nil || len(got) != 1 {
+		t.Errorf("error reported after OnPageError(nil): %+v, %v", got, err)
+	}
+}
+
+func TestPageErrorError(t *testing.T) {
+	tests := []struct {
+		e    PageError
+		want string
+	}{
+		{
+			PageError{Kind: "error", Message: "boom", Source: "app.js", Line: 1, Column: 2},
+			"webview: uncaught error at app.js:1:2: boom",
+		},
+		{
+			PageError{Kind: "unhandledrejection", Message: "network down"},
+			"webview: uncaught rejection: network down",
+		},
+	}
+	for _, tt := range tests {
+		if got := tt.e.Error(); got != tt.want {
+			t.Errorf("Error() = %q, want %q", got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package glaze
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// Page size limits applied by PageRequest.Size.
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 1000
+)
+
+// pagesJS adds glaze.pages and glaze.infiniteScroll, which walk a bound list
+// function returning a Page by following its cursors.
+const pagesJS = `(function () {
+  'use strict';
+  var glaze = window.glaze = window.glaze || {};
+  if (glaze.pages) { return; }
+  glaze.pages = function (fn, opts) {
+    opts = opts || {};
+    var args = opts.args || [];
+    var iterable = {};
+    iterable[Symbol.asyncIterator] = function () {
+      var cursor = "", done = false;
+      return {
+        next: function () {
+          if (done) { return Promise.resolve({ value: undefined, done: true }); }
+          var req = { cursor: cursor, limit: opts.limit || 0 };
+          return Promise.resolve(fn.apply(null, [req].concat(args))).then(function (page) {
+            page = page || { items: [] };
+            cursor = page.next || "";
+            done = !cursor;
+            return { value: page, done: false };
+          });
+        }
+      };
+    };
+    return iterable;
+  };
+  glaze.infiniteScroll = function (fn, opts) {
+    var it = glaze.pages(fn, opts)[Symbol.asyncIterator]();
+    var loading = false, finished = false;
+    var observer = new IntersectionObserver(function (entries) {
+      for (var i = 0; i < entries.length; i++) {
+        if (entries[i].isIntersecting) { load(); return; }
+      }
+    }, { root: opts.root || null, rootMargin: opts.margin || "200px" });
+    function stop() {
+      finished = true;
+      observer.disconnect();
+    }
+    function load() {
+      if (loading || finished) { return; }
+      loading = true;
+      it.next().then(function (r) {
+        loading = false;
+        if (r.done) { stop(); return; }
+        opts.render(r.value.items || [], r.value);
+        if (!r.value.next) {
+          stop();
+          if (opts.onEnd) { opts.onEnd(); }
+          return;
+        }
+        // Observe again so a sentinel still in view loads the next page.
+        observer.unobserve(opts.sentinel);
+        observer.observe(opts.sentinel);
+      }, function (err) {
+        loading = false;
+        if (opts.onError) { opts.onError(err); }
+      });
+    }
+    observer.observe(opts.sentinel);
+    return { stop: stop, more: load };
+  };
+})();`
+
+// PageRequest selects a page of a collection. Bound list functions take it
+// as their first argument when paged with glaze.pages or
+// glaze.infiniteScroll in the page.
+type PageRequest struct {
+	// Cursor is the Next value of the previous page, empty for the first.
+	Cursor string `json:"cursor"`
+
+	// Limit is the number of items asked for; see Size.
+	Limit int `json:"limit"`
+}
+
+// Size returns r.Limit clamped to [1, MaxPageSize], or DefaultPageSize when
+// no limit was given.
+func (r PageRequest) Size() int {
+	switch {
+	case r.Limit <= 0:
+		return DefaultPageSize
+	case r.Limit > MaxPageSize:
+		return MaxPageSize
+	default:
+		return r.Limit
+	}
+}
+
+// Page is one page of a collection returned by a bound list function.
+// Binding a function that returns a Page installs glaze.pages and
+// glaze.infiniteScroll in the page:
+//
+//	w.Bind("notes_list", func(req glaze.PageRequest, query string) (glaze.Page[Note], error) {
+//		return store.List(query, req.Cursor, req.Size())
+//	})
+//
+//	// JavaScript
+//	for await (const page of glaze.pages(notes_list, { args: ["milk"] })) { render(page.items); }
+//	glaze.infiniteScroll(notes_list, { sentinel: footer, render: (items) => append(items) });
+type Page[T any] struct {
+	// Items are the elements of this page.
+	Items []T `json:"items"`
+
+	// Next is the cursor of the following page, or empty on the last one.
+	// Cursors are opaque to the page.
+	Next string `json:"next,omitempty"`
+
+	// Total is the size of the whole collection, or -1 when unknown.
+	Total int `json:"total"`
+}
+
+func (Page[T]) glazePage() {}
+
+// pageType is implemented by every Page type.
+var pageType = reflect.TypeFor[interface{ glazePage() }]()
+
+// Paginate returns the page of items that req selects, using offsets as
+// cursors. It suits collections held in memory; backends that can page
+// themselves, such as SQL queries, should build a Page with their own
+// cursors. An invalid cursor returns an *Error coded "invalid_cursor".
+func Paginate[T any](items []T, req PageRequest) (Page[T], error) {
+	offset := 0
+	if req.Cursor != "" {
+		n, err := strconv.Atoi(req.Cursor)
+		if err != nil || n < 0 {
+			return Page[T]{}, &Error{Code: "invalid_cursor", Message: "invalid page cursor", Data: req.Cursor}
+		}
+		offset = min(n, len(items))
+	}
+	end := min(offset+req.Size(), len(items))
+	page := Page[T]{Items: items[offset:end], Total: len(items)}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if end < len(items) {
+		page.Next = strconv.Itoa(end)
+	}
+	return page, nil
+}
@@ -0,0 +1,104 @@
+package glaze
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		name      string
+		req       PageRequest
+		wantItems []int
+		wantNext  string
+		wantCode  string
+	}{
+		{name: "first page", req: PageRequest{Limit: 2}, wantItems: []int{1, 2}, wantNext: "2"},
+		{name: "middle page", req: PageRequest{Cursor: "2", Limit: 2}, wantItems: []int{3, 4}, wantNext: "4"},
+		{name: "last page", req: PageRequest{Cursor: "4", Limit: 2}, wantItems: []int{5}},
+		{name: "default size", req: PageRequest{}, wantItems: items},
+		{name: "past the end", req: PageRequest{Cursor: "9"}, wantItems: []int{}},
+		{name: "invalid cursor", req: PageRequest{Cursor: "x"}, wantCode: "invalid_cursor"},
+		{name: "negative cursor", req: PageRequest{Cursor: "-1"}, wantCode: "invalid_cursor"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := Paginate(items, tt.req)
+			if tt.wantCode != "" {
+				var e *Error
+				if !errors.As(err, &e) || e.Code != tt.wantCode {
+					t.Fatalf("Paginate() error = %v, want code %q", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Paginate() unexpected error: %v", err)
+			}
+			if !slices.Equal(page.Items, tt.wantItems) || page.Next != tt.wantNext || page.Total != len(items) {
+				t.Errorf("Paginate() = %+v, want items %v, next %q, total %d", page, tt.wantItems, tt.wantNext, len(items))
+			}
+		})
+	}
+}
+
+func TestPageRequestSize(t *testing.T) {
+	for _, tt := range []struct{ limit, want int }{
+		{0, DefaultPageSize},
+		{-3, DefaultPageSize},
+		{20, 20},
+		{MaxPageSize + 1, MaxPageSize},
+	} {
+		if got := (PageRequest{Limit: tt.limit}).Size(); got != tt.want {
+			t.Errorf("PageRequest{Limit: %d}.Size() = %d, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestBindPageInstallsHelpers(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	notes := []string{"a", "b", "c"}
+	_ = w.Bind("notes_count", func() int { return len(notes) })
+	_ = w.Bind("notes_list", func(req PageRequest) (Page[string], error) { return Paginate(notes, req) })
+	_ = w.Bind("tags_list", func(req PageRequest) (Page[string], error) { return Paginate(notes, req) })
+
+	countHelpers := func() int {
+		n := 0
+		for _, js := range w.inits {
+			if js == pagesJS {
+				n++
+			}
+		}
+		return n
+	}
+	if _, err := w.call(t, "notes_count"); err != nil || countHelpers() != 0 {
+		t.Fatalf("paging helpers installed for a function that returns no Page (err %v)", err)
+	}
+
+	got, err := w.call(t, "notes_list", map[string]any{"cursor": "1", "limit": 1})
+	if err != nil {
+		t.Fatalf("notes_list error: %v", err)
+	}
+	raw, _ := json.Marshal(got)
+	if want := `{"items":["b"],"next":"2","total":3}`; string(raw) != want {
+		t.Errorf("notes_list result = %s, want %s", raw, want)
+	}
+	_, _ = w.call(t, "tags_list", map[string]any{})
+	if n := countHelpers(); n != 1 {
+		t.Fatalf("paging helpers installed %d times, want once", n)
+	}
+}
+
+func TestTypeIdentGeneric(t *testing.T) {
+	if got := typeIdent(reflect.TypeFor[Page[tsNote]]()); got != "PageOfTsNote" {
+		t.Errorf("typeIdent(Page[tsNote]) = %q, want PageOfTsNote", got)
+	}
+	if got := typeIdent(reflect.TypeFor[PageRequest]()); got != "PageRequest" {
+		t.Errorf("typeIdent(PageRequest) = %q, want PageRequest", got)
+	}
+}
@@ -0,0 +1,214 @@
+package glaze
+
+import "errors"
+
+// pasteBindingName is the hidden binding the page calls for every paste.
+const pasteBindingName = "__glaze_paste"
+
+// pasteJS intercepts paste events before the page sees them, hands the
+// clipboard content to Go and inserts whatever Go returns at the position
+// the selection had when the paste happened. If the Go call fails, the
+// original plain text is inserted. glaze._paste.off removes the listener and
+// glaze._paste.on puts it back.
+const pasteJS = `(function () {
+  'use strict';
+  var glaze = window.glaze = window.glaze || {};
+  if (glaze._paste) { return; }
+
+  function readFile(file) {
+    return new Promise(function (resolve) {
+      var reader = new FileReader();
+      reader.onload = function () {
+        resolve({ name: file.name, type: file.type, data: String(reader.result).split(",")[1] || "" });
+      };
+      reader.onerror = function () { resolve(null); };
+      reader.readAsDataURL(file);
+    });
+  }
+
+  // field reports whether el edits plain text through a selection range,
+  // as textareas and text-like inputs do; number and email inputs do not.
+  function field(el) {
+    if (!el || (el.tagName !== "TEXTAREA" && el.tagName !== "INPUT")) { return false; }
+    try {
+      return typeof el.setRangeText === "function" && el.selectionStart !== null;
+    } catch (e) {
+      return false;
+    }
+  }
+
+  // plain returns the text of data, taking it from the HTML, parsed in an
+  // inert document, when Go returned only HTML.
+  function plain(data) {
+    if (data.text) { return data.text; }
+    if (!data.html) { return ""; }
+    return new DOMParser().parseFromString(data.html, "text/html").body.textContent || "";
+  }
+
+  // place records where the paste goes, since the page may move the
+  // selection while Go runs.
+  function place(target) {
+    if (field(target)) {
+      return { field: true, start: target.selectionStart, end: target.selectionEnd };
+    }
+    var sel = window.getSelection();
+    return { range: sel && sel.rangeCount ? sel.getRangeAt(0).cloneRange() : null };
+  }
+
+  function insert(target, at, data) {
+    if (at.field) {
+      var text = plain(data);
+      if (!text) { return; }
+      target.setRangeText(text, at.start, at.end, "end");
+      target.dispatchEvent(new InputEvent("input", { bubbles: true, inputType: "insertFromPaste", data: text }));
+      return;
+    }
+    if (target && target.focus) { target.focus(); }
+    if (at.range) {
+      var sel = window.getSelection();
+      sel.removeAllRanges();
+      sel.addRange(at.range);
+    }
+    if (data.html) {
+      document.execCommand("insertHTML", false, data.html);
+    } else if (data.text) {
+      document.execCommand("insertText", false, data.text);
+    }
+  }
+
+  function onPaste(event) {
+    if (!window.` + pasteBindingName + ` || !event.clipboardData) { return; }
+    event.preventDefault();
+    event.stopImmediatePropagation();
+    var target = event.target;
+    var at = place(target);
+    var clip = event.clipboardData;
+    var text = clip.getData("text/plain");
+    var html = clip.getData("text/html");
+    var files = Array.prototype.slice.call(clip.files || []);
+    Promise.all(files.map(readFile)).then(function (read) {
+      return window.` + pasteBindingName + `({
+        text: text,
+        html: html,
+        files: read.filter(Boolean),
+        target: target && target.tagName ? target.tagName.toLowerCase() + (target.id ? "#" + target.id : "") : ""
+      });
+    }).then(function (result) {
+      insert(target, at, result || {});
+    }, function () {
+      insert(target, at, { text: text });
+    });
+  }
+
+  var active = false;
+  glaze._paste = {
+    on: function () {
+      if (!active) { active = true; window.addEventListener("paste", onPaste, true); }
+    },
+    off: function () {
+      if (active) { active = false; window.removeEventListener("paste", onPaste, true); }
+    }
+  };
+  glaze._paste.on();
+})();`
+
+// pasteOffJS removes the paste listener from the current document.
+const pasteOffJS = "window.glaze && window.glaze._paste && window.glaze._paste.off();"
+
+// pasteOnJS puts the paste listener back in the current document.
+const pasteOnJS = "window.glaze && window.glaze._paste && window.glaze._paste.on();"
+
+// PasteData is the content of a paste, as seen by and returned from an
+// OnPaste handler.
+type PasteData struct {
+	// Text is the plain-text flavour of the clipboard.
+	Text string `json:"text"`
+
+	// HTML is the rich-text flavour of the clipboard, if any.
+	HTML string `json:"html,omitempty"`
+
+	// Files holds pasted files such as screenshots.
+	Files []PasteFile `json:"files,omitempty"`
+
+	// Target identifies the element receiving the paste as "tag#id". It is
+	// informational and ignored in the handler's result.
+	Target string `json:"target,omitempty"`
+}
+
+// PasteFile is a file pasted from the clipboard.
+type PasteFile struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Data []byte `json:"data"`
+}
+
+// PasteFunc inspects a paste and returns what should be inserted instead.
+// The result's HTML is inserted when set, otherwise its Text; returning an
+// empty PasteData swallows the paste. Returning an error inserts the
+// original plain text.
+type PasteFunc func(PasteData) (PasteData, error)
+
+// OnPaste routes every paste in the window through fn before it reaches the
+// page, so Go can transform it — for example store a pasted image as an
+// attachment and insert a link to it, or scrub formatting by returning only
+// Text. Calling OnPaste again replaces the handler; nil removes the
+// listener and restores normal pasting.
+//
+// Interception happens in the page, ahead of page scripts, since the native
+// engines expose no paste hook. Text fields get the result through
+// setRangeText, at the selection they had when the paste happened, and fire
+// an input event. Documents loaded after OnPaste(w, nil) drop the listener
+// as soon as they reach the bridge; a paste before that is inserted
+// unchanged. It must be called from the UI thread, like Bind.
+func OnPaste(w WebView, fn PasteFunc) error {
+	if w == nil {
+		return errors.New("webview: OnPaste requires a non-nil WebView")
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.paste = fn
+	installed := b.scripts["paste"]
+	b.mu.Unlock()
+	if fn == nil {
+		if installed {
+			w.Eval(pasteOffJS)
+		}
+		return nil
+	}
+
+	if err := b.bindHidden(pasteBindingName, b.handlePaste); err != nil {
+		return err
+	}
+	if err := b.onDocument("paste", b.pasteDocument); err != nil {
+		return err
+	}
+	b.injectScript("paste", pasteJS)
+	if installed {
+		w.Eval(pasteOnJS)
+	}
+	return nil
+}
+
+// pasteDocument removes the listener the init script installs in a new
+// document when OnPaste was cleared.
+func (b *bridge) pasteDocument(doc string) {
+	b.mu.Lock()
+	off := b.paste == nil
+	b.mu.Unlock()
+	if doc != "" && off {
+		b.w.Dispatch(func() { b.w.Eval(pasteOffJS) })
+	}
+}
+
+// handlePaste is bound as pasteBindingName.
+func (b *bridge) handlePaste(data PasteData) (PasteData, error) {
+	b.mu.Lock()
+	fn := b.paste
+	b.mu.Unlock()
+	if fn == nil {
+		return PasteData{Text: data.Text, HTML: data.HTML}, nil
+	}
+	out, err := fn(data)
+	out.Target = ""
+	return out, err
+}
@@ -0,0 +1,96 @@
+package glaze
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestOnPasteTransformsContent(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	var seen PasteData
+	err := OnPaste(w, func(in PasteData) (PasteData, error) {
+		seen = in
+		return PasteData{Text: strings.ToUpper(in.Text)}, nil
+	})
+	if err != nil {
+		t.Fatalf("OnPaste() unexpected error: %v", err)
+	}
+	if !strings.Contains(strings.Join(w.inits, "\n"), pasteJS) {
+		t.Fatal("OnPaste() did not inject the paste runtime")
+	}
+
+	png := []byte("\x89PNG")
+	got, err := w.call(t, pasteBindingName, map[string]any{
+		"text":   "hello",
+		"html":   "<b>hello</b>",
+		"files":  []map[string]string{{"name": "shot.png", "type": "image/png", "data": base64.StdEncoding.EncodeToString(png)}},
+		"target": "textarea#note",
+	})
+	if err != nil {
+		t.Fatalf("paste binding unexpected error: %v", err)
+	}
+	if out := got.(PasteData); out.Text != "HELLO" || out.HTML != "" {
+		t.Fatalf("paste result = %+v, want upper-cased text only", out)
+	}
+	if seen.Target != "textarea#note" || len(seen.Files) != 1 || string(seen.Files[0].Data) != string(png) {
+		t.Fatalf("handler saw %+v, want target and decoded file", seen)
+	}
+}
+
+func TestOnPasteReplaceAndClear(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	_ = OnPaste(w, func(PasteData) (PasteData, error) { return PasteData{Text: "first"}, nil })
+	inits := len(w.inits)
+	_ = OnPaste(w, func(PasteData) (PasteData, error) { return PasteData{Text: "second"}, nil })
+	if len(w.inits) != inits {
+		t.Fatalf("init scripts = %d after replacing, want %d", len(w.inits), inits)
+	}
+	got, _ := w.call(t, pasteBindingName, map[string]any{"text": "x"})
+	if got.(PasteData).Text != "second" {
+		t.Fatalf("paste result = %+v, want replaced handler", got)
+	}
+
+	_ = OnPaste(w, nil)
+	if evals := w.evals; len(evals) == 0 || evals[len(evals)-1] != pasteOffJS {
+		t.Fatal("OnPaste(nil) did not remove the listener")
+	}
+	got, _ = w.call(t, pasteBindingName, map[string]any{"text": "x", "html": "<i>x</i>"})
+	if out := got.(PasteData); out.Text != "x" || out.HTML != "<i>x</i>" {
+		t.Fatalf("paste result = %+v, want original content", out)
+	}
+
+	// A new document runs the init script again; the listener goes.
+	evals := len(w.evals)
+	_, _ = w.call(t, documentName, "doc2")
+	if len(w.evals) != evals+1 || w.evals[evals] != pasteOffJS {
+		t.Fatalf("new document evals = %q, want the listener removed", w.evals[evals:])
+	}
+
+	_ = OnPaste(w, func(PasteData) (PasteData, error) { return PasteData{}, nil })
+	if w.evals[len(w.evals)-1] != pasteOnJS {
+		t.Fatal("OnPaste() did not restore the listener")
+	}
+}
+
+func TestOnPasteNilNeverInstalls(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	if err := OnPaste(w, nil); err != nil {
+		t.Fatalf("OnPaste(nil) unexpected error: %v", err)
+	}
+	if len(w.inits) != 0 || len(w.evals) != 0 {
+		t.Fatalf("OnPaste(nil) injected %d scripts and ran %d", len(w.inits), len(w.evals))
+	}
+}
+
+func TestOnPasteNilWebView(t *testing.T) {
+	if err := OnPaste(nil, nil); err == nil {
+		t.Fatal("OnPaste() expected error for nil WebView")
+	}
+}
@@ -0,0 +1,719 @@
+package glaze
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxPeerFrame bounds the size of one message between peers.
+const maxPeerFrame = 16 << 20
+
+// peerDialTimeout bounds connecting to a peer and its handshake.
+const peerDialTimeout = 10 * time.Second
+
+// PeerMessage is a message received from a peer.
+type PeerMessage struct {
+	// From is the fingerprint of the peer that sent the message.
+	From string `json:"from"`
+
+	// Topic names the kind of message, chosen by the app.
+	Topic string `json:"topic"`
+
+	// Data is the JSON payload of the message.
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// peerChunk is the largest piece of a stream sent in one frame.
+const peerChunk = 64 << 10
+
+// peerFrame is a message on the wire. Frames with a Stream ID belong to a
+// stream: the first has Open and the topic, the next carry Chunk, and the
+// last has End.
+type peerFrame struct {
+	Topic  string          `json:"topic,omitempty"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	Stream uint32          `json:"stream,omitempty"`
+	Open   bool            `json:"open,omitempty"`
+	Chunk  []byte          `json:"chunk,omitempty"`
+	End    bool            `json:"end,omitempty"`
+}
+
+// PeerLinkOptions configures ListenPeers.
+type PeerLinkOptions struct {
+	// Addr is the TCP address to listen on. Defaults to any free port on
+	// the first local network address, the one StartDiscovery announces,
+	// rather than on every interface; advertise the port with
+	// StartDiscovery.
+	Addr string
+
+	// Key identifies this instance to its peers. LoadPeerKey keeps one
+	// across runs; nil generates one for this run only.
+	Key ed25519.PrivateKey
+
+	// Peers lists the fingerprints of paired devices, which may connect
+	// and be dialled.
+	Peers []string
+
+	// Accept decides whether to talk to a peer not in Peers, for both
+	// incoming and outgoing connections, e.g. by asking the user to
+	// confirm a new device. Peers that are neither listed nor accepted are
+	// refused; a fingerprint given to Dial is trusted for that connection.
+	Accept func(fingerprint string) bool
+
+	// OnMessage receives the messages from peers, from a background
+	// goroutine.
+	OnMessage func(PeerMessage)
+
+	// OnStream receives the streams peers open, each in its own goroutine.
+	// The connection waits while a stream is not read, so read it to the
+	// end or close it. Nil hands streams to the page when Prefix is set,
+	// and refuses them otherwise.
+	OnStream func(*PeerStream)
+
+	// Prefix, when set, binds {Prefix}_dial, {Prefix}_send and
+	// {Prefix}_connected for the page and emits "{Prefix}:message" events
+	// with each PeerMessage, and "{Prefix}:open" and "{Prefix}:close" with
+	// the fingerprint of a peer that connects or disconnects. It also binds
+	// {Prefix}_open, {Prefix}_write and {Prefix}_close for sending streams,
+	// and emits "{Prefix}:stream", "{Prefix}:data" and "{Prefix}:end" for
+	// the streams peers open; see PeerStream.
+	Prefix string
+}
+
+// PeerLink is an encrypted channel between running instances of an app, for
+// transferring data directly from device to device. Connections use TLS 1.3
+// with self-signed certificates; each instance is known by the fingerprint
+// of its key, which peers can publish in their discovery TXT record and
+// check before they talk. The methods of a PeerLink may be called from any
+// goroutine.
+type PeerLink struct {
+	w           WebView
+	opts        PeerLinkOptions
+	ln          net.Listener
+	cert        tls.Certificate
+	fingerprint string
+
+	mu      sync.Mutex
+	conns   map[string]*peerConn
+	streams map[uint32]*PeerStream // opened by the page
+	seq     uint32
+
+	done   chan struct{}
+	closed sync.Once
+	wg     sync.WaitGroup
+}
+
+// peerConn is a connection to a peer.
+type peerConn struct {
+	fingerprint string
+	conn        *tls.Conn
+
+	wmu sync.Mutex
+	seq uint32 // last stream ID sent, under wmu
+
+	incoming map[uint32]*PeerStream // used by the read goroutine only
+}
+
+// PeerStream is a one-way stream of bytes between peers, for transfers too
+// large for one message, such as files. The peer that opens it with
+// OpenStream writes and closes it; the other reads it, through OnStream.
+//
+// Pages use it through the Prefix bindings: {Prefix}_open(fingerprint,
+// topic) returns a stream ID, {Prefix}_write(id, data) sends base64 data
+// and {Prefix}_close(id) ends the stream. Streams from peers arrive as a
+// "{Prefix}:stream" event with from, topic and stream, then
+// "{Prefix}:data" events with stream and base64 data, and "{Prefix}:end"
+// with stream and complete, false when the connection dropped first.
+type PeerStream struct {
+	// Peer is the fingerprint of the peer at the other end.
+	Peer string
+
+	// Topic names the stream, chosen by the app.
+	Topic string
+
+	id uint32
+	pc *peerConn
+
+	// Receiving end.
+	r *io.PipeReader
+	w *io.PipeWriter
+}
+
+// ListenPeers starts accepting connections from peers:
+//
+//	key, err := glaze.LoadPeerKey(filepath.Join(dataDir, "peer.key"))
+//	link, err := glaze.ListenPeers(w, glaze.PeerLinkOptions{Key: key, Peers: paired, Prefix: "p2p"})
+//	defer link.Close()
+//	d, err := glaze.StartDiscovery(w, glaze.DiscoveryOptions{
+//		Service: "_notes._tcp",
+//		Port:    link.Port(),
+//		Text:    map[string]string{"fp": link.Fingerprint()},
+//	})
+//
+//	// JavaScript
+//	const fp = await p2p_dial(peer.addrs[0] + ":" + peer.port, peer.text.fp);
+//	await p2p_send(fp, "note", note);
+//	glaze.on("p2p:message", (m) => m.topic === "note" && addNote(m.data));
+//
+// Only paired peers, listed in Peers or approved by Accept, may connect; a
+// link with neither refuses every incoming connection and dials only the
+// fingerprints it is given.
+//
+// w may be nil when opts.Prefix is empty. Like Bind, ListenPeers must be
+// called from the UI thread when opts.Prefix is set.
+func ListenPeers(w WebView, opts PeerLinkOptions) (*PeerLink, error) {
+	if opts.Prefix != "" && w == nil {
+		return nil, errors.New("webview: ListenPeers requires a non-nil WebView with a Prefix")
+	}
+	if opts.Addr == "" {
+		host := "127.0.0.1"
+		if ips := localIPv4(); len(ips) > 0 {
+			host = ips[0].String()
+		}
+		opts.Addr = net.JoinHostPort(host, "0")
+	}
+	if opts.Key == nil {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("webview: listen peers: %w", err)
+		}
+		opts.Key = key
+	}
+	cert, err := peerCertificate(opts.Key)
+	if err != nil {
+		return nil, fmt.Errorf("webview: listen peers: %w", err)
+	}
+	l := &PeerLink{
+		w:           w,
+		opts:        opts,
+		cert:        cert,
+		fingerprint: PeerFingerprint(opts.Key.Public().(ed25519.PublicKey)),
+		conns:       make(map[string]*peerConn),
+		streams:     make(map[uint32]*PeerStream),
+		done:        make(chan struct{}),
+	}
+	ln, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("webview: listen peers: %w", err)
+	}
+	l.ln = ln
+	if opts.Prefix != "" {
+		if _, err := BindAll(w, map[string]any{
+			opts.Prefix + "_dial":      l.Dial,
+			opts.Prefix + "_send":      l.Send,
+			opts.Prefix + "_connected": l.Connected,
+			opts.Prefix + "_open":      l.openPageStream,
+			opts.Prefix + "_write":     l.writePageStream,
+			opts.Prefix + "_close":     l.closePageStream,
+		}); err != nil {
+			ln.Close()
+			return nil, err
+		}
+	}
+	l.wg.Add(1)
+	go l.accept()
+	return l, nil
+}
+
+// LoadPeerKey returns the key kept in the file at path, creating it on
+// first use, so an instance keeps its fingerprint across runs.
+func LoadPeerKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if len(data) != ed25519.SeedSize {
+			return nil, fmt.Errorf("webview: peer key %s is damaged", path)
+		}
+		return ed25519.NewKeyFromSeed(data), nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("webview: load peer key: %w", err)
+	}
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("webview: create peer key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("webview: store peer key: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, key.Seed(), 0o600); err != nil {
+		return nil, fmt.Errorf("webview: store peer key: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return nil, fmt.Errorf("webview: store peer key: %w", err)
+	}
+	return key, nil
+}
+
+// PeerFingerprint returns the fingerprint peers know the instance with the
+// public key pub by: the hex SHA-256 of the key.
+func PeerFingerprint(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:])
+}
+
+// peerCertificate returns a self-signed certificate for key. Peers check the
+// key, not the certificate, so it never expires in practice.
+func peerCertificate(key ed25519.PrivateKey) (tls.Certificate, error) {
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "glaze peer"},
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// Port returns the TCP port the link listens on.
+func (l *PeerLink) Port() int {
+	return l.ln.Addr().(*net.TCPAddr).Port
+}
+
+// Fingerprint returns the fingerprint peers know this instance by.
+func (l *PeerLink) Fingerprint() string {
+	return l.fingerprint
+}
+
+// tlsConfig returns the TLS configuration of a connection. The peer's
+// certificate is checked against want, when it is set, and Accept; its
+// fingerprint is stored in *got.
+func (l *PeerLink) tlsConfig(want string, got *string) *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{l.cert},
+		MinVersion:   tls.VersionTLS13,
+		ClientAuth:   tls.RequireAnyClientCert,
+		// Peers are self-signed; VerifyPeerCertificate pins their keys.
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(raw [][]byte, _ [][]*x509.Certificate) error {
+			if len(raw) == 0 {
+				return errors.New("peer sent no certificate")
+			}
+			cert, err := x509.ParseCertificate(raw[0])
+			if err != nil {
+				return err
+			}
+			pub, ok := cert.PublicKey.(ed25519.PublicKey)
+			if !ok {
+				return errors.New("peer key is not ed25519")
+			}
+			fp := PeerFingerprint(pub)
+			switch {
+			case fp == l.fingerprint:
+				return errors.New("connected to self")
+			case want != "" && fp != want:
+				return fmt.Errorf("peer fingerprint %s, want %s", fp, want)
+			case want == "" && !l.trusted(fp):
+				return fmt.Errorf("peer %s not accepted", fp)
+			}
+			*got = fp
+			return nil
+		},
+	}
+}
+
+// trusted reports whether the peer fp is paired: listed in Peers or
+// approved by Accept.
+func (l *PeerLink) trusted(fp string) bool {
+	if slices.Contains(l.opts.Peers, fp) {
+		return true
+	}
+	return l.opts.Accept != nil && l.opts.Accept(fp)
+}
+
+func (l *PeerLink) accept() {
+	defer l.wg.Done()
+	for {
+		conn, err := l.ln.Accept()
+		if err != nil {
+			select {
+			case <-l.done:
+				return
+			default:
+			}
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		l.wg.Add(1)
+		go func() {
+			defer l.wg.Done()
+			var fp string
+			tc := tls.Server(conn, l.tlsConfig("", &fp))
+			_ = tc.SetDeadline(time.Now().Add(peerDialTimeout))
+			if err := tc.Handshake(); err != nil {
+				tc.Close()
+				return
+			}
+			_ = tc.SetDeadline(time.Time{})
+			l.register(fp, tc)
+		}()
+	}
+}
+
+// Dial connects to the peer listening at addr, "host:port", and returns its
+// fingerprint. A non-empty fingerprint must match the peer's, which guards
+// against impostors on the network, and trusts that peer; an empty one
+// connects only to a paired peer. A connection already open to the same
+// peer is replaced.
+func (l *PeerLink) Dial(addr, fingerprint string) (string, error) {
+	select {
+	case <-l.done:
+		return "", errors.New("webview: peer link closed")
+	default:
+	}
+	var fp string
+	d := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: peerDialTimeout},
+		Config:    l.tlsConfig(fingerprint, &fp),
+	}
+	conn, err := d.Dial("tcp", addr)
+	if err != nil {
+		return "", fmt.Errorf("webview: dial peer %s: %w", addr, err)
+	}
+	if l.register(fp, conn.(*tls.Conn)) == nil {
+		return "", errors.New("webview: peer link closed")
+	}
+	return fp, nil
+}
+
+// register records conn as the connection to the peer fp, replacing any
+// other, and returns it; nil when the link is closed.
+func (l *PeerLink) register(fp string, conn *tls.Conn) *peerConn {
+	pc := &peerConn{fingerprint: fp, conn: conn, incoming: make(map[uint32]*PeerStream)}
+	l.mu.Lock()
+	select {
+	case <-l.done:
+		l.mu.Unlock()
+		conn.Close()
+		return nil
+	default:
+	}
+	old := l.conns[fp]
+	l.conns[fp] = pc
+	l.mu.Unlock()
+	if old != nil {
+		old.conn.Close()
+	} else {
+		l.emit(":open", fp)
+	}
+	l.wg.Add(1)
+	go l.read(pc)
+	return pc
+}
+
+// read delivers the messages of pc until it closes.
+func (l *PeerLink) read(pc *peerConn) {
+	defer l.wg.Done()
+	defer func() {
+		pc.conn.Close()
+		for _, s := range pc.incoming {
+			s.w.CloseWithError(io.ErrUnexpectedEOF)
+		}
+		l.mu.Lock()
+		current := l.conns[pc.fingerprint] == pc
+		if current {
+			delete(l.conns, pc.fingerprint)
+		}
+		for id, s := range l.streams {
+			if s.pc == pc {
+				delete(l.streams, id)
+			}
+		}
+		l.mu.Unlock()
+		if current {
+			l.emit(":close", pc.fingerprint)
+		}
+	}()
+	for {
+		frame, err := readPeerFrame(pc.conn)
+		if err != nil {
+			return
+		}
+		if frame.Stream != 0 {
+			l.streamFrame(pc, frame)
+			continue
+		}
+		m := PeerMessage{From: pc.fingerprint, Topic: frame.Topic, Data: frame.Data}
+		l.emit(":message", m)
+		if l.opts.OnMessage != nil {
+			l.opts.OnMessage(m)
+		}
+	}
+}
+
+// streamFrame delivers a frame of a stream opened by the peer of pc. It
+// waits while the stream's reader is behind, which holds back the
+// connection.
+func (l *PeerLink) streamFrame(pc *peerConn, f peerFrame) {
+	s := pc.incoming[f.Stream]
+	switch {
+	case f.Open:
+		if s != nil || (l.opts.OnStream == nil && l.opts.Prefix == "") {
+			return
+		}
+		s = &PeerStream{Peer: pc.fingerprint, Topic: f.Topic, id: f.Stream}
+		s.r, s.w = io.Pipe()
+		pc.incoming[f.Stream] = s
+		l.wg.Add(1)
+		go func() {
+			defer l.wg.Done()
+			if l.opts.OnStream != nil {
+				l.opts.OnStream(s)
+			} else {
+				l.streamToPage(s)
+			}
+		}()
+	case s == nil:
+		// A stream this side refused or closed.
+	case f.End:
+		s.w.Close()
+		delete(pc.incoming, f.Stream)
+	default:
+		if _, err := s.w.Write(f.Chunk); err != nil {
+			// The reader closed the stream; drop the rest.
+			delete(pc.incoming, f.Stream)
+		}
+	}
+}
+
+// streamToPage emits s to the page as "{Prefix}:stream", "{Prefix}:data"
+// and "{Prefix}:end" events.
+func (l *PeerLink) streamToPage(s *PeerStream) {
+	id := s.Peer[:8] + "-" + strconv.FormatUint(uint64(s.id), 10)
+	l.emit(":stream", map[string]string{"from": s.Peer, "topic": s.Topic, "stream": id})
+	buf := make([]byte, peerChunk)
+	for {
+		n, err := s.Read(buf)
+		if n > 0 {
+			l.emit(":data", map[string]any{"stream": id, "data": buf[:n]})
+		}
+		if err != nil {
+			l.emit(":end", map[string]any{"stream": id, "complete": err == io.EOF})
+			return
+		}
+	}
+}
+
+// OpenStream opens a stream under topic to the connected peer with the
+// given fingerprint. Write sends the data and Close ends the stream.
+func (l *PeerLink) OpenStream(fingerprint, topic string) (*PeerStream, error) {
+	l.mu.Lock()
+	pc := l.conns[fingerprint]
+	l.mu.Unlock()
+	if pc == nil {
+		return nil, fmt.Errorf("webview: peer %s is not connected", fingerprint)
+	}
+	pc.wmu.Lock()
+	defer pc.wmu.Unlock()
+	pc.seq++
+	s := &PeerStream{Peer: fingerprint, Topic: topic, id: pc.seq, pc: pc}
+	if err := writePeerFrame(pc.conn, peerFrame{Topic: topic, Stream: s.id, Open: true}); err != nil {
+		return nil, fmt.Errorf("webview: open peer stream: %w", err)
+	}
+	return s, nil
+}
+
+// Read reads from a stream opened by the peer. It returns io.EOF once the
+// peer has closed the stream, and io.ErrUnexpectedEOF if the connection
+// dropped first.
+func (s *PeerStream) Read(p []byte) (int, error) {
+	if s.r == nil {
+		return 0, errors.New("webview: peer stream is write-only")
+	}
+	return s.r.Read(p)
+}
+
+// Write sends p on a stream opened with OpenStream.
+func (s *PeerStream) Write(p []byte) (int, error) {
+	if s.pc == nil {
+		return 0, errors.New("webview: peer stream is read-only")
+	}
+	s.pc.wmu.Lock()
+	defer s.pc.wmu.Unlock()
+	n := 0
+	for len(p) > 0 {
+		chunk := p[:min(len(p), peerChunk)]
+		if err := writePeerFrame(s.pc.conn, peerFrame{Stream: s.id, Chunk: chunk}); err != nil {
+			return n, fmt.Errorf("webview: write peer stream: %w", err)
+		}
+		n += len(chunk)
+		p = p[len(chunk):]
+	}
+	return n, nil
+}
+
+// Close ends a stream opened with OpenStream. On a stream opened by the
+// peer, it stops reading and discards the rest.
+func (s *PeerStream) Close() error {
+	if s.pc == nil {
+		return s.r.Close()
+	}
+	s.pc.wmu.Lock()
+	defer s.pc.wmu.Unlock()
+	return writePeerFrame(s.pc.conn, peerFrame{Stream: s.id, End: true})
+}
+
+// openPageStream is bound as {Prefix}_open.
+func (l *PeerLink) openPageStream(fingerprint, topic string) (uint32, error) {
+	s, err := l.OpenStream(fingerprint, topic)
+	if err != nil {
+		return 0, err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.seq++
+	l.streams[l.seq] = s
+	return l.seq, nil
+}
+
+// writePageStream is bound as {Prefix}_write.
+func (l *PeerLink) writePageStream(id uint32, data []byte) error {
+	l.mu.Lock()
+	s := l.streams[id]
+	l.mu.Unlock()
+	if s == nil {
+		return fmt.Errorf("webview: peer stream %d is not open", id)
+	}
+	_, err := s.Write(data)
+	return err
+}
+
+// closePageStream is bound as {Prefix}_close.
+func (l *PeerLink) closePageStream(id uint32) error {
+	l.mu.Lock()
+	s := l.streams[id]
+	delete(l.streams, id)
+	l.mu.Unlock()
+	if s == nil {
+		return fmt.Errorf("webview: peer stream %d is not open", id)
+	}
+	return s.Close()
+}
+
+// Send sends data, encoded as JSON, under topic to the connected peer with
+// the given fingerprint.
+func (l *PeerLink) Send(fingerprint, topic string, data any) error {
+	l.mu.Lock()
+	pc := l.conns[fingerprint]
+	l.mu.Unlock()
+	if pc == nil {
+		return fmt.Errorf("webview: peer %s is not connected", fingerprint)
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("webview: send to peer: %w", err)
+	}
+	pc.wmu.Lock()
+	defer pc.wmu.Unlock()
+	if err := writePeerFrame(pc.conn, peerFrame{Topic: topic, Data: raw}); err != nil {
+		return fmt.Errorf("webview: send to peer: %w", err)
+	}
+	return nil
+}
+
+// Connected returns the fingerprints of the connected peers, sorted.
+func (l *PeerLink) Connected() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fps := make([]string, 0, len(l.conns))
+	for fp := range l.conns {
+		fps = append(fps, fp)
+	}
+	slices.Sort(fps)
+	return fps
+}
+
+// Disconnect closes the connection to the peer with the given fingerprint.
+func (l *PeerLink) Disconnect(fingerprint string) {
+	l.mu.Lock()
+	pc := l.conns[fingerprint]
+	l.mu.Unlock()
+	if pc != nil {
+		pc.conn.Close()
+	}
+}
+
+// Close stops accepting connections and closes those open.
+func (l *PeerLink) Close() error {
+	var err error
+	l.closed.Do(func() {
+		l.mu.Lock()
+		close(l.done)
+		conns := make([]*peerConn, 0, len(l.conns))
+		for _, pc := range l.conns {
+			conns = append(conns, pc)
+		}
+		l.mu.Unlock()
+		err = l.ln.Close()
+		for _, pc := range conns {
+			pc.conn.Close()
+		}
+		l.wg.Wait()
+	})
+	return err
+}
+
+func (l *PeerLink) emit(suffix string, payload any) {
+	if l.opts.Prefix != "" {
+		_ = Emit(l.w, l.opts.Prefix+suffix, payload)
+	}
+}
+
+// writePeerFrame writes f as its length, four bytes big-endian, followed by
+// its JSON encoding.
+func writePeerFrame(w io.Writer, f peerFrame) error {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxPeerFrame {
+		return fmt.Errorf("message of %d bytes exceeds %d", len(data), maxPeerFrame)
+	}
+	buf := binary.BigEndian.AppendUint32(make([]byte, 0, 4+len(data)), uint32(len(data)))
+	_, err = w.Write(append(buf, data...))
+	return err
+}
+
+// readPeerFrame reads a frame written by writePeerFrame.
+func readPeerFrame(r io.Reader) (peerFrame, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return peerFrame{}, err
+	}
+	n := binary.BigEndian.Uint32(size[:])
+	if n > maxPeerFrame {
+		return peerFrame{}, fmt.Errorf("message of %d bytes exceeds %d", n, maxPeerFrame)
+	}
+	data := make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return peerFrame{}, err
+	}
+	var f peerFrame
+	err := json.Unmarshal(data, &f)
+	return f, err
+}
@@ -0,0 +1,212 @@
+package glaze
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func listenTestPeers(t *testing.T, w WebView, opts PeerLinkOptions) *PeerLink {
+	t.Helper()
+	opts.Addr = "127.0.0.1:0"
+	l, err := ListenPeers(w, opts)
+	if err != nil {
+		t.Fatalf("ListenPeers: %v", err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+	return l
+}
+
+func TestPeerLinkMessages(t *testing.T) {
+	got := make(chan PeerMessage, 1)
+	a := listenTestPeers(t, nil, PeerLinkOptions{})
+	b := listenTestPeers(t, nil, PeerLinkOptions{Peers: []string{a.Fingerprint()}, OnMessage: func(m PeerMessage) { got <- m }})
+
+	fp, err := a.Dial(b.ln.Addr().String(), b.Fingerprint())
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	if fp != b.Fingerprint() {
+		t.Fatalf("Dial = %s, want %s", fp, b.Fingerprint())
+	}
+	if err := a.Send(fp, "note", map[string]string{"title": "hi"}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	select {
+	case m := <-got:
+		if m.From != a.Fingerprint() || m.Topic != "note" || string(m.Data) != `{"title":"hi"}` {
+			t.Fatalf("message = %+v", m)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("message not received")
+	}
+	if c := a.Connected(); !slices.Equal(c, []string{b.Fingerprint()}) {
+		t.Fatalf("Connected = %q", c)
+	}
+	waitFor(t, func() bool { return slices.Equal(b.Connected(), []string{a.Fingerprint()}) })
+
+	a.Disconnect(fp)
+	waitFor(t, func() bool { return len(a.Connected()) == 0 && len(b.Connected()) == 0 })
+	if err := a.Send(fp, "note", nil); err == nil {
+		t.Fatal("Send to a disconnected peer succeeded")
+	}
+}
+
+func TestPeerLinkRejectsPeers(t *testing.T) {
+	a := listenTestPeers(t, nil, PeerLinkOptions{})
+	b := listenTestPeers(t, nil, PeerLinkOptions{})
+	if _, err := a.Dial(b.ln.Addr().String(), strings.Repeat("0", 64)); err == nil {
+		t.Fatal("Dial accepted a peer with another fingerprint")
+	}
+	if _, err := a.Dial(a.ln.Addr().String(), ""); err == nil {
+		t.Fatal("Dial connected to itself")
+	}
+
+	// Dialling without a fingerprint needs a paired peer.
+	if _, err := a.Dial(b.ln.Addr().String(), ""); err == nil {
+		t.Fatal("Dial connected to an unpaired peer")
+	}
+
+	// Peers are refused by default, and by Accept.
+	c := listenTestPeers(t, nil, PeerLinkOptions{Accept: func(fp string) bool { return fp != a.Fingerprint() }})
+	for _, l := range []*PeerLink{b, c} {
+		if _, err := a.Dial(l.ln.Addr().String(), l.Fingerprint()); err == nil {
+			// TLS 1.3 clients finish the handshake before the server checks
+			// them; the server then drops the connection.
+			waitFor(t, func() bool { return len(a.Connected()) == 0 })
+		}
+		if len(l.Connected()) != 0 {
+			t.Fatalf("refused peer connected: %q", l.Connected())
+		}
+	}
+}
+
+func TestPeerLinkStreams(t *testing.T) {
+	got := make(chan []byte, 1)
+	a := listenTestPeers(t, nil, PeerLinkOptions{})
+	b := listenTestPeers(t, nil, PeerLinkOptions{
+		Peers: []string{a.Fingerprint()},
+		OnStream: func(s *PeerStream) {
+			if s.Peer != a.Fingerprint() || s.Topic != "file" {
+				t.Errorf("stream from %s on %q", s.Peer, s.Topic)
+			}
+			data, err := io.ReadAll(s)
+			if err != nil {
+				t.Errorf("read stream: %v", err)
+			}
+			got <- data
+		},
+	})
+	fp, err := a.Dial(b.ln.Addr().String(), b.Fingerprint())
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	s, err := a.OpenStream(fp, "file")
+	if err != nil {
+		t.Fatalf("OpenStream: %v", err)
+	}
+	want := bytes.Repeat([]byte("0123456789"), peerChunk/4)
+	if _, err := s.Write(want); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	select {
+	case data := <-got:
+		if !bytes.Equal(data, want) {
+			t.Fatalf("received %d bytes, want %d", len(data), len(want))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream not received")
+	}
+}
+
+func TestPeerLinkPrefix(t *testing.T) {
+	wa, wb := &fakeWebView{}, &fakeWebView{}
+	a := listenTestPeers(t, wa, PeerLinkOptions{Prefix: "p2p"})
+	b := listenTestPeers(t, wb, PeerLinkOptions{Prefix: "p2p", Peers: []string{a.Fingerprint()}})
+
+	fp, err := wa.call(t, "p2p_dial", b.ln.Addr().String(), b.Fingerprint())
+	if err != nil || fp != b.Fingerprint() {
+		t.Fatalf("p2p_dial = %v, %v", fp, err)
+	}
+	if _, err := wa.call(t, "p2p_send", fp, "ping", 1); err != nil {
+		t.Fatalf("p2p_send: %v", err)
+	}
+	waitFor(t, func() bool {
+		wb.mu.Lock()
+		defer wb.mu.Unlock()
+		return slices.ContainsFunc(wb.evals, func(js string) bool {
+			return strings.Contains(js, `"p2p:message"`) && strings.Contains(js, `"topic":"ping"`)
+		})
+	})
+	wb.mu.Lock()
+	opened := slices.ContainsFunc(wb.evals, func(js string) bool {
+		return strings.Contains(js, `"p2p:open", "`+a.Fingerprint()+`"`)
+	})
+	wb.mu.Unlock()
+	if !opened {
+		t.Fatal("p2p:open not emitted")
+	}
+	if c, err := wa.call(t, "p2p_connected"); err != nil || !slices.Equal(c.([]string), []string{b.Fingerprint()}) {
+		t.Fatalf("p2p_connected = %v, %v", c, err)
+	}
+
+	id, err := wa.call(t, "p2p_open", fp, "file")
+	if err != nil {
+		t.Fatalf("p2p_open: %v", err)
+	}
+	if _, err := wa.call(t, "p2p_write", id, []byte("hello")); err != nil {
+		t.Fatalf("p2p_write: %v", err)
+	}
+	if _, err := wa.call(t, "p2p_close", id); err != nil {
+		t.Fatalf("p2p_close: %v", err)
+	}
+	for _, want := range []string{`"p2p:stream"`, `"p2p:data"`, `"p2p:end"`} {
+		waitFor(t, func() bool {
+			wb.mu.Lock()
+			defer wb.mu.Unlock()
+			return slices.ContainsFunc(wb.evals, func(js string) bool { return strings.Contains(js, want) })
+		})
+	}
+	wb.mu.Lock()
+	data := slices.ContainsFunc(wb.evals, func(js string) bool { return strings.Contains(js, `"data":"aGVsbG8="`) })
+	wb.mu.Unlock()
+	if !data {
+		t.Fatal("p2p:data does not carry the stream's bytes")
+	}
+}
+
+func TestPeerFrames(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writePeerFrame(&buf, peerFrame{Topic: "t", Data: []byte(`[1,2]`)}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := readPeerFrame(&buf)
+	if err != nil || f.Topic != "t" || string(f.Data) != "[1,2]" {
+		t.Fatalf("readPeerFrame = %+v, %v", f, err)
+	}
+	if _, err := readPeerFrame(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff})); err == nil {
+		t.Fatal("oversized frame accepted")
+	}
+}
+
+func TestLoadPeerKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys", "peer.key")
+	k1, err := LoadPeerKey(path)
+	if err != nil {
+		t.Fatalf("LoadPeerKey: %v", err)
+	}
+	k2, err := LoadPeerKey(path)
+	if err != nil {
+		t.Fatalf("LoadPeerKey again: %v", err)
+	}
+	if !k1.Equal(k2) {
+		t.Fatal("key not kept across loads")
+	}
+}
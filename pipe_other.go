@@ -0,0 +1,15 @@
+//go:build !windows
+
+package glaze
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+var errNoPipes = errors.New("webview: named pipes are only supported on windows")
+
+func listenPipe(string) (net.Listener, error) { return nil, errNoPipes }
+
+func dialPipe(context.Context, string) (net.Conn, error) { return nil, errNoPipes }
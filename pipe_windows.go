@@ -0,0 +1,220 @@
+package glaze
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// pipeBufferSize is the in and out buffer size of each pipe instance.
+const pipeBufferSize = 64 << 10
+
+// pipeAddr is the net.Addr of a named pipe.
+type pipeAddr string
+
+func (pipeAddr) Network() string  { return "pipe" }
+func (a pipeAddr) String() string { return string(a) }
+
+// pipeConn is one connection over a named pipe. The handle is opened for
+// overlapped I/O, so the *os.File goes through the runtime poller and
+// supports concurrent reads and writes and deadlines.
+type pipeConn struct {
+	*os.File
+	addr pipeAddr
+}
+
+func (c *pipeConn) LocalAddr() net.Addr  { return c.addr }
+func (c *pipeConn) RemoteAddr() net.Addr { return c.addr }
+
+// pipeListener accepts connections on a named pipe. One instance of the
+// pipe always waits for the next client, so clients find the name while a
+// connection is being handed over.
+type pipeListener struct {
+	name string
+	sa   *windows.SecurityAttributes
+
+	mu        sync.Mutex
+	pending   windows.Handle // instance waiting for a client
+	accepting bool
+	closed    bool
+}
+
+// defaultPipeName returns a pipe name unique to this process.
+func defaultPipeName() (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(`\\.\pipe\glaze-%d-%s`, os.Getpid(), hex.EncodeToString(b[:])), nil
+}
+
+// listenPipe creates the named pipe name, or a unique one when name is
+// empty. Only the current user may connect, and remote clients are
+// rejected; the first instance claims the name, so listenPipe fails rather
+// than share a pipe another process created.
+func listenPipe(name string) (net.Listener, error) {
+	if name == "" {
+		var err error
+		if name, err = defaultPipeName(); err != nil {
+			return nil, fmt.Errorf("webview: pipe name: %w", err)
+		}
+	}
+	if !strings.HasPrefix(name, `\\.\pipe\`) {
+		return nil, fmt.Errorf(`webview: pipe name %q must start with \\.\pipe\`, name)
+	}
+	user, err := windows.GetCurrentProcessToken().GetTokenUser()
+	if err != nil {
+		return nil, fmt.Errorf("webview: pipe owner: %w", err)
+	}
+	sd, err := windows.SecurityDescriptorFromString("D:P(A;;GA;;;" + user.User.Sid.String() + ")")
+	if err != nil {
+		return nil, fmt.Errorf("webview: pipe security: %w", err)
+	}
+	l := &pipeListener{
+		name: name,
+		sa:   &windows.SecurityAttributes{Length: uint32(unsafe.Sizeof(windows.SecurityAttributes{})), SecurityDescriptor: sd},
+	}
+	if l.pending, err = l.instance(true); err != nil {
+		return nil, fmt.Errorf("webview: listen pipe %s: %w", name, err)
+	}
+	return l, nil
+}
+
+// instance creates an instance of the pipe for the next client.
+func (l *pipeListener) instance(first bool) (windows.Handle, error) {
+	name, err := windows.UTF16PtrFromString(l.name)
+	if err != nil {
+		return windows.InvalidHandle, err
+	}
+	flags := uint32(windows.PIPE_ACCESS_DUPLEX | windows.FILE_FLAG_OVERLAPPED)
+	if first {
+		flags |= windows.FILE_FLAG_FIRST_PIPE_INSTANCE
+	}
+	mode := uint32(windows.PIPE_TYPE_BYTE | windows.PIPE_READMODE_BYTE | windows.PIPE_WAIT | windows.PIPE_REJECT_REMOTE_CLIENTS)
+	return windows.CreateNamedPipe(name, flags, mode, windows.PIPE_UNLIMITED_INSTANCES, pipeBufferSize, pipeBufferSize, 0, l.sa)
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	l.mu.Lock()
+	if l.closed || l.accepting {
+		l.mu.Unlock()
+		if l.closed {
+			return nil, net.ErrClosed
+		}
+		return nil, errors.New("webview: concurrent Accept on a pipe listener")
+	}
+	h := l.pending
+	l.accepting = true
+	l.mu.Unlock()
+
+	err := l.connect(h)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.accepting = false
+	if l.closed {
+		_ = windows.CloseHandle(h)
+		return nil, net.ErrClosed
+	}
+	next, nerr := l.instance(false)
+	if nerr != nil {
+		// Without a waiting instance the pipe is gone; report it from here
+		// on, after handing over the connection made.
+		l.closed = true
+		next = windows.InvalidHandle
+	}
+	l.pending = next
+	if err != nil {
+		_ = windows.CloseHandle(h)
+		return nil, &net.OpError{Op: "accept", Net: "pipe", Addr: pipeAddr(l.name), Err: err}
+	}
+	return &pipeConn{File: os.NewFile(uintptr(h), l.name), addr: pipeAddr(l.name)}, nil
+}
+
+// connect waits for a client to connect to the pipe instance h, checking
+// periodically whether the listener was closed.
+func (l *pipeListener) connect(h windows.Handle) error {
+	event, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return err
+	}
+	defer windows.CloseHandle(event)
+	ov := windows.Overlapped{HEvent: event}
+	switch err := windows.ConnectNamedPipe(h, &ov); err {
+	case nil, windows.ERROR_PIPE_CONNECTED:
+		return nil
+	case windows.ERROR_IO_PENDING:
+	default:
+		return err
+	}
+	var n uint32
+	for {
+		ev, err := windows.WaitForSingleObject(event, 100)
+		if err != nil {
+			return err
+		}
+		if ev == windows.WAIT_OBJECT_0 {
+			return windows.GetOverlappedResult(h, &ov, &n, false)
+		}
+		l.mu.Lock()
+		closed := l.closed
+		l.mu.Unlock()
+		if closed {
+			_ = windows.CancelIoEx(h, &ov)
+			_ = windows.GetOverlappedResult(h, &ov, &n, true)
+			return net.ErrClosed
+		}
+	}
+}
+
+func (l *pipeListener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	if l.pending == windows.InvalidHandle {
+		return nil
+	}
+	if l.accepting {
+		return nil // Accept notices and closes the handle
+	}
+	return windows.CloseHandle(l.pending)
+}
+
+func (l *pipeListener) Addr() net.Addr { return pipeAddr(l.name) }
+
+// dialPipe connects to the named pipe name, waiting while every instance
+// is busy.
+func dialPipe(ctx context.Context, name string) (net.Conn, error) {
+	path, err := windows.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		h, err := windows.CreateFile(path, windows.GENERIC_READ|windows.GENERIC_WRITE, 0, nil, windows.OPEN_EXISTING,
+			windows.FILE_FLAG_OVERLAPPED|windows.SECURITY_SQOS_PRESENT|windows.SECURITY_IDENTIFICATION, 0)
+		if err == nil {
+			return &pipeConn{File: os.NewFile(uintptr(h), name), addr: pipeAddr(name)}, nil
+		}
+		if err != windows.ERROR_PIPE_BUSY {
+			return nil, &net.OpError{Op: "dial", Net: "pipe", Addr: pipeAddr(name), Err: err}
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+}
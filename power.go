@@ -0,0 +1,120 @@
+package glaze
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PowerStatus describes the machine's power source and battery state.
+type PowerStatus struct {
+	// HasBattery reports whether a system battery is present.
+	HasBattery bool `json:"hasBattery"`
+
+	// Level is the battery charge between 0 and 1. It is 1 when there is no
+	// battery.
+	Level float64 `json:"level"`
+
+	// Charging reports whether the battery is currently charging.
+	Charging bool `json:"charging"`
+
+	// OnBattery reports whether the machine is running on battery power.
+	OnBattery bool `json:"onBattery"`
+}
+
+// defaultPowerPollInterval is used by OnPowerChange when no interval is given.
+const defaultPowerPollInterval = 30 * time.Second
+
+// ReadPowerStatus returns the current power status. Its signature makes it
+// directly bindable: w.Bind("power_status", glaze.ReadPowerStatus).
+func ReadPowerStatus() (PowerStatus, error) {
+	return readPowerStatus()
+}
+
+// OnPowerChange polls the power status every interval (30s if zero) and calls
+// fn, from a background goroutine, whenever it changes — for example when the
+// laptop is unplugged — so apps can throttle background work. fn is also
+// called once with the initial status. The returned function stops polling.
+func OnPowerChange(interval time.Duration, fn func(PowerStatus)) (stop func()) {
+	if interval <= 0 {
+		interval = defaultPowerPollInterval
+	}
+	return watchChanges(interval, readPowerStatus, func(a, b PowerStatus) bool { return a == b }, fn)
+}
+
+// parseSysfsPower reads the Linux power_supply class directory at root
+// (normally /sys/class/power_supply).
+func parseSysfsPower(root string) (PowerStatus, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return PowerStatus{}, err
+	}
+	read := func(dir, name string) string {
+		data, _ := os.ReadFile(filepath.Join(root, dir, name))
+		return strings.TrimSpace(string(data))
+	}
+
+	status := PowerStatus{Level: 1}
+	mainsSeen, mainsOnline := false, false
+	var levels []float64
+	for _, e := range entries {
+		switch read(e.Name(), "type") {
+		case "Mains":
+			mainsSeen = true
+			if read(e.Name(), "online") == "1" {
+				mainsOnline = true
+			}
+		case "Battery":
+			if read(e.Name(), "scope") == "Device" {
+				continue // peripheral battery such as a mouse
+			}
+			status.HasBattery = true
+			if capacity, err := strconv.Atoi(read(e.Name(), "capacity")); err == nil {
+				levels = append(levels, float64(capacity)/100)
+			}
+			switch read(e.Name(), "status") {
+			case "Charging":
+				status.Charging = true
+			case "Discharging":
+				if !mainsSeen {
+					status.OnBattery = true
+				}
+			}
+		}
+	}
+	if len(levels) > 0 {
+		var sum float64
+		for _, l := range levels {
+			sum += l
+		}
+		status.Level = sum / float64(len(levels))
+	}
+	if mainsSeen {
+		status.OnBattery = status.HasBattery && !mainsOnline
+	}
+	return status, nil
+}
+
+var pmsetBatteryRe = regexp.MustCompile(`(\d+)%;\s*([a-zA-Z ]+);`)
+
+// parsePmset parses the output of "pmset -g batt" on macOS.
+func parsePmset(out string) (PowerStatus, error) {
+	if !strings.Contains(out, "drawing from") {
+		return PowerStatus{}, errors.New("webview: unexpected pmset output")
+	}
+	status := PowerStatus{
+		Level:     1,
+		OnBattery: strings.Contains(out, "'Battery Power'"),
+	}
+	if m := pmsetBatteryRe.FindStringSubmatch(out); m != nil {
+		status.HasBattery = true
+		level, _ := strconv.Atoi(m[1])
+		status.Level = float64(level) / 100
+		status.Charging = strings.TrimSpace(m[2]) == "charging"
+	}
+	return status, nil
+}
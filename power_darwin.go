@@ -0,0 +1,14 @@
+package glaze
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+func readPowerStatus() (PowerStatus, error) {
+	out, err := exec.Command("pmset", "-g", "batt").Output()
+	if err != nil {
+		return PowerStatus{}, fmt.Errorf("webview: pmset: %w", err)
+	}
+	return parsePmset(string(out))
+}
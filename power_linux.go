@@ -0,0 +1,5 @@
+package glaze
+
+func readPowerStatus() (PowerStatus, error) {
+	return parseSysfsPower("/sys/class/power_supply")
+}
@@ -0,0 +1,99 @@
+package glaze
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSupply(t *testing.T, root, name string, attrs map[string]string) {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range attrs {
+		if err := os.WriteFile(filepath.Join(dir, k), []byte(v+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestParseSysfsPower(t *testing.T) {
+	root := t.TempDir()
+	writeSupply(t, root, "AC", map[string]string{"type": "Mains", "online": "0"})
+	writeSupply(t, root, "BAT0", map[string]string{"type": "Battery", "capacity": "42", "status": "Discharging"})
+	writeSupply(t, root, "hidpp_battery_0", map[string]string{"type": "Battery", "scope": "Device", "capacity": "5"})
+
+	got, err := parseSysfsPower(root)
+	if err != nil {
+		t.Fatalf("parseSysfsPower() unexpected error: %v", err)
+	}
+	want := PowerStatus{HasBattery: true, Level: 0.42, OnBattery: true}
+	if got != want {
+		t.Fatalf("parseSysfsPower() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseSysfsPowerCharging(t *testing.T) {
+	root := t.TempDir()
+	writeSupply(t, root, "ADP1", map[string]string{"type": "Mains", "online": "1"})
+	writeSupply(t, root, "BAT1", map[string]string{"type": "Battery", "capacity": "80", "status": "Charging"})
+
+	got, err := parseSysfsPower(root)
+	if err != nil {
+		t.Fatalf("parseSysfsPower() unexpected error: %v", err)
+	}
+	want := PowerStatus{HasBattery: true, Level: 0.8, Charging: true}
+	if got != want {
+		t.Fatalf("parseSysfsPower() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseSysfsPowerDesktop(t *testing.T) {
+	got, err := parseSysfsPower(t.TempDir())
+	if err != nil {
+		t.Fatalf("parseSysfsPower() unexpected error: %v", err)
+	}
+	if got != (PowerStatus{Level: 1}) {
+		t.Fatalf("parseSysfsPower() = %+v, want no battery", got)
+	}
+}
+
+func TestParsePmset(t *testing.T) {
+	tests := []struct {
+		name string
+		out  string
+		want PowerStatus
+	}{
+		{
+			name: "discharging",
+			out:  "Now drawing from 'Battery Power'\n -InternalBattery-0 (id=4653155)\t85%; discharging; 5:12 remaining present: true\n",
+			want: PowerStatus{HasBattery: true, Level: 0.85, OnBattery: true},
+		},
+		{
+			name: "charging",
+			out:  "Now drawing from 'AC Power'\n -InternalBattery-0 (id=4653155)\t61%; charging; 1:02 remaining present: true\n",
+			want: PowerStatus{HasBattery: true, Level: 0.61, Charging: true},
+		},
+		{
+			name: "desktop",
+			out:  "Now drawing from 'AC Power'\n",
+			want: PowerStatus{Level: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePmset(tt.out)
+			if err != nil {
+				t.Fatalf("parsePmset() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("parsePmset() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+	if _, err := parsePmset("garbage"); err == nil {
+		t.Fatal("parsePmset() expected error for unexpected output")
+	}
+}
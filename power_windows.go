@@ -0,0 +1,48 @@
+package glaze
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                 = syscall.NewLazyDLL("kernel32.dll")
+	procGetSystemPowerStatus = kernel32.NewProc("GetSystemPowerStatus")
+)
+
+// systemPowerStatus mirrors the Win32 SYSTEM_POWER_STATUS structure.
+type systemPowerStatus struct {
+	ACLineStatus        byte
+	BatteryFlag         byte
+	BatteryLifePercent  byte
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+const (
+	batteryFlagCharging   = 8
+	batteryFlagNoBattery  = 128
+	batteryFlagUnknown    = 255
+	batteryPercentUnknown = 255
+)
+
+func readPowerStatus() (PowerStatus, error) {
+	var s systemPowerStatus
+	if r, _, err := procGetSystemPowerStatus.Call(uintptr(unsafe.Pointer(&s))); r == 0 {
+		return PowerStatus{}, fmt.Errorf("webview: GetSystemPowerStatus failed: %w", err)
+	}
+	status := PowerStatus{
+		HasBattery: s.BatteryFlag != batteryFlagNoBattery && s.BatteryFlag != batteryFlagUnknown,
+		Level:      1,
+	}
+	if status.HasBattery {
+		status.Charging = s.BatteryFlag&batteryFlagCharging != 0
+		status.OnBattery = s.ACLineStatus == 0
+		if s.BatteryLifePercent != batteryPercentUnknown {
+			status.Level = float64(s.BatteryLifePercent) / 100
+		}
+	}
+	return status, nil
+}
@@ -0,0 +1,32 @@
+package glaze
+
+// prefetchJS adds preconnect and prefetch hints for a URL to the current
+// document, skipping hints that are already present.
+const prefetchJS = `(function (url) {
+  var target = new URL(url, location.href);
+  function hint(rel, href) {
+    var links = document.querySelectorAll("link[rel=" + rel + "]");
+    for (var i = 0; i < links.length; i++) {
+      if (links[i].href === href) { return; }
+    }
+    var link = document.createElement("link");
+    link.rel = rel;
+    link.href = href;
+    if (rel === "prefetch") { link.as = "document"; }
+    (document.head || document.documentElement).appendChild(link);
+  }
+  if (target.origin !== location.origin) { hint("preconnect", target.origin + "/"); }
+  hint("prefetch", target.href);
+})`
+
+// Prefetch hints the engine to warm its HTTP cache and connection for an
+// upcoming navigation to url, which may be relative to the current page.
+// Switching between server-rendered AppWindow pages then feels instant
+// because the next document is already downloaded when the user clicks.
+//
+// Hints live in the current document, so call Prefetch again after each
+// navigation. It may be called from any goroutine.
+func Prefetch(w WebView, url string) {
+	js := prefetchJS + "(" + marshalJSON(url) + ");"
+	w.Dispatch(func() { w.Eval(js) })
+}
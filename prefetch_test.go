@@ -0,0 +1,22 @@
+package glaze
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrefetch(t *testing.T) {
+	w := &fakeWebView{}
+	Prefetch(w, `/notes?q="x"`)
+
+	if len(w.evals) != 1 {
+		t.Fatalf("evals = %d, want 1", len(w.evals))
+	}
+	js := w.evals[0]
+	if !strings.HasPrefix(js, prefetchJS) {
+		t.Fatal("Prefetch() did not evaluate the prefetch script")
+	}
+	if !strings.HasSuffix(js, `("/notes?q=\"x\"");`) {
+		t.Fatalf("Prefetch() did not pass the URL as a JSON string: %s", js[len(prefetchJS):])
+	}
+}
@@ -0,0 +1,25 @@
+package glaze
+
+import "image"
+
+// fitImage scales img to fit within maxW x maxH, keeping its aspect ratio,
+// with nearest-neighbour sampling. Images that already fit are only
+// converted. Taskbar previews are small and short-lived, so speed matters
+// more than filtering quality.
+func fitImage(img image.Image, maxW, maxH int) *image.RGBA {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > maxW || h > maxH {
+		scale := min(float64(maxW)/float64(w), float64(maxH)/float64(h))
+		w = max(1, int(float64(w)*scale))
+		h = max(1, int(float64(h)*scale))
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		sy := b.Min.Y + y*b.Dy()/h
+		for x := range w {
+			dst.Set(x, y, img.At(b.Min.X+x*b.Dx()/w, sy))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package glaze
+
+import (
+	"errors"
+	"image"
+)
+
+// SetTaskbarPreview replaces the taskbar thumbnail and peek preview of the
+// window on Windows. Other platforms render their own previews, so it
+// always returns an error there.
+func SetTaskbarPreview(_ WebView, _ image.Image) error {
+	return errors.New("webview: taskbar previews are only supported on Windows")
+}
@@ -0,0 +1,35 @@
+package glaze
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestFitImage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(10, 10, 410, 210))
+	src.Set(10, 10, color.RGBA{R: 255, A: 255})
+
+	tests := []struct {
+		name       string
+		maxW, maxH int
+		wantW      int
+		wantH      int
+	}{
+		{"fits", 800, 600, 400, 200},
+		{"width bound", 200, 600, 200, 100},
+		{"height bound", 800, 50, 100, 50},
+		{"tiny", 1, 1, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fitImage(src, tt.maxW, tt.maxH)
+			if got.Bounds().Dx() != tt.wantW || got.Bounds().Dy() != tt.wantH {
+				t.Fatalf("size = %v, want %dx%d", got.Bounds().Size(), tt.wantW, tt.wantH)
+			}
+			if c := got.RGBAAt(0, 0); c.R != 255 {
+				t.Errorf("top-left pixel = %v, want red", c)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	dwmapi                            = syscall.NewLazyDLL("dwmapi.dll")
+	procDwmSetWindowAttribute         = dwmapi.NewProc("DwmSetWindowAttribute")
+	procDwmSetIconicThumbnail         = dwmapi.NewProc("DwmSetIconicThumbnail")
+	procDwmSetIconicLivePreviewBitmap = dwmapi.NewProc("DwmSetIconicLivePreviewBitmap")
+	procDwmInvalidateIconicBitmaps    = dwmapi.NewProc("DwmInvalidateIconicBitmaps")
+	comctl32                          = syscall.NewLazyDLL("comctl32.dll")
+	procSetWindowSubclass             = comctl32.NewProc("SetWindowSubclass")
+	procRemoveWindowSubclass          = comctl32.NewProc("RemoveWindowSubclass")
+	procDefSubclassProc               = comctl32.NewProc("DefSubclassProc")
+	gdi32                             = syscall.NewLazyDLL("gdi32.dll")
+	procCreateDIBSection              = gdi32.NewProc("CreateDIBSection")
+	procDeleteObject                  = gdi32.NewProc("DeleteObject")
+	procGetClientRect                 = user32.NewProc("GetClientRect")
+)
+
+const (
+	dwmwaForceIconicRepresentation = 7
+	dwmwaHasIconicBitmap           = 10
+
+	wmDwmSendIconicThumbnail         = 0x0323
+	wmDwmSendIconicLivePreviewBitmap = 0x0326
+
+	// previewSubclassID identifies glaze's window subclass.
+	previewSubclassID = 0x676c617a
+)
+
+// taskbarPreviews holds the preview image of each window that has one.
+var taskbarPreviews struct {
+	mu     sync.Mutex
+	images map[uintptr]*image.RGBA
+	proc   uintptr
+}
+
+type bitmapInfoHeader struct {
+	Size          uint32
+	Width         int32
+	Height        int32
+	Planes        uint16
+	BitCount      uint16
+	Compression   uint32
+	SizeImage     uint32
+	XPelsPerMeter int32
+	YPelsPerMeter int32
+	ClrUsed       uint32
+	ClrImportant  uint32
+}
+
+// SetTaskbarPreview replaces the taskbar thumbnail and the peek preview of
+// the window with img, scaled to fit, so hovering the taskbar button shows
+// a branded snapshot instead of whatever the page is mid-rendering. Pass a
+// nil image to restore the live previews drawn by Windows.
+//
+// It must be called from the UI thread; use Dispatch from other goroutines.
+func SetTaskbarPreview(w WebView, img image.Image) error {
+	hwnd := uintptr(w.Window())
+	if hwnd == 0 {
+		return errors.New("webview: window handle is not available")
+	}
+
+	taskbarPreviews.mu.Lock()
+	defer taskbarPreviews.mu.Unlock()
+	if taskbarPreviews.proc == 0 {
+		taskbarPreviews.proc = syscall.NewCallback(previewSubclassProc)
+		taskbarPreviews.images = make(map[uintptr]*image.RGBA)
+	}
+
+	if img == nil {
+		if _, ok := taskbarPreviews.images[hwnd]; !ok {
+			return nil
+		}
+		delete(taskbarPreviews.images, hwnd)
+		procRemoveWindowSubclass.Call(hwnd, taskbarPreviews.proc, previewSubclassID)
+		return setIconicAttributes(hwnd, false)
+	}
+
+	rgba := fitImage(img, img.Bounds().Dx(), img.Bounds().Dy())
+	if _, ok := taskbarPreviews.images[hwnd]; !ok {
+		if r, _, err := procSetWindowSubclass.Call(hwnd, taskbarPreviews.proc, previewSubclassID, 0); r == 0 {
+			return fmt.Errorf("webview: SetWindowSubclass failed: %w", err)
+		}
+		if err := setIconicAttributes(hwnd, true); err != nil {
+			procRemoveWindowSubclass.Call(hwnd, taskbarPreviews.proc, previewSubclassID)
+			return err
+		}
+	}
+	taskbarPreviews.images[hwnd] = rgba
+	procDwmInvalidateIconicBitmaps.Call(hwnd)
+	return nil
+}
+
+func setIconicAttributes(hwnd uintptr, on bool) error {
+	var value int32
+	if on {
+		value = 1
+	}
+	for _, attr := range []uintptr{dwmwaForceIconicRepresentation, dwmwaHasIconicBitmap} {
+		if hr, _, _ := procDwmSetWindowAttribute.Call(hwnd, attr, uintptr(unsafe.Pointer(&value)), unsafe.Sizeof(value)); int32(hr) < 0 {
+			return fmt.Errorf("webview: DwmSetWindowAttribute failed: HRESULT 0x%08x", uint32(hr))
+		}
+	}
+	return nil
+}
+
+// previewSubclassProc answers DWM's requests for the iconic bitmaps and
+// passes every other message on.
+func previewSubclassProc(hwnd, msg, wParam, lParam, _, _ uintptr) uintptr {
+	switch msg {
+	case wmDwmSendIconicThumbnail, wmDwmSendIconicLivePreviewBitmap:
+		taskbarPreviews.mu.Lock()
+		img := taskbarPreviews.images[hwnd]
+		taskbarPreviews.mu.Unlock()
+		if img == nil {
+			break
+		}
+		if msg == wmDwmSendIconicThumbnail {
+			// The maximum size is packed as HIWORD(width), LOWORD(height).
+			bmp := createBitmap(fitImage(img, int(lParam>>16&0xffff), int(lParam&0xffff)))
+			procDwmSetIconicThumbnail.Call(hwnd, bmp, 0)
+			procDeleteObject.Call(bmp)
+		} else {
+			var rect winRect
+			procGetClientRect.Call(hwnd, uintptr(unsafe.Pointer(&rect)))
+			bmp := createBitmap(fitImage(img, int(rect.Right-rect.Left), int(rect.Bottom-rect.Top)))
+			procDwmSetIconicLivePreviewBitmap.Call(hwnd, bmp, 0, 0)
+			procDeleteObject.Call(bmp)
+		}
+		return 0
+	}
+	r, _, _ := procDefSubclassProc.Call(hwnd, msg, wParam, lParam)
+	return r
+}
+
+// createBitmap copies img into a top-down 32-bit DIB section, the format
+// DWM expects, with premultiplied BGRA pixels.
+func createBitmap(img *image.RGBA) uintptr {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	header := bitmapInfoHeader{
+		Size:     uint32(unsafe.Sizeof(bitmapInfoHeader{})),
+		Width:    int32(w),
+		Height:   -int32(h),
+		Planes:   1,
+		BitCount: 32,
+	}
+	var bits unsafe.Pointer
+	bmp, _, _ := procCreateDIBSection.Call(0, uintptr(unsafe.Pointer(&header)), 0, uintptr(unsafe.Pointer(&bits)), 0, 0)
+	if bmp == 0 || bits == nil {
+		return 0
+	}
+	dst := unsafe.Slice((*byte)(bits), w*h*4)
+	for y := range h {
+		row := img.Pix[y*img.Stride : y*img.Stride+w*4]
+		for x := 0; x < w*4; x += 4 {
+			i := y*w*4 + x
+			dst[i], dst[i+1], dst[i+2], dst[i+3] = row[x+2], row[x+1], row[x], row[x+3]
+		}
+	}
+	return bmp
+}
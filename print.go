@@ -0,0 +1,68 @@
+package glaze
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// printJS opens the engine's print dialog for the page with a temporary
+// print stylesheet applying the PrintOptions it is called with. The
+// stylesheet goes on afterprint, which engines fire once the dialog is
+// closed whether window.print blocks or not; a stylesheet left by a print
+// whose afterprint never came is replaced by the next one.
+const printJS = `(function (o) {
+  'use strict';
+  var old = document.getElementById("__glaze_print");
+  if (old) { old.parentNode.removeChild(old); }
+  var style = document.createElement("style");
+  style.id = "__glaze_print";
+  style.media = "print";
+  var page = "@page {";
+  if (o.landscape) { page += " size: landscape;"; }
+  if (o.margin) { page += " margin: " + o.margin + ";"; }
+  style.textContent = page + " }" + (o.scale && o.scale !== 1 ? " html { zoom: " + o.scale + "; }" : "");
+  document.head.appendChild(style);
+  function done() {
+    window.removeEventListener("afterprint", done);
+    if (style.parentNode) { style.parentNode.removeChild(style); }
+  }
+  window.addEventListener("afterprint", done);
+  window.print();
+})`
+
+// PrintOptions configures Print.
+type PrintOptions struct {
+	// Scale zooms the printed page; 0.5 prints at half size. Zero means 1.
+	Scale float64 `json:"scale,omitempty"`
+
+	// Landscape prints in landscape orientation.
+	Landscape bool `json:"landscape,omitempty"`
+
+	// Margin is the CSS page margin, such as "1cm" or "10mm 15mm". Empty
+	// keeps the engine's default.
+	Margin string `json:"margin,omitempty"`
+}
+
+// Print opens the print dialog of the engine for the page in w, with the
+// scale, orientation and margins of opts applied for the print only. Like
+// Eval, Print must be called from the UI thread.
+//
+// Print has no preview or page range of its own: glaze has no PDF renderer
+// to paginate the page ahead of the dialog, so both are left to the
+// engine's dialog, which WebKitGTK and WebView2 show with a preview and a
+// page range field and WKWebView shows as the standard macOS print panel.
+func Print(w WebView, opts PrintOptions) error {
+	if w == nil {
+		return errors.New("webview: Print requires a non-nil WebView")
+	}
+	if opts.Scale < 0 {
+		return fmt.Errorf("webview: invalid print scale %g", opts.Scale)
+	}
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return err
+	}
+	w.Eval(printJS + "(" + string(data) + ");")
+	return nil
+}
@@ -0,0 +1,29 @@
+package glaze
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrint(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	if err := Print(w, PrintOptions{Scale: 0.8, Landscape: true, Margin: "1cm"}); err != nil {
+		t.Fatalf("Print() unexpected error: %v", err)
+	}
+	if len(w.evals) != 1 || !strings.HasSuffix(w.evals[0], `({"scale":0.8,"landscape":true,"margin":"1cm"});`) {
+		t.Fatalf("evals = %q, want the print script called with the options", w.evals)
+	}
+
+	if strings.Contains(w.evals[0], "setTimeout") {
+		t.Error("print stylesheet is removed on a timer, not on afterprint")
+	}
+
+	if err := Print(w, PrintOptions{Scale: -1}); err == nil {
+		t.Error("Print() with a negative scale returned no error")
+	}
+	if err := Print(nil, PrintOptions{}); err == nil {
+		t.Error("Print(nil) returned no error")
+	}
+}
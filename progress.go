@@ -0,0 +1,122 @@
+package glaze
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// progressInterval is the minimum time between progress updates sent to the
+// page, so tight loops can report freely without flooding it.
+const progressInterval = 50 * time.Millisecond
+
+// callsJS keeps track of the promise returned by every binding call, keyed
+// by the call id the native bridge hands to Go, and delivers progress updates
+// to its onprogress handler.
+const callsJS = `(function () {
+  'use strict';
+  var glaze = window.glaze = window.glaze || {};
+  if (glaze._calls) { return; }
+  var calls = glaze._calls = {};
+  glaze._progress = function (id, event) {
+    var promise = calls[id];
+    if (promise && typeof promise.onprogress === "function") { promise.onprogress(event); }
+  };
+  function patch() {
+    if (!window.__webview__) { return false; }
+    var proto = Object.getPrototypeOf(window.__webview__);
+    if (proto._glazeCalls || Object.isFrozen(proto)) { return true; }
+    proto._glazeCalls = true;
+    var post = proto.post;
+    var call = proto.call;
+    var lastId = null;
+    proto.post = function (message) {
+      try { lastId = JSON.parse(message).id; } catch (e) { lastId = null; }
+      return post.apply(this, arguments);
+    };
+    proto.call = function () {
+      lastId = null;
+      var promise = call.apply(this, arguments);
+      var id = lastId;
+      if (id !== null && promise && typeof promise.then === "function") {
+        promise.onprogress = null;
+        calls[id] = promise;
+        var done = function () { delete calls[id]; };
+        promise.then(done, done);
+      }
+      return promise;
+    };
+    return true;
+  }
+  if (!patch()) { document.addEventListener("DOMContentLoaded", patch); }
+})();`
+
+// Progress reports the progress of a long-running bound call. A bound
+// function receives one by declaring a *Progress parameter, which is filled
+// in by glaze rather than passed from JavaScript:
+//
+//	w.Bind("export", func(path string, p *glaze.Progress) error {
+//		for i, row := range rows {
+//			p.Report(float64(i+1), float64(len(rows)), "writing rows")
+//			...
+//		}
+//		return nil
+//	})
+//
+// The page receives each report as an onprogress event on the promise the
+// call returned:
+//
+//	const job = window.export("out.csv");
+//	job.onprogress = (e) => bar.value = e.done / e.total;
+//	await job;
+//
+// Updates are rate limited, so Report may be called on every iteration.
+// A Progress is safe for concurrent use; it is a no-op once the call has
+// returned or when the function is not called from a page.
+type Progress struct {
+	w  WebView
+	id string
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// ProgressEvent is the payload of the page's onprogress events.
+type ProgressEvent struct {
+	Done    float64 `json:"done"`
+	Total   float64 `json:"total"`
+	Message string  `json:"message,omitempty"`
+}
+
+// Report sends done out of total units of work, with an optional status
+// message. A total of zero means the amount of work is unknown. Reports are
+// dropped when they follow the previous one too closely, except the one
+// where done reaches total.
+func (p *Progress) Report(done, total float64, message string) {
+	if p == nil || p.w == nil {
+		return
+	}
+	p.mu.Lock()
+	now := time.Now()
+	if now.Sub(p.last) < progressInterval && (total == 0 || done < total) {
+		p.mu.Unlock()
+		return
+	}
+	p.last = now
+	p.mu.Unlock()
+
+	data, _ := json.Marshal(ProgressEvent{Done: done, Total: total, Message: message})
+	js := "window.glaze && window.glaze._progress && window.glaze._progress(" + marshalJSON(p.id) + ", " + string(data) + ");"
+	p.w.Dispatch(func() { p.w.Eval(js) })
+}
+
+// setupProgress installs the call tracking runtime when a function taking a
+// *Progress is bound.
+func setupProgress(w WebView) {
+	bridgeFor(w).injectScript("calls", callsJS)
+}
+
+func progressParam(w WebView, id string) reflect.Value {
+	return reflect.ValueOf(&Progress{w: w, id: id})
+}
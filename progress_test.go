@@ -0,0 +1,72 @@
+package glaze
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProgressParameter(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	fn, err := makeBinding(w, "compute", func(n int, p *Progress) int {
+		for i := range n {
+			p.Report(float64(i+1), float64(n), "step")
+		}
+		return n
+	})
+	if err != nil {
+		t.Fatalf("makeBinding() unexpected error: %v", err)
+	}
+	if len(w.inits) != 1 || w.inits[0] != callsJS {
+		t.Fatalf("init scripts = %d, want the call tracking runtime", len(w.inits))
+	}
+
+	got, err := fn("call-7", `[100]`)
+	if err != nil {
+		t.Fatalf("call unexpected error: %v", err)
+	}
+	if got != 100 {
+		t.Fatalf("call = %v, want 100", got)
+	}
+
+	var reports []string
+	for _, js := range w.evals {
+		if strings.Contains(js, "_progress(") {
+			reports = append(reports, js)
+		}
+	}
+	// The first report goes out at once, the rest are rate limited except
+	// the final one.
+	if len(reports) < 2 || len(reports) > 10 {
+		t.Fatalf("progress reports = %d, want first and final only", len(reports))
+	}
+	if !strings.Contains(reports[0], `_progress("call-7", {"done":1,"total":100,"message":"step"})`) {
+		t.Errorf("first report = %s", reports[0])
+	}
+	if !strings.Contains(reports[len(reports)-1], `"done":100,"total":100`) {
+		t.Errorf("final report = %s", reports[len(reports)-1])
+	}
+}
+
+func TestProgressParameterNotFromJS(t *testing.T) {
+	fn, err := makeFuncWrapper(func(p *Progress, parts ...string) string {
+		p.Report(1, 1, "") // no window: must not panic
+		return strings.Join(parts, "-")
+	})
+	if err != nil {
+		t.Fatalf("makeFuncWrapper() unexpected error: %v", err)
+	}
+	got, err := fn("id", `["a", "b"]`)
+	if err != nil || got != "a-b" {
+		t.Fatalf("call = %v, %v; want a-b", got, err)
+	}
+	if got, err := fn("id", `[]`); err != nil || got != "" {
+		t.Fatalf("call without arguments = %v, %v; want empty string", got, err)
+	}
+}
+
+func TestProgressNil(t *testing.T) {
+	var p *Progress
+	p.Report(1, 2, "")
+}
@@ -0,0 +1,56 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// encodeQR encodes text as the smallest QR code at error correction level M,
+// which pairing URLs fit comfortably, with a quiet zone of four modules. The
+// matrix has one bit per module.
+func encodeQR(text string) (*gozxing.BitMatrix, error) {
+	hints := map[gozxing.EncodeHintType]any{
+		gozxing.EncodeHintType_ERROR_CORRECTION: "M",
+		gozxing.EncodeHintType_CHARACTER_SET:    "UTF-8",
+		gozxing.EncodeHintType_MARGIN:           4,
+	}
+	m, err := qrcode.NewQRCodeWriter().Encode(text, gozxing.BarcodeFormat_QR_CODE, 0, 0, hints)
+	if err != nil {
+		return nil, fmt.Errorf("webview: encode QR code: %w", err)
+	}
+	return m, nil
+}
+
+// qrSVG renders m as an SVG image, one unit per module.
+func qrSVG(m *gozxing.BitMatrix) string {
+	w, h := m.GetWidth(), m.GetHeight()
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, w, h)
+	fmt.Fprintf(&sb, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, w, h)
+	for y := range h {
+		for x := range w {
+			if m.Get(x, y) {
+				fmt.Fprintf(&sb, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	sb.WriteString(`"/></svg>`)
+	return sb.String()
+}
+
+// QRCodeSVG returns text encoded as a QR code in an SVG image, such as a
+// pairing URL for a phone to scan.
+func QRCodeSVG(text string) (string, error) {
+	if text == "" {
+		return "", errors.New("webview: QR code text must not be empty")
+	}
+	m, err := encodeQR(text)
+	if err != nil {
+		return "", err
+	}
+	return qrSVG(m), nil
+}
@@ -0,0 +1,28 @@
+package glaze
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncodeQR(t *testing.T) {
+	text := "http://192.168.1.20:54321/?token=" + strings.Repeat("x", 32)
+	s := &scanner{formats: []string{"qr_code"}}
+	got := s.decode(qrFrame(t, text, 4).image())
+	if len(got) != 1 || got[0].Value != text {
+		t.Fatalf("decoded %v, want %q", got, text)
+	}
+}
+
+func TestQRCodeSVG(t *testing.T) {
+	svg, err := QRCodeSVG("hello")
+	if err != nil {
+		t.Fatalf("QRCodeSVG: %v", err)
+	}
+	if !strings.HasPrefix(svg, "<svg ") || !strings.Contains(svg, `viewBox="0 0 29 29"`) {
+		t.Fatalf("svg = %.80s", svg)
+	}
+	if _, err := QRCodeSVG(""); err == nil {
+		t.Fatal("QRCodeSVG accepted empty text")
+	}
+}
@@ -0,0 +1,79 @@
+package glaze
+
+import (
+	"fmt"
+	"sort"
+)
+
+// readyName is the hidden binding glazeReady confirms the bridge through.
+const readyName = "__glaze_ready"
+
+// readyJS defines window.glazeReady, a promise that resolves, and a
+// "glazeready" window event that fires, once a call has made the round trip
+// to Go and every function bound when it answered is defined in the page.
+// Names still missing after two seconds, such as ones unbound meanwhile, do
+// not hold it back.
+const readyJS = `(function () {
+  'use strict';
+  if (window.glazeReady) { return; }
+  var resolve;
+  window.glazeReady = new Promise(function (r) { resolve = r; });
+  function defined(name) {
+    return typeof window[name] === "function";
+  }
+  function done() {
+    resolve();
+    window.dispatchEvent(new Event("glazeready"));
+  }
+  function wait(names, tries) {
+    var missing = names.filter(function (n) { return !defined(n); });
+    if (missing.length === 0 || tries >= 400) { done(); return; }
+    setTimeout(function () { wait(missing, tries + 1); }, 5);
+  }
+  function check() {
+    if (!window.__webview__ || !defined("` + readyName + `")) { setTimeout(check, 5); return; }
+    window.` + readyName + `().then(function (names) { wait(names || [], 0); }, function () { setTimeout(check, 20); });
+  }
+  check();
+})();`
+
+// installReady defines window.glazeReady in every page of the window. The
+// native WebView does so on its first Bind, so pages can wait for the
+// bindings registered before Navigate:
+//
+//	await window.glazeReady;
+//	const notes = await notes_list();
+//
+// A failure to bind the hidden function is returned, and the next Bind
+// tries again.
+func (b *bridge) installReady() error {
+	b.mu.Lock()
+	installed := b.readyInstalled
+	b.mu.Unlock()
+	if installed {
+		return nil
+	}
+	if err := b.bindHidden(readyName, b.readyNames); err != nil {
+		return fmt.Errorf("webview: install glazeReady: %w", err)
+	}
+	b.injectScript("ready", readyJS)
+	b.mu.Lock()
+	b.readyInstalled = true
+	b.mu.Unlock()
+	return nil
+}
+
+// readyNames returns the names of the functions bound in the window, for
+// glazeReady to wait for.
+func (b *bridge) readyNames() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	names := make([]string, 0, len(b.bindings))
+	for name := range b.bindings {
+		if !b.hidden[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,39 @@
+package glaze
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestInstallReady(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	_ = w.Bind("notes_list", func() []string { return nil })
+	_ = w.Bind("notes_add", func(string) error { return nil })
+	b := bridgeFor(w)
+	if err := b.installReady(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.installReady(); err != nil {
+		t.Fatal(err)
+	}
+
+	n := 0
+	for _, js := range w.inits {
+		if js == readyJS {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Fatalf("ready runtime installed %d times, want once", n)
+	}
+
+	got, err := w.call(t, readyName)
+	if err != nil {
+		t.Fatalf("%s unexpected error: %v", readyName, err)
+	}
+	if names, _ := got.([]string); !slices.Equal(names, []string{"notes_add", "notes_list"}) {
+		t.Errorf("%s = %v, want the bound names without hidden ones", readyName, got)
+	}
+}
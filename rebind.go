@@ -0,0 +1,248 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RebindMethods replaces the object behind the methods bound under prefix,
+// for example after reloading configuration or swapping a plugin, without
+// tearing the window down. It binds obj's methods as BindMethodsWithOptions
+// does, but a name that is already bound has its Go function swapped in
+// place: the page keeps its function, calls already running finish on the
+// old object and the next call reaches the new one. Methods bound for prefix
+// by an earlier BindMethods or RebindMethods call that obj no longer has are
+// unbound, and bound for the first time if obj adds them.
+//
+//	cfg := loadConfig()
+//	if _, err := glaze.RebindMethods(w, "notes", NewNotes(cfg), opts); err != nil {
+//		log.Println(err)
+//	}
+//
+// If a method cannot be bound, the bindings already replaced are restored
+// and the error is returned. RebindMethods must be called from the UI
+// thread, like Bind; use Dispatch from other goroutines.
+func RebindMethods(w WebView, prefix string, obj any, opts BindOptions) ([]string, error) {
+	if w == nil {
+		return nil, errors.New("webview: RebindMethods requires a non-nil WebView")
+	}
+	methods, err := serviceMethods("RebindMethods", prefix, obj, opts)
+	if err != nil {
+		return nil, err
+	}
+	return rebindService(w, prefix, methods, opts, nil)
+}
+
+// rebindService swaps the methods bound under prefix for methods, as
+// RebindMethods describes, and records contract as the interface the
+// service is bound through, if any.
+func rebindService(w WebView, prefix string, methods []serviceMethod, opts BindOptions, contract reflect.Type) ([]string, error) {
+	var err error
+	b := bridgeFor(w)
+	b.mu.Lock()
+	previous := b.services[prefix]
+	originals := make(map[string]any, len(methods))
+	codecs := make(map[string]Codec, len(methods))
+	for _, m := range methods {
+		if f, ok := b.funcs[m.name]; ok {
+			originals[m.name] = f
+			codecs[m.name] = b.codecs[m.name]
+		}
+	}
+	b.mu.Unlock()
+
+	// Undo the replacements done so far, in reverse order.
+	var done []string
+	rollback := func() error {
+		var errs []error
+		for i := len(done) - 1; i >= 0; i-- {
+			name := done[i]
+			if original, ok := originals[name]; ok {
+				if err := replaceBinding(w, name, original); err != nil {
+					errs = append(errs, fmt.Errorf("restoring %s: %w", name, err))
+					continue
+				}
+				b.mu.Lock()
+				if codecs[name] != nil {
+					b.codecs[name] = codecs[name]
+				} else {
+					delete(b.codecs, name)
+				}
+				b.mu.Unlock()
+				continue
+			}
+			if err := w.Unbind(name); err != nil {
+				errs = append(errs, fmt.Errorf("unbinding %s: %w", name, err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	var bound, members []string
+	current := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		if _, ok := originals[m.name]; ok {
+			err = rebindWithOptions(w, m.name, m.fn, opts.callOptions())
+		} else {
+			err = BindWithOptions(w, m.name, m.fn, opts.callOptions())
+		}
+		if err != nil {
+			return nil, errors.Join(fmt.Errorf("binding %s: %w", m.name, err), rollback())
+		}
+		done = append(done, m.name)
+		bound = append(bound, m.name)
+		members = append(members, m.member)
+		current[m.name] = true
+	}
+
+	var removed []string
+	for _, name := range previous {
+		if current[name] {
+			continue
+		}
+		// Unbind fails only for names already unbound by other means.
+		_ = w.Unbind(name)
+		removed = append(removed, name)
+	}
+	if opts.Namespace {
+		if len(members) > 0 {
+			installNamespace(w, prefix, members)
+		} else {
+			b.mu.Lock()
+			b.dropRuntimeScriptLocked("namespace:" + prefix)
+			b.mu.Unlock()
+		}
+		if len(removed) > 0 {
+			// Pages loaded later get the new members only.
+			w.Eval(namespaceRemoveJS(prefix, removed))
+		}
+	}
+	b.recordService(prefix, bound, opts, contract)
+	return bound, nil
+}
+
+// ReplaceService swaps the object bound under prefix for obj, keeping the
+// BindOptions it was bound with, for example after reconnecting a database
+// or reloading a plugin:
+//
+//	if _, err := glaze.ReplaceService(w, "store", NewStore(db)); err != nil {
+//		log.Println(err)
+//	}
+//
+// It is RebindMethods with the options of the last BindMethods,
+// RebindMethods, BindAll or BindInterface call for prefix, and fails if
+// nothing was bound under prefix. A service bound with BindInterface keeps
+// its interface: obj must implement it, and only its methods are bound. The
+// page keeps its functions throughout; a failed swap leaves the old object
+// bound. Call it from the UI thread.
+func ReplaceService(w WebView, prefix string, obj any) ([]string, error) {
+	if w == nil {
+		return nil, errors.New("webview: ReplaceService requires a non-nil WebView")
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	opts, ok := b.serviceOpts[prefix]
+	contract := b.contracts[prefix]
+	b.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("webview: ReplaceService: no service bound under prefix %q", prefix)
+	}
+	if contract == nil {
+		return RebindMethods(w, prefix, obj, opts)
+	}
+	v, err := contractValue("ReplaceService", contract, obj)
+	if err != nil {
+		return nil, err
+	}
+	methods, err := serviceMethodsOf("ReplaceService", prefix, v, opts)
+	if err != nil {
+		return nil, err
+	}
+	return rebindService(w, prefix, methods, opts, contract)
+}
+
+// rebindWithOptions swaps the function behind the bound name for f,
+// honouring opts as BindWithOptions does.
+func rebindWithOptions(w WebView, name string, f any, opts BindingOptions) error {
+	wrapped, err := applyBindingOptions(name, f, opts)
+	if err != nil {
+		return err
+	}
+	if err := replaceBinding(w, name, wrapped); err != nil {
+		return err
+	}
+	setCallOptions(w, name, opts)
+	return nil
+}
+
+// rebinder is implemented by WebViews that can swap the function behind a
+// binding without unbinding it from the page.
+type rebinder interface {
+	rebind(name string, f any) error
+}
+
+// replaceBinding binds f as name in place of the current function. Other
+// WebView implementations fall back to Unbind and Bind, which leaves the
+// page without the function between the two.
+func replaceBinding(w WebView, name string, f any) error {
+	if r, ok := w.(rebinder); ok {
+		return r.rebind(name, f)
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	codec := b.codecs[name]
+	original := b.funcs[name]
+	b.mu.Unlock()
+	if err := w.Unbind(name); err != nil {
+		return err
+	}
+	if err := w.Bind(name, f); err != nil {
+		if original != nil {
+			_ = w.Bind(name, original)
+		}
+		return err
+	}
+	if codec != nil {
+		b.mu.Lock()
+		if b.codecs == nil {
+			b.codecs = make(map[string]Codec)
+		}
+		b.codecs[name] = codec
+		b.mu.Unlock()
+	}
+	return nil
+}
+
+// recordService remembers the names bound for prefix by BindMethods and
+// RebindMethods, the options used and the interface bound through, if any.
+func (b *bridge) recordService(prefix string, names []string, opts BindOptions, contract reflect.Type) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.services == nil {
+		b.services = make(map[string][]string)
+		b.serviceOpts = make(map[string]BindOptions)
+		b.contracts = make(map[string]reflect.Type)
+	}
+	b.services[prefix] = append([]string(nil), names...)
+	b.serviceOpts[prefix] = opts
+	if contract != nil {
+		b.contracts[prefix] = contract
+	} else {
+		delete(b.contracts, prefix)
+	}
+}
+
+// namespaceRemoveJS builds the script that deletes the members bound as the
+// given names from the namespace object window[prefix].
+func namespaceRemoveJS(prefix string, names []string) string {
+	var b strings.Builder
+	b.WriteString("(function () {\n  var ns = window[" + marshalJSON(prefix) + "];\n  if (!ns) { return; }\n")
+	for _, name := range names {
+		member := strings.TrimPrefix(name, prefix+".")
+		b.WriteString("  delete ns[" + marshalJSON(member) + "];\n")
+	}
+	b.WriteString("})();")
+	return b.String()
+}
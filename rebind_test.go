@@ -0,0 +1,132 @@
+package glaze
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+type rebindServiceV1 struct{}
+
+func (rebindServiceV1) Greeting() string { return "v1" }
+
+func (rebindServiceV1) Legacy() {}
+
+type rebindServiceV2 struct{ name string }
+
+func (s rebindServiceV2) Greeting() string { return s.name }
+
+func (rebindServiceV2) Extra() int { return 2 }
+
+func TestRebindMethods(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	if _, err := BindMethods(w, "cfg", rebindServiceV1{}); err != nil {
+		t.Fatalf("BindMethods() unexpected error: %v", err)
+	}
+	names, err := RebindMethods(w, "cfg", rebindServiceV2{name: "v2"}, BindOptions{})
+	if err != nil {
+		t.Fatalf("RebindMethods() unexpected error: %v", err)
+	}
+	if want := []string{"cfg_extra", "cfg_greeting"}; !slices.Equal(names, want) {
+		t.Errorf("RebindMethods() = %v, want %v", names, want)
+	}
+	if got, _ := w.call(t, "cfg_greeting"); got != "v2" {
+		t.Errorf("cfg_greeting = %v, want the new implementation", got)
+	}
+	if got, _ := w.call(t, "cfg_extra"); got != 2 {
+		t.Errorf("cfg_extra = %v, want 2", got)
+	}
+	if _, bound := w.bound["cfg_legacy"]; bound {
+		t.Error("method missing from the new object is still bound")
+	}
+
+	// A second swap starts from the names bound by the first.
+	if _, err := RebindMethods(w, "cfg", rebindServiceV1{}, BindOptions{}); err != nil {
+		t.Fatalf("second RebindMethods() unexpected error: %v", err)
+	}
+	if _, bound := w.bound["cfg_extra"]; bound {
+		t.Error("cfg_extra still bound after swapping back")
+	}
+	if got, _ := w.call(t, "cfg_greeting"); got != "v1" {
+		t.Errorf("cfg_greeting = %v after swapping back, want v1", got)
+	}
+}
+
+func TestRebindMethodsNamespace(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	opts := BindOptions{Namespace: true}
+	if _, err := BindMethodsWithOptions(w, "cfg", rebindServiceV1{}, opts); err != nil {
+		t.Fatalf("BindMethodsWithOptions() unexpected error: %v", err)
+	}
+	if _, err := RebindMethods(w, "cfg", rebindServiceV2{}, opts); err != nil {
+		t.Fatalf("RebindMethods() unexpected error: %v", err)
+	}
+	// Pages loaded later get the new members only; the current one loses
+	// the removed member.
+	if last := w.evals[len(w.evals)-1]; !strings.Contains(last, `delete ns["legacy"]`) {
+		t.Errorf("last eval = %q, want the removed member deleted", last)
+	}
+	for _, js := range w.inits {
+		if strings.Contains(js, `ns["legacy"]`) {
+			t.Errorf("removed member still registered for new pages: %q", js)
+		}
+	}
+	if _, bound := w.bound["cfg.extra"]; !bound {
+		t.Error("new namespace member not bound")
+	}
+}
+
+func TestRebindMethodsRollsBack(t *testing.T) {
+	w := &fakeWebView{failBind: "cfg_greeting"}
+	defer w.Destroy()
+
+	_ = w.Bind("cfg_extra", func() int { return 1 })
+	if _, err := RebindMethods(w, "cfg", rebindServiceV2{name: "v2"}, BindOptions{}); err == nil {
+		t.Fatal("RebindMethods() expected error")
+	}
+	if got, _ := w.call(t, "cfg_extra"); got != 1 {
+		t.Errorf("cfg_extra = %v after failed swap, want the original restored", got)
+	}
+	if _, bound := w.bound["cfg_greeting"]; bound {
+		t.Error("cfg_greeting bound after failed swap")
+	}
+}
+
+func TestRebindMethodsNilWebView(t *testing.T) {
+	if _, err := RebindMethods(nil, "cfg", rebindServiceV1{}, BindOptions{}); err == nil {
+		t.Fatal("RebindMethods(nil) expected error")
+	}
+}
+
+func TestReplaceService(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	if _, err := ReplaceService(w, "cfg", rebindServiceV2{}); err == nil {
+		t.Fatal("ReplaceService() before binding expected error")
+	}
+	if _, err := BindMethodsWithOptions(w, "cfg", rebindServiceV1{}, BindOptions{Namespace: true}); err != nil {
+		t.Fatalf("BindMethodsWithOptions() unexpected error: %v", err)
+	}
+	names, err := ReplaceService(w, "cfg", rebindServiceV2{name: "v2"})
+	if err != nil {
+		t.Fatalf("ReplaceService() unexpected error: %v", err)
+	}
+	if want := []string{"cfg.extra", "cfg.greeting"}; !slices.Equal(names, want) {
+		t.Errorf("ReplaceService() = %v, want the namespace names %v", names, want)
+	}
+	if got, _ := w.call(t, "cfg.greeting"); got != "v2" {
+		t.Errorf("cfg.greeting = %v, want the new implementation", got)
+	}
+
+	if _, err := UnbindMethods(w, "cfg"); err != nil {
+		t.Fatalf("UnbindMethods() unexpected error: %v", err)
+	}
+	if _, err := ReplaceService(w, "cfg", rebindServiceV1{}); err == nil {
+		t.Fatal("ReplaceService() after UnbindMethods expected error")
+	}
+}
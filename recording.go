@@ -0,0 +1,212 @@
+package glaze
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRecordingFPS         = 5
+	defaultRecordingMaxWidth    = 800
+	defaultRecordingMaxDuration = 30 * time.Second
+)
+
+// RecordingOptions configures StartRecording.
+type RecordingOptions struct {
+	// FPS is how many frames are captured per second. Defaults to 5.
+	FPS int
+
+	// MaxWidth scales frames down to at most this many pixels wide.
+	// Defaults to 800.
+	MaxWidth int
+
+	// MaxDuration stops capturing after this long, bounding memory use;
+	// StopRecording still has to be called to write the file. Defaults to
+	// 30 seconds.
+	MaxDuration time.Duration
+}
+
+// recorder collects the frames of one recording.
+type recorder struct {
+	path    string
+	opts    RecordingOptions
+	capture func() (image.Image, error)
+	now     func() time.Time
+	stop    chan struct{}
+
+	mu     sync.Mutex
+	frames []*image.Paletted
+	times  []time.Time
+	err    error
+}
+
+// StartRecording starts recording the window into an animated GIF written
+// to path by StopRecording, for bug reports and release notes. Frames come
+// from Capture, so the same limits apply: only the page is recorded, and
+// cross-origin content may be missing. Frames identical to the previous one
+// are folded into it. Only one recording per window can run at a time.
+//
+// It may be called from any goroutine.
+func StartRecording(w WebView, path string, opts RecordingOptions) error {
+	if opts.FPS <= 0 {
+		opts.FPS = defaultRecordingFPS
+	}
+	if opts.MaxWidth <= 0 {
+		opts.MaxWidth = defaultRecordingMaxWidth
+	}
+	if opts.MaxDuration <= 0 {
+		opts.MaxDuration = defaultRecordingMaxDuration
+	}
+	r := &recorder{
+		path:    path,
+		opts:    opts,
+		capture: func() (image.Image, error) { return Capture(w) },
+		now:     time.Now,
+		stop:    make(chan struct{}),
+	}
+
+	b := bridgeFor(w)
+	b.mu.Lock()
+	if b.recording != nil {
+		b.mu.Unlock()
+		return errors.New("webview: a recording is already running")
+	}
+	b.recording = r
+	b.mu.Unlock()
+
+	go r.run()
+	return nil
+}
+
+// StopRecording stops the recording started by StartRecording and writes
+// the GIF. Encoding takes a moment for long recordings, so prefer calling it
+// from a background goroutine. It returns the first capture error when no
+// frame could be recorded.
+func StopRecording(w WebView) error {
+	b := bridgeFor(w)
+	b.mu.Lock()
+	r := b.recording
+	b.recording = nil
+	b.mu.Unlock()
+	if r == nil {
+		return errors.New("webview: no recording is running")
+	}
+	close(r.stop)
+	return r.save()
+}
+
+func (r *recorder) run() {
+	interval := time.Second / time.Duration(r.opts.FPS)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	deadline := r.now().Add(r.opts.MaxDuration)
+	for {
+		img, err := r.capture()
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+		r.add(img, err)
+		if !r.now().Before(deadline) {
+			return
+		}
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// add quantizes a captured frame and appends it unless it repeats the
+// previous one.
+func (r *recorder) add(img image.Image, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		if r.err == nil {
+			r.err = err
+		}
+		return
+	}
+	frame := quantizeWebSafe(fitImage(img, r.opts.MaxWidth, img.Bounds().Dy()))
+	if n := len(r.frames); n > 0 && r.frames[n-1].Rect == frame.Rect && bytes.Equal(r.frames[n-1].Pix, frame.Pix) {
+		return
+	}
+	r.frames = append(r.frames, frame)
+	r.times = append(r.times, r.now())
+}
+
+// save encodes the frames, each shown until the next one was captured.
+func (r *recorder) save() error {
+	r.mu.Lock()
+	frames, times, captureErr := r.frames, r.times, r.err
+	r.mu.Unlock()
+	if len(frames) == 0 {
+		if captureErr != nil {
+			return fmt.Errorf("webview: recording: %w", captureErr)
+		}
+		return errors.New("webview: recording has no frames")
+	}
+
+	anim := &gif.GIF{Image: frames, Delay: make([]int, len(frames))}
+	last := 100 / r.opts.FPS
+	for i := range frames {
+		if i+1 < len(frames) {
+			// GIF delays are in hundredths of a second.
+			anim.Delay[i] = max(1, int(times[i+1].Sub(times[i])/(10*time.Millisecond)))
+		} else {
+			anim.Delay[i] = max(1, last)
+		}
+	}
+
+	f, err := os.Create(r.path)
+	if err != nil {
+		return fmt.Errorf("webview: recording: %w", err)
+	}
+	if err := gif.EncodeAll(f, anim); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("webview: recording: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("webview: recording: %w", err)
+	}
+	return nil
+}
+
+// webSafePalette is the 6x6x6 colour cube, indexed as 36*r + 6*g + b.
+var webSafePalette = func() color.Palette {
+	p := make(color.Palette, 0, 216)
+	for r := range 6 {
+		for g := range 6 {
+			for b := range 6 {
+				p = append(p, color.RGBA{uint8(r * 0x33), uint8(g * 0x33), uint8(b * 0x33), 0xff})
+			}
+		}
+	}
+	return p
+}()
+
+// quantizeWebSafe maps img onto webSafePalette by rounding each channel,
+// which is fast enough to run at recording frame rates.
+func quantizeWebSafe(img *image.RGBA) *image.Paletted {
+	b := img.Bounds()
+	out := image.NewPaletted(image.Rect(0, 0, b.Dx(), b.Dy()), webSafePalette)
+	for y := range b.Dy() {
+		src := img.Pix[y*img.Stride : y*img.Stride+b.Dx()*4]
+		dst := out.Pix[y*out.Stride : y*out.Stride+b.Dx()]
+		for x := range dst {
+			r, g, bl := int(src[4*x]), int(src[4*x+1]), int(src[4*x+2])
+			dst[x] = uint8(36*((r+25)/51) + 6*((g+25)/51) + (bl+25)/51)
+		}
+	}
+	return out
+}
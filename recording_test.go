@@ -0,0 +1,109 @@
+package glaze
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func solidImage(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestRecorderFoldsRepeatedFrames(t *testing.T) {
+	start := time.Unix(0, 0)
+	now := start
+	r := &recorder{
+		path: filepath.Join(t.TempDir(), "demo.gif"),
+		opts: RecordingOptions{FPS: 5, MaxWidth: 4},
+		now:  func() time.Time { return now },
+	}
+
+	red := solidImage(8, 6, color.RGBA{R: 255, A: 255})
+	blue := solidImage(8, 6, color.RGBA{B: 255, A: 255})
+	for _, step := range []struct {
+		img image.Image
+		at  time.Duration
+	}{
+		{red, 0},
+		{red, 200 * time.Millisecond},
+		{blue, 400 * time.Millisecond},
+	} {
+		now = start.Add(step.at)
+		r.add(step.img, nil)
+	}
+	r.add(nil, errors.New("ignored once frames exist"))
+
+	if err := r.save(); err != nil {
+		t.Fatalf("save() unexpected error: %v", err)
+	}
+	f, err := os.Open(r.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	anim, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatalf("DecodeAll() unexpected error: %v", err)
+	}
+	if len(anim.Image) != 2 {
+		t.Fatalf("frames = %d, want 2", len(anim.Image))
+	}
+	if anim.Delay[0] != 40 || anim.Delay[1] != 20 {
+		t.Fatalf("delays = %v, want [40 20]", anim.Delay)
+	}
+	if b := anim.Image[0].Bounds(); b.Dx() != 4 || b.Dy() != 3 {
+		t.Fatalf("frame size = %v, want 4x3", b.Size())
+	}
+	if got := anim.Image[1].ColorIndexAt(0, 0); webSafePalette[got] != (color.RGBA{B: 255, A: 255}) {
+		t.Fatalf("second frame colour = %v, want blue", webSafePalette[got])
+	}
+}
+
+func TestRecorderWithoutFrames(t *testing.T) {
+	r := &recorder{opts: RecordingOptions{FPS: 5}}
+	r.add(nil, errors.New("capture failed"))
+	if err := r.save(); err == nil || err.Error() != "webview: recording: capture failed" {
+		t.Fatalf("save() error = %v, want capture error", err)
+	}
+}
+
+func TestStartStopRecording(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	w.answerEvals(t, func(string) (any, error) { return nil, errors.New("no canvas") })
+
+	if err := StopRecording(w); err == nil {
+		t.Fatal("StopRecording() without recording expected error")
+	}
+	path := filepath.Join(t.TempDir(), "demo.gif")
+	if err := StartRecording(w, path, RecordingOptions{FPS: 50}); err != nil {
+		t.Fatalf("StartRecording() unexpected error: %v", err)
+	}
+	if err := StartRecording(w, path, RecordingOptions{}); err == nil {
+		t.Fatal("second StartRecording() expected error")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if err := StopRecording(w); err == nil {
+		t.Fatal("StopRecording() expected the capture error")
+	}
+}
+
+func TestQuantizeWebSafe(t *testing.T) {
+	img := solidImage(2, 1, color.RGBA{R: 0x30, G: 0x99, B: 0xfe, A: 255})
+	got := quantizeWebSafe(img)
+	if want := (color.RGBA{R: 0x33, G: 0x99, B: 0xff, A: 255}); got.At(1, 0) != want {
+		t.Fatalf("quantized = %v, want %v", got.At(1, 0), want)
+	}
+}
@@ -0,0 +1,473 @@
+package glaze
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Paths served by a Remote besides the remote UI.
+const (
+	remoteSocketPath = "/glaze-remote"
+	remoteScriptPath = "/glaze-remote.js"
+	remoteCookie     = "glaze_remote"
+)
+
+// remoteQueue is how many events may wait for a remote that is slow to
+// read; a remote that falls further behind is disconnected.
+const remoteQueue = 256
+
+// remoteWriteTimeout bounds the write of one event to a remote.
+const remoteWriteTimeout = 10 * time.Second
+
+// remoteJS is the client of a Remote, loaded by the remote UI from
+// /glaze-remote.js. It gives the phone the glaze.on, glaze.once and
+// glaze.off of the desktop page, plus glaze.emit, over a WebSocket that
+// reconnects when the network drops.
+const remoteJS = `(function () {
+  'use strict';
+  var glaze = window.glaze = window.glaze || {};
+  if (glaze.emit) { return; }
+  var listeners = {};
+  var queue = [];
+  var socket = null;
+  var delay = 500;
+  glaze.on = function (event, cb) {
+    (listeners[event] = listeners[event] || []).push(cb);
+    return function () { glaze.off(event, cb); };
+  };
+  glaze.once = function (event, cb) {
+    var off = glaze.on(event, function (payload) {
+      off();
+      cb(payload);
+    });
+    return off;
+  };
+  glaze.off = function (event, cb) {
+    if (cb === undefined) { delete listeners[event]; return; }
+    var list = listeners[event] || [];
+    var i = list.indexOf(cb);
+    if (i >= 0) { list.splice(i, 1); }
+  };
+  glaze.emit = function (event, payload) {
+    var msg = JSON.stringify({ event: event, payload: payload });
+    if (socket && socket.readyState === 1) { socket.send(msg); } else { queue.push(msg); }
+  };
+  function dispatch(event, payload) {
+    (listeners[event] || []).slice().forEach(function (cb) {
+      try { cb(payload); } catch (err) { setTimeout(function () { throw err; }); }
+    });
+  }
+  function connect() {
+    socket = new WebSocket((location.protocol === "https:" ? "wss://" : "ws://") + location.host + "` + remoteSocketPath + `");
+    socket.onopen = function () {
+      delay = 500;
+      queue.splice(0).forEach(function (msg) { socket.send(msg); });
+      dispatch("remote:open");
+    };
+    socket.onmessage = function (e) {
+      var m = JSON.parse(e.data);
+      dispatch(m.event, m.payload);
+    };
+    socket.onclose = function () {
+      dispatch("remote:close");
+      setTimeout(connect, delay);
+      delay = Math.min(delay * 2, 10000);
+    };
+  }
+  connect();
+})();`
+
+// remotePage is served when RemoteOptions.Handler is nil.
+const remotePage = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><meta name="viewport" content="width=device-width">
+<title>Remote</title><script src="` + remoteScriptPath + `"></script></head>
+<body><p>Connected to the desktop app.</p></body></html>`
+
+// RemoteEvent is an event sent by a remote.
+type RemoteEvent struct {
+	// Event is the event name.
+	Event string `json:"event"`
+
+	// Payload is the JSON payload of the event.
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// RemotePairing is what a phone needs to connect to a Remote.
+type RemotePairing struct {
+	// URL opens the remote UI; it carries the token.
+	URL string `json:"url"`
+
+	// QR is URL as a QR code in an SVG image.
+	QR string `json:"qr"`
+}
+
+// RemoteOptions configures StartRemote.
+type RemoteOptions struct {
+	// Addr is the TCP address to listen on. Defaults to ":0", any free port
+	// on every interface, so phones on the local network can connect.
+	Addr string
+
+	// Token authorizes remotes. Defaults to a random token, which pairing
+	// again after a restart requires; set one kept across runs to avoid it.
+	Token string
+
+	// Handler serves the remote UI, which loads /glaze-remote.js for the
+	// event bus. Nil serves a placeholder page.
+	Handler http.Handler
+
+	// Topics lists the events shared with remotes, both ways: names, or
+	// prefixes ending in "*" such as "player:*". Nil shares every event,
+	// which lets remotes emit any event to the page.
+	Topics []string
+
+	// OnEvent receives the events sent by remotes, from a background
+	// goroutine.
+	OnEvent func(RemoteEvent)
+
+	// Prefix, when set, binds {Prefix}_pairing for the page and emits
+	// "{Prefix}:clients" events with the number of connected remotes.
+	Prefix string
+}
+
+// Remote lets a phone browser on the local network act as a remote control
+// or second screen for the app. It serves a remote UI whose glaze.on and
+// glaze.emit share events with the window: events sent with Emit reach
+// remotes, and events remotes emit reach the page's listeners and OnEvent.
+// Remotes pair by opening a URL carrying a token, typically by scanning a
+// QR code the app shows. The methods of a Remote may be called from any
+// goroutine.
+type Remote struct {
+	w    WebView
+	opts RemoteOptions
+	ln   net.Listener
+	srv  *http.Server
+	url  string
+
+	mu      sync.Mutex
+	clients map[*remoteConn]bool
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// remoteConn is a connected remote. Events for it wait in out and are
+// written by its own goroutine, so a slow phone never blocks Emit.
+type remoteConn struct {
+	ws   *wsConn
+	out  chan []byte
+	once sync.Once
+	done chan struct{}
+}
+
+// send queues msg for the remote, or disconnects it when its queue is full.
+func (c *remoteConn) send(msg []byte) {
+	select {
+	case <-c.done:
+	case c.out <- msg:
+	default:
+		// A remote that cannot keep up is dropped; it reconnects.
+		c.close()
+	}
+}
+
+// writeLoop writes queued events until the remote is closed or a write
+// fails or times out.
+func (c *remoteConn) writeLoop() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case msg := <-c.out:
+			if err := c.ws.write(wsText, msg); err != nil {
+				c.close()
+				return
+			}
+		}
+	}
+}
+
+func (c *remoteConn) close() {
+	c.once.Do(func() {
+		close(c.done)
+		c.ws.Close()
+	})
+}
+
+// StartRemote starts serving remotes for w:
+//
+//	r, err := glaze.StartRemote(w, glaze.RemoteOptions{
+//		Handler: http.FileServerFS(remoteUI),
+//		Topics:  []string{"player:*"},
+//		Prefix:  "remote",
+//	})
+//	defer r.Close()
+//
+//	// JavaScript, in the desktop page
+//	const { qr } = await remote_pairing();
+//	pairing.innerHTML = qr;
+//
+//	// JavaScript, in the remote UI
+//	glaze.on("player:state", render);
+//	play.onclick = () => glaze.emit("player:toggle");
+//
+// The connection is plain HTTP on the local network, authorized by the
+// token. A window has at most one Remote. Like Bind, StartRemote must be
+// called from the UI thread when opts.Prefix is set.
+func StartRemote(w WebView, opts RemoteOptions) (*Remote, error) {
+	if w == nil {
+		return nil, errors.New("webview: StartRemote requires a non-nil WebView")
+	}
+	if opts.Addr == "" {
+		opts.Addr = ":0"
+	}
+	if opts.Token == "" {
+		var key [24]byte
+		if _, err := rand.Read(key[:]); err != nil {
+			return nil, fmt.Errorf("webview: start remote: %w", err)
+		}
+		opts.Token = base64.RawURLEncoding.EncodeToString(key[:])
+	}
+	r := &Remote{w: w, opts: opts, clients: make(map[*remoteConn]bool)}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	if b.remote != nil {
+		b.mu.Unlock()
+		return nil, errors.New("webview: a remote is already started for this window")
+	}
+	b.remote = r
+	b.mu.Unlock()
+
+	ln, err := net.Listen("tcp", opts.Addr)
+	if err != nil {
+		r.detach()
+		return nil, fmt.Errorf("webview: start remote: %w", err)
+	}
+	r.ln = ln
+	host := "127.0.0.1"
+	if ips := localIPv4(); len(ips) > 0 {
+		host = ips[0].String()
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	r.url = "http://" + net.JoinHostPort(host, strconv.Itoa(port)) + "/?token=" + url.QueryEscape(opts.Token)
+
+	if opts.Prefix != "" {
+		if err := w.Bind(opts.Prefix+"_pairing", r.Pairing); err != nil {
+			ln.Close()
+			r.detach()
+			return nil, err
+		}
+	}
+	r.srv = &http.Server{Handler: r}
+	go func() { _ = r.srv.Serve(ln) }()
+	return r, nil
+}
+
+// URL returns the pairing URL, which carries the token.
+func (r *Remote) URL() string {
+	return r.url
+}
+
+// Pairing returns the pairing URL and its QR code.
+func (r *Remote) Pairing() (RemotePairing, error) {
+	qr, err := QRCodeSVG(r.url)
+	if err != nil {
+		return RemotePairing{}, err
+	}
+	return RemotePairing{URL: r.url, QR: qr}, nil
+}
+
+// Clients returns the number of connected remotes.
+func (r *Remote) Clients() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.clients)
+}
+
+// Close disconnects the remotes and stops serving.
+func (r *Remote) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	clients := make([]*remoteConn, 0, len(r.clients))
+	for c := range r.clients {
+		clients = append(clients, c)
+	}
+	r.mu.Unlock()
+	r.detach()
+	err := r.srv.Close()
+	for _, c := range clients {
+		c.close()
+	}
+	r.wg.Wait()
+	return err
+}
+
+// detach stops the window from forwarding events to r.
+func (r *Remote) detach() {
+	b := bridgeFor(r.w)
+	b.mu.Lock()
+	if b.remote == r {
+		b.remote = nil
+	}
+	b.mu.Unlock()
+}
+
+// shared reports whether event is shared with remotes.
+func (r *Remote) shared(event string) bool {
+	if r.opts.Topics == nil {
+		return true
+	}
+	return slices.ContainsFunc(r.opts.Topics, func(t string) bool {
+		if prefix, ok := strings.CutSuffix(t, "*"); ok {
+			return strings.HasPrefix(event, prefix)
+		}
+		return t == event
+	})
+}
+
+// forward queues event, with its JSON payload, for the remotes but except.
+// It never waits for a remote.
+func (r *Remote) forward(event string, data json.RawMessage, except *remoteConn) {
+	if !r.shared(event) {
+		return
+	}
+	msg, err := json.Marshal(RemoteEvent{Event: event, Payload: data})
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	clients := make([]*remoteConn, 0, len(r.clients))
+	for c := range r.clients {
+		if c != except {
+			clients = append(clients, c)
+		}
+	}
+	r.mu.Unlock()
+	for _, c := range clients {
+		c.send(msg)
+	}
+}
+
+// authorized reports whether req carries the token, in the query or in the
+// cookie set on pairing.
+func (r *Remote) authorized(req *http.Request) bool {
+	token := req.URL.Query().Get("token")
+	if token == "" {
+		if c, err := req.Cookie(remoteCookie); err == nil {
+			token = c.Value
+		}
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(r.opts.Token)) == 1
+}
+
+func (r *Remote) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if !r.authorized(req) {
+		http.Error(rw, "pair this device by scanning the code shown in the app", http.StatusUnauthorized)
+		return
+	}
+	if req.URL.Query().Has("token") {
+		http.SetCookie(rw, &http.Cookie{
+			Name:     remoteCookie,
+			Value:    r.opts.Token,
+			Path:     "/",
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		if req.Method == http.MethodGet && req.URL.Path != remoteSocketPath {
+			// Keep the token out of the address bar and history.
+			u := *req.URL
+			q := u.Query()
+			q.Del("token")
+			u.RawQuery = q.Encode()
+			http.Redirect(rw, req, u.RequestURI(), http.StatusSeeOther)
+			return
+		}
+	}
+	switch req.URL.Path {
+	case remoteSocketPath:
+		r.serveSocket(rw, req)
+	case remoteScriptPath:
+		rw.Header().Set("Content-Type", "text/javascript; charset=utf-8")
+		_, _ = io.WriteString(rw, remoteJS)
+	default:
+		if r.opts.Handler != nil {
+			r.opts.Handler.ServeHTTP(rw, req)
+			return
+		}
+		rw.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = io.WriteString(rw, remotePage)
+	}
+}
+
+func (r *Remote) serveSocket(rw http.ResponseWriter, req *http.Request) {
+	ws, err := upgradeWebSocket(rw, req)
+	if err != nil {
+		return
+	}
+	ws.writeTimeout = remoteWriteTimeout
+	c := &remoteConn{ws: ws, out: make(chan []byte, remoteQueue), done: make(chan struct{})}
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		ws.Close()
+		return
+	}
+	r.clients[c] = true
+	r.wg.Add(1)
+	n := len(r.clients)
+	r.mu.Unlock()
+	go c.writeLoop()
+	r.clientsChanged(n)
+
+	defer func() {
+		c.close()
+		r.mu.Lock()
+		delete(r.clients, c)
+		n := len(r.clients)
+		closed := r.closed
+		r.mu.Unlock()
+		if !closed {
+			r.clientsChanged(n)
+		}
+		r.wg.Done()
+	}()
+	for {
+		msg, err := ws.read()
+		if err != nil {
+			return
+		}
+		var ev RemoteEvent
+		if err := json.Unmarshal(msg, &ev); err != nil || ev.Event == "" || !r.shared(ev.Event) {
+			continue
+		}
+		if len(ev.Payload) == 0 {
+			ev.Payload = json.RawMessage("null")
+		}
+		bridgeFor(r.w).emitPage(ev.Event, ev.Payload)
+		r.forward(ev.Event, ev.Payload, c)
+		if r.opts.OnEvent != nil {
+			r.opts.OnEvent(ev)
+		}
+	}
+}
+
+func (r *Remote) clientsChanged(n int) {
+	if r.opts.Prefix != "" {
+		bridgeFor(r.w).emitPage(r.opts.Prefix+":clients", []byte(strconv.Itoa(n)))
+	}
+}
@@ -0,0 +1,236 @@
+package glaze
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func startTestRemote(t *testing.T, w WebView, opts RemoteOptions) *Remote {
+	t.Helper()
+	opts.Addr = "127.0.0.1:0"
+	r, err := StartRemote(w, opts)
+	if err != nil {
+		t.Fatalf("StartRemote: %v", err)
+	}
+	t.Cleanup(func() { _ = r.Close() })
+	return r
+}
+
+// remoteClient is a WebSocket client connected to a Remote.
+type remoteClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func dialRemote(t *testing.T, r *Remote, token, origin string) (*remoteClient, int) {
+	t.Helper()
+	addr := r.ln.Addr().String()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	req := "GET " + remoteSocketPath + "?token=" + url.QueryEscape(token) + " HTTP/1.1\r\n" +
+		"Host: " + addr + "\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n"
+	if origin != "" {
+		req += "Origin: " + origin + "\r\n"
+	}
+	if _, err := io.WriteString(conn, req+"\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+	if resp.StatusCode == http.StatusSwitchingProtocols {
+		if got := resp.Header.Get("Sec-WebSocket-Accept"); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+			t.Fatalf("Sec-WebSocket-Accept = %q", got)
+		}
+	}
+	return &remoteClient{conn: conn, r: br}, resp.StatusCode
+}
+
+func (c *remoteClient) send(t *testing.T, event string, payload any) {
+	t.Helper()
+	data, _ := json.Marshal(map[string]any{"event": event, "payload": payload})
+	if err := writeWSFrame(c.conn, wsText, data, true); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+}
+
+func (c *remoteClient) receive(t *testing.T) RemoteEvent {
+	t.Helper()
+	_ = c.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, op, masked, payload, err := readWSFrame(c.r)
+	if err != nil || op != wsText || masked {
+		t.Fatalf("receive: op %d, masked %v, %v", op, masked, err)
+	}
+	var ev RemoteEvent
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		t.Fatalf("decode %s: %v", payload, err)
+	}
+	return ev
+}
+
+func TestRemoteSharesEvents(t *testing.T) {
+	w := &fakeWebView{}
+	got := make(chan RemoteEvent, 1)
+	r := startTestRemote(t, w, RemoteOptions{
+		Token:   "secret",
+		Topics:  []string{"player:*", "ping"},
+		OnEvent: func(ev RemoteEvent) { got <- ev },
+	})
+	a, status := dialRemote(t, r, "secret", "")
+	if status != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake status = %d", status)
+	}
+	b, _ := dialRemote(t, r, "secret", "")
+	waitFor(t, func() bool { return r.Clients() == 2 })
+
+	if err := Emit(w, "player:state", map[string]bool{"playing": true}); err != nil {
+		t.Fatal(err)
+	}
+	_ = Emit(w, "secret:thing", 1) // not shared
+	_ = Emit(w, "ping", 2)
+	for _, c := range []*remoteClient{a, b} {
+		if ev := c.receive(t); ev.Event != "player:state" || string(ev.Payload) != `{"playing":true}` {
+			t.Fatalf("remote received %+v", ev)
+		}
+		if ev := c.receive(t); ev.Event != "ping" {
+			t.Fatalf("remote received %+v, want ping", ev)
+		}
+	}
+
+	a.send(t, "player:toggle", nil)
+	select {
+	case ev := <-got:
+		if ev.Event != "player:toggle" {
+			t.Fatalf("OnEvent = %+v", ev)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("OnEvent not called")
+	}
+	if ev := b.receive(t); ev.Event != "player:toggle" {
+		t.Fatalf("other remote received %+v", ev)
+	}
+	w.mu.Lock()
+	last := w.evals[len(w.evals)-1]
+	w.mu.Unlock()
+	if last != `window.glaze._emit("player:toggle", null);` {
+		t.Fatalf("page eval = %q", last)
+	}
+
+	a.send(t, "app:quit", nil) // not shared
+	a.send(t, "ping", 3)
+	if ev := <-got; ev.Event != "ping" {
+		t.Fatalf("OnEvent = %+v, want the unshared event dropped", ev)
+	}
+}
+
+func TestRemoteAuthorization(t *testing.T) {
+	r := startTestRemote(t, &fakeWebView{}, RemoteOptions{})
+	if _, status := dialRemote(t, r, "wrong", ""); status != http.StatusUnauthorized {
+		t.Fatalf("wrong token status = %d, want 401", status)
+	}
+	token := r.opts.Token
+	if _, status := dialRemote(t, r, token, "http://evil.example"); status != http.StatusForbidden {
+		t.Fatalf("cross-origin status = %d, want 403", status)
+	}
+
+	// Pairing sets a cookie and drops the token from the address.
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar}
+	pairing := "http://" + r.ln.Addr().String() + "/?token=" + url.QueryEscape(token)
+	resp, err := client.Get(pairing)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.Request.URL.RawQuery != "" || !strings.Contains(string(body), remoteScriptPath) {
+		t.Fatalf("pairing: %d at %s: %.60s", resp.StatusCode, resp.Request.URL, body)
+	}
+	resp, err = client.Get("http://" + r.ln.Addr().String() + remoteScriptPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("script with cookie status = %d", resp.StatusCode)
+	}
+	resp, err = http.Get("http://" + r.ln.Addr().String() + remoteScriptPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("script without token status = %d, want 401", resp.StatusCode)
+	}
+}
+
+func TestRemotePrefix(t *testing.T) {
+	w := &fakeWebView{}
+	r := startTestRemote(t, w, RemoteOptions{Prefix: "remote"})
+	if !strings.Contains(r.URL(), ":"+strconv.Itoa(r.ln.Addr().(*net.TCPAddr).Port)+"/?token=") {
+		t.Fatalf("URL = %q", r.URL())
+	}
+	got, err := w.call(t, "remote_pairing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := got.(RemotePairing)
+	if p.URL != r.URL() || !strings.HasPrefix(p.QR, "<svg") {
+		t.Fatalf("pairing = %+v", p)
+	}
+	if _, err := StartRemote(w, RemoteOptions{}); err == nil {
+		t.Fatal("second remote for the window started")
+	}
+
+	dialRemote(t, r, r.opts.Token, "")
+	waitFor(t, func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return len(w.evals) > 0 && w.evals[len(w.evals)-1] == `window.glaze._emit("remote:clients", 1);`
+	})
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if r.Clients() != 0 {
+		t.Fatalf("Clients after Close = %d", r.Clients())
+	}
+	// Closing frees the window for another remote.
+	startTestRemote(t, w, RemoteOptions{})
+}
+
+func TestRemoteDropsStalledClient(t *testing.T) {
+	w := &fakeWebView{}
+	r := startTestRemote(t, w, RemoteOptions{Token: "secret"})
+	dialRemote(t, r, "secret", "") // never reads
+	waitFor(t, func() bool { return r.Clients() == 1 })
+
+	payload := strings.Repeat("x", 64<<10)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 4 * remoteQueue {
+			_ = Emit(w, "frame", payload)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Emit blocked on a remote that stopped reading")
+	}
+	waitFor(t, func() bool { return r.Clients() == 0 })
+}
@@ -0,0 +1,174 @@
+package glaze
+
+import (
+	"bytes"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RenderCacheOptions configures a RenderCache.
+type RenderCacheOptions struct {
+	// TTL bounds how long a cached response is reused. Zero keeps responses
+	// until they are invalidated.
+	TTL time.Duration
+
+	// Match reports whether a GET request may be served from the cache.
+	// Defaults to caching every GET request.
+	Match func(r *http.Request) bool
+}
+
+// RenderCache is an http.Handler that keeps rendered responses in memory so
+// template-heavy pages render instantly on repeat visits within a session.
+//
+// Only successful (200) GET responses are cached, keyed by path and query.
+// Responses that set cookies or send Cache-Control no-store or private are
+// never stored. Because an AppWindow server has exactly one client, request
+// headers are not part of the key.
+//
+// Call Invalidate, InvalidatePrefix or InvalidateAll after changing the data
+// a page renders.
+type RenderCache struct {
+	next http.Handler
+	opts RenderCacheOptions
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cachedResponse
+}
+
+type cachedResponse struct {
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// NewRenderCache wraps next with a render cache.
+func NewRenderCache(next http.Handler, opts RenderCacheOptions) *RenderCache {
+	return &RenderCache{
+		next:    next,
+		opts:    opts,
+		now:     time.Now,
+		entries: make(map[string]cachedResponse),
+	}
+}
+
+func (c *RenderCache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet || (c.opts.Match != nil && !c.opts.Match(r)) {
+		c.next.ServeHTTP(w, r)
+		return
+	}
+
+	key := r.URL.RequestURI()
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && !entry.expires.IsZero() && c.now().After(entry.expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mu.Unlock()
+
+	if ok {
+		// Each response gets its own copy, so callers changing their
+		// headers in place leave the entry alone.
+		for k, v := range entry.header {
+			w.Header()[k] = slices.Clone(v)
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(entry.body)
+		return
+	}
+
+	rec := &cacheRecorder{ResponseWriter: w, status: http.StatusOK}
+	c.next.ServeHTTP(rec, r)
+	if !rec.cacheable() {
+		return
+	}
+
+	// Clone deep-copies the values, which callers may still change.
+	entry = cachedResponse{header: w.Header().Clone(), body: rec.body.Bytes()}
+	if c.opts.TTL > 0 {
+		entry.expires = c.now().Add(c.opts.TTL)
+	}
+	c.mu.Lock()
+	c.entries[key] = entry
+	c.mu.Unlock()
+}
+
+// Invalidate drops the cached responses for the given request URIs
+// (path plus optional query, e.g. "/notes?page=2").
+func (c *RenderCache) Invalidate(uris ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, uri := range uris {
+		delete(c.entries, uri)
+	}
+}
+
+// InvalidatePrefix drops every cached response whose request URI starts with
+// prefix, e.g. "/notes" after a note changed.
+func (c *RenderCache) InvalidatePrefix(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for uri := range c.entries {
+		if strings.HasPrefix(uri, prefix) {
+			delete(c.entries, uri)
+		}
+	}
+}
+
+// InvalidateAll empties the cache.
+func (c *RenderCache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.entries)
+}
+
+// cacheRecorder passes a response through to the client while keeping a copy
+// of its status and body.
+type cacheRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *cacheRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *cacheRecorder) Write(p []byte) (int, error) {
+	r.wroteHeader = true
+	r.body.Write(p)
+	return r.ResponseWriter.Write(p)
+}
+
+// Flush keeps streamed responses streaming through the cache.
+func (r *cacheRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *cacheRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *cacheRecorder) cacheable() bool {
+	if r.status != http.StatusOK {
+		return false
+	}
+	h := r.Header()
+	if h.Get("Set-Cookie") != "" {
+		return false
+	}
+	cc := strings.ToLower(h.Get("Cache-Control"))
+	return !strings.Contains(cc, "no-store") && !strings.Contains(cc, "private")
+}
@@ -0,0 +1,157 @@
+package glaze
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// countingHandler renders the number of times it has been called.
+func countingHandler(calls *int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*calls++
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+			return
+		case "/private":
+			w.Header().Set("Cache-Control", "private")
+		case "/cookie":
+			http.SetCookie(w, &http.Cookie{Name: "s", Value: "1"})
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "render %d", *calls)
+	})
+}
+
+func get(t *testing.T, h http.Handler, method, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+func TestRenderCacheServesRepeatVisits(t *testing.T) {
+	var calls int
+	c := NewRenderCache(countingHandler(&calls), RenderCacheOptions{})
+
+	first := get(t, c, http.MethodGet, "/notes")
+	second := get(t, c, http.MethodGet, "/notes")
+	if calls != 1 {
+		t.Fatalf("handler calls = %d, want 1", calls)
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Fatalf("cached body = %q, want %q", second.Body.String(), first.Body.String())
+	}
+	if got := second.Header().Get("Content-Type"); got != "text/html" {
+		t.Fatalf("cached Content-Type = %q, want text/html", got)
+	}
+
+	get(t, c, http.MethodGet, "/notes?page=2")
+	if calls != 2 {
+		t.Fatalf("handler calls = %d, want query to be a separate entry", calls)
+	}
+}
+
+func TestRenderCacheCopiesHeaders(t *testing.T) {
+	var calls int
+	cache := NewRenderCache(countingHandler(&calls), RenderCacheOptions{})
+	// A middleware outside the cache rewrites the header value in place.
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cache.ServeHTTP(w, r)
+		w.Header()["Content-Type"][0] = "text/plain"
+	})
+
+	get(t, h, http.MethodGet, "/notes") // stored
+	get(t, h, http.MethodGet, "/notes") // served
+	if got := get(t, cache, http.MethodGet, "/notes").Header().Get("Content-Type"); got != "text/html" {
+		t.Fatalf("cached Content-Type = %q, want text/html", got)
+	}
+	if calls != 1 {
+		t.Fatalf("handler calls = %d, want 1", calls)
+	}
+}
+
+func TestRenderCacheSkipsUncacheable(t *testing.T) {
+	tests := []struct {
+		method string
+		target string
+	}{
+		{http.MethodPost, "/notes"},
+		{http.MethodGet, "/missing"},
+		{http.MethodGet, "/private"},
+		{http.MethodGet, "/cookie"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+tt.target, func(t *testing.T) {
+			var calls int
+			c := NewRenderCache(countingHandler(&calls), RenderCacheOptions{})
+			get(t, c, tt.method, tt.target)
+			get(t, c, tt.method, tt.target)
+			if calls != 2 {
+				t.Fatalf("handler calls = %d, want 2", calls)
+			}
+		})
+	}
+}
+
+func TestRenderCacheMatch(t *testing.T) {
+	var calls int
+	c := NewRenderCache(countingHandler(&calls), RenderCacheOptions{
+		Match: func(r *http.Request) bool { return r.URL.Path != "/live" },
+	})
+	get(t, c, http.MethodGet, "/live")
+	get(t, c, http.MethodGet, "/live")
+	if calls != 2 {
+		t.Fatalf("handler calls = %d, want 2", calls)
+	}
+}
+
+func TestRenderCacheInvalidation(t *testing.T) {
+	var calls int
+	c := NewRenderCache(countingHandler(&calls), RenderCacheOptions{})
+	for _, p := range []string{"/", "/notes", "/notes/1"} {
+		get(t, c, http.MethodGet, p)
+	}
+
+	c.Invalidate("/")
+	get(t, c, http.MethodGet, "/")
+	if calls != 4 {
+		t.Fatalf("after Invalidate calls = %d, want 4", calls)
+	}
+
+	c.InvalidatePrefix("/notes")
+	get(t, c, http.MethodGet, "/notes")
+	get(t, c, http.MethodGet, "/notes/1")
+	get(t, c, http.MethodGet, "/")
+	if calls != 6 {
+		t.Fatalf("after InvalidatePrefix calls = %d, want 6", calls)
+	}
+
+	c.InvalidateAll()
+	get(t, c, http.MethodGet, "/")
+	if calls != 7 {
+		t.Fatalf("after InvalidateAll calls = %d, want 7", calls)
+	}
+}
+
+func TestRenderCacheTTL(t *testing.T) {
+	var calls int
+	now := time.Unix(0, 0)
+	c := NewRenderCache(countingHandler(&calls), RenderCacheOptions{TTL: time.Minute})
+	c.now = func() time.Time { return now }
+
+	get(t, c, http.MethodGet, "/")
+	now = now.Add(30 * time.Second)
+	get(t, c, http.MethodGet, "/")
+	if calls != 1 {
+		t.Fatalf("calls before expiry = %d, want 1", calls)
+	}
+	now = now.Add(time.Minute)
+	get(t, c, http.MethodGet, "/")
+	if calls != 2 {
+		t.Fatalf("calls after expiry = %d, want 2", calls)
+	}
+}
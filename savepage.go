@@ -0,0 +1,252 @@
+package glaze
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"os"
+	"strconv"
+	"time"
+)
+
+// PageFormat is a file format SavePage writes.
+type PageFormat string
+
+// Formats supported by SavePage.
+const (
+	// PageHTML is a single HTML file with images inlined as data URLs.
+	PageHTML PageFormat = "html"
+
+	// PageMHTML is a MIME multipart archive of the page and its images,
+	// as saved by browsers with "Webpage, single file".
+	PageMHTML PageFormat = "mhtml"
+)
+
+// savePageJS serializes the document for SavePage: stylesheets whose rules
+// can be read are inlined and the others kept as absolute links, scripts
+// dropped, canvases replaced by their pixels and form values preserved.
+// Images are fetched; with inline set they become data URLs, otherwise
+// they are returned as resources next to the HTML.
+const savePageJS = `(function (inline) {
+  var doc = document.documentElement;
+  var clone = doc.cloneNode(true);
+
+  var css = "";
+  var inlined = [];
+  Array.prototype.forEach.call(document.styleSheets, function (sheet) {
+    try {
+      var text = "";
+      Array.prototype.forEach.call(sheet.cssRules, function (rule) { text += rule.cssText + "\n"; });
+      css += text;
+      inlined.push(sheet.ownerNode);
+    } catch (e) { /* cross-origin stylesheet, kept as a link */ }
+  });
+  var sheets = doc.querySelectorAll("link[rel=stylesheet], style");
+  Array.prototype.forEach.call(clone.querySelectorAll("link[rel=stylesheet], style"), function (el, i) {
+    if (inlined.indexOf(sheets[i]) >= 0) {
+      el.parentNode.removeChild(el);
+    } else if (el.tagName === "LINK") {
+      el.setAttribute("href", sheets[i].href);
+    }
+  });
+  Array.prototype.forEach.call(clone.querySelectorAll("script"), function (el) {
+    el.parentNode.removeChild(el);
+  });
+  var style = document.createElement("style");
+  style.textContent = css;
+  (clone.querySelector("head") || clone).appendChild(style);
+
+  var canvases = doc.querySelectorAll("canvas");
+  Array.prototype.forEach.call(clone.querySelectorAll("canvas"), function (el, i) {
+    try {
+      var img = document.createElement("img");
+      img.src = canvases[i].toDataURL();
+      img.setAttribute("style", el.getAttribute("style") || "");
+      img.width = canvases[i].clientWidth;
+      img.height = canvases[i].clientHeight;
+      el.parentNode.replaceChild(img, el);
+    } catch (e) { /* tainted canvas */ }
+  });
+  var fields = doc.querySelectorAll("input, textarea, select");
+  Array.prototype.forEach.call(clone.querySelectorAll("input, textarea, select"), function (el, i) {
+    var src = fields[i];
+    if (src.type === "checkbox" || src.type === "radio") {
+      if (src.checked) { el.setAttribute("checked", ""); } else { el.removeAttribute("checked"); }
+    } else if (el.tagName === "TEXTAREA") {
+      el.textContent = src.value;
+    } else {
+      el.setAttribute("value", src.value);
+    }
+  });
+
+  var resources = [];
+  var fetched = {};
+  function load(url) {
+    if (!fetched[url]) {
+      fetched[url] = fetch(url).then(function (r) {
+        if (!r.ok) { throw new Error(r.status); }
+        return r.blob();
+      }).then(function (blob) {
+        return new Promise(function (resolve, reject) {
+          var reader = new FileReader();
+          reader.onload = function () { resolve(reader.result); };
+          reader.onerror = reject;
+          reader.readAsDataURL(blob);
+        });
+      }).then(function (dataURL) {
+        if (!inline) {
+          var comma = dataURL.indexOf(",");
+          resources.push({ url: url, type: dataURL.slice(5, dataURL.indexOf(";")), data: dataURL.slice(comma + 1) });
+        }
+        return dataURL;
+      }, function () { return null; /* unreachable image: keep the link */ });
+    }
+    return fetched[url];
+  }
+  var images = doc.querySelectorAll("img");
+  var pending = Array.prototype.map.call(clone.querySelectorAll("img"), function (el, i) {
+    var url = images[i] ? images[i].currentSrc || images[i].src : el.src;
+    el.removeAttribute("srcset");
+    if (!url || url.indexOf("data:") === 0) { return null; }
+    el.setAttribute("src", url);
+    return load(url).then(function (dataURL) {
+      if (inline && dataURL) { el.setAttribute("src", dataURL); }
+    });
+  });
+  return Promise.all(pending).then(function () {
+    return {
+      url: location.href,
+      title: document.title,
+      html: "<!DOCTYPE html>\n" + clone.outerHTML,
+      resources: resources
+    };
+  });
+})`
+
+// pageSnapshot is the document savePageJS returns.
+type pageSnapshot struct {
+	URL       string         `json:"url"`
+	Title     string         `json:"title"`
+	HTML      string         `json:"html"`
+	Resources []pageResource `json:"resources"`
+}
+
+// pageResource is an image of a pageSnapshot, base64-encoded.
+type pageResource struct {
+	URL  string `json:"url"`
+	Type string `json:"type"`
+	Data string `json:"data"`
+}
+
+// SavePage writes the current page of w to path in format, so reports or
+// sessions generated in the app can be archived and opened in a browser.
+// Scripts are dropped and the document is saved as currently rendered,
+// with form values, canvases, same-origin stylesheets and images included.
+// Cross-origin stylesheets and resources referenced from CSS, such as
+// background images and fonts, are kept as links.
+//
+// It must be called from a background goroutine, never from the UI thread.
+func SavePage(w WebView, path string, format PageFormat) error {
+	if format != PageHTML && format != PageMHTML {
+		return fmt.Errorf("webview: unknown page format %q", format)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultEvalTimeout)
+	defer cancel()
+
+	raw, err := bridgeFor(w).eval(ctx, savePageJS+"("+strconv.FormatBool(format == PageHTML)+")")
+	if err != nil {
+		return fmt.Errorf("webview: save page: %w", err)
+	}
+	var page pageSnapshot
+	if err := json.Unmarshal(raw, &page); err != nil {
+		return fmt.Errorf("webview: save page: %w", err)
+	}
+	data := []byte(page.HTML)
+	if format == PageMHTML {
+		if data, err = page.mhtml(time.Now()); err != nil {
+			return fmt.Errorf("webview: save page: %w", err)
+		}
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("webview: save page: %w", err)
+	}
+	return nil
+}
+
+// mhtml encodes p as an MHTML archive dated date.
+func (p pageSnapshot) mhtml(date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+
+	part, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/html; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+		"Content-Location":          {p.URL},
+	})
+	if err != nil {
+		return nil, err
+	}
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(p.HTML)); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+
+	for _, r := range p.Resources {
+		data, err := base64.StdEncoding.DecodeString(r.Data)
+		if err != nil {
+			return nil, fmt.Errorf("resource %s: %w", r.URL, err)
+		}
+		typ := r.Type
+		if typ == "" {
+			typ = "application/octet-stream"
+		}
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {typ},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Location":          {r.URL},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64Lines(part, data); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "From: <Saved by glaze>\r\n")
+	fmt.Fprintf(&out, "Snapshot-Content-Location: %s\r\n", p.URL)
+	fmt.Fprintf(&out, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", p.Title))
+	fmt.Fprintf(&out, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&out, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&out, "Content-Type: multipart/related; type=\"text/html\"; boundary=\"%s\"\r\n\r\n", mw.Boundary())
+	out.Write(body.Bytes())
+	return out.Bytes(), nil
+}
+
+// writeBase64Lines writes data base64-encoded in lines of 76 characters, as
+// MIME requires.
+func writeBase64Lines(w io.Writer, data []byte) error {
+	enc := base64.StdEncoding.EncodeToString(data)
+	for len(enc) > 0 {
+		n := min(len(enc), 76)
+		if _, err := w.Write([]byte(enc[:n] + "\r\n")); err != nil {
+			return err
+		}
+		enc = enc[n:]
+	}
+	return nil
+}
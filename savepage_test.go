@@ -0,0 +1,107 @@
+package glaze
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSavePage(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	var inline []bool
+	w.answerEvals(t, func(src string) (any, error) {
+		inline = append(inline, strings.HasSuffix(src, "})(true)"))
+		return pageSnapshot{
+			URL:       "http://127.0.0.1/report",
+			Title:     "Report",
+			HTML:      "<!DOCTYPE html>\n<html><body><img src=\"http://127.0.0.1/a.png\"></body></html>",
+			Resources: []pageResource{{URL: "http://127.0.0.1/a.png", Type: "image/png", Data: "iVBORw0K"}},
+		}, nil
+	})
+	dir := t.TempDir()
+
+	htmlPath := filepath.Join(dir, "report.html")
+	if err := SavePage(w, htmlPath, PageHTML); err != nil {
+		t.Fatalf("SavePage(html) unexpected error: %v", err)
+	}
+	if data, _ := os.ReadFile(htmlPath); !strings.HasPrefix(string(data), "<!DOCTYPE html>") {
+		t.Errorf("saved HTML = %q", data)
+	}
+
+	mhtPath := filepath.Join(dir, "report.mhtml")
+	if err := SavePage(w, mhtPath, PageMHTML); err != nil {
+		t.Fatalf("SavePage(mhtml) unexpected error: %v", err)
+	}
+	if len(inline) != 2 || !inline[0] || inline[1] {
+		t.Errorf("inline images requested = %v, want [true false]", inline)
+	}
+
+	data, err := os.ReadFile(mhtPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("read MHTML: %v", err)
+	}
+	if msg.Header.Get("Snapshot-Content-Location") != "http://127.0.0.1/report" || msg.Header.Get("Subject") != "Report" {
+		t.Errorf("MHTML header = %v", msg.Header)
+	}
+	_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	var parts []string
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		body, _ := io.ReadAll(p)
+		parts = append(parts, p.Header.Get("Content-Location")+" "+p.Header.Get("Content-Type"))
+		if p.Header.Get("Content-Type") == "image/png" && strings.TrimSpace(string(body)) != "iVBORw0K" {
+			t.Errorf("image part = %q", body)
+		}
+	}
+	want := []string{"http://127.0.0.1/report text/html; charset=utf-8", "http://127.0.0.1/a.png image/png"}
+	if strings.Join(parts, "|") != strings.Join(want, "|") {
+		t.Errorf("parts = %q, want %q", parts, want)
+	}
+
+	if err := SavePage(w, filepath.Join(dir, "x"), "pdf"); err == nil {
+		t.Error("SavePage() with unknown format returned no error")
+	}
+}
+
+func TestSavePageKeepsUnreadableStylesheets(t *testing.T) {
+	// Cross-origin sheets throw on cssRules; only the inlined ones may be
+	// dropped from the copy, the rest must stay as links.
+	if strings.Contains(savePageJS, `"script, link[rel=stylesheet], style"`) {
+		t.Error("savePageJS removes every stylesheet link, including unreadable ones")
+	}
+	if !strings.Contains(savePageJS, "inlined.push(sheet.ownerNode)") {
+		t.Error("savePageJS does not track which stylesheets were inlined")
+	}
+}
+
+func TestWriteBase64Lines(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeBase64Lines(&buf, bytes.Repeat([]byte{0xff}, 120)); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	if len(lines) != 3 || len(lines[0]) != 76 || len(lines[2]) != 8 {
+		t.Errorf("lines = %q", lines)
+	}
+}
@@ -0,0 +1,303 @@
+package glaze
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/aztec"
+	"github.com/makiuchi-d/gozxing/datamatrix"
+	"github.com/makiuchi-d/gozxing/oned"
+	"github.com/makiuchi-d/gozxing/qrcode"
+)
+
+// scanBindingName is the hidden binding the page sends camera frames to.
+const scanBindingName = "__glaze_scan"
+
+// defaultScanInterval is how often a frame is decoded when ScanOptions does
+// not say.
+const defaultScanInterval = 250 * time.Millisecond
+
+// scanFrameSize is the largest side, in pixels, of a frame sent to Go; the
+// camera image is scaled down to it, which keeps codes readable at a
+// fraction of the transfer and decoding cost.
+const scanFrameSize = 640
+
+// scanRepeat is how long a code must be out of view before it is reported
+// again.
+const scanRepeat = 2 * time.Second
+
+// scannerJS opens the camera with getUserMedia and, every interval, draws a
+// frame to a canvas and sends its grayscale pixels to Go, which decodes
+// them and answers with the codes that came into view. The next frame is
+// taken only once Go has answered, so a slow decode never queues frames.
+// The promise settles once the camera is running, or with the reason it
+// cannot be.
+const scannerJS = `(function (opts) {
+  var glaze = window.glaze = window.glaze || {};
+  if (!navigator.mediaDevices || !navigator.mediaDevices.getUserMedia) {
+    throw new Error("camera access is not available");
+  }
+  if (glaze._scanner) { glaze._scanner.stop(); }
+  return navigator.mediaDevices.getUserMedia({ video: { facingMode: "environment" }, audio: false }).then(function (stream) {
+    var video = opts.preview ? document.querySelector(opts.preview) : null;
+    if (!video) { video = document.createElement("video"); }
+    video.muted = true;
+    video.playsInline = true;
+    video.srcObject = stream;
+    var canvas = document.createElement("canvas");
+    var ctx = canvas.getContext("2d", { willReadFrequently: true });
+    var stopped = false;
+    var timer = null;
+    function frame() {
+      var w = video.videoWidth, h = video.videoHeight;
+      if (!w || !h) { return null; }
+      var scale = Math.min(1, opts.size / Math.max(w, h));
+      w = Math.max(1, Math.round(w * scale));
+      h = Math.max(1, Math.round(h * scale));
+      canvas.width = w;
+      canvas.height = h;
+      ctx.drawImage(video, 0, 0, w, h);
+      var rgba = ctx.getImageData(0, 0, w, h).data;
+      var gray = new Uint8Array(w * h);
+      for (var i = 0, j = 0; j < gray.length; i += 4, j++) {
+        gray[j] = (rgba[i] * 77 + rgba[i + 1] * 150 + rgba[i + 2] * 29) >> 8;
+      }
+      var bin = "";
+      for (var k = 0; k < gray.length; k += 8192) {
+        bin += String.fromCharCode.apply(null, gray.subarray(k, k + 8192));
+      }
+      return { width: w, height: h, pixels: btoa(bin) };
+    }
+    function tick() {
+      if (stopped) { return; }
+      var f = frame();
+      (f ? window.` + scanBindingName + `(f) : Promise.resolve(null)).then(function (codes) {
+        (codes || []).forEach(function (result) {
+          if (glaze._emit) { glaze._emit("scan", result); }
+        });
+      }, function () {}).then(function () {
+        if (!stopped) { timer = setTimeout(tick, opts.interval); }
+      });
+    }
+    glaze._scanner = {
+      stop: function () {
+        stopped = true;
+        clearTimeout(timer);
+        stream.getTracks().forEach(function (track) { track.stop(); });
+        video.srcObject = null;
+        glaze._scanner = null;
+      }
+    };
+    return video.play().then(function () {
+      tick();
+      return true;
+    });
+  });
+})`
+
+// scanReaders are the decoders of the formats StartScanner reads, by their
+// BarcodeDetector names.
+var scanReaders = map[string]func() gozxing.Reader{
+	"aztec":       func() gozxing.Reader { return aztec.NewAztecReader() },
+	"codabar":     oned.NewCodaBarReader,
+	"code_128":    oned.NewCode128Reader,
+	"code_39":     oned.NewCode39Reader,
+	"code_93":     oned.NewCode93Reader,
+	"data_matrix": func() gozxing.Reader { return datamatrix.NewDataMatrixReader() },
+	"ean_13":      oned.NewEAN13Reader,
+	"ean_8":       oned.NewEAN8Reader,
+	"itf":         oned.NewITFReader,
+	"qr_code":     qrcode.NewQRCodeReader,
+	"upc_a":       oned.NewUPCAReader,
+	"upc_e":       oned.NewUPCEReader,
+}
+
+// ScanOptions configures StartScanner.
+type ScanOptions struct {
+	// Formats restricts decoding to these formats, named as in the web's
+	// BarcodeDetector: "qr_code", "aztec", "data_matrix", "code_128",
+	// "code_39", "code_93", "codabar", "ean_13", "ean_8", "itf", "upc_a"
+	// and "upc_e". Empty means all of them; fewer formats decode faster.
+	Formats []string `json:"-"`
+
+	// Interval is the time between decoded frames. Zero means 250ms.
+	Interval time.Duration `json:"-"`
+
+	// Preview is a CSS selector for a <video> element that shows the camera
+	// feed. Empty scans without a preview.
+	Preview string `json:"preview"`
+}
+
+// ScanResult is a code decoded by the scanner.
+type ScanResult struct {
+	Format string `json:"format"`
+	Value  string `json:"value"`
+}
+
+// scanner is the state of the scanner started by StartScanner.
+type scanner struct {
+	fn      func(ScanResult)
+	formats []string
+
+	mu   sync.Mutex
+	seen map[string]time.Time // last sighting, by format and value
+}
+
+// scanFrame is a grayscale camera frame sent by the page, one byte per
+// pixel, row by row.
+type scanFrame struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Pixels []byte `json:"pixels"`
+}
+
+// image returns the frame as an image, sharing its pixels.
+func (f scanFrame) image() *image.Gray {
+	return &image.Gray{Pix: f.Pixels, Stride: f.Width, Rect: image.Rect(0, 0, f.Width, f.Height)}
+}
+
+// StartScanner opens the system camera from the page and calls fn for every
+// QR code or barcode that comes into view. Pages also receive results
+// through glaze.on("scan", cb). A code that stays in view is reported once.
+// Calling StartScanner again restarts the camera with the new options.
+//
+// The page captures frames with getUserMedia, which every supported engine
+// has, and sends them scaled down and in grayscale to Go, where they are
+// decoded; nothing depends on the engine's own barcode support. StartScanner
+// returns an error for an unknown format, or when camera access is not
+// available or is denied. The scanner belongs to the current page and stops
+// when it navigates away.
+//
+// It waits for the camera to start, so it must be called from a background
+// goroutine, never from the UI thread.
+func StartScanner(w WebView, opts ScanOptions, fn func(ScanResult)) error {
+	if opts.Interval <= 0 {
+		opts.Interval = defaultScanInterval
+	}
+	formats := slices.Clone(opts.Formats)
+	if len(formats) == 0 {
+		for name := range scanReaders {
+			formats = append(formats, name)
+		}
+		slices.Sort(formats)
+	}
+	for _, name := range formats {
+		if scanReaders[name] == nil {
+			return fmt.Errorf("webview: start scanner: unknown format %q", name)
+		}
+	}
+	args, err := json.Marshal(struct {
+		ScanOptions
+		Interval int64 `json:"interval"`
+		Size     int   `json:"size"`
+	}{opts, opts.Interval.Milliseconds(), scanFrameSize})
+	if err != nil {
+		return fmt.Errorf("webview: start scanner: %w", err)
+	}
+
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.scan = &scanner{fn: fn, formats: formats, seen: make(map[string]time.Time)}
+	b.mu.Unlock()
+
+	bindErr := make(chan error, 1)
+	w.Dispatch(func() {
+		b.injectScript("events", eventsJS)
+		bindErr <- b.bindHidden(scanBindingName, b.handleScan)
+	})
+	if err := <-bindErr; err != nil {
+		return fmt.Errorf("webview: start scanner: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), defaultEvalTimeout)
+	defer cancel()
+	if _, err := b.eval(ctx, scannerJS+"("+string(args)+")"); err != nil {
+		return fmt.Errorf("webview: start scanner: %w", err)
+	}
+	return nil
+}
+
+// StopScanner stops the scanner started by StartScanner and releases the
+// camera.
+//
+// It may be called from any goroutine.
+func StopScanner(w WebView) {
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.scan = nil
+	b.mu.Unlock()
+	w.Dispatch(func() {
+		w.Eval("window.glaze && window.glaze._scanner && window.glaze._scanner.stop();")
+	})
+}
+
+// handleScan is bound as scanBindingName. It decodes a frame, calls the
+// scanner's handler for each code that came into view and returns them for
+// the page to emit.
+func (b *bridge) handleScan(f scanFrame) ([]ScanResult, error) {
+	b.mu.Lock()
+	s := b.scan
+	b.mu.Unlock()
+	if s == nil {
+		return nil, nil
+	}
+	if f.Width <= 0 || f.Height <= 0 || f.Width > scanFrameSize || f.Height > scanFrameSize {
+		return nil, fmt.Errorf("webview: scanner frame of %dx%d pixels is out of range", f.Width, f.Height)
+	}
+	if len(f.Pixels) != f.Width*f.Height {
+		return nil, errors.New("webview: scanner frame size does not match its pixels")
+	}
+	fresh := s.fresh(s.decode(f.image()), time.Now())
+	if s.fn != nil {
+		for _, r := range fresh {
+			s.fn(r)
+		}
+	}
+	return fresh, nil
+}
+
+// decode returns the codes of the scanner's formats found in img.
+func (s *scanner) decode(img image.Image) []ScanResult {
+	bmp, err := gozxing.NewBinaryBitmap(gozxing.NewHybridBinarizer(gozxing.NewLuminanceSourceFromImage(img)))
+	if err != nil {
+		return nil
+	}
+	var found []ScanResult
+	for _, name := range s.formats {
+		// A frame without a code of this format is the common case, not an
+		// error worth reporting.
+		res, err := scanReaders[name]().Decode(bmp, nil)
+		if err == nil && res.GetText() != "" {
+			found = append(found, ScanResult{Format: name, Value: res.GetText()})
+		}
+	}
+	return found
+}
+
+// fresh returns the results not seen within scanRepeat of now, and records
+// all of them as seen.
+func (s *scanner) fresh(results []ScanResult, now time.Time) []ScanResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, at := range s.seen {
+		if now.Sub(at) > scanRepeat {
+			delete(s.seen, key)
+		}
+	}
+	var fresh []ScanResult
+	for _, r := range results {
+		key := r.Format + ":" + r.Value
+		if _, ok := s.seen[key]; !ok {
+			fresh = append(fresh, r)
+		}
+		s.seen[key] = now
+	}
+	return fresh
+}
@@ -0,0 +1,110 @@
+package glaze
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// qrFrame renders text as a QR code in a grayscale frame, as the page sends
+// it, with scale pixels per module.
+func qrFrame(t *testing.T, text string, scale int) scanFrame {
+	t.Helper()
+	m, err := encodeQR(text)
+	if err != nil {
+		t.Fatalf("encodeQR() unexpected error: %v", err)
+	}
+	n := m.GetWidth() * scale
+	f := scanFrame{Width: n, Height: n, Pixels: make([]byte, n*n)}
+	for y := range n {
+		for x := range n {
+			f.Pixels[y*n+x] = 0xff
+			if m.Get(x/scale, y/scale) {
+				f.Pixels[y*n+x] = 0
+			}
+		}
+	}
+	return f
+}
+
+func TestStartScanner(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	w.answerEvals(t, func(src string) (any, error) {
+		if !strings.Contains(src, `"interval":100`) || strings.Contains(src, "BarcodeDetector") {
+			t.Errorf("scanner source does not carry the options: %s", src[len(src)-80:])
+		}
+		return true, nil
+	})
+
+	var got []ScanResult
+	err := StartScanner(w, ScanOptions{Formats: []string{"qr_code"}, Interval: 100 * time.Millisecond}, func(r ScanResult) {
+		got = append(got, r)
+	})
+	if err != nil {
+		t.Fatalf("StartScanner() unexpected error: %v", err)
+	}
+
+	frame := qrFrame(t, "TICKET-42", 4)
+	res, err := w.call(t, scanBindingName, frame)
+	if err != nil {
+		t.Fatalf("scan binding unexpected error: %v", err)
+	}
+	if len(got) != 1 || got[0] != (ScanResult{Format: "qr_code", Value: "TICKET-42"}) {
+		t.Fatalf("results = %+v, want TICKET-42", got)
+	}
+	if codes, _ := res.([]ScanResult); len(codes) != 1 {
+		t.Fatalf("binding returned %#v, want the decoded code for the page", res)
+	}
+
+	// A code that stays in view is reported once.
+	if _, err := w.call(t, scanBindingName, frame); err != nil {
+		t.Fatalf("scan binding unexpected error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("results = %+v, want the repeated code dropped", got)
+	}
+
+	if _, err := w.call(t, scanBindingName, scanFrame{Width: 2, Height: 2, Pixels: []byte{0}}); err == nil {
+		t.Fatal("scan binding accepted a frame whose pixels do not match its size")
+	}
+
+	StopScanner(w)
+	if last := w.evals[len(w.evals)-1]; !strings.Contains(last, "_scanner.stop()") {
+		t.Fatalf("last eval = %q, want scanner stop", last)
+	}
+}
+
+func TestStartScannerFormats(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	err := StartScanner(w, ScanOptions{Formats: []string{"pdf417"}}, nil)
+	if err == nil || !strings.Contains(err.Error(), "unknown format") {
+		t.Fatalf("StartScanner() error = %v, want unknown format error", err)
+	}
+	if len(w.evals) != 0 {
+		t.Fatal("StartScanner() opened the camera for an unknown format")
+	}
+
+	// A code outside the requested formats is not reported.
+	s := &scanner{formats: []string{"ean_13"}, seen: make(map[string]time.Time)}
+	f := qrFrame(t, "TICKET-42", 4)
+	if got := s.decode(f.image()); len(got) != 0 {
+		t.Fatalf("decode() = %+v, want nothing for a QR code when scanning EAN-13", got)
+	}
+}
+
+func TestStartScannerUnsupported(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	w.answerEvals(t, func(string) (any, error) {
+		return nil, errors.New("camera access is not available")
+	})
+
+	err := StartScanner(w, ScanOptions{}, nil)
+	if err == nil || !strings.Contains(err.Error(), "not available") {
+		t.Fatalf("StartScanner() error = %v, want camera error", err)
+	}
+}
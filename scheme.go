@@ -0,0 +1,187 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// urlFlag marks the command line of a launch started by the desktop to open
+// a URL with the app. The argument after it is the URL.
+const urlFlag = "--glaze-url"
+
+// schemeName matches URL scheme names as defined by RFC 3986.
+var schemeName = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+
+// SchemeHandler describes how the app registers as the handler of URL
+// schemes such as mailto or web+notes.
+type SchemeHandler struct {
+	// ID names the registration, e.g. "com.example.notes". It must be a
+	// valid file name.
+	ID string
+
+	// Name is the label shown by the desktop when choosing a handler.
+	Name string
+
+	// Exec is the executable launched with the URL. It defaults to the
+	// running executable.
+	Exec string
+
+	// Schemes lists the lowercase schemes to handle, without the colon.
+	Schemes []string
+}
+
+// RegisterSchemeHandler registers the app as the handler of h.Schemes for
+// the current user: a desktop entry, ID-url.desktop, made the default
+// handler with xdg-mime on Linux, and a URL protocol in HKEY_CURRENT_USER on
+// Windows. Activations launch Exec with the URL, which OnURLActivation picks
+// up.
+//
+// Schemes that already have a default chosen in Windows Settings, such as
+// mailto, keep it until the user picks the app there. macOS only routes URLs
+// to schemes declared in the bundle's Info.plist, so it returns an error.
+func RegisterSchemeHandler(h SchemeHandler) error {
+	h, err := h.normalize()
+	if err != nil {
+		return err
+	}
+	return registerSchemeHandler(h)
+}
+
+// UnregisterSchemeHandler removes a registration made by
+// RegisterSchemeHandler. On Linux the desktop entry goes and the app is
+// taken out of the defaults in mimeapps.list; on Windows only the values
+// it added go, and only for schemes whose protocol still opens Exec.
+func UnregisterSchemeHandler(h SchemeHandler) error {
+	h, err := h.normalize()
+	if err != nil {
+		return err
+	}
+	return unregisterSchemeHandler(h)
+}
+
+// OnURLActivation calls fn with the URL the app was launched to open through
+// a SchemeHandler registration, and reports whether it was. Call it at
+// startup, before parsing the command line.
+func OnURLActivation(fn func(url string)) bool {
+	u, ok := ReceivedURL(os.Args[1:])
+	if ok {
+		fn(u)
+	}
+	return ok
+}
+
+// ReceivedURL extracts the activation URL from command-line arguments, as
+// passed by a SchemeHandler registration.
+func ReceivedURL(args []string) (string, bool) {
+	for i, arg := range args {
+		if arg == urlFlag && i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+	return "", false
+}
+
+func (h SchemeHandler) normalize() (SchemeHandler, error) {
+	if h.ID == "" || strings.ContainsAny(h.ID, `/\:`) {
+		return h, errors.New("webview: SchemeHandler.ID must be a non-empty file name")
+	}
+	if len(h.Schemes) == 0 {
+		return h, errors.New("webview: SchemeHandler.Schemes must not be empty")
+	}
+	for _, s := range h.Schemes {
+		if !schemeName.MatchString(s) {
+			return h, fmt.Errorf("webview: invalid URL scheme %q", s)
+		}
+	}
+	if h.Name == "" {
+		h.Name = h.ID
+	}
+	if h.Exec == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return h, err
+		}
+		h.Exec = exe
+	}
+	return h, nil
+}
+
+// schemeMimeTypes returns the freedesktop MIME types standing for schemes.
+func schemeMimeTypes(schemes []string) []string {
+	types := make([]string, len(schemes))
+	for i, s := range schemes {
+		types[i] = "x-scheme-handler/" + s
+	}
+	return types
+}
+
+// schemeRegistryKey is the per-user Windows registry key of a URL protocol.
+func schemeRegistryKey(scheme string) string {
+	return `HKCU\Software\Classes\` + scheme
+}
+
+// schemeOpenCommand is the command line a Windows URL protocol opens h
+// with.
+func schemeOpenCommand(h SchemeHandler) string {
+	return `"` + h.Exec + `" ` + urlFlag + ` "%1"`
+}
+
+// schemeRegistryCommands returns the reg.exe invocations that register h as
+// the Windows URL protocol for scheme, in order.
+func schemeRegistryCommands(h SchemeHandler, scheme string) []shellCommand {
+	key := schemeRegistryKey(scheme)
+	command := schemeOpenCommand(h)
+	return []shellCommand{
+		{name: "reg.exe", args: []string{"add", key, "/ve", "/d", "URL:" + h.Name, "/f"}},
+		{name: "reg.exe", args: []string{"add", key, "/v", "URL Protocol", "/d", "", "/f"}},
+		{name: "reg.exe", args: []string{"add", key + `\shell\open\command`, "/ve", "/d", command, "/f"}},
+	}
+}
+
+// schemeDesktopID is the desktop entry name of a scheme handler on Linux,
+// kept apart from the share target entry of the same ID.
+func schemeDesktopID(id string) string {
+	return id + "-url"
+}
+
+// removeMimeDefaults returns content, a mimeapps.list file, with desktop
+// removed from the defaults and associations of types. Entries left empty
+// are dropped, so the desktop falls back to its own choice; other
+// applications listed for the types stay.
+func removeMimeDefaults(content, desktop string, types []string) string {
+	lines := strings.Split(content, "\n")
+	out := lines[:0]
+	var section string
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			section = trimmed
+			out = append(out, line)
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, "=")
+		if !ok || (section != "[Default Applications]" && section != "[Added Associations]") || !slices.Contains(types, strings.TrimSpace(key)) {
+			out = append(out, line)
+			continue
+		}
+		var keep []string
+		for _, d := range strings.Split(value, ";") {
+			if d = strings.TrimSpace(d); d != "" && d != desktop {
+				keep = append(keep, d)
+			}
+		}
+		if len(keep) == 0 {
+			continue
+		}
+		line = strings.TrimSpace(key) + "=" + strings.Join(keep, ";")
+		if strings.HasSuffix(value, ";") {
+			line += ";"
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
@@ -0,0 +1,9 @@
+package glaze
+
+import "errors"
+
+var errSchemeHandlerDarwin = errors.New("webview: URL schemes on macOS must be declared in the app bundle (CFBundleURLTypes)")
+
+func registerSchemeHandler(SchemeHandler) error { return errSchemeHandlerDarwin }
+
+func unregisterSchemeHandler(SchemeHandler) error { return errSchemeHandlerDarwin }
@@ -0,0 +1,54 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func registerSchemeHandler(h SchemeHandler) error {
+	types := schemeMimeTypes(h.Schemes)
+	id := schemeDesktopID(h.ID)
+	if err := writeDesktopEntry(id, desktopEntry(h.Name, h.Exec, urlFlag+" %u", types)); err != nil {
+		return fmt.Errorf("webview: register scheme handler: %w", err)
+	}
+	args := append([]string{"default", id + ".desktop"}, types...)
+	return runShell([]shellCommand{{name: "xdg-mime", args: args}})
+}
+
+func unregisterSchemeHandler(h SchemeHandler) error {
+	id := schemeDesktopID(h.ID)
+	if err := resetMimeDefaults(id+".desktop", schemeMimeTypes(h.Schemes)); err != nil {
+		return fmt.Errorf("webview: unregister scheme handler: %w", err)
+	}
+	if err := removeDesktopEntry(id); err != nil {
+		return fmt.Errorf("webview: unregister scheme handler: %w", err)
+	}
+	return nil
+}
+
+// resetMimeDefaults takes desktop out of the user's defaults for types,
+// which xdg-mime can set but not unset.
+func resetMimeDefaults(desktop string, types []string) error {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	path := filepath.Join(dir, "mimeapps.list")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if out := removeMimeDefaults(string(data), desktop, types); out != string(data) {
+		return os.WriteFile(path, []byte(out), 0o644) //nolint:gosec // mimeapps.list is world-readable
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package glaze
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReceivedURL(t *testing.T) {
+	u, ok := ReceivedURL([]string{urlFlag, "mailto:ada@example.com"})
+	if !ok || u != "mailto:ada@example.com" {
+		t.Fatalf("ReceivedURL() = %q, %v", u, ok)
+	}
+	if _, ok := ReceivedURL([]string{urlFlag}); ok {
+		t.Fatal("ReceivedURL() reported an activation without URL")
+	}
+	if _, ok := ReceivedURL([]string{"mailto:x"}); ok {
+		t.Fatal("ReceivedURL() reported an activation without the flag")
+	}
+}
+
+func TestSchemeHandlerNormalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		h       SchemeHandler
+		wantErr bool
+	}{
+		{"valid", SchemeHandler{ID: "com.example.mail", Exec: "/opt/mail", Schemes: []string{"mailto", "web+notes"}}, false},
+		{"no id", SchemeHandler{Schemes: []string{"mailto"}}, true},
+		{"no schemes", SchemeHandler{ID: "x"}, true},
+		{"uppercase", SchemeHandler{ID: "x", Schemes: []string{"MailTo"}}, true},
+		{"colon", SchemeHandler{ID: "x", Schemes: []string{"mailto:"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.h.normalize()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchemeRegistryCommands(t *testing.T) {
+	cmds := schemeRegistryCommands(SchemeHandler{Name: "Mail", Exec: `C:\Apps\mail.exe`}, "mailto")
+	if len(cmds) != 3 {
+		t.Fatalf("commands = %d, want 3", len(cmds))
+	}
+	if !slices.Contains(cmds[1].args, "URL Protocol") {
+		t.Errorf("second command = %v, want URL Protocol value", cmds[1].args)
+	}
+	last := cmds[2].args
+	if last[1] != `HKCU\Software\Classes\mailto\shell\open\command` || !strings.Contains(last[4], `"C:\Apps\mail.exe" --glaze-url "%1"`) {
+		t.Errorf("open command = %v", last)
+	}
+	if got := schemeMimeTypes([]string{"mailto"}); got[0] != "x-scheme-handler/mailto" {
+		t.Errorf("schemeMimeTypes() = %v", got)
+	}
+}
+
+func TestRemoveMimeDefaults(t *testing.T) {
+	in := strings.Join([]string{
+		"[Default Applications]",
+		"x-scheme-handler/mailto=com.example.mail-url.desktop",
+		"x-scheme-handler/web+notes=other.desktop;com.example.mail-url.desktop;",
+		"text/plain=com.example.mail-url.desktop",
+		"[Added Associations]",
+		"x-scheme-handler/mailto=com.example.mail-url.desktop;",
+		"",
+	}, "\n")
+	want := strings.Join([]string{
+		"[Default Applications]",
+		"x-scheme-handler/web+notes=other.desktop;",
+		"text/plain=com.example.mail-url.desktop",
+		"[Added Associations]",
+		"",
+	}, "\n")
+	types := schemeMimeTypes([]string{"mailto", "web+notes"})
+	if got := removeMimeDefaults(in, "com.example.mail-url.desktop", types); got != want {
+		t.Fatalf("removeMimeDefaults() =\n%s\nwant\n%s", got, want)
+	}
+	if got := removeMimeDefaults(want, "com.example.mail-url.desktop", types); got != want {
+		t.Fatalf("removeMimeDefaults() changed a file without the entry:\n%s", got)
+	}
+	if schemeDesktopID("com.example.mail") == "com.example.mail" {
+		t.Fatal("scheme handler shares the share target's desktop entry name")
+	}
+}
@@ -0,0 +1,71 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+func registerSchemeHandler(h SchemeHandler) error {
+	for _, s := range h.Schemes {
+		for _, cmd := range schemeRegistryCommands(h, s) {
+			if err := runShell([]shellCommand{cmd}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func unregisterSchemeHandler(h SchemeHandler) error {
+	var errs []error
+	for _, s := range h.Schemes {
+		if err := removeSchemeRegistration(h, s); err != nil {
+			errs = append(errs, fmt.Errorf("webview: unregister scheme %s: %w", s, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// removeSchemeRegistration deletes the values schemeRegistryCommands added
+// for scheme, if its protocol still opens h: another app may have taken the
+// scheme since. Keys are deleted only once empty, so verbs, icons and
+// values other apps added stay.
+func removeSchemeRegistration(h SchemeHandler, scheme string) error {
+	path := `Software\Classes\` + scheme
+	key, err := registry.OpenKey(registry.CURRENT_USER, path+`\shell\open\command`, registry.QUERY_VALUE)
+	if errors.Is(err, registry.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	command, _, err := key.GetStringValue("")
+	key.Close()
+	if err != nil || command != schemeOpenCommand(h) {
+		return nil
+	}
+
+	if err := registry.DeleteKey(registry.CURRENT_USER, path+`\shell\open\command`); err != nil {
+		return err
+	}
+	// RegDeleteKey refuses keys with subkeys, which keeps other verbs.
+	_ = registry.DeleteKey(registry.CURRENT_USER, path+`\shell\open`)
+	_ = registry.DeleteKey(registry.CURRENT_USER, path+`\shell`)
+
+	key, err = registry.OpenKey(registry.CURRENT_USER, path, registry.QUERY_VALUE|registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer key.Close()
+	for _, name := range []string{"URL Protocol", ""} {
+		if err := key.DeleteValue(name); err != nil && !errors.Is(err, registry.ErrNotExist) {
+			return err
+		}
+	}
+	if info, err := key.Stat(); err == nil && info.SubKeyCount == 0 && info.ValueCount == 0 {
+		_ = registry.DeleteKey(registry.CURRENT_USER, path)
+	}
+	return nil
+}
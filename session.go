@@ -0,0 +1,331 @@
+package glaze
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrNoSession is returned by SessionStore.Load for a session never saved.
+var ErrNoSession = errors.New("webview: no such session")
+
+// restoreName is the hidden binding pages fetch the state RestoreWindow
+// left for them through.
+const restoreName = "__glaze_restore"
+
+// windowStateJS reads the state CaptureWindow records.
+const windowStateJS = `({
+  url: location.href,
+  title: document.title,
+  width: window.outerWidth,
+  height: window.outerHeight,
+  scrollX: window.scrollX,
+  scrollY: window.scrollY,
+  zoom: parseFloat(document.documentElement.style.zoom) || 1
+})`
+
+// restoreJS applies the zoom and scroll position RestoreWindow left for the
+// page once it has loaded, so the layout the offsets refer to exists.
+const restoreJS = `(function () {
+  'use strict';
+  if (window.__glaze_restore_installed) { return; }
+  window.__glaze_restore_installed = true;
+  function apply() {
+    if (typeof window.` + restoreName + ` !== "function") { return; }
+    window.` + restoreName + `(location.href).then(function (s) {
+      if (!s) { return; }
+      if (s.zoom && s.zoom !== 1) { document.documentElement.style.zoom = String(s.zoom); }
+      window.scrollTo(s.scrollX || 0, s.scrollY || 0);
+    });
+  }
+  if (document.readyState === "complete") {
+    apply();
+  } else {
+    window.addEventListener("load", apply);
+  }
+})();`
+
+// WindowState is the restorable state of one window.
+type WindowState struct {
+	// Key identifies the kind of window to the app, such as "editor" or
+	// "preview", so it knows how to recreate it.
+	Key string `json:"key,omitempty"`
+
+	// Title is the document title of the page.
+	Title string `json:"title,omitempty"`
+
+	// URL is the address of the page. For a window of an App it is a path,
+	// such as "/notes?id=3", relative to App.URL, which changes from run
+	// to run.
+	URL string `json:"url"`
+
+	// Document is the file open in the window, if any, as reported by
+	// Document.Path.
+	Document string `json:"document,omitempty"`
+
+	// Width and Height are the outer size of the window.
+	Width  int `json:"width,omitempty"`
+	Height int `json:"height,omitempty"`
+
+	// ScrollX and ScrollY are the scroll offsets of the page.
+	ScrollX float64 `json:"scrollX"`
+	ScrollY float64 `json:"scrollY"`
+
+	// Zoom is the CSS zoom of the page's root element; 1 is unzoomed.
+	Zoom float64 `json:"zoom,omitempty"`
+
+	// Data holds app-specific state, such as selected tabs.
+	Data json.RawMessage `json:"data,omitempty"`
+}
+
+// Session is a named set of windows saved with SessionStore.
+type Session struct {
+	// Name identifies the session, e.g. "last" or a workspace name.
+	Name string `json:"name"`
+
+	// Saved is when the session was saved.
+	Saved time.Time `json:"saved"`
+
+	// Windows are the windows open when the session was saved.
+	Windows []WindowState `json:"windows"`
+}
+
+// SessionStore keeps sessions as JSON files, so IDE- and browser-like apps
+// can reopen the windows of the last run:
+//
+//	store := glaze.SessionStore{ID: "com.example.notes"}
+//
+//	// On quit, from a background goroutine.
+//	state, _ := glaze.CaptureWindow(w)
+//	state.Document = doc.Path()
+//	_ = store.Save(glaze.Session{Name: "last", Windows: []glaze.WindowState{state}})
+//
+//	// On launch, from the UI thread.
+//	if s, err := store.Load("last"); err == nil && len(s.Windows) > 0 {
+//		_ = doc.Open(s.Windows[0].Document)
+//		_ = glaze.RestoreWindow(w, s.Windows[0])
+//	}
+type SessionStore struct {
+	// ID names the app, e.g. "com.example.notes". Sessions are kept in a
+	// "sessions" directory under a directory of that name in
+	// os.UserConfigDir. It must be a valid file name.
+	ID string
+
+	// Dir overrides the directory sessions are kept in.
+	Dir string
+}
+
+// dir returns the directory of s.
+func (s SessionStore) dir() (string, error) {
+	if s.Dir != "" {
+		return s.Dir, nil
+	}
+	if !validFileName(s.ID) {
+		return "", fmt.Errorf("webview: invalid session store ID %q", s.ID)
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("webview: locate config directory: %w", err)
+	}
+	return filepath.Join(dir, s.ID, "sessions"), nil
+}
+
+// file returns the file of the session name.
+func (s SessionStore) file(name string) (string, error) {
+	if !validFileName(name) {
+		return "", fmt.Errorf("webview: invalid session name %q", name)
+	}
+	dir, err := s.dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// Save stores sess under its name, replacing a session of the same name.
+// A zero Saved is set to the current time.
+func (s SessionStore) Save(sess Session) error {
+	path, err := s.file(sess.Name)
+	if err != nil {
+		return err
+	}
+	if sess.Saved.IsZero() {
+		sess.Saved = time.Now().UTC()
+	}
+	data, err := json.MarshalIndent(sess, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("webview: create session directory: %w", err)
+	}
+	// Write through a temporary file so a crash cannot leave half a session.
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("webview: save session: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("webview: save session: %w", err)
+	}
+	return nil
+}
+
+// Load returns the session name, or ErrNoSession.
+func (s SessionStore) Load(name string) (Session, error) {
+	path, err := s.file(name)
+	if err != nil {
+		return Session{}, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Session{}, ErrNoSession
+	}
+	if err != nil {
+		return Session{}, fmt.Errorf("webview: load session: %w", err)
+	}
+	var sess Session
+	if err := json.Unmarshal(data, &sess); err != nil {
+		return Session{}, fmt.Errorf("webview: load session %q: %w", name, err)
+	}
+	return sess, nil
+}
+
+// List returns the names of the saved sessions, sorted.
+func (s SessionStore) List() ([]string, error) {
+	dir, err := s.dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("webview: list sessions: %w", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), ".json"); ok && !e.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Delete removes the session name. Deleting a missing session is not an
+// error.
+func (s SessionStore) Delete(name string) error {
+	path, err := s.file(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("webview: delete session: %w", err)
+	}
+	return nil
+}
+
+// CaptureWindow reads the URL, title, size, scroll offsets and zoom of the
+// page in w. Pages of an App are recorded by their path under App.URL. It
+// must be called from a background goroutine, never from the UI thread.
+func CaptureWindow(w WebView) (WindowState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultEvalTimeout)
+	defer cancel()
+	raw, err := bridgeFor(w).eval(ctx, windowStateJS)
+	if err != nil {
+		return WindowState{}, fmt.Errorf("webview: capture window: %w", err)
+	}
+	var state WindowState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return WindowState{}, fmt.Errorf("webview: capture window: %w", err)
+	}
+	state.URL = bridgeFor(w).appPath(state.URL)
+	return state, nil
+}
+
+// RestoreWindow sizes w as recorded in state and navigates it to
+// state.URL, restoring the zoom and scroll position once the page has
+// loaded. A path is resolved against the App.URL of the current run, so w
+// must then be a window of an App. Documents and Data are the app's to
+// restore. Like Navigate, RestoreWindow must be called from the UI thread.
+func RestoreWindow(w WebView, state WindowState) error {
+	if w == nil {
+		return errors.New("webview: RestoreWindow requires a non-nil WebView")
+	}
+	if state.URL == "" {
+		return errors.New("webview: RestoreWindow requires a URL")
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	app := b.app
+	b.mu.Unlock()
+	page, target := state.URL, state.URL
+	if strings.HasPrefix(state.URL, "/") {
+		if app == nil {
+			return fmt.Errorf("webview: RestoreWindow: %q is relative, but the window is not an App window", state.URL)
+		}
+		page = strings.TrimSuffix(app.url, "/") + state.URL
+		target = app.windowURL(state.URL)
+	}
+	if err := b.bindHidden(restoreName, b.takeRestore); err != nil {
+		return err
+	}
+	b.injectScript("session-restore", restoreJS)
+	b.mu.Lock()
+	if b.restores == nil {
+		b.restores = make(map[string]WindowState)
+	}
+	b.restores[page] = state
+	b.mu.Unlock()
+	if state.Width > 0 && state.Height > 0 {
+		w.SetSize(state.Width, state.Height, HintNone)
+	}
+	if state.Title != "" {
+		w.SetTitle(state.Title)
+	}
+	w.Navigate(target)
+	return nil
+}
+
+// appPath returns url as a path relative to the App the window belongs
+// to, or unchanged if it is not under App.URL.
+func (b *bridge) appPath(url string) string {
+	b.mu.Lock()
+	app := b.app
+	b.mu.Unlock()
+	if app == nil {
+		return url
+	}
+	rest, ok := strings.CutPrefix(url, strings.TrimSuffix(app.url, "/"))
+	if !ok || (rest != "" && !strings.ContainsAny(rest[:1], "/?#")) {
+		return url
+	}
+	if !strings.HasPrefix(rest, "/") {
+		rest = "/" + rest
+	}
+	return rest
+}
+
+// takeRestore returns, once, the state RestoreWindow left for url.
+func (b *bridge) takeRestore(url string) *WindowState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state, ok := b.restores[url]
+	if !ok {
+		return nil
+	}
+	delete(b.restores, url)
+	return &state
+}
+
+// validFileName reports whether name can be used as a file name on its own.
+func validFileName(name string) bool {
+	return name != "" && name == filepath.Base(name) && name != "." && name != ".."
+}
@@ -0,0 +1,169 @@
+package glaze
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSessionStore(t *testing.T) {
+	store := SessionStore{Dir: t.TempDir()}
+
+	if names, err := store.List(); err != nil || len(names) != 0 {
+		t.Fatalf("List() on empty store = %v, %v; want nothing", names, err)
+	}
+	if _, err := store.Load("last"); !errors.Is(err, ErrNoSession) {
+		t.Fatalf("Load() of missing session error = %v, want ErrNoSession", err)
+	}
+
+	want := Session{Name: "last", Windows: []WindowState{{
+		Key:      "editor",
+		URL:      "http://127.0.0.1/notes",
+		Document: "/tmp/a.md",
+		Width:    800,
+		Height:   600,
+		ScrollY:  120,
+		Zoom:     1.25,
+		Data:     json.RawMessage(`{"tab":"outline"}`),
+	}}}
+	if err := store.Save(want); err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+	if err := store.Save(Session{Name: "work"}); err != nil {
+		t.Fatalf("Save() unexpected error: %v", err)
+	}
+
+	got, err := store.Load("last")
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if got.Saved.IsZero() {
+		t.Error("Load().Saved is zero, want the save time")
+	}
+	gotJSON, _ := json.Marshal(got.Windows)
+	wantJSON, _ := json.Marshal(want.Windows)
+	if string(gotJSON) != string(wantJSON) {
+		t.Errorf("Load().Windows = %s, want %s", gotJSON, wantJSON)
+	}
+
+	if names, err := store.List(); err != nil || !slices.Equal(names, []string{"last", "work"}) {
+		t.Errorf("List() = %v, %v; want [last work]", names, err)
+	}
+	if err := store.Delete("work"); err != nil {
+		t.Fatalf("Delete() unexpected error: %v", err)
+	}
+	if err := store.Delete("work"); err != nil {
+		t.Errorf("Delete() of missing session unexpected error: %v", err)
+	}
+	if names, _ := store.List(); !slices.Equal(names, []string{"last"}) {
+		t.Errorf("List() after Delete = %v, want [last]", names)
+	}
+
+	for _, name := range []string{"", ".", "..", "a/b"} {
+		if err := store.Save(Session{Name: name}); err == nil {
+			t.Errorf("Save(%q) returned no error", name)
+		}
+	}
+	if _, err := (SessionStore{ID: "../x"}).List(); err == nil {
+		t.Error("List() with invalid ID returned no error")
+	}
+}
+
+func TestRestoreWindow(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	state := WindowState{URL: "http://127.0.0.1/notes", Title: "Notes", ScrollY: 40, Zoom: 1.5}
+	if err := RestoreWindow(w, state); err != nil {
+		t.Fatalf("RestoreWindow() unexpected error: %v", err)
+	}
+	if !slices.Equal(w.navigated, []string{state.URL}) {
+		t.Errorf("navigated = %q, want %q", w.navigated, state.URL)
+	}
+	if w.title != "Notes" {
+		t.Errorf("title = %q, want Notes", w.title)
+	}
+	if !slices.Contains(w.inits, restoreJS) {
+		t.Error("restore script was not injected")
+	}
+
+	got, err := w.call(t, restoreName, "http://127.0.0.1/other")
+	if err != nil || got.(*WindowState) != nil {
+		t.Fatalf("restore of another page = %v, %v; want nil", got, err)
+	}
+	got, err = w.call(t, restoreName, state.URL)
+	if err != nil {
+		t.Fatalf("restore call unexpected error: %v", err)
+	}
+	if s, _ := got.(*WindowState); s == nil || s.ScrollY != 40 || s.Zoom != 1.5 {
+		t.Fatalf("restore call = %+v, want the saved state", got)
+	}
+	if got, _ := w.call(t, restoreName, state.URL); got.(*WindowState) != nil {
+		t.Error("restore state was handed out twice")
+	}
+
+	if err := RestoreWindow(w, WindowState{}); err == nil {
+		t.Error("RestoreWindow() without a URL returned no error")
+	}
+}
+
+func TestCaptureWindow(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	w.answerEvals(t, func(src string) (any, error) {
+		if !strings.Contains(src, "location.href") {
+			t.Errorf("evaluated %q, want the window state read", src)
+		}
+		return map[string]any{"url": "http://127.0.0.1/", "title": "Home", "width": 640, "height": 480, "scrollY": 10, "zoom": 1}, nil
+	})
+
+	state, err := CaptureWindow(w)
+	if err != nil {
+		t.Fatalf("CaptureWindow() unexpected error: %v", err)
+	}
+	if state.URL != "http://127.0.0.1/" || state.Title != "Home" || state.Width != 640 || state.ScrollY != 10 {
+		t.Errorf("CaptureWindow() = %+v", state)
+	}
+}
+
+func TestSessionURLsRelativeToApp(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	w.answerEvals(t, func(string) (any, error) {
+		return map[string]any{"url": "http://127.0.0.1:4100/notes?id=3#top"}, nil
+	})
+	old := &App{url: "http://127.0.0.1:4100"}
+	old.link(w)
+	state, err := CaptureWindow(w)
+	if err != nil {
+		t.Fatalf("CaptureWindow() unexpected error: %v", err)
+	}
+	if state.URL != "/notes?id=3#top" {
+		t.Fatalf("captured URL = %q, want the path under the app", state.URL)
+	}
+
+	// The next run serves the app on another port.
+	next := &fakeWebView{}
+	defer next.Destroy()
+	(&App{url: "http://127.0.0.1:5200", token: "t"}).link(next)
+	if err := RestoreWindow(next, state); err != nil {
+		t.Fatalf("RestoreWindow() unexpected error: %v", err)
+	}
+	if want := "http://127.0.0.1:5200/notes?id=3&" + appTokenParam + "=t#top"; len(next.navigated) != 1 || next.navigated[0] != want {
+		t.Errorf("navigated = %q, want %q", next.navigated, want)
+	}
+	if got, _ := next.call(t, restoreName, "http://127.0.0.1:5200/notes?id=3#top"); got.(*WindowState) == nil {
+		t.Error("restore state not found under the new app URL")
+	}
+
+	other := &fakeWebView{}
+	defer other.Destroy()
+	if err := RestoreWindow(other, state); err == nil {
+		t.Error("RestoreWindow() of a path outside an App returned no error")
+	}
+	if got := bridgeFor(w).appPath("http://127.0.0.1:41000/x"); got != "http://127.0.0.1:41000/x" {
+		t.Errorf("appPath() of another origin = %q, want it unchanged", got)
+	}
+}
@@ -0,0 +1,124 @@
+package glaze
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// shapeChunkSize is how much of a response body the shaper writes between
+// throughput pauses.
+const shapeChunkSize = 4096
+
+// NetworkShape describes simulated network conditions for ShapeHandler.
+// It is a development aid for testing how an app behaves with a slow or
+// unreliable backend; do not enable it in release builds.
+type NetworkShape struct {
+	// Latency delays every response.
+	Latency time.Duration
+
+	// Jitter adds a random extra delay of up to Jitter to each response.
+	Jitter time.Duration
+
+	// BytesPerSecond caps the throughput of response bodies. Zero means
+	// unlimited.
+	BytesPerSecond int
+
+	// FailureRate is the fraction of requests, between 0 and 1, answered
+	// with FailureStatus instead of reaching the handler.
+	FailureRate float64
+
+	// FailureStatus is the status of injected failures. Defaults to 502 Bad
+	// Gateway.
+	FailureStatus int
+}
+
+// ShapeHandler wraps next so its responses are delayed, throttled and
+// occasionally failed according to shape. AppOptions.NetworkShape applies
+// it to an AppWindow handler.
+func ShapeHandler(next http.Handler, shape NetworkShape) http.Handler {
+	return &shapedHandler{next: next, shape: shape, rand: rand.Float64, sleep: sleepContext}
+}
+
+type shapedHandler struct {
+	next  http.Handler
+	shape NetworkShape
+	rand  func() float64
+	sleep func(r *http.Request, d time.Duration) bool
+}
+
+func (h *shapedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	delay := h.shape.Latency
+	if h.shape.Jitter > 0 {
+		delay += time.Duration(h.rand() * float64(h.shape.Jitter))
+	}
+	if !h.sleep(r, delay) {
+		return
+	}
+	if h.shape.FailureRate > 0 && h.rand() < h.shape.FailureRate {
+		status := h.shape.FailureStatus
+		if status == 0 {
+			status = http.StatusBadGateway
+		}
+		http.Error(w, "glaze: injected network failure", status)
+		return
+	}
+	if h.shape.BytesPerSecond > 0 {
+		w = &throttledWriter{ResponseWriter: w, r: r, rate: h.shape.BytesPerSecond, sleep: h.sleep}
+	}
+	h.next.ServeHTTP(w, r)
+}
+
+// sleepContext waits for d unless the request is cancelled first, and
+// reports whether the full delay elapsed.
+func sleepContext(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return r.Context().Err() == nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+// throttledWriter paces response body writes to rate bytes per second.
+type throttledWriter struct {
+	http.ResponseWriter
+	r     *http.Request
+	rate  int
+	sleep func(r *http.Request, d time.Duration) bool
+}
+
+func (t *throttledWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := min(len(p), shapeChunkSize)
+		m, err := t.ResponseWriter.Write(p[:n])
+		written += m
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+		t.Flush()
+		if !t.sleep(t.r, time.Duration(n)*time.Second/time.Duration(t.rate)) {
+			return written, t.r.Context().Err()
+		}
+	}
+	return written, nil
+}
+
+// Flush keeps throttled bytes flowing to the client as they are paced.
+func (t *throttledWriter) Flush() {
+	if f, ok := t.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (t *throttledWriter) Unwrap() http.ResponseWriter {
+	return t.ResponseWriter
+}
@@ -0,0 +1,94 @@
+package glaze
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShapeHandler(t *testing.T) {
+	body := strings.Repeat("x", 10000)
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(body))
+	})
+
+	tests := []struct {
+		name       string
+		shape      NetworkShape
+		rand       float64
+		wantStatus int
+		wantSleeps []time.Duration
+	}{
+		{
+			name:       "latency and jitter",
+			shape:      NetworkShape{Latency: 100 * time.Millisecond, Jitter: 50 * time.Millisecond},
+			rand:       0.5,
+			wantStatus: http.StatusOK,
+			wantSleeps: []time.Duration{125 * time.Millisecond},
+		},
+		{
+			name:       "throttled",
+			shape:      NetworkShape{BytesPerSecond: 4096},
+			wantStatus: http.StatusOK,
+			wantSleeps: []time.Duration{0, time.Second, time.Second, time.Duration(10000-2*4096) * time.Second / 4096},
+		},
+		{
+			name:       "failure",
+			shape:      NetworkShape{FailureRate: 0.3},
+			rand:       0.2,
+			wantStatus: http.StatusBadGateway,
+			wantSleeps: []time.Duration{0},
+		},
+		{
+			name:       "no failure above rate",
+			shape:      NetworkShape{FailureRate: 0.3, FailureStatus: http.StatusServiceUnavailable},
+			rand:       0.4,
+			wantStatus: http.StatusOK,
+			wantSleeps: []time.Duration{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sleeps []time.Duration
+			h := &shapedHandler{
+				next:  next,
+				shape: tt.shape,
+				rand:  func() float64 { return tt.rand },
+				sleep: func(_ *http.Request, d time.Duration) bool { sleeps = append(sleeps, d); return true },
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && rec.Body.String() != body {
+				t.Fatalf("body length = %d, want %d", rec.Body.Len(), len(body))
+			}
+			if len(sleeps) != len(tt.wantSleeps) {
+				t.Fatalf("sleeps = %v, want %v", sleeps, tt.wantSleeps)
+			}
+			for i := range sleeps {
+				if sleeps[i] != tt.wantSleeps[i] {
+					t.Fatalf("sleeps = %v, want %v", sleeps, tt.wantSleeps)
+				}
+			}
+		})
+	}
+}
+
+func TestShapeHandlerCancelledDuringDelay(t *testing.T) {
+	called := false
+	h := ShapeHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true }),
+		NetworkShape{Latency: time.Hour})
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx, cancel := context.WithCancel(req.Context())
+	cancel()
+	h.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+	if called {
+		t.Fatal("handler reached after the request was cancelled")
+	}
+}
@@ -0,0 +1,115 @@
+package glaze
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// shareFlag marks the command line of a launch started by the desktop to
+// hand the app shared files. Everything after it is a shared path.
+const shareFlag = "--glaze-share"
+
+// ShareTarget describes how the app appears as a destination for files
+// shared from the desktop.
+type ShareTarget struct {
+	// ID names the registration, e.g. "com.example.notes". It must be a
+	// valid file name.
+	ID string
+
+	// Name is the label shown in the desktop's menus.
+	Name string
+
+	// Exec is the executable launched with the shared files. It defaults to
+	// the running executable.
+	Exec string
+
+	// MimeTypes lists the file types accepted on Linux. It defaults to any
+	// file.
+	MimeTypes []string
+}
+
+// SharedItems holds what the desktop shared with the app.
+type SharedItems struct {
+	Files []string `json:"files"`
+}
+
+// RegisterShareTarget registers the app as a destination for shared files:
+// an "Open With" entry for MimeTypes on Linux, through a desktop entry in
+// $XDG_DATA_HOME/applications, and a "Send to" menu item on Windows. The
+// desktop then launches Exec with the files, which OnShare picks up.
+//
+// macOS only offers share targets to app extensions and document types
+// declared in the bundle's Info.plist, so it returns an error there.
+func RegisterShareTarget(t ShareTarget) error {
+	t, err := t.normalize()
+	if err != nil {
+		return err
+	}
+	return registerShareTarget(t)
+}
+
+// UnregisterShareTarget removes a registration made by RegisterShareTarget.
+func UnregisterShareTarget(t ShareTarget) error {
+	t, err := t.normalize()
+	if err != nil {
+		return err
+	}
+	return unregisterShareTarget(t)
+}
+
+// OnShare calls fn with the files shared with the app if it was launched
+// through a ShareTarget registration, and reports whether it was. Call it at
+// startup, before parsing the command line.
+func OnShare(fn func(SharedItems)) bool {
+	items, ok := ReceivedShare(os.Args[1:])
+	if ok {
+		fn(items)
+	}
+	return ok
+}
+
+// ReceivedShare extracts shared files from command-line arguments, as
+// passed by a ShareTarget registration.
+func ReceivedShare(args []string) (SharedItems, bool) {
+	for i, arg := range args {
+		if arg == shareFlag {
+			return SharedItems{Files: append([]string{}, args[i+1:]...)}, true
+		}
+	}
+	return SharedItems{}, false
+}
+
+func (t ShareTarget) normalize() (ShareTarget, error) {
+	if t.ID == "" || strings.ContainsAny(t.ID, `/\:`) {
+		return t, errors.New("webview: ShareTarget.ID must be a non-empty file name")
+	}
+	if t.Name == "" {
+		t.Name = t.ID
+	}
+	if t.Exec == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return t, err
+		}
+		t.Exec = exe
+	}
+	if len(t.MimeTypes) == 0 {
+		t.MimeTypes = []string{"application/octet-stream"}
+	}
+	return t, nil
+}
+
+// desktopEntry renders a freedesktop desktop entry launching exec with
+// args, registered for mimeTypes.
+func desktopEntry(name, exec, args string, mimeTypes []string) string {
+	// Quote Exec per the Desktop Entry spec: backslash, quote, backtick and
+	// dollar are escaped inside double quotes, and % is doubled.
+	r := strings.NewReplacer(`\`, `\\\\`, `"`, `\\"`, "`", "\\\\`", `$`, `\\$`, "%", "%%")
+	return "[Desktop Entry]\n" +
+		"Type=Application\n" +
+		"Name=" + name + "\n" +
+		`Exec="` + r.Replace(exec) + `" ` + args + "\n" +
+		"MimeType=" + strings.Join(mimeTypes, ";") + ";\n" +
+		"NoDisplay=true\n"
+}
@@ -0,0 +1,9 @@
+package glaze
+
+import "errors"
+
+var errShareTargetDarwin = errors.New("webview: share targets on macOS must be declared in the app bundle (share extension or CFBundleDocumentTypes)")
+
+func registerShareTarget(ShareTarget) error { return errShareTargetDarwin }
+
+func unregisterShareTarget(ShareTarget) error { return errShareTargetDarwin }
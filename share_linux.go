@@ -0,0 +1,71 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+func registerShareTarget(t ShareTarget) error {
+	entry := desktopEntry(t.Name, t.Exec, shareFlag+" %F", t.MimeTypes)
+	if err := writeDesktopEntry(t.ID, entry); err != nil {
+		return fmt.Errorf("webview: register share target: %w", err)
+	}
+	return nil
+}
+
+func unregisterShareTarget(t ShareTarget) error {
+	if err := removeDesktopEntry(t.ID); err != nil {
+		return fmt.Errorf("webview: unregister share target: %w", err)
+	}
+	return nil
+}
+
+// writeDesktopEntry installs a desktop entry for the user, as id.desktop in
+// $XDG_DATA_HOME/applications.
+func writeDesktopEntry(id, entry string) error {
+	path, err := desktopEntryPath(id)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(entry), 0o644); err != nil { //nolint:gosec // desktop entries are world-readable
+		return err
+	}
+	refreshDesktopDatabase(filepath.Dir(path))
+	return nil
+}
+
+func removeDesktopEntry(id string) error {
+	path, err := desktopEntryPath(id)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	refreshDesktopDatabase(filepath.Dir(path))
+	return nil
+}
+
+func desktopEntryPath(id string) (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dir, "applications", id+".desktop"), nil
+}
+
+// refreshDesktopDatabase updates the MIME type cache so the entry shows up
+// without logging out. It is best effort: not every desktop ships the tool.
+func refreshDesktopDatabase(dir string) {
+	_ = exec.Command("update-desktop-database", dir).Run()
+}
@@ -0,0 +1,46 @@
+package glaze
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestReceivedShare(t *testing.T) {
+	items, ok := ReceivedShare([]string{"-v", shareFlag, "/tmp/a.txt", "/tmp/b c.png"})
+	if !ok || !slices.Equal(items.Files, []string{"/tmp/a.txt", "/tmp/b c.png"}) {
+		t.Fatalf("ReceivedShare() = %+v, %v", items, ok)
+	}
+	if _, ok := ReceivedShare([]string{"-v", "/tmp/a.txt"}); ok {
+		t.Fatal("ReceivedShare() reported a share without the share flag")
+	}
+}
+
+func TestShareTargetNormalize(t *testing.T) {
+	if _, err := (ShareTarget{}).normalize(); err == nil {
+		t.Error("normalize() expected error for empty ID")
+	}
+	if _, err := (ShareTarget{ID: "../evil"}).normalize(); err == nil {
+		t.Error("normalize() expected error for ID with a path separator")
+	}
+	got, err := (ShareTarget{ID: "com.example.notes", Exec: "/opt/notes"}).normalize()
+	if err != nil {
+		t.Fatalf("normalize() unexpected error: %v", err)
+	}
+	if got.Name != "com.example.notes" || len(got.MimeTypes) != 1 {
+		t.Fatalf("normalize() = %+v", got)
+	}
+}
+
+func TestDesktopEntry(t *testing.T) {
+	entry := desktopEntry("Notes", `/opt/my "notes"/100%/notes`, shareFlag+" %F", []string{"text/plain", "image/png"})
+	for _, want := range []string{
+		"Name=Notes\n",
+		`Exec="/opt/my \\"notes\\"/100%%/notes" --glaze-share %F` + "\n",
+		"MimeType=text/plain;image/png;\n",
+	} {
+		if !strings.Contains(entry, want) {
+			t.Errorf("desktop entry missing %q:\n%s", want, entry)
+		}
+	}
+}
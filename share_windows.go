@@ -0,0 +1,56 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func registerShareTarget(t ShareTarget) error {
+	path, err := sendToPath(t)
+	if err != nil {
+		return err
+	}
+	// Pass values through the environment so they are never parsed as
+	// PowerShell.
+	script := strings.Join([]string{
+		"$s = (New-Object -ComObject WScript.Shell).CreateShortcut($env:GLAZE_LINK_PATH)",
+		"$s.TargetPath = $env:GLAZE_LINK_TARGET",
+		"$s.Arguments = '" + shareFlag + "'",
+		"$s.Save()",
+	}, "; ")
+	return runShell([]shellCommand{{
+		name: "powershell.exe",
+		args: []string{"-NoProfile", "-NonInteractive", "-Command", script},
+		env:  []string{"GLAZE_LINK_PATH=" + path, "GLAZE_LINK_TARGET=" + t.Exec},
+	}})
+}
+
+func unregisterShareTarget(t ShareTarget) error {
+	path, err := sendToPath(t)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("webview: unregister share target: %w", err)
+	}
+	return nil
+}
+
+// sendToPath returns the shortcut that adds the app to Explorer's "Send to"
+// menu, named after the target's display name.
+func sendToPath(t ShareTarget) (string, error) {
+	appData := os.Getenv("APPDATA")
+	if appData == "" {
+		return "", errors.New("webview: share target: APPDATA is not set")
+	}
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, t.Name)
+	return filepath.Join(appData, "Microsoft", "Windows", "SendTo", name+".lnk"), nil
+}
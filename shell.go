@@ -0,0 +1,129 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// shellCommand is an external command used for desktop shell integration.
+type shellCommand struct {
+	name string
+	args []string
+	env  []string
+
+	// ignoreExit accepts a non-zero exit status as success, for tools such
+	// as explorer.exe that report failure even when they did their job.
+	ignoreExit bool
+}
+
+// RevealInFolder opens the platform file manager with path selected:
+// Finder on macOS, Explorer on Windows and the freedesktop FileManager1
+// service on Linux (falling back to opening the parent directory).
+func RevealInFolder(path string) error {
+	abs, err := existingPath(path)
+	if err != nil {
+		return err
+	}
+	return runShell(revealCommands(runtime.GOOS, abs))
+}
+
+// Trash moves path to the platform trash / recycle bin instead of deleting it,
+// so users can restore it like any file removed from a native app.
+func Trash(path string) error {
+	abs, err := existingPath(path)
+	if err != nil {
+		return err
+	}
+	return runShell(trashCommands(runtime.GOOS, abs))
+}
+
+func existingPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("webview: resolve path %s: %w", path, err)
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return "", fmt.Errorf("webview: %w", err)
+	}
+	return abs, nil
+}
+
+func revealCommands(goos, path string) []shellCommand {
+	switch goos {
+	case "darwin":
+		return []shellCommand{{name: "open", args: []string{"-R", path}}}
+	case "windows":
+		return []shellCommand{{name: "explorer.exe", args: []string{"/select," + path}, ignoreExit: true}}
+	default:
+		fileURL := (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+		return []shellCommand{
+			{name: "dbus-send", args: []string{
+				"--session", "--print-reply", "--type=method_call",
+				"--dest=org.freedesktop.FileManager1",
+				"/org/freedesktop/FileManager1",
+				"org.freedesktop.FileManager1.ShowItems",
+				"array:string:" + fileURL, "string:",
+			}},
+			{name: "xdg-open", args: []string{filepath.Dir(path)}},
+		}
+	}
+}
+
+func trashCommands(goos, path string) []shellCommand {
+	switch goos {
+	case "darwin":
+		// Pass the path through argv so it is never parsed as AppleScript.
+		return []shellCommand{{name: "osascript", args: []string{
+			"-e", "on run argv",
+			"-e", `tell application "Finder" to delete POSIX file (item 1 of argv)`,
+			"-e", "end run",
+			path,
+		}}}
+	case "windows":
+		// Pass the path through the environment so it is never parsed as
+		// PowerShell.
+		script := strings.Join([]string{
+			"Add-Type -AssemblyName Microsoft.VisualBasic",
+			"$p = $env:GLAZE_TRASH_PATH",
+			"if (Test-Path -LiteralPath $p -PathType Container) { [Microsoft.VisualBasic.FileIO.FileSystem]::DeleteDirectory($p, 'OnlyErrorDialogs', 'SendToRecycleBin') }",
+			"else { [Microsoft.VisualBasic.FileIO.FileSystem]::DeleteFile($p, 'OnlyErrorDialogs', 'SendToRecycleBin') }",
+		}, "; ")
+		return []shellCommand{{
+			name: "powershell.exe",
+			args: []string{"-NoProfile", "-NonInteractive", "-Command", script},
+			env:  []string{"GLAZE_TRASH_PATH=" + path},
+		}}
+	default:
+		return []shellCommand{
+			{name: "gio", args: []string{"trash", path}},
+			{name: "trash-put", args: []string{path}},
+		}
+	}
+}
+
+// runShell runs each command in turn until one succeeds.
+func runShell(cmds []shellCommand) error {
+	var errs []error
+	for _, c := range cmds {
+		cmd := exec.Command(c.name, c.args...)
+		if len(c.env) > 0 {
+			cmd.Env = append(os.Environ(), c.env...)
+		}
+		out, err := cmd.CombinedOutput()
+		var exitErr *exec.ExitError
+		if err == nil || (c.ignoreExit && errors.As(err, &exitErr)) {
+			return nil
+		}
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", c.name, err))
+	}
+	return fmt.Errorf("webview: %w", errors.Join(errs...))
+}
@@ -0,0 +1,90 @@
+package glaze
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRevealCommands(t *testing.T) {
+	tests := []struct {
+		goos string
+		path string
+		want string
+	}{
+		{goos: "darwin", path: "/tmp/a b.txt", want: "open -R /tmp/a b.txt"},
+		{goos: "windows", path: `C:\Notes\a.txt`, want: `explorer.exe /select,C:\Notes\a.txt`},
+		{goos: "linux", path: "/tmp/a b.txt", want: "array:string:file:///tmp/a%20b.txt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goos, func(t *testing.T) {
+			cmds := revealCommands(tt.goos, tt.path)
+			got := cmds[0].name + " " + strings.Join(cmds[0].args, " ")
+			if !strings.Contains(got, tt.want) {
+				t.Fatalf("revealCommands(%q) = %q, want it to contain %q", tt.goos, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevealCommandsLinuxFallsBackToParent(t *testing.T) {
+	cmds := revealCommands("linux", "/home/me/notes/a.txt")
+	last := cmds[len(cmds)-1]
+	if last.name != "xdg-open" || last.args[0] != "/home/me/notes" {
+		t.Fatalf("last fallback = %s %v, want xdg-open /home/me/notes", last.name, last.args)
+	}
+}
+
+func TestTrashCommandsKeepPathOutOfScripts(t *testing.T) {
+	const path = `/tmp/evil"; rm -rf ~; ".txt`
+	for _, goos := range []string{"darwin", "windows"} {
+		cmd := trashCommands(goos, path)[0]
+		for _, arg := range cmd.args {
+			if arg != path && strings.Contains(arg, "evil") {
+				t.Fatalf("trashCommands(%q) interpolated path into %q", goos, arg)
+			}
+		}
+	}
+	if env := trashCommands("windows", path)[0].env; len(env) != 1 || env[0] != "GLAZE_TRASH_PATH="+path {
+		t.Fatalf("windows trash env = %v, want path in GLAZE_TRASH_PATH", env)
+	}
+}
+
+func TestTrashMissingPath(t *testing.T) {
+	if err := Trash(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("Trash() expected error for missing path")
+	}
+}
+
+func TestRunShellFallsBack(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	tmp := t.TempDir()
+	marker := filepath.Join(tmp, "ran")
+	err := runShell([]shellCommand{
+		{name: filepath.Join(tmp, "does-not-exist")},
+		{name: "sh", args: []string{"-c", "touch " + marker}},
+	})
+	if err != nil {
+		t.Fatalf("runShell() unexpected error: %v", err)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Fatalf("fallback command did not run: %v", err)
+	}
+}
+
+func TestRunShellReportsAllFailures(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+	err := runShell([]shellCommand{
+		{name: "sh", args: []string{"-c", "echo first >&2; exit 1"}},
+		{name: "sh", args: []string{"-c", "echo second >&2; exit 2"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "first") || !strings.Contains(err.Error(), "second") {
+		t.Fatalf("runShell() error = %v, want both failures", err)
+	}
+}
@@ -0,0 +1,76 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// shutdownHook is a teardown function registered with OnShutdown.
+type shutdownHook struct {
+	priority int
+	seq      int
+	fn       func() error
+}
+
+var shutdownHooks struct {
+	mu    sync.Mutex
+	seq   int
+	hooks []*shutdownHook
+}
+
+// OnShutdown registers fn to run when the app quits. Hooks run in ascending
+// priority order, and hooks with equal priority run in reverse registration
+// order like deferred calls, so teardown can be layered explicitly — for
+// example tray icons at 10, sockets at 20, databases at 30 and temporary
+// directories at 40.
+//
+// AppWindow runs the hooks after the window has closed and its HTTP server
+// has stopped. Apps that drive New and Run themselves call RunShutdownHooks
+// after Run returns. The returned function unregisters the hook.
+func OnShutdown(priority int, fn func() error) (remove func()) {
+	shutdownHooks.mu.Lock()
+	defer shutdownHooks.mu.Unlock()
+	shutdownHooks.seq++
+	h := &shutdownHook{priority: priority, seq: shutdownHooks.seq, fn: fn}
+	shutdownHooks.hooks = append(shutdownHooks.hooks, h)
+	return func() {
+		shutdownHooks.mu.Lock()
+		defer shutdownHooks.mu.Unlock()
+		shutdownHooks.hooks = slices.DeleteFunc(shutdownHooks.hooks, func(o *shutdownHook) bool { return o == h })
+	}
+}
+
+// RunShutdownHooks runs and unregisters every hook registered with
+// OnShutdown. A failing or panicking hook does not stop the ones after it;
+// their errors are joined.
+func RunShutdownHooks() error {
+	shutdownHooks.mu.Lock()
+	hooks := shutdownHooks.hooks
+	shutdownHooks.hooks = nil
+	shutdownHooks.mu.Unlock()
+
+	slices.SortFunc(hooks, func(a, b *shutdownHook) int {
+		if a.priority != b.priority {
+			return a.priority - b.priority
+		}
+		return b.seq - a.seq
+	})
+	var errs []error
+	for _, h := range hooks {
+		if err := runShutdownHook(h.fn); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func runShutdownHook(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("webview: shutdown hook panicked: %v", r)
+		}
+	}()
+	return fn()
+}
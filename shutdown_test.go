@@ -0,0 +1,53 @@
+package glaze
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRunShutdownHooksOrder(t *testing.T) {
+	var order []string
+	hook := func(name string) func() error {
+		return func() error { order = append(order, name); return nil }
+	}
+	OnShutdown(30, hook("db"))
+	OnShutdown(10, hook("tray"))
+	OnShutdown(20, hook("socket-a"))
+	OnShutdown(20, hook("socket-b"))
+	remove := OnShutdown(5, hook("removed"))
+	remove()
+
+	if err := RunShutdownHooks(); err != nil {
+		t.Fatalf("RunShutdownHooks() unexpected error: %v", err)
+	}
+	want := []string{"tray", "socket-b", "socket-a", "db"}
+	if !slices.Equal(order, want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+
+	order = nil
+	if err := RunShutdownHooks(); err != nil || len(order) != 0 {
+		t.Fatalf("second run = %v, %v; want no hooks", order, err)
+	}
+}
+
+func TestRunShutdownHooksErrors(t *testing.T) {
+	errDB := errors.New("db busy")
+	ran := false
+	OnShutdown(1, func() error { return errDB })
+	OnShutdown(2, func() error { panic("boom") })
+	OnShutdown(3, func() error { ran = true; return nil })
+
+	err := RunShutdownHooks()
+	if !errors.Is(err, errDB) {
+		t.Errorf("error %v does not wrap %v", err, errDB)
+	}
+	if err == nil || !strings.Contains(err.Error(), "panicked: boom") {
+		t.Errorf("error %v missing panic", err)
+	}
+	if !ran {
+		t.Error("hook after failures did not run")
+	}
+}
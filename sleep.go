@@ -0,0 +1,67 @@
+package glaze
+
+import (
+	"strconv"
+	"sync"
+)
+
+// sleepInhibitor holds the release function of the active InhibitSleep call.
+var sleepInhibitor struct {
+	mu      sync.Mutex
+	release func()
+}
+
+// InhibitSleep keeps the display awake and the system from idling into sleep
+// until AllowSleep is called or the process exits, for media players, kiosks
+// and long-running tasks. reason is shown by systems that list inhibitors.
+// It is a no-op while sleep is already inhibited.
+//
+// It uses caffeinate on macOS and SetThreadExecutionState on Windows. On
+// Linux it calls org.freedesktop.ScreenSaver.Inhibit over the session bus,
+// which desktops honour for screen blanking, and holds a logind idle/sleep
+// inhibitor through systemd-inhibit against suspend; it fails only if both
+// are unavailable.
+func InhibitSleep(reason string) error {
+	sleepInhibitor.mu.Lock()
+	defer sleepInhibitor.mu.Unlock()
+	if sleepInhibitor.release != nil {
+		return nil
+	}
+	release, err := inhibitSleep(reason)
+	if err != nil {
+		return err
+	}
+	sleepInhibitor.release = release
+	return nil
+}
+
+// AllowSleep lifts the inhibition set by InhibitSleep. It is a no-op when
+// sleep is not inhibited.
+func AllowSleep() {
+	sleepInhibitor.mu.Lock()
+	defer sleepInhibitor.mu.Unlock()
+	if sleepInhibitor.release != nil {
+		sleepInhibitor.release()
+		sleepInhibitor.release = nil
+	}
+}
+
+// sleepCommand returns the helper process that holds the inhibition on
+// Unix systems. It watches pid so the inhibition ends if the app dies without
+// calling AllowSleep.
+func sleepCommand(goos, reason string, pid int) shellCommand {
+	if goos == "darwin" {
+		// -d keeps the display on, -i prevents idle sleep.
+		return shellCommand{name: "caffeinate", args: []string{"-d", "-i", "-w", strconv.Itoa(pid)}}
+	}
+	if reason == "" {
+		reason = "Application requested"
+	}
+	return shellCommand{name: "systemd-inhibit", args: []string{
+		"--what=idle:sleep",
+		"--who=glaze",
+		"--why=" + reason,
+		"--mode=block",
+		"tail", "--pid=" + strconv.Itoa(pid), "-f", "/dev/null",
+	}}
+}
@@ -0,0 +1,5 @@
+package glaze
+
+func inhibitSleep(reason string) (func(), error) {
+	return startSleepCommand(reason)
+}
@@ -0,0 +1,128 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"unsafe"
+)
+
+// screenSaver holds the GIO entry points used to call
+// org.freedesktop.ScreenSaver over the session bus.
+var screenSaver struct {
+	once sync.Once
+	err  error
+	conn uintptr // GDBusConnection, shared for the life of the process
+
+	busGet      func(busType int32, cancellable uintptr, gerror *uintptr) uintptr
+	call        func(conn uintptr, name, path, iface, method string, params, replyType uintptr, flags, timeout int32, cancellable uintptr, gerror *uintptr) uintptr
+	newString   func(s string) uintptr
+	newUint32   func(v uint32) uintptr
+	newTuple    func(children unsafe.Pointer, n uintptr) uintptr
+	childValue  func(v uintptr, index uintptr) uintptr
+	getUint32   func(v uintptr) uint32
+	variantFree func(v uintptr)
+	errorFree   func(gerror uintptr)
+}
+
+// gBusTypeSession is G_BUS_TYPE_SESSION.
+const gBusTypeSession = 2
+
+func loadScreenSaver() error {
+	screenSaver.once.Do(func() {
+		s := &screenSaver
+		if s.err = openNative("libgio-2.0.so.0", []nativeFunc{
+			{&s.busGet, "g_bus_get_sync"},
+			{&s.call, "g_dbus_connection_call_sync"},
+			{&s.newString, "g_variant_new_string"},
+			{&s.newUint32, "g_variant_new_uint32"},
+			{&s.newTuple, "g_variant_new_tuple"},
+			{&s.childValue, "g_variant_get_child_value"},
+			{&s.getUint32, "g_variant_get_uint32"},
+			{&s.variantFree, "g_variant_unref"},
+			{&s.errorFree, "g_error_free"},
+		}); s.err != nil {
+			return
+		}
+		var gerror uintptr
+		if s.conn = s.busGet(gBusTypeSession, 0, &gerror); s.conn == 0 {
+			s.err = fmt.Errorf("webview: connect to the session bus: %s", takeGError(gerror, s.errorFree))
+		}
+	})
+	return screenSaver.err
+}
+
+// takeGError returns the message of a GError and frees it with free, the
+// caller's g_error_free.
+func takeGError(gerror uintptr, free func(gerror uintptr)) string {
+	if gerror == 0 {
+		return "unknown error"
+	}
+	ptr := *(*unsafe.Pointer)(unsafe.Pointer(&gerror))
+	// GError is { GQuark domain; gint code; gchar *message; }.
+	msg := goString(*(*uintptr)(unsafe.Add(ptr, 8)))
+	free(gerror)
+	return msg
+}
+
+// screenSaverCall calls method of org.freedesktop.ScreenSaver with args and
+// returns the first uint32 of the reply, if any.
+func screenSaverCall(method string, args ...uintptr) (uint32, error) {
+	s := &screenSaver
+	var params uintptr
+	if len(args) > 0 {
+		params = s.newTuple(unsafe.Pointer(&args[0]), uintptr(len(args))) // floating, consumed by call
+	} else {
+		params = s.newTuple(nil, 0)
+	}
+	var gerror uintptr
+	reply := s.call(s.conn, "org.freedesktop.ScreenSaver", "/org/freedesktop/ScreenSaver",
+		"org.freedesktop.ScreenSaver", method, params, 0, 0, -1, 0, &gerror)
+	if reply == 0 {
+		return 0, fmt.Errorf("webview: ScreenSaver.%s: %s", method, takeGError(gerror, s.errorFree))
+	}
+	defer s.variantFree(reply)
+	if method != "Inhibit" {
+		return 0, nil
+	}
+	child := s.childValue(reply, 0)
+	defer s.variantFree(child)
+	return s.getUint32(child), nil
+}
+
+// inhibitScreenSaver keeps the screen saver and display blanking off
+// through org.freedesktop.ScreenSaver. The service lifts the inhibition if
+// the app's bus connection closes, so it never outlives the process.
+func inhibitScreenSaver(reason string) (func(), error) {
+	if err := loadScreenSaver(); err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		reason = "Application requested"
+	}
+	cookie, err := screenSaverCall("Inhibit", screenSaver.newString("glaze"), screenSaver.newString(reason))
+	if err != nil {
+		return nil, err
+	}
+	return func() { _, _ = screenSaverCall("UnInhibit", screenSaver.newUint32(cookie)) }, nil
+}
+
+// inhibitSleep combines the two Linux inhibitors: the screen saver, which
+// desktops honour for the display, and a logind inhibitor through
+// systemd-inhibit, which keeps the system from suspending. Either one is
+// enough to succeed.
+func inhibitSleep(reason string) (func(), error) {
+	display, displayErr := inhibitScreenSaver(reason)
+	system, systemErr := startSleepCommand(reason)
+	if displayErr != nil && systemErr != nil {
+		return nil, errors.Join(displayErr, systemErr)
+	}
+	return func() {
+		if display != nil {
+			display()
+		}
+		if system != nil {
+			system()
+		}
+	}, nil
+}
@@ -0,0 +1,45 @@
+package glaze
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSleepCommand(t *testing.T) {
+	mac := sleepCommand("darwin", "ignored", 42)
+	if mac.name != "caffeinate" || !slices.Equal(mac.args, []string{"-d", "-i", "-w", "42"}) {
+		t.Errorf("darwin command = %s %v", mac.name, mac.args)
+	}
+
+	linux := sleepCommand("linux", "Playing video", 42)
+	if linux.name != "systemd-inhibit" {
+		t.Fatalf("linux command = %s, want systemd-inhibit", linux.name)
+	}
+	for _, want := range []string{"--why=Playing video", "--what=idle:sleep", "--pid=42"} {
+		if !slices.Contains(linux.args, want) {
+			t.Errorf("linux args %v missing %q", linux.args, want)
+		}
+	}
+	if def := sleepCommand("linux", "", 1); !slices.Contains(def.args, "--why=Application requested") {
+		t.Errorf("linux args %v missing default reason", def.args)
+	}
+}
+
+func TestInhibitSleepIdempotent(t *testing.T) {
+	defer func() {
+		sleepInhibitor.mu.Lock()
+		sleepInhibitor.release = nil
+		sleepInhibitor.mu.Unlock()
+	}()
+	released := 0
+	sleepInhibitor.release = func() { released++ }
+
+	if err := InhibitSleep("again"); err != nil {
+		t.Fatalf("InhibitSleep() unexpected error while active: %v", err)
+	}
+	AllowSleep()
+	AllowSleep()
+	if released != 1 {
+		t.Fatalf("release called %d times, want 1", released)
+	}
+}
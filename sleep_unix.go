@@ -0,0 +1,22 @@
+//go:build darwin || linux
+
+package glaze
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// startSleepCommand starts the helper process of sleepCommand and returns a
+// function stopping it.
+func startSleepCommand(reason string) (func(), error) {
+	c := sleepCommand(runtime.GOOS, reason, os.Getpid())
+	cmd := exec.Command(c.name, c.args...)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("webview: inhibit sleep: %w", err)
+	}
+	go func() { _ = cmd.Wait() }()
+	return func() { _ = cmd.Process.Kill() }, nil
+}
@@ -0,0 +1,37 @@
+package glaze
+
+import (
+	"fmt"
+	"runtime"
+)
+
+var procSetThreadExecutionState = kernel32.NewProc("SetThreadExecutionState")
+
+const (
+	esSystemRequired  = 0x00000001
+	esDisplayRequired = 0x00000002
+	esContinuous      = 0x80000000
+)
+
+// inhibitSleep holds the execution state from a dedicated OS thread, since
+// Windows ties it to the thread that set it and drops it when that thread
+// exits.
+func inhibitSleep(_ string) (func(), error) {
+	started := make(chan error, 1)
+	done := make(chan struct{})
+	go func() {
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		if r, _, err := procSetThreadExecutionState.Call(esContinuous | esSystemRequired | esDisplayRequired); r == 0 {
+			started <- fmt.Errorf("webview: SetThreadExecutionState failed: %w", err)
+			return
+		}
+		started <- nil
+		<-done
+		procSetThreadExecutionState.Call(esContinuous)
+	}()
+	if err := <-started; err != nil {
+		return nil, err
+	}
+	return func() { close(done) }, nil
+}
@@ -0,0 +1,205 @@
+package glaze
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// StaticOptions configures StaticHandler.
+type StaticOptions struct {
+	// Dir is the directory of fsys to serve, such as "dist" for an
+	// embed.FS holding dist/index.html. Defaults to the root.
+	Dir string
+
+	// Index is the file served for a directory. Defaults to "index.html".
+	Index string
+
+	// SPA serves the root index file, with status 200, for paths that
+	// match no file and have no extension, so a client-side router sees
+	// its own routes: /settings/profile loads index.html, while a missing
+	// /app.js is still a 404.
+	SPA bool
+}
+
+// staticTypes are the content types of common frontend files, used before
+// the system table, which on Windows comes from the registry and may map
+// .js to text/plain.
+var staticTypes = map[string]string{
+	".html":        "text/html; charset=utf-8",
+	".css":         "text/css; charset=utf-8",
+	".js":          "text/javascript; charset=utf-8",
+	".mjs":         "text/javascript; charset=utf-8",
+	".json":        "application/json",
+	".map":         "application/json",
+	".webmanifest": "application/manifest+json",
+	".svg":         "image/svg+xml",
+	".wasm":        "application/wasm",
+	".png":         "image/png",
+	".jpg":         "image/jpeg",
+	".jpeg":        "image/jpeg",
+	".gif":         "image/gif",
+	".webp":        "image/webp",
+	".avif":        "image/avif",
+	".ico":         "image/x-icon",
+	".woff":        "font/woff",
+	".woff2":       "font/woff2",
+	".ttf":         "font/ttf",
+	".txt":         "text/plain; charset=utf-8",
+}
+
+// staticHandler serves a file tree; see StaticHandler.
+type staticHandler struct {
+	fsys fs.FS
+	opts StaticOptions
+
+	mu    sync.Mutex
+	etags map[string]staticETag // by file name
+}
+
+// staticETag is the ETag of a file as it was when hashed.
+type staticETag struct {
+	modTime time.Time
+	size    int64
+	etag    string
+}
+
+// StaticHandler returns a handler serving the files of fsys, typically an
+// embed.FS with a built frontend, as AppOptions.Handler or under a mux:
+//
+//	//go:embed dist
+//	var dist embed.FS
+//
+//	err := glaze.AppWindow(glaze.AppOptions{
+//		Handler: glaze.StaticHandler(dist, glaze.StaticOptions{Dir: "dist", SPA: true}),
+//	})
+//
+// Content types come from the file extension, with a built-in table for
+// web files that does not depend on the system. Responses carry an ETag
+// hashed from the content and Cache-Control: no-cache, so the page
+// revalidates each file and gets 304 Not Modified while it is unchanged;
+// use AssetManifest for fingerprinted, immutable caching. Range and HEAD
+// requests are supported. Requests under a mux prefix should go through
+// http.StripPrefix.
+func StaticHandler(fsys fs.FS, opts StaticOptions) http.Handler {
+	if opts.Index == "" {
+		opts.Index = "index.html"
+	}
+	if dir := strings.Trim(opts.Dir, "/"); dir != "" && dir != "." {
+		sub, err := fs.Sub(fsys, dir)
+		if err != nil {
+			// Only an invalid path fails; serve nothing rather than the
+			// parent tree.
+			return http.NotFoundHandler()
+		}
+		fsys = sub
+	}
+	return &staticHandler{fsys: fsys, opts: opts, etags: make(map[string]staticETag)}
+}
+
+func (h *staticHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+	if name == "" {
+		name = "."
+	}
+	if h.serveFile(w, r, name) {
+		return
+	}
+	if h.opts.SPA && path.Ext(name) == "" && h.serveFile(w, r, ".") {
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// serveFile serves name, or the index file of the directory name, and
+// reports whether it exists.
+func (h *staticHandler) serveFile(w http.ResponseWriter, r *http.Request, name string) bool {
+	if !fs.ValidPath(name) {
+		return false
+	}
+	info, err := fs.Stat(h.fsys, name)
+	if err != nil {
+		return false
+	}
+	if info.IsDir() {
+		name = path.Join(name, h.opts.Index)
+		if info, err = fs.Stat(h.fsys, name); err != nil || info.IsDir() {
+			return false
+		}
+	}
+	f, err := h.fsys.Open(name)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	content, ok := f.(io.ReadSeeker)
+	if !ok {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			http.Error(w, "read error", http.StatusInternalServerError)
+			return true
+		}
+		content = bytes.NewReader(data)
+	}
+	etag, err := h.etag(name, info, content)
+	if err != nil {
+		http.Error(w, "read error", http.StatusInternalServerError)
+		return true
+	}
+
+	header := w.Header()
+	if ct := staticType(name); ct != "" {
+		header.Set("Content-Type", ct)
+	}
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", "no-cache")
+	// ServeContent answers If-None-Match with 304 and handles Range.
+	http.ServeContent(w, r, name, info.ModTime(), content)
+	return true
+}
+
+// etag returns the ETag of name, hashing content when the file is new or
+// its size or modification time changed, then rewinds content.
+func (h *staticHandler) etag(name string, info fs.FileInfo, content io.ReadSeeker) (string, error) {
+	h.mu.Lock()
+	cached, ok := h.etags[name]
+	h.mu.Unlock()
+	if ok && cached.size == info.Size() && cached.modTime.Equal(info.ModTime()) {
+		return cached.etag, nil
+	}
+	sum := sha256.New()
+	if _, err := io.Copy(sum, content); err != nil {
+		return "", err
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	etag := `"` + hex.EncodeToString(sum.Sum(nil)[:16]) + `"`
+	h.mu.Lock()
+	h.etags[name] = staticETag{modTime: info.ModTime(), size: info.Size(), etag: etag}
+	h.mu.Unlock()
+	return etag, nil
+}
+
+// staticType returns the content type of a file name, or "" to let
+// ServeContent sniff it.
+func staticType(name string) string {
+	ext := strings.ToLower(path.Ext(name))
+	if ct, ok := staticTypes[ext]; ok {
+		return ct
+	}
+	return mime.TypeByExtension(ext)
+}
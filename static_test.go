@@ -0,0 +1,85 @@
+package glaze
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestStaticHandler(t *testing.T) {
+	fsys := fstest.MapFS{
+		"dist/index.html":      {Data: []byte("<!doctype html><p>app</p>")},
+		"dist/app.js":          {Data: []byte("console.log(1)")},
+		"dist/app.css":         {Data: []byte("p{}")},
+		"dist/docs/index.html": {Data: []byte("<p>docs</p>")},
+		"dist/logo.unknownext": {Data: []byte("\x89PNG\r\n\x1a\n")},
+		"secret.txt":           {Data: []byte("outside dist")},
+	}
+	h := StaticHandler(fsys, StaticOptions{Dir: "dist", SPA: true})
+	get := func(target string, header http.Header) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		target      string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"/", 200, "text/html; charset=utf-8", "<!doctype html><p>app</p>"},
+		{"/app.js", 200, "text/javascript; charset=utf-8", "console.log(1)"},
+		{"/app.css", 200, "text/css; charset=utf-8", "p{}"},
+		{"/docs/", 200, "text/html; charset=utf-8", "<p>docs</p>"},
+		{"/settings/profile", 200, "text/html; charset=utf-8", "<!doctype html><p>app</p>"},
+		{"/missing.js", 404, "", ""},
+		{"/../secret.txt", 404, "", ""},
+		{"/logo.unknownext", 200, "image/png", ""},
+	}
+	for _, tt := range tests {
+		rec := get(tt.target, nil)
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.target, rec.Code, tt.status)
+			continue
+		}
+		if tt.contentType != "" && rec.Header().Get("Content-Type") != tt.contentType {
+			t.Errorf("%s: Content-Type = %q, want %q", tt.target, rec.Header().Get("Content-Type"), tt.contentType)
+		}
+		if tt.body != "" && rec.Body.String() != tt.body {
+			t.Errorf("%s: body = %q, want %q", tt.target, rec.Body.String(), tt.body)
+		}
+	}
+
+	first := get("/app.js", nil)
+	etag := first.Header().Get("ETag")
+	if etag == "" || first.Header().Get("Cache-Control") != "no-cache" {
+		t.Fatalf("headers = %v, want an ETag and no-cache", first.Header())
+	}
+	if rec := get("/app.js", http.Header{"If-None-Match": {etag}}); rec.Code != http.StatusNotModified {
+		t.Errorf("revalidation status = %d, want 304", rec.Code)
+	}
+	if rec := get("/app.js", http.Header{"Range": {"bytes=0-6"}}); rec.Code != http.StatusPartialContent || rec.Body.String() != "console" {
+		t.Errorf("range = %d %q, want 206 \"console\"", rec.Code, rec.Body.String())
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/app.js", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", rec.Code)
+	}
+}
+
+func TestStaticHandlerWithoutSPA(t *testing.T) {
+	h := StaticHandler(fstest.MapFS{"index.html": {Data: []byte("home")}}, StaticOptions{})
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/settings", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404 without SPA", rec.Code)
+	}
+}
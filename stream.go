@@ -0,0 +1,68 @@
+package glaze
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+)
+
+// defaultStreamFlushBytes is the amount of output StreamWriter accumulates
+// before flushing when no threshold is given.
+const defaultStreamFlushBytes = 4 << 10
+
+// StreamWriter writes through to an http.ResponseWriter and flushes it every
+// time at least threshold bytes have been written since the last flush, so
+// the browser can start painting a large page before it is fully rendered.
+type StreamWriter struct {
+	w         http.ResponseWriter
+	rc        *http.ResponseController
+	threshold int
+	pending   int
+}
+
+// NewStreamWriter returns a StreamWriter for w. A threshold of zero or less
+// uses 4 KiB.
+func NewStreamWriter(w http.ResponseWriter, threshold int) *StreamWriter {
+	if threshold <= 0 {
+		threshold = defaultStreamFlushBytes
+	}
+	return &StreamWriter{w: w, rc: http.NewResponseController(w), threshold: threshold}
+}
+
+func (s *StreamWriter) Write(p []byte) (int, error) {
+	n, err := s.w.Write(p)
+	s.pending += n
+	if err == nil && s.pending >= s.threshold {
+		err = s.Flush()
+	}
+	return n, err
+}
+
+// Flush sends everything written so far to the client. Writers that cannot
+// flush are tolerated: output is then delivered when the handler returns.
+func (s *StreamWriter) Flush() error {
+	s.pending = 0
+	if err := s.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// StreamTemplate executes the named template into w through a StreamWriter,
+// flushing progressively and once more at the end. It sets an HTML
+// Content-Type if the handler has not set one.
+//
+// AppWindow's tcp transport, and the gateway of its unix and pipe
+// transports, forward flushed output immediately. AppTransportScheme
+// buffers the whole response, so there the page paints once the template
+// has finished.
+func StreamTemplate(w http.ResponseWriter, tpl *template.Template, name string, data any) error {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	}
+	sw := NewStreamWriter(w, 0)
+	if err := tpl.ExecuteTemplate(sw, name, data); err != nil {
+		return err
+	}
+	return sw.Flush()
+}
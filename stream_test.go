@@ -0,0 +1,102 @@
+package glaze
+
+import (
+	"bufio"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// flushRecorder counts flushes on top of httptest.ResponseRecorder.
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushes int
+}
+
+func (f *flushRecorder) Flush() {
+	f.flushes++
+	f.ResponseRecorder.Flush()
+}
+
+func TestStreamWriterFlushesAtThreshold(t *testing.T) {
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	sw := NewStreamWriter(rec, 10)
+
+	_, _ = sw.Write([]byte("12345"))
+	if rec.flushes != 0 {
+		t.Fatalf("flushes = %d, want 0 below threshold", rec.flushes)
+	}
+	_, _ = sw.Write([]byte("67890"))
+	if rec.flushes != 1 {
+		t.Fatalf("flushes = %d, want 1 at threshold", rec.flushes)
+	}
+	_, _ = sw.Write([]byte("1"))
+	if rec.flushes != 1 {
+		t.Fatalf("flushes = %d, want counter reset after flush", rec.flushes)
+	}
+}
+
+func TestStreamTemplate(t *testing.T) {
+	tpl := template.Must(template.New("").Parse(`{{define "page"}}<p>{{.}}</p>{{end}}`))
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	if err := StreamTemplate(rec, tpl, "page", "hi"); err != nil {
+		t.Fatalf("StreamTemplate() unexpected error: %v", err)
+	}
+	if rec.Body.String() != "<p>hi</p>" {
+		t.Fatalf("body = %q", rec.Body.String())
+	}
+	if rec.flushes == 0 {
+		t.Fatal("StreamTemplate() did not flush")
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Fatalf("Content-Type = %q, want text/html", ct)
+	}
+}
+
+func TestUnixGatewayStreamsFlushedOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix transport is not supported on windows")
+	}
+	setup, err := setupUnixTransport("", nil, nil)
+	if err != nil {
+		t.Fatalf("setupUnixTransport() unexpected error: %v", err)
+	}
+	defer func() { _ = setup.close() }()
+
+	release := make(chan struct{})
+	defer close(release)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("head\n"))
+		_ = http.NewResponseController(w).Flush()
+		<-release
+		_, _ = w.Write([]byte("tail\n"))
+	})}
+	defer srv.Close()
+	setup.start()
+	go func() { _ = srv.Serve(setup.listener) }()
+
+	resp, err := http.Get(setup.baseURL)
+	if err != nil {
+		t.Fatalf("GET gateway: %v", err)
+	}
+	defer resp.Body.Close()
+
+	line := make(chan string, 1)
+	go func() {
+		s, _ := bufio.NewReader(resp.Body).ReadString('\n')
+		line <- s
+	}()
+	select {
+	case got := <-line:
+		if got != "head\n" {
+			t.Fatalf("first chunk = %q, want %q", got, "head\n")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("gateway buffered the flushed chunk")
+	}
+}
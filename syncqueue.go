@@ -0,0 +1,427 @@
+package glaze
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults used by NewSyncQueue when SyncOptions leaves them zero.
+const (
+	defaultSyncInterval    = 30 * time.Second
+	defaultSyncMinBackoff  = time.Second
+	defaultSyncMaxBackoff  = 5 * time.Minute
+	defaultSyncMaxAttempts = 10
+	defaultSyncTimeout     = 30 * time.Second
+)
+
+// SyncTask is an HTTP request queued in a SyncQueue.
+type SyncTask struct {
+	// ID identifies the task. Enqueue assigns one when it is empty.
+	ID string `json:"id"`
+
+	// Method is the HTTP method. Defaults to POST.
+	Method string `json:"method,omitempty"`
+
+	// URL is the address the request is sent to: a path relative to
+	// SyncOptions.BaseURL, or an absolute URL under it.
+	URL string `json:"url"`
+
+	// Header holds the request headers.
+	Header map[string]string `json:"header,omitempty"`
+
+	// Body is the request body, typically JSON.
+	Body string `json:"body,omitempty"`
+
+	// Attempts counts the failed attempts so far.
+	Attempts int `json:"attempts,omitempty"`
+
+	// Next is when the task is due; zero means now.
+	Next time.Time `json:"next,omitzero"`
+
+	// LastError describes the last failed attempt.
+	LastError string `json:"lastError,omitempty"`
+}
+
+// SyncResult reports the end of a task: sent, or given up on.
+type SyncResult struct {
+	// Task is the task, with its attempts counted.
+	Task SyncTask `json:"task"`
+
+	// Status is the HTTP status of the last response, or 0 when none was
+	// received.
+	Status int `json:"status"`
+
+	// Body is the body of the last response.
+	Body string `json:"body,omitempty"`
+
+	// Error describes why the task was given up on; empty when it was sent.
+	Error string `json:"error,omitempty"`
+}
+
+// SyncOptions configures NewSyncQueue.
+type SyncOptions struct {
+	// BaseURL is the server the queue talks to, such as
+	// "https://api.example.com/v1/". It is required: tasks are sent only to
+	// URLs with its scheme and host and under its path, so the page cannot
+	// make the app send requests to other hosts, such as intranet services
+	// the page itself could not reach.
+	BaseURL string
+
+	// Path is the file the queue is kept in, so tasks survive restarts.
+	// Empty keeps the queue in memory only.
+	Path string
+
+	// Client sends the requests. Defaults to a client with a 30 second
+	// timeout.
+	Client *http.Client
+
+	// Interval is how often due tasks are sent. Defaults to 30 seconds.
+	Interval time.Duration
+
+	// MinBackoff and MaxBackoff bound the delay before retrying a failed
+	// task, which doubles after each failure. Default to 1 second and 5
+	// minutes.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// MaxAttempts is how many times a task is tried before it is given up
+	// on. Defaults to 10.
+	MaxAttempts int
+
+	// OnResult receives the result of each task, from a background
+	// goroutine.
+	OnResult func(SyncResult)
+
+	// Prefix, when set, binds {Prefix}_enqueue, {Prefix}_pending and
+	// {Prefix}_sync for the page and emits "{Prefix}:done" and
+	// "{Prefix}:failed" events with each SyncResult.
+	Prefix string
+}
+
+// SyncQueue sends queued HTTP requests in the background, retrying failures
+// with exponential backoff, so offline-first apps can record changes locally
+// and push them once the server is reachable:
+//
+//	q, err := glaze.NewSyncQueue(w, glaze.SyncOptions{
+//		BaseURL: "https://api.example.com/",
+//		Path:    filepath.Join(dataDir, "sync.json"),
+//		Prefix:  "sync",
+//	})
+//	defer q.Close()
+//	_, _ = q.Enqueue(glaze.SyncTask{URL: "notes", Body: string(note)})
+//
+//	// JavaScript
+//	glaze.on("sync:failed", (r) => toast("Could not sync: " + r.error));
+//
+// Tasks are sent in order, one at a time. A 2xx response completes a task;
+// network errors, 408, 429 and 5xx responses are retried; other responses
+// fail it at once. The methods of a SyncQueue may be called from any
+// goroutine.
+type SyncQueue struct {
+	w    WebView
+	opts SyncOptions
+	base *url.URL
+
+	mu    sync.Mutex
+	tasks []SyncTask
+	seq   uint64
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewSyncQueue loads the queue kept at opts.Path and starts sending its
+// tasks. w may be nil when opts.Prefix is empty. Like Bind, it must be
+// called from the UI thread when opts.Prefix is set.
+func NewSyncQueue(w WebView, opts SyncOptions) (*SyncQueue, error) {
+	if opts.Prefix != "" && w == nil {
+		return nil, errors.New("webview: NewSyncQueue requires a non-nil WebView with a Prefix")
+	}
+	base, err := url.Parse(opts.BaseURL)
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("webview: NewSyncQueue requires an http or https BaseURL, not %q", opts.BaseURL)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	base.RawPath, base.RawQuery, base.Fragment = "", "", ""
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: defaultSyncTimeout}
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultSyncInterval
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = defaultSyncMinBackoff
+	}
+	if opts.MaxBackoff < opts.MinBackoff {
+		opts.MaxBackoff = max(defaultSyncMaxBackoff, opts.MinBackoff)
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultSyncMaxAttempts
+	}
+	q := &SyncQueue{w: w, opts: opts, base: base, wake: make(chan struct{}, 1), done: make(chan struct{})}
+	if err := q.load(); err != nil {
+		return nil, err
+	}
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+	if opts.Prefix != "" {
+		if _, err := BindAll(w, map[string]any{
+			opts.Prefix + "_enqueue": q.Enqueue,
+			opts.Prefix + "_pending": q.Pending,
+			opts.Prefix + "_sync":    q.SyncNow,
+		}); err != nil {
+			q.cancel()
+			return nil, err
+		}
+	}
+	go q.loop()
+	return q, nil
+}
+
+// Enqueue adds task to the queue, stores the queue and wakes the sender. It
+// returns the ID of the task, or an error when its URL is outside BaseURL.
+func (q *SyncQueue) Enqueue(task SyncTask) (string, error) {
+	if task.URL == "" {
+		return "", errors.New("webview: sync task requires a URL")
+	}
+	u, err := q.resolve(task.URL)
+	if err != nil {
+		return "", err
+	}
+	task.URL = u
+	if task.Method == "" {
+		task.Method = http.MethodPost
+	}
+	q.mu.Lock()
+	if task.ID == "" {
+		q.seq++
+		task.ID = strconv.FormatInt(time.Now().UnixNano(), 36) + "-" + strconv.FormatUint(q.seq, 10)
+	}
+	task.Attempts, task.Next, task.LastError = 0, time.Time{}, ""
+	q.tasks = append(q.tasks, task)
+	err = q.storeLocked()
+	q.mu.Unlock()
+	if err != nil {
+		return "", err
+	}
+	q.SyncNow()
+	return task.ID, nil
+}
+
+// resolve returns raw resolved against the base URL, or an error when it
+// falls outside it.
+func (q *SyncQueue) resolve(raw string) (string, error) {
+	u, err := q.base.Parse(raw)
+	if err != nil {
+		return "", fmt.Errorf("webview: sync task URL: %w", err)
+	}
+	if u.Scheme != q.base.Scheme || u.Host != q.base.Host || u.User != nil ||
+		!strings.HasPrefix(u.Path, q.base.Path) || slices.Contains(strings.Split(u.Path, "/"), "..") {
+		return "", fmt.Errorf("webview: sync task URL %s is %w", raw, errSyncOutside)
+	}
+	return u.String(), nil
+}
+
+// errSyncOutside is returned for task URLs outside SyncOptions.BaseURL.
+var errSyncOutside = errors.New("outside the sync queue's BaseURL")
+
+// Pending returns the tasks not yet sent or given up on, in order.
+func (q *SyncQueue) Pending() []SyncTask {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return slices.Clone(q.tasks)
+}
+
+// SyncNow sends the due tasks without waiting for the next interval, such
+// as when the app learns it is back online.
+func (q *SyncQueue) SyncNow() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Close stops sending. Tasks not yet sent stay in the queue file for the
+// next run.
+func (q *SyncQueue) Close() error {
+	q.cancel()
+	<-q.done
+	return nil
+}
+
+func (q *SyncQueue) loop() {
+	defer close(q.done)
+	ticker := time.NewTicker(q.opts.Interval)
+	defer ticker.Stop()
+	for {
+		q.run(time.Now())
+		select {
+		case <-q.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-q.wake:
+		}
+	}
+}
+
+// run sends the tasks due at now, in order, until one is still failing.
+func (q *SyncQueue) run(now time.Time) {
+	for q.ctx.Err() == nil {
+		q.mu.Lock()
+		if len(q.tasks) == 0 || q.tasks[0].Next.After(now) {
+			q.mu.Unlock()
+			return
+		}
+		task := q.tasks[0]
+		q.mu.Unlock()
+
+		status, body, err := q.send(task)
+		if q.ctx.Err() != nil {
+			return
+		}
+		result := SyncResult{Task: task, Status: status, Body: body}
+		retry := false
+		if err != nil {
+			task.Attempts++
+			task.LastError = err.Error()
+			result.Task = task
+			retry = retryable(status) && !errors.Is(err, errSyncOutside) && task.Attempts < q.opts.MaxAttempts
+			if !retry {
+				result.Error = err.Error()
+			}
+		}
+
+		q.mu.Lock()
+		if retry {
+			task.Next = now.Add(q.backoff(task.Attempts))
+			q.tasks[0] = task
+		} else {
+			q.tasks = q.tasks[1:]
+		}
+		storeErr := q.storeLocked()
+		q.mu.Unlock()
+		if retry {
+			// Keep the order: later tasks wait for this one.
+			return
+		}
+		if storeErr != nil && result.Error == "" {
+			result.Error = storeErr.Error()
+		}
+		q.report(result)
+	}
+}
+
+// send performs task, returning the response status and body, and an error
+// unless the status is 2xx.
+func (q *SyncQueue) send(task SyncTask) (int, string, error) {
+	// Tasks stored by an earlier run may predate a change of BaseURL.
+	target, err := q.resolve(task.URL)
+	if err != nil {
+		return 0, "", err
+	}
+	req, err := http.NewRequestWithContext(q.ctx, task.Method, target, bytes.NewReader([]byte(task.Body)))
+	if err != nil {
+		return 0, "", err
+	}
+	for k, v := range task.Header {
+		req.Header.Set(k, v)
+	}
+	resp, err := q.opts.Client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return resp.StatusCode, "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, string(body), fmt.Errorf("%s %s: %s", task.Method, task.URL, resp.Status)
+	}
+	return resp.StatusCode, string(body), nil
+}
+
+// retryable reports whether a request that failed with status, or with no
+// response when status is 0, may succeed later.
+func retryable(status int) bool {
+	return status == 0 || status == http.StatusRequestTimeout ||
+		status == http.StatusTooManyRequests || status >= 500
+}
+
+// backoff returns the delay before the attempt after the given number of
+// failed attempts.
+func (q *SyncQueue) backoff(attempts int) time.Duration {
+	d := q.opts.MinBackoff
+	for i := 1; i < attempts && d < q.opts.MaxBackoff; i++ {
+		d *= 2
+	}
+	return min(d, q.opts.MaxBackoff)
+}
+
+func (q *SyncQueue) report(r SyncResult) {
+	if q.opts.Prefix != "" {
+		event := q.opts.Prefix + ":done"
+		if r.Error != "" {
+			event = q.opts.Prefix + ":failed"
+		}
+		_ = Emit(q.w, event, r)
+	}
+	if q.opts.OnResult != nil {
+		q.opts.OnResult(r)
+	}
+}
+
+func (q *SyncQueue) load() error {
+	if q.opts.Path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(q.opts.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("webview: load sync queue: %w", err)
+	}
+	if err := json.Unmarshal(data, &q.tasks); err != nil {
+		return fmt.Errorf("webview: load sync queue: %w", err)
+	}
+	return nil
+}
+
+// storeLocked writes the queue to its file. q.mu must be held.
+func (q *SyncQueue) storeLocked() error {
+	if q.opts.Path == "" {
+		return nil
+	}
+	data, err := json.Marshal(q.tasks)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(q.opts.Path), 0o700); err != nil {
+		return fmt.Errorf("webview: store sync queue: %w", err)
+	}
+	tmp := q.opts.Path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("webview: store sync queue: %w", err)
+	}
+	if err := os.Rename(tmp, q.opts.Path); err != nil {
+		return fmt.Errorf("webview: store sync queue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package glaze
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSyncQueue(t *testing.T) {
+	var mu sync.Mutex
+	hits := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		hits[r.URL.Path]++
+		n := hits[r.URL.Path]
+		mu.Unlock()
+		switch {
+		case r.URL.Path == "/flaky" && n < 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/bad":
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			_, _ = w.Write(body)
+		}
+	}))
+	defer srv.Close()
+
+	results := make(chan SyncResult, 3)
+	q, err := NewSyncQueue(nil, SyncOptions{
+		BaseURL:    srv.URL,
+		Interval:   5 * time.Millisecond,
+		MinBackoff: time.Millisecond,
+		MaxBackoff: 2 * time.Millisecond,
+		OnResult:   func(r SyncResult) { results <- r },
+	})
+	if err != nil {
+		t.Fatalf("NewSyncQueue() unexpected error: %v", err)
+	}
+	defer q.Close()
+
+	for _, path := range []string{"/flaky", "/bad", "/ok"} {
+		if _, err := q.Enqueue(SyncTask{URL: srv.URL + path, Body: path}); err != nil {
+			t.Fatalf("Enqueue(%s) unexpected error: %v", path, err)
+		}
+	}
+	var got []SyncResult
+	for range 3 {
+		select {
+		case r := <-results:
+			got = append(got, r)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out with results %+v", got)
+		}
+	}
+
+	if !strings.HasSuffix(got[0].Task.URL, "/flaky") || got[0].Error != "" || got[0].Task.Attempts != 2 || got[0].Body != "/flaky" {
+		t.Errorf("flaky result = %+v, want sent after two retries", got[0])
+	}
+	if !strings.HasSuffix(got[1].Task.URL, "/bad") || got[1].Status != http.StatusBadRequest || got[1].Error == "" {
+		t.Errorf("bad result = %+v, want failed without retry", got[1])
+	}
+	if !strings.HasSuffix(got[2].Task.URL, "/ok") || got[2].Error != "" || got[2].Task.Method != http.MethodPost {
+		t.Errorf("ok result = %+v, want sent", got[2])
+	}
+	if p := q.Pending(); len(p) != 0 {
+		t.Errorf("Pending() = %+v, want empty", p)
+	}
+	if _, err := q.Enqueue(SyncTask{}); err == nil {
+		t.Error("Enqueue() without URL returned no error")
+	}
+}
+
+func TestSyncQueueBaseURL(t *testing.T) {
+	q, err := NewSyncQueue(nil, SyncOptions{BaseURL: "https://api.example.com/v1", Interval: time.Hour})
+	if err != nil {
+		t.Fatalf("NewSyncQueue() unexpected error: %v", err)
+	}
+	defer q.Close()
+
+	for raw, want := range map[string]string{
+		"notes":                              "https://api.example.com/v1/notes",
+		"/v1/notes?x=1":                      "https://api.example.com/v1/notes?x=1",
+		"https://api.example.com/v1/a/b":     "https://api.example.com/v1/a/b",
+		"http://api.example.com/v1/notes":    "",
+		"https://intranet.local/v1/notes":    "",
+		"https://api.example.com/admin":      "",
+		"https://api.example.com/v1/../x":    "",
+		"/v1/%2e%2e/admin":                   "",
+		"https://u:p@api.example.com/v1/n":   "",
+		"//169.254.169.254/latest/meta-data": "",
+	} {
+		got, err := q.resolve(raw)
+		if want == "" {
+			if err == nil {
+				t.Errorf("resolve(%q) = %q, want an error", raw, got)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("resolve(%q) = %q, %v, want %q", raw, got, err, want)
+		}
+	}
+	if _, err := q.Enqueue(SyncTask{URL: "http://10.0.0.1/"}); err == nil {
+		t.Error("Enqueue() accepted a URL outside BaseURL")
+	}
+
+	for _, base := range []string{"", "notes", "ftp://example.com/"} {
+		if _, err := NewSyncQueue(nil, SyncOptions{BaseURL: base}); err == nil {
+			t.Errorf("NewSyncQueue(BaseURL %q) returned no error", base)
+		}
+	}
+}
+
+func TestSyncQueuePersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync.json")
+	opts := SyncOptions{BaseURL: "http://127.0.0.1:1/", Path: path, Interval: time.Hour, MinBackoff: time.Hour}
+
+	q, err := NewSyncQueue(nil, opts)
+	if err != nil {
+		t.Fatalf("NewSyncQueue() unexpected error: %v", err)
+	}
+	// Nothing listens on port 1, so the task stays queued for an hour.
+	id, err := q.Enqueue(SyncTask{URL: "http://127.0.0.1:1/notes", Body: "{}"})
+	if err != nil {
+		t.Fatalf("Enqueue() unexpected error: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(q.Pending()) == 1 && q.Pending()[0].Attempts == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	_ = q.Close()
+
+	q, err = NewSyncQueue(nil, opts)
+	if err != nil {
+		t.Fatalf("NewSyncQueue() reload unexpected error: %v", err)
+	}
+	defer q.Close()
+	p := q.Pending()
+	if len(p) != 1 || p[0].ID != id || p[0].Attempts != 1 || p[0].LastError == "" {
+		t.Fatalf("reloaded Pending() = %+v, want the failed task", p)
+	}
+}
+
+func TestSyncQueueBackoff(t *testing.T) {
+	q := &SyncQueue{opts: SyncOptions{MinBackoff: time.Second, MaxBackoff: 5 * time.Second}}
+	for attempts, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 30: 5 * time.Second} {
+		if got := q.backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
+
+func TestSyncQueuePrefix(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+	q, err := NewSyncQueue(w, SyncOptions{BaseURL: "https://api.example.com/", Prefix: "sync", Interval: time.Hour})
+	if err != nil {
+		t.Fatalf("NewSyncQueue() unexpected error: %v", err)
+	}
+	defer q.Close()
+	for _, name := range []string{"sync_enqueue", "sync_pending", "sync_sync"} {
+		if _, ok := w.bound[name]; !ok {
+			t.Errorf("%s was not bound", name)
+		}
+	}
+	if _, err := NewSyncQueue(nil, SyncOptions{BaseURL: "https://api.example.com/", Prefix: "sync"}); err == nil {
+		t.Error("NewSyncQueue() with a Prefix and no WebView returned no error")
+	}
+}
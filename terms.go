@@ -0,0 +1,202 @@
+package glaze
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrTermsDeclined is returned by AcceptTerms and AppWindow when the user
+// declines the terms or closes the terms window without accepting them.
+var ErrTermsDeclined = errors.New("webview: terms were declined")
+
+// termsAnswerName is the hidden binding the terms page calls with the
+// user's answer.
+const termsAnswerName = "__glaze_terms"
+
+// Terms describes a license or terms of use the user must accept before the
+// app opens its main window.
+type Terms struct {
+	// ID names the app, e.g. "com.example.notes". Acceptance is recorded in
+	// a directory of that name under os.UserConfigDir. It must be a valid
+	// file name.
+	ID string
+
+	// Version identifies the terms. Changing it asks users who accepted an
+	// earlier version again.
+	Version string
+
+	// Title is the window title. Defaults to "License Agreement".
+	Title string
+
+	// Text is the plain text of the terms.
+	Text string
+
+	// HTML, when set, is shown instead of Text. It must be trusted markup.
+	HTML template.HTML
+
+	// Path overrides the file the acceptance is recorded in.
+	Path string
+
+	// Width and Height set the window dimensions. Default to 640x560.
+	Width  int
+	Height int
+}
+
+// termsRecord is the acceptance stored on disk.
+type termsRecord struct {
+	Version  string    `json:"version"`
+	Accepted time.Time `json:"accepted"`
+}
+
+// path returns the file acceptance of t is recorded in.
+func (t Terms) path() (string, error) {
+	if t.Path != "" {
+		return t.Path, nil
+	}
+	if t.ID == "" || t.ID != filepath.Base(t.ID) || t.ID == "." || t.ID == ".." {
+		return "", fmt.Errorf("webview: invalid terms ID %q", t.ID)
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("webview: locate config directory: %w", err)
+	}
+	return filepath.Join(dir, t.ID, "terms.json"), nil
+}
+
+// TermsAccepted reports whether the user has accepted this version of t.
+func TermsAccepted(t Terms) (bool, error) {
+	path, err := t.path()
+	if err != nil {
+		return false, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("webview: read terms acceptance: %w", err)
+	}
+	var rec termsRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		// A damaged record counts as no acceptance; asking again is safe.
+		return false, nil
+	}
+	return rec.Version == t.Version, nil
+}
+
+// recordTermsAcceptance stores that the user accepted this version of t.
+func recordTermsAcceptance(t Terms, now time.Time) error {
+	path, err := t.path()
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(termsRecord{Version: t.Version, Accepted: now.UTC()})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("webview: create terms directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("webview: record terms acceptance: %w", err)
+	}
+	return nil
+}
+
+// AcceptTerms shows t in a window of its own on first run and blocks until
+// the user accepts or declines. Acceptance is recorded, so later runs return
+// at once until t.Version changes. It returns ErrTermsDeclined if the user
+// declines or closes the window.
+//
+// Like Run, AcceptTerms must be called from the main thread, before the
+// main window is created. AppWindow calls it when AppOptions.Terms is set.
+func AcceptTerms(t Terms) error {
+	accepted, err := TermsAccepted(t)
+	if err != nil || accepted {
+		return err
+	}
+	if t.Title == "" {
+		t.Title = "License Agreement"
+	}
+	if t.Width <= 0 {
+		t.Width = 640
+	}
+	if t.Height <= 0 {
+		t.Height = 560
+	}
+
+	w, err := New(false)
+	if err != nil {
+		return fmt.Errorf("webview: %w", err)
+	}
+	defer w.Destroy()
+
+	answer := make(chan bool, 1)
+	if err := w.Bind(termsAnswerName, func(accept bool) {
+		select {
+		case answer <- accept:
+			w.Terminate()
+		default:
+		}
+	}); err != nil {
+		return err
+	}
+	w.SetTitle(t.Title)
+	w.SetSize(t.Width, t.Height, HintNone)
+	w.SetHtml(termsPage(t))
+	w.Run()
+
+	select {
+	case accept := <-answer:
+		if accept {
+			return recordTermsAcceptance(t, time.Now())
+		}
+	default:
+		// Window closed without an answer.
+	}
+	return ErrTermsDeclined
+}
+
+// termsPage renders the page shown by AcceptTerms.
+func termsPage(t Terms) string {
+	var buf bytes.Buffer
+	_ = termsTemplate.Execute(&buf, t) // template is trusted, Text is escaped
+	return buf.String()
+}
+
+var termsTemplate = template.Must(template.New("terms").Parse(`<!doctype html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+  <title>{{.Title}}</title>
+  <style>
+    html, body { height: 100%; margin: 0; }
+    body {
+      display: flex;
+      flex-direction: column;
+      font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Roboto, sans-serif;
+      background: #f9fafb;
+      color: #111827;
+    }
+    main { flex: 1; overflow: auto; margin: 16px 16px 0 16px; padding: 16px; background: #fff; border: 1px solid #d1d5db; border-radius: 8px; }
+    pre { margin: 0; white-space: pre-wrap; font: 13px/1.5 ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; }
+    footer { display: flex; gap: 8px; justify-content: flex-end; padding: 16px; }
+    button { border: 1px solid #d1d5db; background: #fff; color: #111827; border-radius: 6px; padding: 8px 16px; cursor: pointer; font-size: 14px; }
+    button.primary { border-color: #2563eb; background: #2563eb; color: #fff; }
+  </style>
+</head>
+<body>
+  <main>{{if .HTML}}{{.HTML}}{{else}}<pre>{{.Text}}</pre>{{end}}</main>
+  <footer>
+    <button onclick="__glaze_terms(false)">Decline</button>
+    <button class="primary" onclick="__glaze_terms(true)">Accept</button>
+  </footer>
+</body>
+</html>`))
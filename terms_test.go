@@ -0,0 +1,84 @@
+package glaze
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTermsAcceptance(t *testing.T) {
+	terms := Terms{Version: "2024-01", Path: filepath.Join(t.TempDir(), "app", "terms.json")}
+
+	if ok, err := TermsAccepted(terms); err != nil || ok {
+		t.Fatalf("TermsAccepted() before acceptance = %v, %v; want false", ok, err)
+	}
+	if err := recordTermsAcceptance(terms, time.Now()); err != nil {
+		t.Fatalf("recordTermsAcceptance() unexpected error: %v", err)
+	}
+	if ok, err := TermsAccepted(terms); err != nil || !ok {
+		t.Fatalf("TermsAccepted() after acceptance = %v, %v; want true", ok, err)
+	}
+	// AcceptTerms returns at once, without opening a window.
+	if err := AcceptTerms(terms); err != nil {
+		t.Fatalf("AcceptTerms() for accepted terms = %v", err)
+	}
+
+	terms.Version = "2025-01"
+	if ok, _ := TermsAccepted(terms); ok {
+		t.Error("TermsAccepted() = true for a new version")
+	}
+}
+
+func TestTermsAcceptedDamagedRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "terms.json")
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := TermsAccepted(Terms{Path: path}); err != nil || ok {
+		t.Fatalf("TermsAccepted() = %v, %v; want false, nil", ok, err)
+	}
+}
+
+func TestTermsPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		wantErr bool
+	}{
+		{name: "valid", id: "com.example.notes"},
+		{name: "empty", id: "", wantErr: true},
+		{name: "separator", id: "a/b", wantErr: true},
+		{name: "parent", id: "..", wantErr: true},
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AppData", t.TempDir())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := Terms{ID: tt.id}.path()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("path() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && filepath.Base(filepath.Dir(path)) != tt.id {
+				t.Errorf("path() = %q, want it under a %q directory", path, tt.id)
+			}
+		})
+	}
+}
+
+func TestTermsPage(t *testing.T) {
+	page := termsPage(Terms{Title: "EULA", Text: "No <script> allowed & such"})
+	if !strings.Contains(page, "No &lt;script&gt; allowed &amp; such") {
+		t.Error("terms text is not escaped")
+	}
+	if !strings.Contains(page, termsAnswerName+"(true)") || !strings.Contains(page, termsAnswerName+"(false)") {
+		t.Error("terms page does not answer through the hidden binding")
+	}
+
+	page = termsPage(Terms{HTML: "<h1>Terms</h1>", Text: "ignored"})
+	if !strings.Contains(page, "<h1>Terms</h1>") || strings.Contains(page, "ignored") {
+		t.Error("HTML terms not rendered in place of Text")
+	}
+}
@@ -0,0 +1,314 @@
+package glaze
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// txName is the hidden binding glaze.transaction begins, commits and rolls
+// back transactions through.
+const txName = "__glaze_tx"
+
+// txKey marks the extra argument that ties a call to a transaction.
+const txKey = "__glaze_tx"
+
+// txIdleTimeout is how long an open transaction may go without a call
+// before it is rolled back, so a page that never settles it does not hold
+// its locks forever.
+var txIdleTimeout = time.Minute
+
+// transactionJS adds glaze.transaction. Calls made through tx.run carry the
+// transaction ID as a trailing marker argument, which makeBinding strips.
+// Wrappers that call the bound function later, such as Serial's queue, keep
+// the transaction through glaze._txBind. tx.run rejects when f makes no
+// call while it runs, since a call it makes later, after an await or a
+// timer, would silently run outside the transaction.
+const transactionJS = `(function () {
+  'use strict';
+  var glaze = window.glaze = window.glaze || {};
+  if (glaze.transaction) { return; }
+  var current = null;
+  function patch() {
+    if (!window.__webview__) { return false; }
+    var proto = Object.getPrototypeOf(window.__webview__);
+    if (proto._glazeTx || Object.isFrozen(proto)) { return true; }
+    proto._glazeTx = true;
+    var call = proto.call;
+    proto.call = function () {
+      if (current === null) { return call.apply(this, arguments); }
+      current.calls++;
+      var args = Array.prototype.slice.call(arguments);
+      args.push({ ` + txKey + `: current.id });
+      return call.apply(this, args);
+    };
+    return true;
+  }
+  glaze._txBind = function (fn) {
+    var tx = current;
+    if (tx === null) { return fn; }
+    tx.calls++;
+    return function () {
+      var prev = current;
+      current = tx;
+      try {
+        return fn.apply(this, arguments);
+      } finally {
+        current = prev;
+      }
+    };
+  };
+  glaze.transaction = function (fn) {
+    return window.` + txName + `("begin", glaze._document || "").then(function (id) {
+      var tx = {
+        id: id,
+        run: function (f) {
+          var ctx = { id: id, calls: 0 };
+          var prev = current;
+          var result;
+          current = ctx;
+          try {
+            result = f.apply(null, Array.prototype.slice.call(arguments, 1));
+          } finally {
+            current = prev;
+          }
+          if (ctx.calls === 0) {
+            return Promise.reject(new Error("glaze: tx.run must call a bound function before it returns"));
+          }
+          return result;
+        }
+      };
+      return Promise.resolve().then(function () { return fn(tx); }).then(function (value) {
+        return window.` + txName + `("commit", id).then(function () { return value; });
+      }, function (err) {
+        return window.` + txName + `("rollback", id).then(function () { throw err; }, function () { throw err; });
+      });
+    });
+  };
+  if (!patch()) { document.addEventListener("DOMContentLoaded", patch); }
+})();`
+
+// Transaction is a unit of work that SetTransactions begins for each
+// glaze.transaction in the page. *sql.Tx implements it.
+type Transaction interface {
+	Commit() error
+	Rollback() error
+}
+
+// Tx is a transaction opened by glaze.transaction in the page. A bound
+// function receives the one its call belongs to by declaring a *Tx
+// parameter, which is nil for calls made outside a transaction. Like
+// *Progress, it is not sent by JavaScript. Calls in the same transaction
+// run one at a time.
+type Tx struct {
+	// ID identifies the transaction in the page.
+	ID string
+
+	// Transaction is the value the begin function returned, such as a
+	// *sql.Tx.
+	Transaction Transaction
+
+	mu    sync.Mutex
+	doc   string      // document that opened it
+	timer *time.Timer // rolls it back once idle
+}
+
+// SetTransactions lets the page group calls to functions bound in w into
+// one transaction, so multi-step UI operations commit or fail as a whole.
+// begin opens the transaction for each glaze.transaction in the page:
+//
+//	_ = glaze.SetTransactions(w, func() (glaze.Transaction, error) { return db.Begin() })
+//	w.Bind("notes_move", func(tx *glaze.Tx, id int64, folder string) error {
+//		_, err := tx.Transaction.(*sql.Tx).Exec(`UPDATE notes SET folder = ? WHERE id = ?`, folder, id)
+//		return err
+//	})
+//
+//	// JavaScript
+//	await glaze.transaction(async (tx) => {
+//	  await tx.run(notes_move, id, "archive");
+//	  await tx.run(folders_touch, "archive");
+//	});
+//
+// The transaction commits when the function given to glaze.transaction
+// resolves and rolls back when it rejects. It is also rolled back when the
+// page that opened it navigates away or reloads, when the window is
+// destroyed, and after a minute without calls. Only calls made through
+// tx.run belong to it, and tx.run rejects a function that does not call a
+// bound function right away. Like Bind, SetTransactions must be called
+// from the UI thread.
+func SetTransactions(w WebView, begin func() (Transaction, error)) error {
+	if w == nil {
+		return errors.New("webview: SetTransactions requires a non-nil WebView")
+	}
+	if begin == nil {
+		return errors.New("webview: SetTransactions requires a begin function")
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	b.txBegin = begin
+	b.mu.Unlock()
+	err := b.onDocument("transactions", func(doc string) {
+		b.abandonTxs(func(tx *Tx) bool { return doc == "" || tx.doc != doc })
+	})
+	if err != nil {
+		return err
+	}
+	if err := b.bindHidden(txName, b.transaction); err != nil {
+		return err
+	}
+	b.injectScript("transaction", transactionJS)
+	return nil
+}
+
+// transaction serves glaze.transaction: op is "begin", with the ID of the
+// page's document, or "commit" or "rollback", with the transaction ID.
+func (b *bridge) transaction(op, id string) (string, error) {
+	if op == "begin" {
+		b.mu.Lock()
+		begin := b.txBegin
+		b.txSeq++
+		doc := id
+		id = "tx" + strconv.FormatUint(b.txSeq, 10)
+		b.mu.Unlock()
+		t, err := begin()
+		if err != nil {
+			return "", err
+		}
+		tx := &Tx{ID: id, Transaction: t, doc: doc}
+		b.mu.Lock()
+		if b.txs == nil {
+			b.txs = make(map[string]*Tx)
+		}
+		b.txs[id] = tx
+		tx.timer = time.AfterFunc(txIdleTimeout, func() {
+			b.abandonTxs(func(t *Tx) bool { return t == tx })
+		})
+		b.mu.Unlock()
+		return id, nil
+	}
+
+	tx := b.takeTx(id)
+	if tx == nil {
+		return "", fmt.Errorf("webview: transaction %q is not open", id)
+	}
+	// Wait for calls still running in the transaction.
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+	switch op {
+	case "commit":
+		return "", tx.Transaction.Commit()
+	case "rollback":
+		return "", tx.Transaction.Rollback()
+	default:
+		_ = tx.Transaction.Rollback()
+		return "", fmt.Errorf("webview: unknown transaction operation %q", op)
+	}
+}
+
+// takeTx removes the open transaction id and stops its idle timer.
+func (b *bridge) takeTx(id string) *Tx {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	tx := b.txs[id]
+	if tx == nil {
+		return nil
+	}
+	delete(b.txs, id)
+	if tx.timer != nil {
+		tx.timer.Stop()
+	}
+	return tx
+}
+
+// abandonTxs rolls back the open transactions drop selects, once the calls
+// running in them return. It does not wait, so it is safe on the UI thread.
+func (b *bridge) abandonTxs(drop func(*Tx) bool) {
+	b.mu.Lock()
+	var ids []string
+	for id, tx := range b.txs {
+		if drop(tx) {
+			ids = append(ids, id)
+		}
+	}
+	b.mu.Unlock()
+	for _, id := range ids {
+		if tx := b.takeTx(id); tx != nil {
+			go func() {
+				tx.mu.Lock()
+				defer tx.mu.Unlock()
+				_ = tx.Transaction.Rollback()
+			}()
+		}
+	}
+}
+
+// splitTx removes the transaction marker glaze.transaction appends to the
+// arguments of a call, returning the transaction ID it names.
+func splitTx(args []json.RawMessage) ([]json.RawMessage, string) {
+	if len(args) == 0 {
+		return args, ""
+	}
+	last := bytes.TrimSpace(args[len(args)-1])
+	if len(last) == 0 || last[0] != '{' || !bytes.Contains(last, []byte(txKey)) {
+		return args, ""
+	}
+	var marker map[string]string
+	if err := json.Unmarshal(last, &marker); err != nil || len(marker) != 1 {
+		return args, ""
+	}
+	return args[:len(args)-1], marker[txKey]
+}
+
+// enterTx looks up the transaction txID names for the call id in w and
+// waits for its turn to run in it. The returned function ends the call.
+func enterTx(w WebView, id, txID string) (func(), error) {
+	if w == nil {
+		return nil, fmt.Errorf("webview: transaction %q is not open", txID)
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	tx := b.txs[txID]
+	b.mu.Unlock()
+	if tx == nil {
+		return nil, fmt.Errorf("webview: transaction %q is not open", txID)
+	}
+	tx.mu.Lock()
+	b.mu.Lock()
+	if b.txs[txID] != tx {
+		// Committed or rolled back while this call waited.
+		b.mu.Unlock()
+		tx.mu.Unlock()
+		return nil, fmt.Errorf("webview: transaction %q is not open", txID)
+	}
+	if b.txCalls == nil {
+		b.txCalls = make(map[string]*Tx)
+	}
+	b.txCalls[id] = tx
+	tx.timer.Stop()
+	b.mu.Unlock()
+	return func() {
+		b.mu.Lock()
+		delete(b.txCalls, id)
+		if b.txs[txID] == tx {
+			tx.timer.Reset(txIdleTimeout)
+		}
+		b.mu.Unlock()
+		tx.mu.Unlock()
+	}, nil
+}
+
+func txParam(w WebView, id string) reflect.Value {
+	var tx *Tx
+	if w != nil {
+		b := bridgeFor(w)
+		b.mu.Lock()
+		tx = b.txCalls[id]
+		b.mu.Unlock()
+	}
+	return reflect.ValueOf(tx)
+}
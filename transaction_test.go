@@ -0,0 +1,173 @@
+package glaze
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeTransaction struct {
+	mu  sync.Mutex
+	log []string
+}
+
+func (t *fakeTransaction) record(s string) {
+	t.mu.Lock()
+	t.log = append(t.log, s)
+	t.mu.Unlock()
+}
+
+func (t *fakeTransaction) Commit() error   { t.record("commit"); return nil }
+func (t *fakeTransaction) Rollback() error { t.record("rollback"); return nil }
+
+func TestTransactions(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	var opened []*fakeTransaction
+	if err := SetTransactions(w, func() (Transaction, error) {
+		tx := &fakeTransaction{}
+		opened = append(opened, tx)
+		return tx, nil
+	}); err != nil {
+		t.Fatalf("SetTransactions() unexpected error: %v", err)
+	}
+	if !strings.Contains(strings.Join(w.inits, "\n"), "glaze.transaction") {
+		t.Fatalf("init scripts = %d, want the transaction runtime", len(w.inits))
+	}
+	_ = w.Bind("notes_move", func(tx *Tx, id int, folder string) string {
+		if tx == nil {
+			return "no tx"
+		}
+		tx.Transaction.(*fakeTransaction).record(folder)
+		return tx.ID
+	})
+
+	if got, _ := w.call(t, "notes_move", 1, "inbox"); got != "no tx" {
+		t.Errorf("call outside a transaction got %v, want a nil *Tx", got)
+	}
+
+	id, err := w.call(t, txName, "begin", "")
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+	marker := map[string]any{txKey: id}
+	if got, err := w.call(t, "notes_move", 1, "archive", marker); err != nil || got != id {
+		t.Fatalf("call in transaction = %v, %v, want the transaction %v", got, err, id)
+	}
+	if _, err := w.call(t, txName, "commit", id); err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+	if got := strings.Join(opened[0].log, ","); got != "archive,commit" {
+		t.Errorf("transaction log = %s, want archive,commit", got)
+	}
+	if _, err := w.call(t, "notes_move", 1, "late", marker); err == nil || !strings.Contains(err.Error(), "not open") {
+		t.Errorf("call in a committed transaction error = %v, want not open", err)
+	}
+
+	id, _ = w.call(t, txName, "begin", "")
+	if _, err := w.call(t, txName, "rollback", id); err != nil {
+		t.Fatalf("rollback: %v", err)
+	}
+	if got := strings.Join(opened[1].log, ","); got != "rollback" {
+		t.Errorf("second transaction log = %s, want rollback", got)
+	}
+	if _, err := w.call(t, txName, "commit", id); err == nil {
+		t.Error("committing a rolled back transaction returned no error")
+	}
+}
+
+// waitLog waits for the transaction log to read want.
+func (t *fakeTransaction) waitLog(tb testing.TB, want string) {
+	tb.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		t.mu.Lock()
+		got := strings.Join(t.log, ",")
+		t.mu.Unlock()
+		if got == want {
+			return
+		}
+		if time.Now().After(deadline) {
+			tb.Fatalf("transaction log = %q, want %q", got, want)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestTransactionsAbandoned(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	var mu sync.Mutex
+	var opened []*fakeTransaction
+	if err := SetTransactions(w, func() (Transaction, error) {
+		tx := &fakeTransaction{}
+		mu.Lock()
+		opened = append(opened, tx)
+		mu.Unlock()
+		return tx, nil
+	}); err != nil {
+		t.Fatalf("SetTransactions() unexpected error: %v", err)
+	}
+
+	// A navigation rolls back what the previous document left open, but
+	// not a transaction the new document has already begun.
+	_, _ = w.call(t, documentName, "doc1")
+	_, _ = w.call(t, txName, "begin", "doc1")
+	_, _ = w.call(t, txName, "begin", "doc2")
+	_, _ = w.call(t, documentName, "doc2")
+	opened[0].waitLog(t, "rollback")
+	opened[1].mu.Lock()
+	got := strings.Join(opened[1].log, ",")
+	opened[1].mu.Unlock()
+	if got != "" {
+		t.Fatalf("new document's transaction log = %q, want it left open", got)
+	}
+
+	// Destroy rolls back everything still open.
+	forgetBridge(w)
+	opened[1].waitLog(t, "rollback")
+}
+
+func TestTransactionIdleTimeout(t *testing.T) {
+	defer func(d time.Duration) { txIdleTimeout = d }(txIdleTimeout)
+	txIdleTimeout = 20 * time.Millisecond
+
+	w := &fakeWebView{}
+	defer w.Destroy()
+	tx := &fakeTransaction{}
+	if err := SetTransactions(w, func() (Transaction, error) { return tx, nil }); err != nil {
+		t.Fatalf("SetTransactions() unexpected error: %v", err)
+	}
+	id, _ := w.call(t, txName, "begin", "")
+	tx.waitLog(t, "rollback")
+	if _, err := w.call(t, txName, "commit", id); err == nil {
+		t.Fatal("committing an expired transaction returned no error")
+	}
+}
+
+func TestSplitTx(t *testing.T) {
+	tests := []struct {
+		req    string
+		wantN  int
+		wantID string
+	}{
+		{`[1, {"__glaze_tx": "tx1"}]`, 1, "tx1"},
+		{`[1, {"__glaze_tx": "tx1", "other": "x"}]`, 2, ""},
+		{`[{"limit": 5}]`, 1, ""},
+		{`[]`, 0, ""},
+	}
+	for _, tt := range tests {
+		var raw []json.RawMessage
+		if err := json.Unmarshal([]byte(tt.req), &raw); err != nil {
+			t.Fatal(err)
+		}
+		args, id := splitTx(raw)
+		if len(args) != tt.wantN || id != tt.wantID {
+			t.Errorf("splitTx(%s) = %d args, %q, want %d, %q", tt.req, len(args), id, tt.wantN, tt.wantID)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package glaze
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrTrialInvalid is returned by ParseTrial for tokens that are
+	// malformed or not signed by the expected key.
+	ErrTrialInvalid = errors.New("webview: invalid trial token")
+
+	// ErrTrialExpired is returned by ParseTrial, along with the trial, for
+	// tokens past their expiry.
+	ErrTrialExpired = errors.New("webview: trial expired")
+)
+
+// FeatureLocked is the Error.Code of calls rejected by Trial.Gate.
+const FeatureLocked = "feature_locked"
+
+// Trial describes an evaluation build: who it was issued to, when it stops
+// working and which features it unlocks. A nil *Trial stands for a full
+// build, where every feature is available.
+type Trial struct {
+	// Subject identifies the evaluator, such as an email address.
+	Subject string `json:"sub,omitempty"`
+
+	// Expires is when the trial ends.
+	Expires time.Time `json:"exp"`
+
+	// Features lists the features the trial unlocks; "*" unlocks all.
+	Features []string `json:"features,omitempty"`
+}
+
+// SignTrial issues a token for t signed with key. It belongs in the vendor's
+// tooling, not in the shipped app, which only needs the public key.
+func SignTrial(key ed25519.PrivateKey, t Trial) (string, error) {
+	if len(key) != ed25519.PrivateKeySize {
+		return "", errors.New("webview: SignTrial requires an ed25519 private key")
+	}
+	payload, err := json.Marshal(t)
+	if err != nil {
+		return "", err
+	}
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString(payload) + "." + enc.EncodeToString(ed25519.Sign(key, payload)), nil
+}
+
+// ParseTrial verifies a token issued by SignTrial against key and returns
+// the trial it carries. A valid token past its expiry at now returns the
+// trial with ErrTrialExpired, so the app can say when it ended.
+//
+//	trial, err := glaze.ParseTrial(token, vendorKey, time.Now())
+//	if err != nil {
+//		// Show the purchase page instead of the app.
+//	}
+func ParseTrial(token string, key ed25519.PublicKey, now time.Time) (*Trial, error) {
+	if len(key) != ed25519.PublicKeySize {
+		return nil, errors.New("webview: ParseTrial requires an ed25519 public key")
+	}
+	encPayload, encSig, ok := strings.Cut(strings.TrimSpace(token), ".")
+	if !ok {
+		return nil, ErrTrialInvalid
+	}
+	enc := base64.RawURLEncoding
+	payload, err := enc.DecodeString(encPayload)
+	if err != nil {
+		return nil, ErrTrialInvalid
+	}
+	sig, err := enc.DecodeString(encSig)
+	if err != nil || !ed25519.Verify(key, payload, sig) {
+		return nil, ErrTrialInvalid
+	}
+	var t Trial
+	if err := json.Unmarshal(payload, &t); err != nil || t.Expires.IsZero() {
+		return nil, ErrTrialInvalid
+	}
+	if !now.Before(t.Expires) {
+		return &t, ErrTrialExpired
+	}
+	return &t, nil
+}
+
+// Allows reports whether feature is available.
+func (t *Trial) Allows(feature string) bool {
+	return t == nil || slices.Contains(t.Features, "*") || slices.Contains(t.Features, feature)
+}
+
+// Gate returns f when the trial allows feature and otherwise a stand-in to
+// bind in its place, whose calls reject with an *Error coded FeatureLocked.
+//
+//	w.Bind("notes_export", trial.Gate("export", notes.Export))
+func (t *Trial) Gate(feature string, f any) any {
+	if t.Allows(feature) {
+		return f
+	}
+	err := &Error{Code: FeatureLocked, Message: feature + " is not available in this trial", Data: feature}
+	return func(...json.RawMessage) error { return err }
+}
+
+// watermarkJS draws a repeated, click-through text watermark over the page
+// and puts it back if the page removes it.
+const watermarkJS = `(function (text) {
+  'use strict';
+  var id = "glaze-watermark";
+  var svg = '<svg xmlns="http://www.w3.org/2000/svg" width="320" height="200">' +
+    '<text x="160" y="100" text-anchor="middle" transform="rotate(-30 160 100)" ' +
+    'font-family="sans-serif" font-size="20" fill="rgba(127,127,127,0.25)"></text></svg>';
+  var doc = new DOMParser().parseFromString(svg, "image/svg+xml");
+  doc.querySelector("text").textContent = text;
+  var url = "url(data:image/svg+xml;base64," +
+    btoa(unescape(encodeURIComponent(new XMLSerializer().serializeToString(doc)))) + ")";
+  function place() {
+    if (document.getElementById(id)) { return; }
+    var el = document.createElement("div");
+    el.id = id;
+    el.setAttribute("aria-hidden", "true");
+    el.style.cssText = "position:fixed;inset:0;z-index:2147483646;pointer-events:none;background-image:" + url + ";";
+    document.documentElement.appendChild(el);
+  }
+  function start() {
+    place();
+    new MutationObserver(place).observe(document.documentElement, { childList: true });
+  }
+  if (document.documentElement) { start(); } else { document.addEventListener("DOMContentLoaded", start); }
+})(`
+
+// ShowWatermark overlays text, such as "EVALUATION COPY", across the window
+// content on the current page and every page loaded later. The watermark
+// lets clicks through. It can be set once per window and, like Bind, must
+// be called from the UI thread.
+func ShowWatermark(w WebView, text string) error {
+	if w == nil {
+		return errors.New("webview: ShowWatermark requires a non-nil WebView")
+	}
+	if strings.TrimSpace(text) == "" {
+		return errors.New("webview: ShowWatermark requires text")
+	}
+	b := bridgeFor(w)
+	b.mu.Lock()
+	set := b.scripts["watermark"]
+	b.mu.Unlock()
+	if set {
+		return errors.New("webview: watermark already set")
+	}
+	b.injectScript("watermark", watermarkJS+marshalJSON(text)+");")
+	return nil
+}
@@ -0,0 +1,98 @@
+package glaze
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrialTokens(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	token, err := SignTrial(priv, Trial{Subject: "eva@example.com", Expires: now.Add(24 * time.Hour), Features: []string{"export"}})
+	if err != nil {
+		t.Fatalf("SignTrial() unexpected error: %v", err)
+	}
+	payload, sig, _ := strings.Cut(token, ".")
+
+	tests := []struct {
+		name    string
+		token   string
+		key     ed25519.PublicKey
+		now     time.Time
+		wantErr error
+	}{
+		{name: "valid", token: token, key: pub, now: now},
+		{name: "expired", token: token, key: pub, now: now.Add(25 * time.Hour), wantErr: ErrTrialExpired},
+		{name: "other key", token: token, key: otherPub, now: now, wantErr: ErrTrialInvalid},
+		{name: "tampered", token: payload + "x." + sig, key: pub, now: now, wantErr: ErrTrialInvalid},
+		{name: "no signature", token: payload, key: pub, now: now, wantErr: ErrTrialInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trial, err := ParseTrial(tt.token, tt.key, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseTrial() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == ErrTrialInvalid {
+				if trial != nil {
+					t.Fatal("ParseTrial() returned a trial for an invalid token")
+				}
+				return
+			}
+			if trial.Subject != "eva@example.com" || !trial.Allows("export") {
+				t.Fatalf("ParseTrial() = %+v", trial)
+			}
+		})
+	}
+}
+
+func TestTrialGate(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	trial := &Trial{Features: []string{"export"}}
+	_ = w.Bind("export", trial.Gate("export", func() string { return "ok" }))
+	_ = w.Bind("sync", trial.Gate("sync", func() string { return "ok" }))
+
+	if got, err := w.call(t, "export"); err != nil || got != "ok" {
+		t.Errorf("export = %v, %v; want allowed", got, err)
+	}
+	_, err := w.call(t, "sync")
+	var locked *Error
+	if !errors.As(err, &locked) || locked.Code != FeatureLocked {
+		t.Errorf("sync error = %v, want a %s error", err, FeatureLocked)
+	}
+
+	var full *Trial
+	if !full.Allows("sync") {
+		t.Error("nil Trial should allow every feature")
+	}
+	if !(&Trial{Features: []string{"*"}}).Allows("sync") {
+		t.Error(`"*" should allow every feature`)
+	}
+}
+
+func TestShowWatermark(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	if err := ShowWatermark(w, `EVALUATION "COPY"`); err != nil {
+		t.Fatalf("ShowWatermark() unexpected error: %v", err)
+	}
+	if len(w.inits) != 1 || !strings.Contains(w.inits[0], `"EVALUATION \"COPY\""`) {
+		t.Fatalf("init scripts = %q, want the watermark with quoted text", w.inits)
+	}
+	if err := ShowWatermark(w, "again"); err == nil {
+		t.Fatal("second ShowWatermark() expected error")
+	}
+	if err := ShowWatermark(&fakeWebView{}, " "); err == nil {
+		t.Fatal("ShowWatermark() expected error for empty text")
+	}
+}
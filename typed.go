@@ -0,0 +1,175 @@
+package glaze
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Bind0 binds f as name in w, like w.Bind, with its signature checked at
+// compile time. Calls decode arguments and call f without reflection; only
+// signatures Bind cannot handle any faster, such as those with injected
+// parameters, go through the reflective path.
+//
+//	_ = glaze.Bind0(w, "notes_count", store.Count)
+//	_ = glaze.Bind1(w, "notes_get", func(id int64) (Note, error) { return store.Get(id) })
+//	_ = glaze.Bind2(w, "notes_move", func(id int64, folder string) (bool, error) { ... })
+//
+// The bound name behaves as with Bind everywhere else: BindOptions,
+// codecs, validate tags, metrics and Unbind all apply.
+func Bind0[R any](w WebView, name string, f func() (R, error)) error {
+	if f == nil {
+		return fmt.Errorf("webview: Bind0 requires a non-nil function")
+	}
+	return bindTyped(w, "Bind0", name, func0[R](f))
+}
+
+// Bind1 is Bind0 for a function of one argument.
+func Bind1[A, R any](w WebView, name string, f func(A) (R, error)) error {
+	if f == nil {
+		return fmt.Errorf("webview: Bind1 requires a non-nil function")
+	}
+	return bindTyped(w, "Bind1", name, func1[A, R](f))
+}
+
+// Bind2 is Bind0 for a function of two arguments.
+func Bind2[A, B, R any](w WebView, name string, f func(A, B) (R, error)) error {
+	if f == nil {
+		return fmt.Errorf("webview: Bind2 requires a non-nil function")
+	}
+	return bindTyped(w, "Bind2", name, func2[A, B, R](f))
+}
+
+// Bind3 is Bind0 for a function of three arguments.
+func Bind3[A, B, C, R any](w WebView, name string, f func(A, B, C) (R, error)) error {
+	if f == nil {
+		return fmt.Errorf("webview: Bind3 requires a non-nil function")
+	}
+	return bindTyped(w, "Bind3", name, func3[A, B, C, R](f))
+}
+
+func bindTyped(w WebView, op, name string, f typedFunc) error {
+	if w == nil {
+		return fmt.Errorf("webview: %s requires a non-nil WebView", op)
+	}
+	return w.Bind(name, f)
+}
+
+// typedFunc is implemented by the function types of the generic Bind
+// helpers. makeBinding calls invoke instead of reflect.Value.Call when no
+// parameter is injected.
+type typedFunc interface {
+	invoke(args *typedArgs) (any, error)
+}
+
+// typedArgs holds the JavaScript arguments of one call to a typedFunc, with
+// what makeBinding worked out about each parameter at bind time.
+type typedArgs struct {
+	codec   Codec
+	raw     []json.RawMessage
+	lenient []bool
+	checks  []*structValidator
+	invalid []FieldError
+}
+
+// typedArg decodes argument i as a T, recording validation failures to be
+// reported once every argument is decoded. An omitted argument is the zero
+// T.
+func typedArg[T any](args *typedArgs, i int) (T, error) {
+	var v T
+	if i >= len(args.raw) {
+		if args.checks[i] != nil {
+			args.checks[i].walk(reflect.ValueOf(v), i, "", &args.invalid)
+		}
+		return v, nil
+	}
+	var err error
+	handled := false
+	if o, ok := any(&v).(optionalArg); ok {
+		handled, err = true, o.decodeArg(args.codec, args.raw[i])
+	}
+	if !handled && args.lenient[i] && args.codec == JSONCodec {
+		handled, err = decodeLenient(args.raw[i], reflect.ValueOf(&v))
+	}
+	if !handled {
+		err = args.codec.Unmarshal(args.raw[i], &v)
+	}
+	if err != nil {
+		return v, argError(i, reflect.TypeFor[T](), err)
+	}
+	if args.checks[i] != nil {
+		args.checks[i].walk(reflect.ValueOf(v), i, "", &args.invalid)
+	}
+	return v, nil
+}
+
+type (
+	func0[R any]          func() (R, error)
+	func1[A, R any]       func(A) (R, error)
+	func2[A, B, R any]    func(A, B) (R, error)
+	func3[A, B, C, R any] func(A, B, C) (R, error)
+)
+
+func (f func0[R]) invoke(*typedArgs) (any, error) { return f() }
+
+func (f func1[A, R]) invoke(args *typedArgs) (any, error) {
+	a, err := typedArg[A](args, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err := validationError(args.invalid); err != nil {
+		return nil, err
+	}
+	return f(a)
+}
+
+func (f func2[A, B, R]) invoke(args *typedArgs) (any, error) {
+	a, err := typedArg[A](args, 0)
+	if err != nil {
+		return nil, err
+	}
+	b, err := typedArg[B](args, 1)
+	if err != nil {
+		return nil, err
+	}
+	if err := validationError(args.invalid); err != nil {
+		return nil, err
+	}
+	return f(a, b)
+}
+
+func (f func3[A, B, C, R]) invoke(args *typedArgs) (any, error) {
+	a, err := typedArg[A](args, 0)
+	if err != nil {
+		return nil, err
+	}
+	b, err := typedArg[B](args, 1)
+	if err != nil {
+		return nil, err
+	}
+	c, err := typedArg[C](args, 2)
+	if err != nil {
+		return nil, err
+	}
+	if err := validationError(args.invalid); err != nil {
+		return nil, err
+	}
+	return f(a, b, c)
+}
+
+// funcSignature returns the signature of the function type t, without the
+// name of the helper type Bind0 to Bind3 wrap it in.
+func funcSignature(t reflect.Type) string {
+	if t.Kind() != reflect.Func || t.Name() == "" {
+		return t.String()
+	}
+	in := make([]reflect.Type, t.NumIn())
+	for i := range in {
+		in[i] = t.In(i)
+	}
+	out := make([]reflect.Type, t.NumOut())
+	for i := range out {
+		out[i] = t.Out(i)
+	}
+	return reflect.FuncOf(in, out, t.IsVariadic()).String()
+}
@@ -0,0 +1,93 @@
+package glaze
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTypedBindings(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	if err := Bind0(w, "count", func() (int, error) { return 3, nil }); err != nil {
+		t.Fatalf("Bind0() unexpected error: %v", err)
+	}
+	_ = Bind1(w, "upper", func(s string) (string, error) { return strings.ToUpper(s), nil })
+	_ = Bind2(w, "wait", func(d time.Duration, at time.Time) (int64, error) { return int64(d/time.Second) + at.Unix(), nil })
+	_ = Bind3(w, "sum", func(a, b, c int) (int, error) { return a + b + c, nil })
+	_ = Bind1(w, "order", func(o vOrder) (string, error) { return o.Name, nil })
+	_ = Bind1(w, "bytes", func(s string) ([]byte, error) { return []byte(s), nil })
+	_ = Bind1(w, "fail", func(string) (any, error) { return nil, &Error{Code: "nope", Message: "no"} })
+	_ = Bind1(w, "deadline", func(ctx context.Context) (bool, error) { return ctx != nil, nil })
+
+	tests := []struct {
+		name    string
+		args    []any
+		want    any
+		wantErr string
+	}{
+		{name: "count", want: 3},
+		{name: "upper", args: []any{"abc"}, want: "ABC"},
+		{name: "wait", args: []any{"2s", 1000}, want: int64(3)},
+		{name: "sum", args: []any{1, 2, 3}, want: 6},
+		{name: "order", args: []any{map[string]any{"name": "ok"}}, want: "ok"},
+		{name: "bytes", args: []any{"hi"}, want: Binary("hi")},
+		{name: "deadline", want: true},
+		{name: "upper", args: []any{1}, wantErr: "argument 0 (string)"},
+		{name: "sum", args: []any{1, 2}, want: 3},
+		{name: "sum", args: []any{1, 2, 3, 4}, wantErr: "function arguments mismatch"},
+		{name: "order", args: []any{map[string]any{}}, wantErr: "name is required"},
+		{name: "fail", args: []any{""}, wantErr: "no"},
+	}
+	for _, tt := range tests {
+		got, err := w.call(t, tt.name, tt.args...)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("%s%v error = %v, want %q", tt.name, tt.args, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s%v unexpected error: %v", tt.name, tt.args, err)
+			continue
+		}
+		if jsonString(t, got) != jsonString(t, tt.want) {
+			t.Errorf("%s%v = %#v, want %#v", tt.name, tt.args, got, tt.want)
+		}
+	}
+
+	var e *Error
+	if _, err := w.call(t, "fail", ""); !errors.As(err, &e) || e.Code != "nope" {
+		t.Errorf("fail error = %v, want the *Error returned", err)
+	}
+}
+
+func TestTypedBindingsRequireArguments(t *testing.T) {
+	if err := Bind1[string, int](nil, "f", func(string) (int, error) { return 0, nil }); err == nil {
+		t.Error("Bind1(nil WebView) returned no error")
+	}
+	if err := Bind0[int](&fakeWebView{}, "f", nil); err == nil {
+		t.Error("Bind0(nil function) returned no error")
+	}
+}
+
+func TestFuncSignature(t *testing.T) {
+	f := func1[string, int](func(string) (int, error) { return 0, nil })
+	if got := funcSignature(reflect.TypeOf(f)); got != "func(string) (int, error)" {
+		t.Errorf("funcSignature() = %q, want the plain function type", got)
+	}
+}
+
+func jsonString(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("json.Marshal(%#v): %v", v, err)
+	}
+	return string(data)
+}
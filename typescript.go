@@ -0,0 +1,334 @@
+package glaze
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// GenerateTypes returns a TypeScript declaration file (.d.ts) describing the
+// functions BindMethodsWithOptions(w, prefix, obj, opts) exposes, with
+// parameter and return types derived from the Go signatures, so frontends get
+// type-checked calls into Go:
+//
+//	src, err := glaze.GenerateTypes("notes", &Notes{}, glaze.BindOptions{Namespace: true})
+//	// handle err
+//	os.WriteFile("web/glaze.d.ts", []byte(src), 0o644)
+//
+// Structs become interfaces named after the Go type, following encoding/json
+// field names and omitempty. Go does not keep parameter names, so parameters
+// are named arg0, arg1 and so on. The output is a global script declaration,
+// usable without imports. For a service bound with BindInterface, pass a nil
+// pointer to the interface, such as (*NotesAPI)(nil), to describe its
+// methods alone.
+func GenerateTypes(prefix string, obj any, opts BindOptions) (string, error) {
+	if obj == nil {
+		return "", fmt.Errorf("webview: GenerateTypes requires a non-nil object")
+	}
+	if opts.Namespace && prefix == "" {
+		return "", fmt.Errorf("webview: GenerateTypes requires a prefix to use as namespace")
+	}
+
+	g := &tsGenerator{names: make(map[reflect.Type]string), taken: make(map[string]bool), naming: opts.FieldNames}
+	var funcs []string
+	t, skip := reflect.TypeOf(obj), 1
+	if t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Interface {
+		// Interface method types have no receiver.
+		t, skip = t.Elem(), 0
+	}
+	methods, err := bindableMethods(t, opts)
+	if err != nil {
+		return "", err
+	}
+	for _, method := range methods {
+		name, member := methodJSName(prefix, method.Name, opts)
+		// Method types from a concrete reflect.Type include the receiver.
+		sig := g.signature(method.Type, skip)
+		if opts.Namespace {
+			funcs = append(funcs, "  "+member+sig+";")
+		} else {
+			funcs = append(funcs, "declare function "+name+sig+";")
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("// Code generated by glaze.GenerateTypes. DO NOT EDIT.\n\n")
+	b.WriteString("/** Promise returned by a Go binding that reports progress. */\n")
+	b.WriteString("interface GlazeProgressCall<T> extends Promise<T> {\n")
+	b.WriteString("  onprogress: ((event: { done: number; total: number; message?: string }) => void) | null;\n")
+	b.WriteString("}\n\n")
+	b.WriteString("/** Rejection value of a Go binding that returned a *glaze.Error. */\n")
+	b.WriteString("interface GlazeError {\n")
+	b.WriteString("  code: string;\n  message: string;\n  data?: any;\n")
+	b.WriteString("}\n")
+	for _, decl := range g.decls {
+		b.WriteString("\n" + decl)
+	}
+	b.WriteString("\n")
+	if opts.Namespace {
+		b.WriteString("declare const " + prefix + ": {\n")
+		for _, f := range funcs {
+			b.WriteString(f + "\n")
+		}
+		b.WriteString("};\n")
+	} else {
+		for _, f := range funcs {
+			b.WriteString(f + "\n")
+		}
+	}
+	return b.String(), nil
+}
+
+// tsGenerator converts Go types to TypeScript, collecting an interface
+// declaration for every named struct it meets.
+type tsGenerator struct {
+	names  map[reflect.Type]string
+	taken  map[string]bool
+	decls  []string
+	naming FieldNaming
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	rawMessageType    = reflect.TypeFor[json.RawMessage]()
+	jsonMarshalerType = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// signature renders the parameter list and return type of a bound function,
+// skipping the first skip parameters and any injected parameter.
+func (g *tsGenerator) signature(ft reflect.Type, skip int) string {
+	var params []string
+	var progress bool
+	// Trailing pointer and Optional parameters may be omitted by the page.
+	optional := 0
+	for i := skip; i < ft.NumIn(); i++ {
+		in := ft.In(i)
+		if _, injected := injectedParams[in]; injected {
+			progress = progress || in == reflect.TypeFor[*Progress]()
+			continue
+		}
+		name := fmt.Sprintf("arg%d", len(params))
+		if ft.IsVariadic() && i == ft.NumIn()-1 {
+			params = append(params, "..."+name+": "+g.array(in.Elem()))
+			optional = 0
+			continue
+		}
+		if omittable(in) {
+			optional++
+		} else {
+			optional = 0
+		}
+		switch {
+		case in == binaryType || binaryResult(in):
+			params = append(params, name+": BufferSource | string")
+			continue
+		case in == timeType:
+			params = append(params, name+": string | number | Date")
+			continue
+		case in == durationType:
+			params = append(params, name+": string | number")
+			continue
+		}
+		params = append(params, name+": "+g.typeOf(in))
+	}
+	for i := len(params) - optional; i < len(params); i++ {
+		params[i] = strings.Replace(params[i], ":", "?:", 1)
+	}
+
+	result := "void"
+	switch outs := resultTypes(ft); {
+	case len(outs) == 1 && binaryResult(outs[0]):
+		result = "Uint8Array | null"
+	case len(outs) == 1:
+		result = g.typeOf(outs[0])
+	case len(outs) > 1:
+		items := make([]string, len(outs))
+		for i, out := range outs {
+			items[i] = g.typeOf(out)
+		}
+		result = "[" + strings.Join(items, ", ") + "]"
+	}
+	promise := "Promise<" + result + ">"
+	if progress {
+		promise = "GlazeProgressCall<" + result + ">"
+	}
+	return "(" + strings.Join(params, ", ") + "): " + promise
+}
+
+//nolint:cyclop
+func (g *tsGenerator) typeOf(t reflect.Type) string {
+	if elem, ok := optionalElem(t); ok {
+		return g.typeOf(elem) + " | null"
+	}
+	switch {
+	case t == timeType:
+		return "string"
+	case t == rawMessageType:
+		return "any"
+	case t == binaryType:
+		return "Uint8Array | null"
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return "any"
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return "string"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Pointer:
+		return g.typeOf(t.Elem()) + " | null"
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // base64, as encoding/json does; see Binary
+		}
+		return g.array(t.Elem()) + " | null"
+	case reflect.Array:
+		return g.array(t.Elem())
+	case reflect.Map:
+		return "Record<string, " + g.typeOf(t.Elem()) + "> | null"
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structBody(t, "")
+		}
+		return g.named(t)
+	default:
+		return "any"
+	}
+}
+
+func (g *tsGenerator) array(elem reflect.Type) string {
+	ts := g.typeOf(elem)
+	if strings.ContainsAny(ts, " |&") {
+		ts = "(" + ts + ")"
+	}
+	return ts + "[]"
+}
+
+// named returns the interface name for a named struct, declaring it on first
+// use. Names are reserved before the fields are walked, so recursive types
+// refer to themselves.
+func (g *tsGenerator) named(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	base := typeIdent(t)
+	name := base
+	for n := 2; g.taken[name]; n++ {
+		name = fmt.Sprintf("%s%d", base, n)
+	}
+	g.taken[name] = true
+	g.names[t] = name
+	idx := len(g.decls)
+	g.decls = append(g.decls, "") // keep declarations in order of first use
+	g.decls[idx] = "interface " + name + " " + g.structBody(t, "") + "\n"
+	return name
+}
+
+// structBody renders the members of a struct as a TypeScript object type.
+func (g *tsGenerator) structBody(t reflect.Type, indent string) string {
+	var fields []string
+	g.fields(t, &fields, map[string]bool{})
+	if len(fields) == 0 {
+		return "{}"
+	}
+	return "{\n" + indent + "  " + strings.Join(fields, "\n"+indent+"  ") + "\n" + indent + "}"
+}
+
+// fields appends the JSON members of t, following encoding/json rules for
+// tags and embedded structs. Names seen at a shallower depth win.
+func (g *tsGenerator) fields(t reflect.Type, out *[]string, seen map[string]bool) {
+	var embedded []reflect.Type
+	for i := range t.NumField() {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		ft := f.Type
+		if f.Anonymous && name == "" {
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				embedded = append(embedded, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = g.naming.name(f.Name)
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		optional := ""
+		if strings.Contains(opts, "omitempty") || strings.Contains(opts, "omitzero") {
+			optional = "?"
+		}
+		ts := g.typeOf(ft)
+		if strings.Contains(opts, "string") {
+			ts = "string"
+		}
+		*out = append(*out, tsKey(name)+optional+": "+ts+";")
+	}
+	for _, et := range embedded {
+		g.fields(et, out, seen)
+	}
+}
+
+// typeIdent returns the name of t as an identifier. Instances of generic
+// types, such as Page[main.Note], become PageOfNote.
+func typeIdent(t reflect.Type) string {
+	name := t.Name()
+	open := strings.IndexByte(name, '[')
+	if open < 0 || !strings.HasSuffix(name, "]") {
+		return name
+	}
+	var b strings.Builder
+	b.WriteString(name[:open] + "Of")
+	for _, arg := range strings.Split(name[open+1:len(name)-1], ",") {
+		if i := strings.LastIndexAny(arg, "./"); i >= 0 {
+			arg = arg[i+1:]
+		}
+		upper := true
+		for _, r := range arg {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+				upper = true
+				continue
+			}
+			if upper {
+				r = unicode.ToUpper(r)
+				upper = false
+			}
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// tsKey quotes a member name when it is not a valid identifier.
+func tsKey(name string) string {
+	for i, r := range name {
+		if r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return marshalJSON(name)
+	}
+	return name
+}
@@ -0,0 +1,89 @@
+package glaze
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type tsNote struct {
+	ID       int64          `json:"id"`
+	Title    string         `json:"title"`
+	Tags     []string       `json:"tags,omitempty"`
+	Parent   *tsNote        `json:"parent"`
+	Meta     map[string]any `json:"meta"`
+	Created  time.Time      `json:"created"`
+	Data     []byte         `json:"data"`
+	Secret   string         `json:"-"`
+	internal string
+	tsAudit
+}
+
+type tsAudit struct {
+	Author string `json:"author"`
+}
+
+type tsNotes struct{}
+
+func (*tsNotes) Add(_ string, _ []string) (tsNote, error)      { return tsNote{}, nil }
+func (*tsNotes) List(_ ...int) []tsNote                        { return nil }
+func (*tsNotes) Delete(_ int64) error                          { return nil }
+func (*tsNotes) Export(_ string, _ *Progress) (string, error)  { return "", nil }
+func (*tsNotes) Snooze(_ time.Time, _ time.Duration) error     { return nil }
+func (*tsNotes) Find(_ *tsNote, _ string, _ *tsAudit) []tsNote { return nil }
+func (*tsNotes) Page(_ int) ([]tsNote, int, error)             { return nil, 0, nil }
+func (*tsNotes) Stats() struct {
+	Count int `json:"count"`
+} {
+	return struct {
+		Count int `json:"count"`
+	}{}
+}
+
+func TestGenerateTypes(t *testing.T) {
+	src, err := GenerateTypes("notes", &tsNotes{}, BindOptions{})
+	if err != nil {
+		t.Fatalf("GenerateTypes() unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"interface tsNote {\n  id: number;\n  title: string;\n  tags?: string[] | null;\n  parent: tsNote | null;\n" +
+			"  meta: Record<string, any> | null;\n  created: string;\n  data: string;\n  author: string;\n}",
+		"declare function notes_add(arg0: string, arg1: string[] | null): Promise<tsNote>;",
+		"declare function notes_list(...arg0: number[]): Promise<tsNote[] | null>;",
+		"declare function notes_delete(arg0: number): Promise<void>;",
+		"declare function notes_export(arg0: string): GlazeProgressCall<string>;",
+		"declare function notes_find(arg0: tsNote | null, arg1: string, arg2?: tsAudit | null): Promise<tsNote[] | null>;",
+		"declare function notes_snooze(arg0: string | number | Date, arg1: string | number): Promise<void>;",
+		"declare function notes_stats(): Promise<{\n  count: number;\n}>;",
+		"declare function notes_page(arg0: number): Promise<[tsNote[] | null, number]>;",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("GenerateTypes() output missing:\n%s\n--- got ---\n%s", want, src)
+		}
+	}
+	if strings.Contains(src, "Secret") || strings.Contains(src, "internal") {
+		t.Error("GenerateTypes() emitted skipped fields")
+	}
+}
+
+func TestGenerateTypesNamespace(t *testing.T) {
+	src, err := GenerateTypes("notes", &tsNotes{}, BindOptions{Namespace: true})
+	if err != nil {
+		t.Fatalf("GenerateTypes() unexpected error: %v", err)
+	}
+	if !strings.Contains(src, "declare const notes: {\n  add(arg0: string, arg1: string[] | null): Promise<tsNote>;") {
+		t.Errorf("GenerateTypes() namespace output:\n%s", src)
+	}
+	if _, err := GenerateTypes("", &tsNotes{}, BindOptions{Namespace: true}); err == nil {
+		t.Error("GenerateTypes() expected error for empty namespace")
+	}
+	if _, err := GenerateTypes("x", nil, BindOptions{}); err == nil {
+		t.Error("GenerateTypes() expected error for nil object")
+	}
+}
+
+func TestTSKey(t *testing.T) {
+	if tsKey("ok_name$1") != "ok_name$1" || tsKey("content-type") != `"content-type"` || tsKey("1st") != `"1st"` {
+		t.Fatal("tsKey() quoted names incorrectly")
+	}
+}
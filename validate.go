@@ -0,0 +1,321 @@
+package glaze
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ValidationFailed is the Error.Code of calls rejected because a struct
+// argument broke its validate tags.
+const ValidationFailed = "validation"
+
+// FieldError reports one field of a struct argument that failed validation.
+// It is the element type of the Data of the *Error a rejected call returns.
+type FieldError struct {
+	// Arg is the index of the JavaScript argument holding the field.
+	Arg int `json:"arg"`
+
+	// Field is the path of the field by its JSON names, such as
+	// "address.city" or "items[2].qty".
+	Field string `json:"field"`
+
+	// Rule is the rule that failed: "required", "min", "max" or "regexp".
+	Rule string `json:"rule"`
+
+	// Message describes the failure, e.g. "must be at least 3 characters".
+	Message string `json:"message"`
+}
+
+// Validate checks v, a struct or pointer to one, against the validate tags
+// of its fields, as bound functions do for their struct arguments. It
+// returns nil or an *Error with Code ValidationFailed and a []FieldError as
+// Data. Rules are separated by commas; regexp takes the rest of the tag:
+//
+//	type NewNote struct {
+//		Title string   `json:"title" validate:"required,max=120"`
+//		Tags  []string `json:"tags" validate:"max=10"`
+//		Slug  string   `json:"slug" validate:"min=3,regexp=^[a-z0-9-]+$"`
+//	}
+//
+// required rejects zero values; min and max bound numbers, the length in
+// characters of strings and the length of slices and maps; regexp matches
+// strings. Empty strings count as missing and only fail required. Nested
+// structs, pointers to them and slices of them are checked too.
+func Validate(v any) error {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return nil
+	}
+	sv, err := validatorFor(rv.Type())
+	if err != nil || sv == nil {
+		return err
+	}
+	var errs []FieldError
+	sv.walk(rv, 0, "", &errs)
+	return validationError(errs)
+}
+
+// validationError builds the error for errs, or returns nil when it is
+// empty.
+func validationError(errs []FieldError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Field + " " + e.Message
+	}
+	return &Error{Code: ValidationFailed, Message: strings.Join(msgs, "; "), Data: errs}
+}
+
+// structValidator checks the tagged fields of one struct type.
+type structValidator struct {
+	fields []fieldCheck
+	done   bool
+}
+
+// fieldCheck holds the rules of one field and the validator of the struct
+// it holds, if any.
+type fieldCheck struct {
+	index    int
+	name     string
+	embedded bool
+	rules    []fieldRule
+	nested   *structValidator
+}
+
+// fieldRule is one parsed validate rule.
+type fieldRule struct {
+	name  string
+	limit float64
+	re    *regexp.Regexp
+}
+
+// validators caches the validator of each struct type; a nil entry means the
+// type has no rules.
+var validators sync.Map
+
+// validatorFor returns the validator for arguments of type t, or nil when
+// nothing in t is tagged.
+func validatorFor(t reflect.Type) (*structValidator, error) {
+	st := structUnder(t)
+	if st == nil {
+		return nil, nil
+	}
+	if v, ok := validators.Load(st); ok {
+		return v.(*structValidator), nil
+	}
+	sv, err := compileValidator(st, make(map[reflect.Type]*structValidator))
+	if err != nil {
+		return nil, err
+	}
+	if len(sv.fields) == 0 {
+		sv = nil
+	}
+	validators.Store(st, sv)
+	return sv, nil
+}
+
+// structUnder returns the struct type under t's pointers, slices and arrays,
+// or nil if there is none.
+func structUnder(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if elem, ok := optionalElem(t); ok {
+		return structUnder(elem)
+	}
+	if t.Kind() != reflect.Struct || t == timeType {
+		return nil
+	}
+	return t
+}
+
+func compileValidator(t reflect.Type, compiling map[reflect.Type]*structValidator) (*structValidator, error) {
+	if sv, ok := compiling[t]; ok {
+		return sv, nil
+	}
+	sv := &structValidator{}
+	compiling[t] = sv
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() && !f.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		check := fieldCheck{index: i, name: name, embedded: f.Anonymous && name == ""}
+		if check.name == "" {
+			check.name = f.Name
+		}
+		rules, err := parseRules(f.Tag.Get("validate"), f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("webview: validate tag of %s.%s: %w", t, f.Name, err)
+		}
+		check.rules = rules
+		if st := structUnder(f.Type); st != nil {
+			nested, err := compileValidator(st, compiling)
+			if err != nil {
+				return nil, err
+			}
+			// A validator still being compiled is a recursive type; keep it.
+			if len(nested.fields) > 0 || !nested.done {
+				check.nested = nested
+			}
+		}
+		if len(check.rules) > 0 || check.nested != nil {
+			sv.fields = append(sv.fields, check)
+		}
+	}
+	sv.done = true
+	return sv, nil
+}
+
+// parseRules parses a validate tag for a field of type t.
+func parseRules(tag string, t reflect.Type) ([]fieldRule, error) {
+	var rules []fieldRule
+	base := t
+	for base.Kind() == reflect.Pointer {
+		base = base.Elem()
+	}
+	for tag != "" {
+		if pattern, ok := strings.CutPrefix(tag, "regexp="); ok {
+			if base.Kind() != reflect.String {
+				return nil, errors.New("regexp applies to strings only")
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, fieldRule{name: "regexp", re: re})
+			break
+		}
+		var item string
+		item, tag, _ = strings.Cut(tag, ",")
+		name, arg, _ := strings.Cut(item, "=")
+		switch name {
+		case "required":
+			rules = append(rules, fieldRule{name: name})
+		case "min", "max":
+			limit, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s needs a number, got %q", name, arg)
+			}
+			if _, _, ok := measure(reflect.Zero(base)); !ok {
+				return nil, fmt.Errorf("%s does not apply to %s", name, base)
+			}
+			rules = append(rules, fieldRule{name: name, limit: limit})
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown rule %q", name)
+		}
+	}
+	return rules, nil
+}
+
+// measure returns the quantity min and max compare for v, with the unit
+// used in messages; ok is false for kinds they do not apply to.
+func measure(v reflect.Value) (n float64, unit string, ok bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), "", true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), " characters", true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(v.Len()), " items", true
+	default:
+		return 0, "", false
+	}
+}
+
+// check returns the failure message of r for v, or "" if v passes.
+func (r fieldRule) check(v reflect.Value) string {
+	if r.name == "required" {
+		if v.IsZero() || (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0 {
+			return "is required"
+		}
+		return ""
+	}
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.String && v.Len() == 0 {
+		// An empty string is a missing value, which only required rejects.
+		return ""
+	}
+	limit := strconv.FormatFloat(r.limit, 'f', -1, 64)
+	switch r.name {
+	case "min":
+		if n, unit, ok := measure(v); ok && n < r.limit {
+			return "must be at least " + limit + unit
+		}
+	case "max":
+		if n, unit, ok := measure(v); ok && n > r.limit {
+			return "must be at most " + limit + unit
+		}
+	case "regexp":
+		if !r.re.MatchString(v.String()) {
+			return "must match " + r.re.String()
+		}
+	}
+	return ""
+}
+
+// walk validates v, which holds values of the validator's struct type under
+// pointers, slices or arrays, appending failures under path to errs.
+func (sv *structValidator) walk(v reflect.Value, arg int, path string, errs *[]FieldError) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			sv.walk(v.Elem(), arg, path, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			sv.walk(v.Index(i), arg, fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	case reflect.Struct:
+		if _, ok := optionalElem(v.Type()); ok && v.CanInterface() {
+			if value, set := v.Interface().(optionalType).optionalValue(); set {
+				sv.walk(value, arg, path, errs)
+			}
+			return
+		}
+		for _, fc := range sv.fields {
+			fv := v.Field(fc.index)
+			p := path
+			if !fc.embedded {
+				p = joinFieldPath(path, fc.name)
+			}
+			for _, r := range fc.rules {
+				if msg := r.check(fv); msg != "" {
+					*errs = append(*errs, FieldError{Arg: arg, Field: p, Rule: r.name, Message: msg})
+				}
+			}
+			if fc.nested != nil {
+				fc.nested.walk(fv, arg, p, errs)
+			}
+		}
+	}
+}
+
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,140 @@
+package glaze
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type vAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type vItem struct {
+	Qty int `json:"qty" validate:"min=1,max=99"`
+}
+
+type vOrder struct {
+	Name    string    `json:"name" validate:"required,max=5"`
+	Code    string    `json:"code" validate:"min=2,regexp=^[a-z]{2,},x$"`
+	Tags    []string  `json:"tags" validate:"max=2"`
+	Price   *float64  `json:"price" validate:"min=0.5"`
+	Address *vAddress `json:"address"`
+	Items   []vItem   `json:"items"`
+	Next    *vOrder   `json:"next"`
+	ignored string    `validate:"required"` //nolint:unused
+}
+
+func TestValidate(t *testing.T) {
+	low := 0.25
+	tests := []struct {
+		name  string
+		order vOrder
+		want  []FieldError
+	}{
+		{name: "valid", order: vOrder{Name: "ok", Code: "ab,x", Items: []vItem{{Qty: 1}}}},
+		{
+			name:  "fields",
+			order: vOrder{Name: "too long", Code: "a", Tags: []string{"a", "b", "c"}, Price: &low},
+			want: []FieldError{
+				{Field: "name", Rule: "max", Message: "must be at most 5 characters"},
+				{Field: "code", Rule: "min", Message: "must be at least 2 characters"},
+				{Field: "code", Rule: "regexp", Message: "must match ^[a-z]{2,},x$"},
+				{Field: "tags", Rule: "max", Message: "must be at most 2 items"},
+				{Field: "price", Rule: "min", Message: "must be at least 0.5"},
+			},
+		},
+		{
+			name:  "nested",
+			order: vOrder{Name: "x", Address: &vAddress{}, Items: []vItem{{Qty: 1}, {Qty: 100}}, Next: &vOrder{}},
+			want: []FieldError{
+				{Field: "address.city", Rule: "required", Message: "is required"},
+				{Field: "items[1].qty", Rule: "max", Message: "must be at most 99"},
+				{Field: "next.name", Rule: "required", Message: "is required"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(&tt.order)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			var verr *Error
+			if !errors.As(err, &verr) || verr.Code != ValidationFailed {
+				t.Fatalf("Validate() = %v, want a validation *Error", err)
+			}
+			if got := verr.Data.([]FieldError); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("field errors = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateBadTags(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{name: "unknown rule", v: struct {
+			A string `validate:"email"`
+		}{}},
+		{name: "bad number", v: struct {
+			A int `validate:"min=x"`
+		}{}},
+		{name: "min on bool", v: struct {
+			A bool `validate:"min=1"`
+		}{}},
+		{name: "regexp on int", v: struct {
+			A int `validate:"regexp=^1$"`
+		}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.v); err == nil {
+				t.Fatal("Validate() expected error for a bad tag")
+			}
+		})
+	}
+}
+
+func TestBindingValidatesArguments(t *testing.T) {
+	called := false
+	fn, err := makeFuncWrapper(func(_ string, o vOrder) string {
+		called = true
+		return o.Name
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = fn("id", `["x", {"name": "", "items": [{"qty": 0}]}]`)
+	var verr *Error
+	if !errors.As(err, &verr) || verr.Code != ValidationFailed {
+		t.Fatalf("error = %v, want a validation *Error", err)
+	}
+	want := []FieldError{
+		{Arg: 1, Field: "name", Rule: "required", Message: "is required"},
+		{Arg: 1, Field: "items[0].qty", Rule: "min", Message: "must be at least 1"},
+	}
+	if got := verr.Data.([]FieldError); !reflect.DeepEqual(got, want) {
+		t.Errorf("field errors = %+v\nwant %+v", got, want)
+	}
+	if called {
+		t.Error("handler ran despite invalid arguments")
+	}
+
+	if got, err := fn("id", `["x", {"name": "ok"}]`); err != nil || got != "ok" {
+		t.Fatalf("valid call = %v, %v", got, err)
+	}
+
+	if _, err := makeFuncWrapper(func(struct {
+		A string `validate:"nope"`
+	}) {
+	}); err == nil {
+		t.Fatal("binding a function with a bad validate tag should fail")
+	}
+}
@@ -0,0 +1,129 @@
+package glaze
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+)
+
+// Conflict is the Error.Code of mutations rejected because the record
+// changed after the page read it.
+const Conflict = "conflict"
+
+// conflictJS adds glaze.retryOnConflict, which repeats a mutation rejected
+// with a conflict against the latest record the error carries.
+const conflictJS = `(function () {
+  'use strict';
+  var glaze = window.glaze = window.glaze || {};
+  if (glaze.retryOnConflict) { return; }
+  glaze.retryOnConflict = function (attempt, opts) {
+    var retries = opts && opts.retries !== undefined ? opts.retries : 3;
+    function run(latest, left) {
+      return Promise.resolve().then(function () { return attempt(latest); }).catch(function (err) {
+        if (!err || err.code !== "` + Conflict + `" || left <= 0) { throw err; }
+        return run(err.data, left - 1);
+      });
+    }
+    return run(undefined, retries);
+  };
+})();`
+
+// Version is an optimistic concurrency token, like an HTTP ETag. Records
+// carry one to the page, mutations send it back, and the store rejects a
+// mutation whose Version is no longer current, so two windows editing the
+// same row cannot silently overwrite each other:
+//
+//	type Note struct {
+//		ID      int64         `json:"id"`
+//		Title   string        `json:"title"`
+//		Version glaze.Version `json:"version"`
+//	}
+//
+//	w.Bind("notes_save", func(n Note) (Note, error) {
+//		next := glaze.NewVersion()
+//		res, err := db.Exec(`UPDATE notes SET title = ?, version = ? WHERE id = ? AND version = ?`,
+//			n.Title, next, n.ID, n.Version)
+//		if err != nil {
+//			return Note{}, err
+//		}
+//		if rows, _ := res.RowsAffected(); rows == 0 {
+//			latest, _ := loadNote(n.ID)
+//			return Note{}, glaze.ConflictError(latest)
+//		}
+//		n.Version = next
+//		return n, nil
+//	})
+//
+// Binding a function with a Version parameter, or a struct parameter with a
+// Version field, installs glaze.retryOnConflict in the page. It calls a
+// mutation again with the latest record whenever it is rejected with a
+// conflict, up to opts.retries times (3 by default):
+//
+//	const saved = await glaze.retryOnConflict(
+//	  (latest) => notes_save({ ...(latest || note), title }), { retries: 3 });
+type Version string
+
+// NewVersion returns a random Version, for a record that was just created
+// or changed.
+func NewVersion() Version {
+	var b [12]byte
+	_, _ = rand.Read(b[:]) // never fails
+	return Version(base64.RawURLEncoding.EncodeToString(b[:]))
+}
+
+// VersionOf returns a Version derived from the JSON encoding of v, for
+// records stored without a version of their own. Equal records have equal
+// versions.
+func VersionOf(v any) (Version, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return Version(base64.RawURLEncoding.EncodeToString(sum[:12])), nil
+}
+
+// ConflictError returns the error a bound mutation returns when the Version
+// it was given is stale. latest, usually the record as stored now, becomes
+// the Data of the error, which glaze.retryOnConflict passes to the next
+// attempt.
+func ConflictError(latest any) *Error {
+	return &Error{Code: Conflict, Message: "the record was changed elsewhere", Data: latest}
+}
+
+// CheckVersion returns nil when got is the current version, and
+// ConflictError(latest) otherwise. It suits stores that read the record
+// before writing it back under a lock or transaction.
+func CheckVersion(got, current Version, latest any) error {
+	if got == current {
+		return nil
+	}
+	return ConflictError(latest)
+}
+
+var versionType = reflect.TypeFor[Version]()
+
+// funcUsesVersion reports whether the function type t takes a Version,
+// directly or as a field of a struct parameter.
+func funcUsesVersion(t reflect.Type) bool {
+	for i := range t.NumIn() {
+		in := t.In(i)
+		for in.Kind() == reflect.Pointer {
+			in = in.Elem()
+		}
+		if in == versionType {
+			return true
+		}
+		if in.Kind() != reflect.Struct {
+			continue
+		}
+		for j := range in.NumField() {
+			if f := in.Field(j); f.IsExported() && f.Type == versionType {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package glaze
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type versionedNote struct {
+	ID      int     `json:"id"`
+	Title   string  `json:"title"`
+	Version Version `json:"version"`
+}
+
+func TestCheckVersion(t *testing.T) {
+	latest := versionedNote{ID: 1, Title: "new", Version: "v2"}
+	if err := CheckVersion("v2", latest.Version, latest); err != nil {
+		t.Fatalf("CheckVersion(current) = %v, want nil", err)
+	}
+	err := CheckVersion("v1", latest.Version, latest)
+	var e *Error
+	if !errors.As(err, &e) || e.Code != Conflict || e.Data != latest {
+		t.Fatalf("CheckVersion(stale) = %#v, want a conflict carrying the latest record", err)
+	}
+}
+
+func TestVersions(t *testing.T) {
+	if a, b := NewVersion(), NewVersion(); a == "" || a == b {
+		t.Errorf("NewVersion() = %q, %q, want distinct tokens", a, b)
+	}
+	a, err := VersionOf(versionedNote{ID: 1, Title: "a"})
+	if err != nil {
+		t.Fatalf("VersionOf() unexpected error: %v", err)
+	}
+	same, _ := VersionOf(versionedNote{ID: 1, Title: "a"})
+	other, _ := VersionOf(versionedNote{ID: 1, Title: "b"})
+	if a != same || a == other {
+		t.Errorf("VersionOf() = %q, %q, %q, want equal only for equal records", a, same, other)
+	}
+	if _, err := VersionOf(func() {}); err == nil {
+		t.Error("VersionOf(func) returned no error")
+	}
+}
+
+func TestFuncUsesVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   any
+		want bool
+	}{
+		{"param", func(int, Version) error { return nil }, true},
+		{"struct field", func(versionedNote) error { return nil }, true},
+		{"pointer to struct", func(*versionedNote) error { return nil }, true},
+		{"result only", func() versionedNote { return versionedNote{} }, false},
+		{"none", func(string) {}, false},
+	}
+	for _, tt := range tests {
+		if got := funcUsesVersion(reflect.TypeOf(tt.fn)); got != tt.want {
+			t.Errorf("funcUsesVersion(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBindVersionedInstallsRetry(t *testing.T) {
+	w := &fakeWebView{}
+	defer w.Destroy()
+
+	_ = w.Bind("notes_save", func(n versionedNote) (versionedNote, error) {
+		return versionedNote{}, CheckVersion(n.Version, "v2", versionedNote{ID: n.ID, Version: "v2"})
+	})
+	_, err := w.call(t, "notes_save", map[string]any{"id": 1, "version": "v1"})
+	var e *Error
+	if !errors.As(err, &e) || e.Code != Conflict {
+		t.Fatalf("notes_save error = %v, want a conflict", err)
+	}
+	n := 0
+	for _, js := range w.inits {
+		if js == conflictJS {
+			n++
+		}
+	}
+	if n != 1 {
+		t.Errorf("retry helper installed %d times, want once", n)
+	}
+}
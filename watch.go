@@ -0,0 +1,34 @@
+package glaze
+
+import (
+	"sync"
+	"time"
+)
+
+// watchChanges calls read every interval and fn, from a background
+// goroutine, with the first successful reading and every reading that differs
+// from the previous one according to equal. Failed readings are skipped. The
+// returned function stops the watcher and is safe to call more than once.
+func watchChanges[T any](interval time.Duration, read func() (T, error), equal func(a, b T) bool, fn func(T)) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var last T
+		first := true
+		for {
+			if v, err := read(); err == nil && (first || !equal(v, last)) {
+				first = false
+				last = v
+				fn(v)
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	var once sync.Once
+	return func() { once.Do(func() { close(done) }) }
+}
@@ -0,0 +1,47 @@
+package glaze
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchChangesReportsChanges(t *testing.T) {
+	var mu sync.Mutex
+	readings := []int{1, 1, 2}
+	read := func() (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		v := readings[0]
+		if len(readings) > 1 {
+			readings = readings[1:]
+		}
+		return v, nil
+	}
+
+	got := make(chan int, 4)
+	stop := watchChanges(time.Millisecond, read, func(a, b int) bool { return a == b }, func(v int) { got <- v })
+	defer stop()
+
+	for i, want := range []int{1, 2} {
+		select {
+		case v := <-got:
+			if v != want {
+				t.Fatalf("change %d = %d, want %d", i, v, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timeout waiting for change %d", i)
+		}
+	}
+	select {
+	case v := <-got:
+		t.Fatalf("unexpected extra change %d", v)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestWatchChangesStopIsIdempotent(t *testing.T) {
+	stop := watchChanges(time.Millisecond, func() (int, error) { return 0, nil }, func(a, b int) bool { return a == b }, func(int) {})
+	stop()
+	stop()
+}
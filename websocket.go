@@ -0,0 +1,205 @@
+package glaze
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocket opcodes (RFC 6455).
+const (
+	wsContinuation = 0x0
+	wsText         = 0x1
+	wsBinary       = 0x2
+	wsClose        = 0x8
+	wsPing         = 0x9
+	wsPong         = 0xA
+)
+
+// maxWSMessage bounds the size of a message received over a WebSocket.
+const maxWSMessage = 1 << 20
+
+// wsGUID is appended to the client key to compute the handshake answer.
+const wsGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// wsConn is the server side of a WebSocket connection.
+type wsConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+
+	// writeTimeout, when set, bounds each write, so a peer that stops
+	// reading fails the write instead of blocking it.
+	writeTimeout time.Duration
+
+	wmu sync.Mutex
+}
+
+// upgradeWebSocket answers a WebSocket handshake and takes over the
+// connection. It refuses pages of another origin than the server's, which
+// could otherwise connect with the user's cookies.
+func upgradeWebSocket(rw http.ResponseWriter, req *http.Request) (*wsConn, error) {
+	key := req.Header.Get("Sec-WebSocket-Key")
+	if !strings.EqualFold(req.Header.Get("Upgrade"), "websocket") ||
+		req.Header.Get("Sec-WebSocket-Version") != "13" || key == "" {
+		http.Error(rw, "websocket handshake expected", http.StatusBadRequest)
+		return nil, errors.New("not a websocket handshake")
+	}
+	if origin := req.Header.Get("Origin"); origin != "" {
+		if u, err := url.Parse(origin); err != nil || u.Host != req.Host {
+			http.Error(rw, "cross-origin websocket refused", http.StatusForbidden)
+			return nil, fmt.Errorf("origin %q refused", origin)
+		}
+	}
+	hj, ok := rw.(http.Hijacker)
+	if !ok {
+		http.Error(rw, "websocket not supported", http.StatusInternalServerError)
+		return nil, errors.New("connection cannot be hijacked")
+	}
+	conn, brw, err := hj.Hijack()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum([]byte(key + wsGUID))
+	_, err = io.WriteString(conn, "HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: "+base64.StdEncoding.EncodeToString(sum[:])+"\r\n\r\n")
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &wsConn{conn: conn, r: brw.Reader}, nil
+}
+
+// read returns the next message, answering pings on the way. It returns
+// io.EOF once the client closes the connection.
+func (c *wsConn) read() ([]byte, error) {
+	var msg []byte
+	for {
+		fin, op, masked, payload, err := readWSFrame(c.r)
+		if err != nil {
+			return nil, err
+		}
+		if !masked {
+			return nil, errors.New("unmasked frame from client")
+		}
+		switch op {
+		case wsPing:
+			if err := c.write(wsPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case wsPong:
+			continue
+		case wsClose:
+			_ = c.write(wsClose, nil)
+			return nil, io.EOF
+		case wsText, wsBinary, wsContinuation:
+		default:
+			return nil, fmt.Errorf("unknown opcode %d", op)
+		}
+		if len(msg)+len(payload) > maxWSMessage {
+			return nil, fmt.Errorf("message exceeds %d bytes", maxWSMessage)
+		}
+		msg = append(msg, payload...)
+		if fin {
+			return msg, nil
+		}
+	}
+}
+
+// write sends one frame; it may be called from any goroutine.
+func (c *wsConn) write(op byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.writeTimeout > 0 {
+		_ = c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
+	}
+	return writeWSFrame(c.conn, op, payload, false)
+}
+
+func (c *wsConn) Close() error {
+	return c.conn.Close()
+}
+
+// readWSFrame reads one frame and unmasks its payload.
+func readWSFrame(r io.Reader) (fin bool, op byte, masked bool, payload []byte, err error) {
+	var h [2]byte
+	if _, err = io.ReadFull(r, h[:]); err != nil {
+		return
+	}
+	fin, op, masked = h[0]&0x80 != 0, h[0]&0x0F, h[1]&0x80 != 0
+	n := uint64(h[1] & 0x7F)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(r, ext[:]); err != nil {
+			return
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(r, ext[:]); err != nil {
+			return
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > maxWSMessage {
+		err = fmt.Errorf("frame of %d bytes exceeds %d", n, maxWSMessage)
+		return
+	}
+	var key [4]byte
+	if masked {
+		if _, err = io.ReadFull(r, key[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, n)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= key[i%4]
+		}
+	}
+	return
+}
+
+// writeWSFrame writes payload as one final frame; clients mask theirs.
+func writeWSFrame(w io.Writer, op byte, payload []byte, mask bool) error {
+	buf := []byte{0x80 | op, 0}
+	switch n := len(payload); {
+	case n < 126:
+		buf[1] = byte(n)
+	case n <= 0xFFFF:
+		buf[1] = 126
+		buf = binary.BigEndian.AppendUint16(buf, uint16(n))
+	default:
+		buf[1] = 127
+		buf = binary.BigEndian.AppendUint64(buf, uint64(n))
+	}
+	if !mask {
+		_, err := w.Write(append(buf, payload...))
+		return err
+	}
+	buf[1] |= 0x80
+	var key [4]byte
+	_, _ = rand.Read(key[:])
+	buf = append(buf, key[:]...)
+	for i, b := range payload {
+		buf = append(buf, b^key[i%4])
+	}
+	_, err := w.Write(buf)
+	return err
+}
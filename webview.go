@@ -87,6 +87,13 @@ type WebView interface {
 	//
 	// f must be a function
 	// f must return either value and error or just error
+	//
+	// JavaScript may omit trailing arguments, which f receives as zero
+	// values, and pass null for pointer parameters.
+	//
+	// Functions bound before Navigate or SetHtml are defined when the page
+	// starts. Pages can await window.glazeReady, or listen for the
+	// "glazeready" window event, before their first call.
 	Bind(name string, f any) error
 
 	// Removes a callback that was previously set by Bind.
@@ -103,7 +110,7 @@ func Init() error {
 		rt := &glazeRuntime{
 			dispatchMap: make(map[uintptr]func()),
 			bindingMap:  make(map[uintptr]bindingEntry),
-			boundNames:  make(map[string]uintptr),
+			boundNames:  make(map[bindingKey]uintptr),
 		}
 
 		libHandle, err := loadLibrary(libraryPath())
@@ -144,6 +151,8 @@ func Init() error {
 			}
 			*s.ptr = ptr
 		}
+		// Older libraries lack it; SetEngineFeatures reports that.
+		rt.pGetNativeHandle, _ = loadSymbol(libHandle, "webview_get_native_handle")
 
 		rt.initCallbacks()
 
@@ -210,6 +219,9 @@ type glazeRuntime struct {
 	pUnbind    uintptr
 	pReturn    uintptr
 
+	// Optional: zero when the library does not export it.
+	pGetNativeHandle uintptr
+
 	// Callback function pointers registered with the native library.
 	dispatchCB uintptr
 	bindingCB  uintptr
@@ -222,10 +234,17 @@ type glazeRuntime struct {
 	// State for managing bound callbacks.
 	bindMu         sync.Mutex
 	bindingMap     map[uintptr]bindingEntry
-	boundNames     map[string]uintptr
+	boundNames     map[bindingKey]uintptr
 	bindingCounter uintptr
 }
 
+// bindingKey names a binding: the library binds names per window, so every
+// window may bind the same name.
+type bindingKey struct {
+	handle uintptr
+	name   string
+}
+
 // bindingEntry stores a bound callback and associated webview handle.
 type bindingEntry struct {
 	fn func(id, req string) (any, error)
@@ -266,6 +285,15 @@ func (w *webview) Dispatch(f func()) {
 }
 
 func (w *webview) Destroy() {
+	forgetBridge(w)
+	w.rt.bindMu.Lock()
+	for key, contextKey := range w.rt.boundNames {
+		if key.handle == w.handle {
+			delete(w.rt.boundNames, key)
+			delete(w.rt.bindingMap, contextKey)
+		}
+	}
+	w.rt.bindMu.Unlock()
 	purego.SyscallN(w.rt.pDestroy, w.handle)
 }
 
@@ -276,6 +304,18 @@ func (w *webview) Window() unsafe.Pointer {
 	return *(*unsafe.Pointer)(unsafe.Pointer(&r1))
 }
 
+// browserController returns the engine's browser object: the WebKitWebView
+// on Linux, the WKWebView on macOS and the ICoreWebView2Controller on
+// Windows, or 0 if the library cannot tell.
+func (w *webview) browserController() uintptr {
+	if w.rt.pGetNativeHandle == 0 {
+		return 0
+	}
+	const kindBrowserController = 2 // WEBVIEW_NATIVE_HANDLE_KIND_BROWSER_CONTROLLER
+	r1, _, _ := purego.SyscallN(w.rt.pGetNativeHandle, w.handle, kindBrowserController)
+	return r1
+}
+
 func (w *webview) SetTitle(title string) {
 	cs, ptr := cString(title)
 	purego.SyscallN(w.rt.pSetTitle, w.handle, uintptr(ptr))
@@ -299,11 +339,33 @@ func (w *webview) SetHtml(html string) {
 }
 
 func (w *webview) Init(js string) {
+	bridgeFor(w).initScript(js)
+}
+
+// initNative registers js with the native library's own Init, which keeps
+// it until the window is destroyed.
+func (w *webview) initNative(js string) {
 	cs, ptr := cString(js)
 	purego.SyscallN(w.rt.pInit, w.handle, uintptr(ptr))
 	runtime.KeepAlive(cs)
 }
 
+// addScript registers js with the engine directly, so that it can be
+// removed; see scriptEngine.
+func (w *webview) addScript(js string) (uintptr, error) {
+	browser := w.browserController()
+	if browser == 0 {
+		return 0, errors.New("webview: the native library does not expose the browser")
+	}
+	return addEngineScript(browser, js)
+}
+
+func (w *webview) removeScript(handle uintptr) {
+	if browser := w.browserController(); browser != 0 {
+		removeEngineScript(browser, handle)
+	}
+}
+
 func (w *webview) Eval(js string) {
 	cs, ptr := cString(js)
 	purego.SyscallN(w.rt.pEval, w.handle, uintptr(ptr))
@@ -311,41 +373,71 @@ func (w *webview) Eval(js string) {
 }
 
 func (w *webview) Bind(name string, f any) error {
-	fn, err := makeFuncWrapper(f)
+	fn, err := makeBinding(w, name, f)
 	if err != nil {
 		return err
 	}
+	fn = bridgeFor(w).instrument(name, fn)
 
 	w.rt.bindMu.Lock()
-	if _, exists := w.rt.boundNames[name]; exists {
+	if _, exists := w.rt.boundNames[bindingKey{w.handle, name}]; exists {
 		w.rt.bindMu.Unlock()
 		return errors.New("function name already bound")
 	}
 	contextKey := w.rt.bindingCounter
 	w.rt.bindingCounter++
 	w.rt.bindingMap[contextKey] = bindingEntry{w: w.handle, fn: fn}
-	w.rt.boundNames[name] = contextKey
+	w.rt.boundNames[bindingKey{w.handle, name}] = contextKey
 	w.rt.bindMu.Unlock()
 
 	nameBytes, namePtr := cString(name)
 	purego.SyscallN(w.rt.pBind, w.handle, uintptr(namePtr), w.rt.bindingCB, contextKey)
 	runtime.KeepAlive(nameBytes)
+	bridgeFor(w).reregisterInits()
+	bridgeFor(w).recordBinding(name, f)
+	bridgeFor(w).installEvents()
+	if name != readyName {
+		return bridgeFor(w).installReady()
+	}
+	return nil
+}
+
+// rebind replaces the function behind a bound name without touching the
+// page: the JavaScript stub stays in place and the next call reaches f.
+func (w *webview) rebind(name string, f any) error {
+	fn, err := makeBinding(w, name, f)
+	if err != nil {
+		return err
+	}
+	fn = bridgeFor(w).instrument(name, fn)
+
+	w.rt.bindMu.Lock()
+	contextKey, exists := w.rt.boundNames[bindingKey{w.handle, name}]
+	if !exists {
+		w.rt.bindMu.Unlock()
+		return errors.New("function name not bound")
+	}
+	w.rt.bindingMap[contextKey] = bindingEntry{w: w.handle, fn: fn}
+	w.rt.bindMu.Unlock()
+	bridgeFor(w).recordBinding(name, f)
 	return nil
 }
 
 func (w *webview) Unbind(name string) error {
 	w.rt.bindMu.Lock()
-	contextKey, exists := w.rt.boundNames[name]
+	contextKey, exists := w.rt.boundNames[bindingKey{w.handle, name}]
 	if !exists {
 		w.rt.bindMu.Unlock()
 		return errors.New("function name not bound")
 	}
-	delete(w.rt.boundNames, name)
+	delete(w.rt.boundNames, bindingKey{w.handle, name})
 	delete(w.rt.bindingMap, contextKey)
 	w.rt.bindMu.Unlock()
+	bridgeFor(w).forgetBinding(name)
 	cs, namePtr := cString(name)
 	purego.SyscallN(w.rt.pUnbind, w.handle, uintptr(namePtr))
 	runtime.KeepAlive(cs)
+	bridgeFor(w).reregisterInits()
 	return nil
 }
 
@@ -355,9 +447,20 @@ var errorType = reflect.TypeFor[error]()
 // validating its signature and caching the relevant details.
 // It returns a closure that, given (id, req string),
 // decodes JSON args, calls the underlying function, and returns (value, error).
+func makeFuncWrapper(f any) (func(id, req string) (any, error), error) {
+	return makeBinding(nil, "", f)
+}
+
+// makeBinding is makeFuncWrapper for a function bound in w as name.
+// Parameters of an injected type, such as *Progress or context.Context, are
+// supplied for each call instead of being decoded from the JavaScript
+// arguments. Arguments and results go through the Codec configured for the
+// binding or window, if any. Functions bound through Bind0 to Bind3 are
+// called without reflection. It must run on the UI thread, since it may
+// install page runtime the injected values rely on.
 //
 //nolint:cyclop,funlen
-func makeFuncWrapper(f any) (func(id, req string) (any, error), error) {
+func makeBinding(w WebView, name string, f any) (func(id, req string) (any, error), error) {
 	v := reflect.ValueOf(f)
 	if v.Kind() != reflect.Func {
 		return nil, errors.New("only functions can be bound")
@@ -365,85 +468,228 @@ func makeFuncWrapper(f any) (func(id, req string) (any, error), error) {
 
 	funcType := v.Type()
 	outCount := funcType.NumOut()
-	if outCount > 2 {
-		return nil, errors.New("function may only return a value or value+error")
+	if outCount > 1 && !funcType.Out(outCount-1).Implements(errorType) {
+		return nil, errors.New("last return value must implement error")
 	}
+	outTypes := resultTypes(funcType)
 
 	numIn := funcType.NumIn()
 	isVariadic := funcType.IsVariadic()
 	inTypes := make([]reflect.Type, numIn)
+	injectors := make([]paramInjector, numIn)
+	checks := make([]*structValidator, numIn)
+	numJS := numIn
 	for i := range numIn {
 		inTypes[i] = funcType.In(i)
+		if inj, ok := injectedParams[inTypes[i]]; ok {
+			injectors[i] = inj
+			numJS--
+			if w != nil && inj.setup != nil {
+				inj.setup(w)
+			}
+			continue
+		}
+		sv, err := validatorFor(inTypes[i])
+		if err != nil {
+			return nil, err
+		}
+		checks[i] = sv
 	}
 
-	var returnsError bool
-	switch outCount {
-	case 1:
-		if funcType.Out(0).Implements(errorType) {
-			returnsError = true
+	returnsError := len(outTypes) < outCount
+
+	if w != nil && funcUsesBinary(funcType) {
+		bridgeFor(w).injectScript("binary", binaryJS)
+	}
+	if w != nil && outCount > 0 && funcType.Out(0).Implements(pageType) {
+		bridgeFor(w).injectScript("pages", pagesJS)
+	}
+	if w != nil && funcUsesVersion(funcType) {
+		bridgeFor(w).injectScript("conflict", conflictJS)
+	}
+	binaryOut := len(outTypes) == 1 && binaryResult(outTypes[0])
+
+	if tf, ok := f.(typedFunc); ok && numJS == numIn {
+		lenient := make([]bool, numIn)
+		for i, t := range inTypes {
+			lenient[i] = lenientType(t)
 		}
-	case 2:
-		if !funcType.Out(1).Implements(errorType) {
-			return nil, errors.New("second return value must implement error")
+		return func(id, req string) (any, error) {
+			args := typedArgs{codec: bindingCodec(w, name), lenient: lenient, checks: checks}
+			if err := json.Unmarshal([]byte(req), &args.raw); err != nil {
+				return nil, err
+			}
+			var txID string
+			if args.raw, txID = splitTx(args.raw); txID != "" {
+				leave, err := enterTx(w, id, txID)
+				if err != nil {
+					return nil, err
+				}
+				defer leave()
+			}
+			if len(args.raw) > numIn {
+				return nil, errors.New("function arguments mismatch")
+			}
+			value, err := tf.invoke(&args)
+			if binaryOut && value != nil && !reflect.ValueOf(value).IsNil() {
+				value = Binary(reflect.ValueOf(value).Bytes())
+			}
+			return encodeResult(w, args.codec, value, err)
+		}, nil
+	}
+
+	results := func(res []reflect.Value) (any, error) {
+		var err error
+		if returnsError {
+			if v := res[len(res)-1].Interface(); v != nil {
+				err = v.(error)
+			}
+			res = res[:len(res)-1]
+		}
+		switch len(res) {
+		case 0:
+			return nil, err
+		case 1:
+			return res[0].Interface(), err
+		default:
+			// Several values reach the page as an array.
+			values := make([]any, len(res))
+			for i, r := range res {
+				values[i] = r.Interface()
+			}
+			return values, err
 		}
 	}
 
 	fn := func(id, req string) (any, error) {
+		codec := bindingCodec(w, name)
 		var rawArgs []json.RawMessage
 		if err := json.Unmarshal([]byte(req), &rawArgs); err != nil {
 			return nil, err
 		}
-		if (!isVariadic && len(rawArgs) != numIn) || (isVariadic && len(rawArgs) < numIn-1) {
+		var txID string
+		if rawArgs, txID = splitTx(rawArgs); txID != "" {
+			leave, err := enterTx(w, id, txID)
+			if err != nil {
+				return nil, err
+			}
+			defer leave()
+		}
+		if !isVariadic && len(rawArgs) > numJS {
 			return nil, errors.New("function arguments mismatch")
 		}
 
-		args := make([]reflect.Value, len(rawArgs))
-		for i := range rawArgs {
-			var argVal reflect.Value
-			if isVariadic && i >= numIn-1 {
-				argVal = reflect.New(inTypes[numIn-1].Elem())
-			} else {
-				argVal = reflect.New(inTypes[i])
+		args := make([]reflect.Value, 0, max(numIn, numIn+len(rawArgs)-numJS))
+		var invalid []FieldError
+		next := 0
+		for i := range numIn {
+			if injectors[i].value != nil {
+				args = append(args, injectors[i].value(w, id))
+				continue
 			}
-			if err := json.Unmarshal(rawArgs[i], argVal.Interface()); err != nil {
-				return nil, err
+			if isVariadic && i == numIn-1 {
+				for ; next < len(rawArgs); next++ {
+					argVal, err := decodeArg(codec, rawArgs[next], inTypes[i].Elem())
+					if err != nil {
+						return nil, argError(next, inTypes[i].Elem(), err)
+					}
+					if checks[i] != nil {
+						checks[i].walk(argVal, next, "", &invalid)
+					}
+					args = append(args, argVal)
+				}
+				break
 			}
-			args[i] = argVal.Elem()
-		}
-
-		res := v.Call(args)
-
-		switch outCount {
-		case 0:
-			return nil, nil //nolint:nilnil
-		case 1:
-			if returnsError {
-				if v := res[0].Interface(); v != nil {
-					return nil, v.(error)
+			// Omitted trailing arguments are zero values.
+			argVal := reflect.Zero(inTypes[i])
+			if next < len(rawArgs) {
+				var err error
+				if argVal, err = decodeArg(codec, rawArgs[next], inTypes[i]); err != nil {
+					return nil, argError(next, inTypes[i], err)
 				}
-				return nil, nil //nolint:nilnil
 			}
-			return res[0].Interface(), nil
-		case 2:
-			var err error
-			if v := res[1].Interface(); v != nil {
-				err = v.(error)
+			if checks[i] != nil {
+				checks[i].walk(argVal, next, "", &invalid)
 			}
-			return res[0].Interface(), err
-		default:
-			panic("unreachable")
+			args = append(args, argVal)
+			next++
 		}
+		if err := validationError(invalid); err != nil {
+			return nil, err
+		}
+
+		value, err := results(v.Call(args))
+		if binaryOut && value != nil && !reflect.ValueOf(value).IsNil() {
+			value = Binary(reflect.ValueOf(value).Bytes())
+		}
+		return encodeResult(w, codec, value, err)
 	}
 
 	return fn, nil
 }
 
+// resultTypes returns the types of the results of bound function type t
+// that reach the page: all but a trailing error. A function with several
+// such results resolves to an array of them.
+func resultTypes(t reflect.Type) []reflect.Type {
+	n := t.NumOut()
+	if n > 0 && t.Out(n-1).Implements(errorType) {
+		n--
+	}
+	types := make([]reflect.Type, n)
+	for i := range types {
+		types[i] = t.Out(i)
+	}
+	return types
+}
+
+// encodeResult prepares the result of a call to a function bound in w for
+// callAndMarshal, encoding it with codec and compressing it as configured.
+func encodeResult(w WebView, codec Codec, value any, err error) (any, error) {
+	if err != nil || value == nil {
+		return value, err
+	}
+	minSize := compressionThreshold(w)
+	if codec == JSONCodec && minSize == 0 {
+		return value, nil
+	}
+	// Encode with the binding's codec; callAndMarshal sends the raw
+	// message as is.
+	data, err := codec.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if minSize > 0 && len(data) >= minSize {
+		return compressResult(data)
+	}
+	return json.RawMessage(data), nil
+}
+
+// paramInjector supplies a bound function parameter that does not come from
+// JavaScript.
+type paramInjector struct {
+	// setup prepares w for the parameter when the function is bound.
+	setup func(w WebView)
+
+	// value returns the parameter for the call id in w. w is nil for
+	// functions wrapped outside a window.
+	value func(w WebView, id string) reflect.Value
+}
+
+// injectedParams maps each injected parameter type to its injector.
+var injectedParams = map[reflect.Type]paramInjector{
+	reflect.TypeFor[*Progress](): {setup: setupProgress, value: progressParam},
+	contextType:                  {value: contextParam},
+	reflect.TypeFor[CallInfo]():  {setup: setupCallInfo, value: callInfoParam},
+	reflect.TypeFor[*Tx]():       {value: txParam},
+}
+
 // callAndMarshal executes a bound function and marshals the result to JSON.
 // Returns the status code (0 for success, -1 for error) and the JSON string.
 func callAndMarshal(fn func(id, req string) (any, error), id, req string) (int, string) {
 	resultValue, err := fn(id, req)
 	if err != nil {
-		return -1, marshalJSON(err.Error())
+		return -1, marshalError(err)
 	}
 
 	data, e := json.Marshal(resultValue)
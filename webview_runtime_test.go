@@ -2,6 +2,8 @@ package glaze
 
 import (
 	"runtime"
+	"slices"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -26,7 +28,7 @@ func TestBindingCallbackReturnsViaDispatch(t *testing.T) {
 				},
 			},
 		},
-		boundNames: make(map[string]uintptr),
+		boundNames: make(map[bindingKey]uintptr),
 	}
 	rt.initCallbacks()
 
@@ -95,3 +97,134 @@ func TestBindingCallbackReturnsViaDispatch(t *testing.T) {
 		t.Fatal("timeout waiting for binding return")
 	}
 }
+
+// stubNative records what the stub native functions of stubRuntime were
+// called with, per window handle.
+type stubNative struct {
+	mu    sync.Mutex
+	names map[uintptr][]string
+	inits map[uintptr][]string
+}
+
+// bound returns the names bound in the window handle, sorted.
+func (n *stubNative) bound(handle uintptr) []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return slices.Sorted(slices.Values(n.names[handle]))
+}
+
+// initScripts returns the scripts registered with Init in the window handle.
+func (n *stubNative) initScripts(handle uintptr) []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return slices.Clone(n.inits[handle])
+}
+
+// stubRuntime returns a glazeRuntime whose native functions are Go
+// callbacks recording their calls.
+func stubRuntime(t *testing.T) (*glazeRuntime, *stubNative) {
+	t.Helper()
+	rt := &glazeRuntime{
+		dispatchMap: make(map[uintptr]func()),
+		bindingMap:  make(map[uintptr]bindingEntry),
+		boundNames:  make(map[bindingKey]uintptr),
+	}
+	rt.initCallbacks()
+	n := &stubNative{names: make(map[uintptr][]string), inits: make(map[uintptr][]string)}
+	noop := purego.NewCallback(func(_, _ uintptr) uintptr { return 0 })
+	rt.pEval, rt.pDestroy = noop, noop
+	rt.pInit = purego.NewCallback(func(handle, js uintptr) uintptr {
+		n.mu.Lock()
+		n.inits[handle] = append(n.inits[handle], goString(js))
+		n.mu.Unlock()
+		return 0
+	})
+	rt.pBind = purego.NewCallback(func(handle, name, _, _ uintptr) uintptr {
+		n.mu.Lock()
+		n.names[handle] = append(n.names[handle], goString(name))
+		n.mu.Unlock()
+		return 0
+	})
+	rt.pUnbind = purego.NewCallback(func(handle, name uintptr) uintptr {
+		n.mu.Lock()
+		n.names[handle] = slices.DeleteFunc(n.names[handle], func(s string) bool { return s == goString(name) })
+		n.mu.Unlock()
+		return 0
+	})
+	return rt, n
+}
+
+func TestBindNamesPerWindow(t *testing.T) {
+	rt, native := stubRuntime(t)
+	first, second := &webview{handle: 1, rt: rt}, &webview{handle: 2, rt: rt}
+	defer second.Destroy()
+
+	save := func(string) error { return nil }
+	if err := first.Bind("save", save); err != nil {
+		t.Fatalf("first window Bind() unexpected error: %v", err)
+	}
+	if err := second.Bind("save", save); err != nil {
+		t.Fatalf("second window Bind() unexpected error: %v", err)
+	}
+	want := []string{readyName, "save"}
+	for _, w := range []*webview{first, second} {
+		if got := native.bound(w.handle); !slices.Equal(got, want) {
+			t.Errorf("window %d bound %q, want %q", w.handle, got, want)
+		}
+	}
+	if err := second.Bind("save", save); err == nil {
+		t.Error("binding a name twice in one window returned no error")
+	}
+
+	if err := first.Unbind("save"); err != nil {
+		t.Fatalf("Unbind() unexpected error: %v", err)
+	}
+	if err := second.rebind("save", save); err != nil {
+		t.Errorf("rebind() in the other window unexpected error: %v", err)
+	}
+
+	first.Destroy()
+	rt.bindMu.Lock()
+	defer rt.bindMu.Unlock()
+	for key := range rt.boundNames {
+		if key.handle == first.handle {
+			t.Errorf("binding %q of the destroyed window kept", key.name)
+		}
+	}
+	if len(rt.boundNames) != 2 {
+		t.Errorf("bound names = %v, want the second window's two", rt.boundNames)
+	}
+}
+
+func TestAppBindInEveryWindow(t *testing.T) {
+	rt, native := stubRuntime(t)
+	first, second := &webview{handle: 1, rt: rt}, &webview{handle: 2, rt: rt}
+	defer first.Destroy()
+	defer second.Destroy()
+
+	// AppOptions.Bind is bound in each window as AppWindow and OpenWindow
+	// do.
+	services := map[string]any{"notes": bindAllNotes{}, "ping": func() string { return "pong" }}
+	for _, w := range []*webview{first, second} {
+		if _, err := BindAll(w, services); err != nil {
+			t.Fatalf("window %d BindAll() unexpected error: %v", w.handle, err)
+		}
+	}
+	if got, want := native.bound(second.handle), native.bound(first.handle); !slices.Equal(got, want) || !slices.Contains(got, "ping") {
+		t.Errorf("second window bound %q, want %q as in the first", got, want)
+	}
+}
+
+func TestBindInstallsEventsBeforeEmit(t *testing.T) {
+	rt, native := stubRuntime(t)
+	w := &webview{handle: 1, rt: rt}
+	defer w.Destroy()
+
+	// A page loaded after the first Bind can call glaze.on at once.
+	if err := w.Bind("save", func(string) error { return nil }); err != nil {
+		t.Fatalf("Bind() unexpected error: %v", err)
+	}
+	if !slices.Contains(native.initScripts(w.handle), eventsJS) {
+		t.Error("events runtime not registered before the first Emit")
+	}
+}
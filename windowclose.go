@@ -0,0 +1,47 @@
+package glaze
+
+import (
+	"errors"
+	"sync"
+)
+
+// windowClosers holds the functions watchWindowClose runs, by native
+// window.
+var windowClosers struct {
+	mu  sync.Mutex
+	fns map[uintptr]func()
+}
+
+// watchWindowClose makes fn run on the UI thread once the native window of
+// w is closed, by the user or the system, before w is destroyed. It must be
+// called from the UI thread, once per window.
+func watchWindowClose(w WebView, fn func()) error {
+	window := uintptr(w.Window())
+	if window == 0 {
+		return errors.New("webview: window handle is not available")
+	}
+	windowClosers.mu.Lock()
+	if windowClosers.fns == nil {
+		windowClosers.fns = make(map[uintptr]func())
+	}
+	windowClosers.fns[window] = fn
+	windowClosers.mu.Unlock()
+	if err := watchNativeClose(window); err != nil {
+		windowClosers.mu.Lock()
+		delete(windowClosers.fns, window)
+		windowClosers.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// windowClosed runs the function watching window, once.
+func windowClosed(window uintptr) {
+	windowClosers.mu.Lock()
+	fn := windowClosers.fns[window]
+	delete(windowClosers.fns, window)
+	windowClosers.mu.Unlock()
+	if fn != nil {
+		fn()
+	}
+}
@@ -0,0 +1,43 @@
+package glaze
+
+import (
+	"sync"
+
+	"github.com/ebitengine/purego/objc"
+)
+
+// windowCloseObserver is the object that observes NSWindowWillClose
+// notifications for watchNativeClose.
+var windowCloseObserver struct {
+	once     sync.Once
+	err      error
+	observer objc.ID
+}
+
+// watchNativeClose calls windowClosed when the NSWindow window posts
+// NSWindowWillCloseNotification.
+func watchNativeClose(window uintptr) error {
+	o := &windowCloseObserver
+	o.once.Do(func() {
+		class, err := objc.RegisterClass("GlazeWindowCloseObserver", objc.GetClass("NSObject"), nil, nil, []objc.MethodDef{{
+			Cmd: objc.RegisterName("glazeWindowWillClose:"),
+			Fn: func(self objc.ID, _ objc.SEL, notification objc.ID) {
+				window := notification.Send(objc.RegisterName("object"))
+				objc.ID(objc.GetClass("NSNotificationCenter")).Send(objc.RegisterName("defaultCenter")).
+					Send(objc.RegisterName("removeObserver:name:object:"), self, nsString("NSWindowWillCloseNotification"), window)
+				windowClosed(uintptr(window))
+			},
+		}})
+		if err != nil {
+			o.err = err
+			return
+		}
+		o.observer = objc.ID(class).Send(objc.RegisterName("new"))
+	})
+	if o.err != nil {
+		return o.err
+	}
+	objc.ID(objc.GetClass("NSNotificationCenter")).Send(objc.RegisterName("defaultCenter")).
+		Send(objc.RegisterName("addObserver:selector:name:object:"), o.observer, objc.RegisterName("glazeWindowWillClose:"), nsString("NSWindowWillCloseNotification"), objc.ID(window))
+	return nil
+}
@@ -0,0 +1,37 @@
+package glaze
+
+import (
+	"sync"
+
+	"github.com/ebitengine/purego"
+)
+
+// gtkDestroy holds the GObject entry point and the callback used to watch
+// windows close.
+var gtkDestroy struct {
+	once     sync.Once
+	err      error
+	callback uintptr
+
+	connect func(instance uintptr, signal string, handler, data, destroyData uintptr, flags int32) uint64
+}
+
+// watchNativeClose calls windowClosed when the GtkWindow window emits
+// "destroy", which it does when it is closed.
+func watchNativeClose(window uintptr) error {
+	s := &gtkDestroy
+	s.once.Do(func() {
+		s.err = openNative("libgobject-2.0.so.0", []nativeFunc{
+			{&s.connect, "g_signal_connect_data"},
+		})
+		s.callback = purego.NewCallback(func(widget, _ uintptr) uintptr {
+			windowClosed(widget)
+			return 0
+		})
+	})
+	if s.err != nil {
+		return s.err
+	}
+	s.connect(window, "destroy", s.callback, 0, 0, 0)
+	return nil
+}
@@ -0,0 +1,39 @@
+package glaze
+
+import (
+	"fmt"
+	"sync"
+	"syscall"
+)
+
+const (
+	wmDestroy = 0x0002
+
+	// closeSubclassID identifies glaze's window subclass that watches for
+	// the window to close.
+	closeSubclassID = 0x676c6331
+)
+
+var (
+	closeSubclassOnce sync.Once
+	closeSubclassProc uintptr
+)
+
+// watchNativeClose calls windowClosed when the window hwnd receives
+// WM_DESTROY.
+func watchNativeClose(hwnd uintptr) error {
+	closeSubclassOnce.Do(func() {
+		closeSubclassProc = syscall.NewCallback(func(hwnd, msg, wParam, lParam, _, _ uintptr) uintptr {
+			if msg == wmDestroy {
+				procRemoveWindowSubclass.Call(hwnd, closeSubclassProc, closeSubclassID)
+				windowClosed(hwnd)
+			}
+			r, _, _ := procDefSubclassProc.Call(hwnd, msg, wParam, lParam)
+			return r
+		})
+	})
+	if r, _, err := procSetWindowSubclass.Call(hwnd, closeSubclassProc, closeSubclassID, 0); r == 0 {
+		return fmt.Errorf("webview: SetWindowSubclass failed: %w", err)
+	}
+	return nil
+}